@@ -2,15 +2,22 @@ package main
 
 import (
 	"context"
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/json"
 	"fmt"
 	"log"
 	"net/http"
 	"os"
 	"os/signal"
+	"strconv"
 	"syscall"
 	"time"
 
 	"github.com/99designs/gqlgen/graphql/handler"
+	"github.com/99designs/gqlgen/graphql/handler/extension"
+	"github.com/99designs/gqlgen/graphql/handler/lru"
+	"github.com/99designs/gqlgen/graphql/handler/transport"
 	"github.com/99designs/gqlgen/graphql/playground"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
@@ -20,12 +27,26 @@ import (
 	"github.com/yousoon/apps/services/booking-service/internal/application/queries"
 	"github.com/yousoon/apps/services/booking-service/internal/domain"
 	"github.com/yousoon/apps/services/booking-service/internal/infrastructure/mongodb"
+	"github.com/yousoon/apps/services/booking-service/internal/infrastructure/realtime"
+	bookingredis "github.com/yousoon/apps/services/booking-service/internal/infrastructure/redis"
+	"github.com/yousoon/apps/services/booking-service/internal/infrastructure/signedurl"
+	"github.com/yousoon/apps/services/booking-service/internal/infrastructure/wallet"
+	"github.com/yousoon/apps/services/booking-service/internal/interface/events"
 	"github.com/yousoon/apps/services/booking-service/internal/interface/graphql/resolver"
+	"github.com/yousoon/apps/services/booking-service/internal/interface/rest"
+	"github.com/yousoon/shared/infrastructure/nats"
+	"github.com/yousoon/shared/infrastructure/outbox"
+	"github.com/yousoon/shared/infrastructure/redis"
+	"github.com/yousoon/shared/infrastructure/scheduler"
+	"github.com/yousoon/shared/observability/metrics"
 )
 
 func main() {
 	// Load configuration
 	cfg := config.Load()
+	if err := cfg.Validate(); err != nil {
+		log.Fatalf("Invalid configuration: %v", err)
+	}
 
 	log.Printf("Starting %s in %s mode", cfg.ServiceName, cfg.Environment)
 
@@ -42,24 +63,302 @@ func main() {
 
 	db := mongoClient.Database(cfg.MongoDatabase)
 
+	// Connect to NATS
+	natsClient, err := nats.NewClient(ctx, nats.Config{
+		URL:           cfg.NatsURL,
+		Name:          cfg.ServiceName,
+		MaxReconnects: -1,
+		ReconnectWait: 2 * time.Second,
+		Timeout:       10 * time.Second,
+	})
+	if err != nil {
+		log.Fatalf("Failed to connect to NATS: %v", err)
+	}
+	defer natsClient.Close()
+
+	// Connect to Redis
+	redisConfig := redis.DefaultConfig()
+	redisConfig.Address = cfg.RedisAddr
+	redisConfig.Password = cfg.RedisPassword
+	redisConfig.DB = cfg.RedisDB
+	redisClient, err := redis.NewClient(ctx, redisConfig)
+	if err != nil {
+		log.Fatalf("Failed to connect to Redis: %v", err)
+	}
+	defer redisClient.Close()
+
 	// Initialize repositories
-	outingRepo := mongodb.NewOutingRepository(db)
+	outboxStore := outbox.NewStore(db)
+	if err := outboxStore.EnsureIndexes(ctx); err != nil {
+		log.Printf("Failed to ensure outbox indexes: %v", err)
+	}
+	outingRepo := mongodb.NewOutingRepository(db, outboxStore)
+	waitlistRepo := mongodb.NewWaitlistRepository(db, outboxStore)
+	standingRepo := mongodb.NewUserStandingRepository(db, outboxStore)
+
+	// Start outbox relay (domain events -> NATS)
+	eventPublisher := nats.NewEventPublisher(natsClient)
+	relayConfig := outbox.DefaultConfig()
+	relayConfig.PollInterval = cfg.OutboxPollInterval
+	relayConfig.BatchSize = cfg.OutboxBatchSize
+	outboxRelay := outbox.NewRelay(outboxStore, eventPublisher, relayConfig)
+
+	workerCtx, stopWorkers := context.WithCancel(ctx)
+	defer stopWorkers()
+	go outboxRelay.Run(workerCtx)
+
+	// Configure QR signing keys
+	qrKeys := make([]domain.QRKey, 0, len(cfg.QRSigningKeys))
+	for _, key := range cfg.QRSigningKeys {
+		qrKeys = append(qrKeys, domain.QRKey{ID: key.ID, Secret: []byte(key.Secret), RetiredAt: key.RetiredAt})
+	}
+	qrKeyRing, err := domain.NewQRKeyRing(qrKeys, cfg.QRCodeWindow, cfg.QRKeyGracePeriod)
+	if err != nil {
+		log.Fatalf("Invalid QR signing keys: %v", err)
+	}
+
+	// Configure offline check-in signing keys
+	checkInSigningKeys := make([]domain.CheckInSigningKey, 0, len(cfg.CheckInSigningKeys))
+	for _, key := range cfg.CheckInSigningKeys {
+		seed, err := base64.StdEncoding.DecodeString(key.Secret)
+		if err != nil || len(seed) != ed25519.SeedSize {
+			log.Fatalf("Invalid check-in signing key %q: expected a base64 %d-byte seed", key.ID, ed25519.SeedSize)
+		}
+		checkInSigningKeys = append(checkInSigningKeys, domain.CheckInSigningKey{
+			ID:         key.ID,
+			PrivateKey: ed25519.NewKeyFromSeed(seed),
+			RetiredAt:  key.RetiredAt,
+		})
+	}
+	checkInKeySet, err := domain.NewCheckInKeySet(checkInSigningKeys, cfg.CheckInKeyGracePeriod)
+	if err != nil {
+		log.Fatalf("Invalid check-in signing keys: %v", err)
+	}
+	checkInKeys := domain.NewCheckInKeys(qrKeyRing, checkInKeySet)
+
+	// Default check-in geofence
+	var defaultGeofence *domain.Geofence
+	if cfg.GeofenceRadiusMeters > 0 {
+		geofence, err := domain.NewGeofence(float64(cfg.GeofenceRadiusMeters), domain.GeofenceMode(cfg.GeofenceMode))
+		if err != nil {
+			log.Fatalf("Invalid check-in geofence: %v", err)
+		}
+		defaultGeofence = &geofence
+	}
+
+	// Cancellation policy and strikes
+	cancellationPolicy, err := domain.NewCancellationPolicy(cfg.FreeCancellationWindow)
+	if err != nil {
+		log.Fatalf("Invalid cancellation policy: %v", err)
+	}
+	strikePolicy, err := domain.NewStrikePolicy(cfg.StrikeThreshold, cfg.StrikeWindow, cfg.BookingBanDuration)
+	if err != nil {
+		log.Fatalf("Invalid strike policy: %v", err)
+	}
+	if cfg.ConfirmationTimeout <= 0 {
+		log.Fatalf("Invalid confirmation timeout %v: must be positive", cfg.ConfirmationTimeout)
+	}
+	if cfg.ShortCodeMaxFailures <= 0 || cfg.ShortCodeFailureWindow <= 0 {
+		log.Fatalf("Invalid short code attempt limit %d per %v: both must be positive", cfg.ShortCodeMaxFailures, cfg.ShortCodeFailureWindow)
+	}
+
+	// Calendar and wallet passes
+	passSigner := signedurl.NewSigner(cfg.PublicBaseURL, cfg.PassLinkSecret)
+	var applePasses *wallet.ApplePassBuilder
+	if cfg.ApplePassTypeID != "" {
+		applePasses, err = wallet.NewApplePassBuilder(wallet.ApplePassConfig{
+			PassTypeID:       cfg.ApplePassTypeID,
+			TeamID:           cfg.AppleTeamID,
+			OrganizationName: cfg.AppleOrganizationName,
+			CertFile:         cfg.ApplePassCertFile,
+			KeyFile:          cfg.ApplePassKeyFile,
+			WWDRCertFile:     cfg.AppleWWDRCertFile,
+		}, checkInKeys)
+		if err != nil {
+			log.Fatalf("Invalid Apple Wallet configuration: %v", err)
+		}
+	}
+	passLinks := rest.NewPassLinks(passSigner, applePasses != nil, cfg.CalendarFeedLinkTTL)
+	var googleWallet *wallet.GoogleWalletIssuer
+	if cfg.GoogleWalletIssuerID != "" {
+		googleWallet, err = wallet.NewGoogleWalletIssuer(wallet.GoogleWalletConfig{
+			IssuerID:            cfg.GoogleWalletIssuerID,
+			ClassSuffix:         cfg.GoogleWalletClassSuffix,
+			ServiceAccountEmail: cfg.GoogleWalletServiceAccount,
+			KeyFile:             cfg.GoogleWalletKeyFile,
+			Origins:             cfg.GoogleWalletOrigins,
+		}, checkInKeys)
+		if err != nil {
+			log.Fatalf("Invalid Google Wallet configuration: %v", err)
+		}
+	}
 
 	// Initialize services (stubs for now - would be gRPC clients)
 	offerService := &stubOfferService{}
-	userService := &stubUserService{}
+	userService := commands.NewStandingUserService(&stubUserService{}, standingRepo)
 	notifyService := &stubNotificationService{}
+	partnerService := &stubPartnerService{}
+
+	quotaLocation, err := time.LoadLocation(cfg.QuotaTimezone)
+	if err != nil {
+		log.Fatalf("Invalid quota timezone %q: %v", cfg.QuotaTimezone, err)
+	}
+	quotaService := bookingredis.NewQuotaService(redisClient, outingRepo, cfg.QuotaCounterTTL, quotaLocation)
+	slotService := bookingredis.NewSlotCapacityService(redisClient, outingRepo)
+	idempotencyStore := bookingredis.NewIdempotencyStore(redisClient, cfg.IdempotencyKeyTTL)
+	shortCodeLimiter := bookingredis.NewShortCodeAttemptLimiter(redisClient, cfg.ShortCodeMaxFailures, cfg.ShortCodeFailureWindow)
 
 	// Initialize command handlers
+	strikeRecorder := commands.NewStrikeRecorder(standingRepo, strikePolicy)
+	waitlistPromoter := commands.NewWaitlistPromoter(waitlistRepo, outingRepo, offerService, quotaService, slotService, cfg.WaitlistHoldTTL)
 	bookOutingHandler := commands.NewBookOutingHandler(
 		outingRepo,
 		offerService,
 		userService,
+		quotaService,
+		slotService,
 		notifyService,
+		idempotencyStore,
 		cfg.BookingExpirationMinutes,
+		cfg.ConfirmationTimeout,
+	)
+	checkInHandler := commands.NewCheckInOutingHandler(
+		outingRepo,
+		offerService,
+		partnerService,
+		notifyService,
+		idempotencyStore,
+		shortCodeLimiter,
+		checkInKeys,
+		defaultGeofence,
 	)
-	checkInHandler := commands.NewCheckInOutingHandler(outingRepo, notifyService)
-	cancelOutingHandler := commands.NewCancelOutingHandler(outingRepo, offerService, notifyService)
+	syncOfflineCheckInsHandler := commands.NewSyncOfflineCheckInsHandler(outingRepo, offerService, partnerService, notifyService, checkInKeys, defaultGeofence)
+	recordOutingBillHandler := commands.NewRecordOutingBillHandler(outingRepo, partnerService)
+	// The stub partner service knows no opening hours, so the opening hours
+	// rule never fires until the partner service client is wired
+	log.Printf("Check-in anomaly rule for opening hours inactive: no partner service client")
+	detectCheckInAnomaliesHandler := commands.NewDetectCheckInAnomaliesHandler(outingRepo, partnerService, domain.AnomalyRules{
+		MaxStaffCheckInsPerMinute: cfg.AnomalyMaxStaffCheckInsPerMinute,
+		OpeningHoursGrace:         cfg.AnomalyOpeningHoursGrace,
+		TravelWindow:              cfg.AnomalyTravelWindow,
+		TravelMinDistanceMeters:   float64(cfg.AnomalyTravelMinDistanceMeters),
+	})
+	cancelOutingHandler := commands.NewCancelOutingHandler(
+		outingRepo,
+		offerService,
+		partnerService,
+		quotaService,
+		slotService,
+		notifyService,
+		waitlistPromoter,
+		strikeRecorder,
+		&cancellationPolicy,
+	)
+	confirmOutingHandler := commands.NewConfirmOutingHandler(outingRepo, partnerService, notifyService)
+	rejectOutingHandler := commands.NewRejectOutingHandler(
+		outingRepo,
+		offerService,
+		partnerService,
+		quotaService,
+		slotService,
+		notifyService,
+		waitlistPromoter,
+	)
+	transferOutingHandler := commands.NewTransferOutingHandler(outingRepo, userService, notifyService)
+	acceptOutingTransferHandler := commands.NewAcceptOutingTransferHandler(
+		outingRepo,
+		offerService,
+		userService,
+		quotaService,
+		notifyService,
+	)
+	cancelOutingTransferHandler := commands.NewCancelOutingTransferHandler(outingRepo)
+	rejectUnconfirmedOutingsHandler := commands.NewRejectUnconfirmedOutingsHandler(
+		outingRepo,
+		offerService,
+		quotaService,
+		slotService,
+		notifyService,
+		waitlistPromoter,
+	)
+	expireOutingsHandler := commands.NewExpireOutingsHandler(outingRepo, quotaService, slotService, waitlistPromoter)
+	markNoShowsHandler := commands.NewMarkNoShowsHandler(outingRepo, strikeRecorder)
+	joinWaitlistHandler := commands.NewJoinWaitlistHandler(waitlistRepo, outingRepo, offerService, userService)
+	leaveWaitlistHandler := commands.NewLeaveWaitlistHandler(waitlistRepo, waitlistPromoter)
+	acceptWaitlistHoldHandler := commands.NewAcceptWaitlistHoldHandler(
+		waitlistRepo,
+		outingRepo,
+		waitlistPromoter,
+		offerService,
+		userService,
+		notifyService,
+		cfg.BookingExpirationMinutes,
+		cfg.ConfirmationTimeout,
+	)
+	expireWaitlistHoldsHandler := commands.NewExpireWaitlistHoldsHandler(waitlistRepo, outingRepo, waitlistPromoter)
+
+	// Background jobs (each run once per interval across replicas)
+	jobScheduler := scheduler.NewScheduler(redisClient, cfg.ServiceName+":jobs:", "booking_jobs")
+	jobScheduler.Register(scheduler.Job{
+		Name:     "expire-outings",
+		Interval: cfg.ExpireJobInterval,
+		LockTTL:  2 * cfg.ExpireJobInterval,
+		Run: func(ctx context.Context) (scheduler.JobResult, error) {
+			result, err := expireOutingsHandler.Handle(ctx, commands.ExpireOutingsCommand{BatchSize: cfg.JobBatchSize})
+			if err != nil {
+				return scheduler.JobResult{}, err
+			}
+			return scheduler.JobResult{Processed: result.ExpiredCount, Failed: result.FailedCount}, nil
+		},
+	})
+	jobScheduler.Register(scheduler.Job{
+		Name:     "mark-no-shows",
+		Interval: cfg.NoShowJobInterval,
+		LockTTL:  2 * cfg.NoShowJobInterval,
+		Run: func(ctx context.Context) (scheduler.JobResult, error) {
+			result, err := markNoShowsHandler.Handle(ctx, commands.MarkNoShowsCommand{
+				GracePeriod: cfg.NoShowGracePeriod,
+				BatchSize:   cfg.JobBatchSize,
+			})
+			if err != nil {
+				return scheduler.JobResult{}, err
+			}
+			return scheduler.JobResult{Processed: result.NoShowCount, Failed: result.FailedCount}, nil
+		},
+	})
+	jobScheduler.Register(scheduler.Job{
+		Name:     "reject-unconfirmed-outings",
+		Interval: cfg.ExpireJobInterval,
+		LockTTL:  2 * cfg.ExpireJobInterval,
+		Run: func(ctx context.Context) (scheduler.JobResult, error) {
+			result, err := rejectUnconfirmedOutingsHandler.Handle(ctx, commands.RejectUnconfirmedOutingsCommand{BatchSize: cfg.JobBatchSize})
+			if err != nil {
+				return scheduler.JobResult{}, err
+			}
+			return scheduler.JobResult{Processed: result.RejectedCount, Failed: result.FailedCount}, nil
+		},
+	})
+	jobScheduler.Register(scheduler.Job{
+		Name:     "expire-waitlist-holds",
+		Interval: cfg.ExpireJobInterval,
+		LockTTL:  2 * cfg.ExpireJobInterval,
+		Run: func(ctx context.Context) (scheduler.JobResult, error) {
+			result, err := expireWaitlistHoldsHandler.Handle(ctx, commands.ExpireWaitlistHoldsCommand{BatchSize: cfg.JobBatchSize})
+			if err != nil {
+				return scheduler.JobResult{}, err
+			}
+			return scheduler.JobResult{Processed: result.ExpiredCount, Failed: result.FailedCount}, nil
+		},
+	})
+	jobScheduler.Start(workerCtx)
+
+	// Check-in anomaly detection, fed by the check-in events relayed to NATS
+	eventSubscriber := nats.NewSubscriber(natsClient)
+	defer eventSubscriber.Close()
+	checkInConsumer := events.NewCheckInConsumer(eventSubscriber, cfg.NatsStreamName, detectCheckInAnomaliesHandler)
+	if err := checkInConsumer.Start(workerCtx); err != nil {
+		log.Printf("Check-in anomaly detection disabled: %v", err)
+	}
 
 	// Initialize query handlers
 	getOutingHandler := queries.NewGetOutingHandler(outingRepo)
@@ -67,35 +366,111 @@ func main() {
 	listUserOutingsHandler := queries.NewListUserOutingsHandler(outingRepo)
 	listPartnerOutingsHandler := queries.NewListPartnerOutingsHandler(outingRepo)
 	listEstablishmentOutingsHandler := queries.NewListEstablishmentOutingsHandler(outingRepo)
+	listFlaggedOutingsHandler := queries.NewListFlaggedOutingsHandler(outingRepo)
 	getBookingStatsHandler := queries.NewGetBookingStatsHandler(outingRepo)
+	getUserSavingsHandler := queries.NewGetUserSavingsHandler(outingRepo)
+	getSlotAvailabilityHandler := queries.NewGetSlotAvailabilityHandler(offerService, slotService)
+	listUserWaitlistHandler := queries.NewListUserWaitlistHandler(waitlistRepo)
+	listIncomingTransfersHandler := queries.NewListIncomingTransfersHandler(outingRepo)
+	exportOutingsHandler := queries.NewExportOutingsHandler(outingRepo, partnerService)
+
+	// Live outing updates, fed by the outing events relayed to NATS
+	outingFeed := realtime.NewOutingFeed(outingRepo)
+	go func() {
+		if err := outingFeed.Run(workerCtx, natsClient.Conn()); err != nil {
+			log.Printf("Live outing feed error: %v", err)
+		}
+	}()
+	watchEstablishmentOutingsHandler := queries.NewWatchEstablishmentOutingsHandler(outingFeed, partnerService)
 
 	// Initialize resolver
 	resolv := resolver.NewResolver(
 		bookOutingHandler,
 		checkInHandler,
+		syncOfflineCheckInsHandler,
+		recordOutingBillHandler,
 		cancelOutingHandler,
+		confirmOutingHandler,
+		rejectOutingHandler,
+		transferOutingHandler,
+		acceptOutingTransferHandler,
+		cancelOutingTransferHandler,
+		joinWaitlistHandler,
+		leaveWaitlistHandler,
+		acceptWaitlistHoldHandler,
 		getOutingHandler,
 		getOutingByQRHandler,
 		listUserOutingsHandler,
 		listPartnerOutingsHandler,
 		listEstablishmentOutingsHandler,
+		listFlaggedOutingsHandler,
 		getBookingStatsHandler,
+		getUserSavingsHandler,
+		getSlotAvailabilityHandler,
+		listUserWaitlistHandler,
+		listIncomingTransfersHandler,
+		watchEstablishmentOutingsHandler,
+		passLinks,
+		googleWallet,
+		checkInKeys,
 	)
 
+	// Metrics server
+	if metricsPort, err := strconv.Atoi(cfg.MetricsPort); err == nil {
+		metricsServer := metrics.NewServer(metricsPort)
+		go func() {
+			if err := metricsServer.Start(); err != nil && err != http.ErrServerClosed {
+				log.Printf("Metrics server error: %v", err)
+			}
+		}()
+		defer metricsServer.Shutdown()
+	}
+
 	// Create GraphQL server
-	srv := handler.NewDefaultServer(nil) // Would use generated.NewExecutableSchema
+	srv := handler.New(nil) // Would use generated.NewExecutableSchema
+
+	// Subscriptions over websocket; the connection must carry the user
+	// forwarded by the router
+	srv.AddTransport(transport.Websocket{
+		KeepAlivePingInterval: 10 * time.Second,
+		InitFunc: func(ctx context.Context, initPayload transport.InitPayload) (context.Context, *transport.InitPayload, error) {
+			if _, ok := ctx.Value("user_id").(string); !ok {
+				return nil, nil, fmt.Errorf("unauthorized")
+			}
+			return ctx, nil, nil
+		},
+	})
+	srv.AddTransport(transport.Options{})
+	srv.AddTransport(transport.GET{})
+	srv.AddTransport(transport.POST{})
+	srv.AddTransport(transport.MultipartForm{})
+	srv.SetQueryCache(lru.New(1000))
+	srv.Use(extension.Introspection{})
+	srv.Use(extension.AutomaticPersistedQuery{Cache: lru.New(100)})
 
 	// HTTP server
 	mux := http.NewServeMux()
 
 	// GraphQL endpoint
-	mux.Handle(cfg.GraphQLPath, srv)
+	mux.Handle(cfg.GraphQLPath, rest.ForwardedUser(srv))
 
 	// Playground (development only)
 	if cfg.PlaygroundEnabled {
 		mux.Handle("/", playground.Handler("Booking Service", cfg.GraphQLPath))
 	}
 
+	// Public keys for partner scanners verifying check-in tokens offline
+	mux.HandleFunc("/.well-known/checkin-keys", checkInKeysHandler(checkInKeySet))
+
+	// Partner outing exports (CSV/XLSX)
+	mux.Handle("/exports/outings", rest.ForwardedUser(rest.NewExportHandler(exportOutingsHandler)))
+
+	// Calendar events, calendar feeds and Apple Wallet passes behind signed links
+	passHandler := rest.NewPassHandler(passSigner, getOutingHandler, listUserOutingsHandler, applePasses)
+	mux.HandleFunc(rest.OutingCalendarPath, passHandler.ServeOutingCalendar)
+	mux.HandleFunc(rest.CalendarFeedPath, passHandler.ServeCalendarFeed)
+	mux.HandleFunc(rest.ApplePassPath, passHandler.ServeApplePass)
+
 	// Health check
 	mux.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusOK)
@@ -141,6 +516,10 @@ func main() {
 
 	log.Println("Shutting down server...")
 
+	// Stop background workers
+	stopWorkers()
+	jobScheduler.Wait()
+
 	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer shutdownCancel()
 
@@ -205,6 +584,64 @@ func (s *stubOfferService) DecrementBookingCount(ctx context.Context, offerID st
 	return nil
 }
 
+func (s *stubOfferService) GetQuota(ctx context.Context, offerID string) (*domain.OfferQuota, error) {
+	quota := domain.NewUnlimitedOfferQuota()
+	return &quota, nil
+}
+
+func (s *stubOfferService) GetSchedule(ctx context.Context, offerID string) (*domain.OfferSchedule, error) {
+	schedule := domain.NewOfferSchedule(true, nil, time.UTC)
+	return &schedule, nil
+}
+
+func (s *stubOfferService) GetGeofence(ctx context.Context, offerID string) (*domain.Geofence, error) {
+	return nil, nil
+}
+
+func (s *stubOfferService) GetCancellationPolicy(ctx context.Context, offerID string) (*domain.CancellationPolicy, error) {
+	return nil, nil
+}
+
+func (s *stubOfferService) RequiresConfirmation(ctx context.Context, offerID string) (bool, error) {
+	return false, nil
+}
+
+// checkInKeysHandler publishes the check-in public keys as a JWK set
+func checkInKeysHandler(keySet *domain.CheckInKeySet) http.HandlerFunc {
+	type jwk struct {
+		KeyID     string     `json:"kid"`
+		KeyType   string     `json:"kty"`
+		Curve     string     `json:"crv"`
+		Algorithm string     `json:"alg"`
+		Use       string     `json:"use"`
+		X         string     `json:"x"`
+		RetiredAt *time.Time `json:"retiredAt,omitempty"`
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		publicKeys := keySet.PublicKeys(time.Now())
+
+		keys := make([]jwk, 0, len(publicKeys))
+		for _, key := range publicKeys {
+			keys = append(keys, jwk{
+				KeyID:     key.ID,
+				KeyType:   "OKP",
+				Curve:     "Ed25519",
+				Algorithm: "EdDSA",
+				Use:       "sig",
+				X:         base64.RawURLEncoding.EncodeToString(key.Key),
+				RetiredAt: key.RetiredAt,
+			})
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Cache-Control", "public, max-age=300")
+		if err := json.NewEncoder(w).Encode(map[string]interface{}{"keys": keys}); err != nil {
+			log.Printf("Failed to write check-in keys: %v", err)
+		}
+	}
+}
+
 type stubUserService struct{}
 
 func (s *stubUserService) GetUserSnapshot(ctx context.Context, userID string) (*domain.UserSnapshot, error) {
@@ -216,6 +653,10 @@ func (s *stubUserService) CanBook(ctx context.Context, userID string) error {
 	return nil
 }
 
+func (s *stubUserService) GetUserIDByEmail(ctx context.Context, email string) (string, error) {
+	return "", domain.ErrUserNotFound
+}
+
 type stubNotificationService struct{}
 
 func (s *stubNotificationService) SendBookingConfirmation(ctx context.Context, outing *domain.Outing) error {
@@ -230,6 +671,40 @@ func (s *stubNotificationService) SendCancellationNotification(ctx context.Conte
 	return nil
 }
 
+func (s *stubNotificationService) SendConfirmationRequest(ctx context.Context, outing *domain.Outing) error {
+	return nil
+}
+
+func (s *stubNotificationService) SendBookingDecision(ctx context.Context, outing *domain.Outing) error {
+	return nil
+}
+
+func (s *stubNotificationService) SendTransferOffer(ctx context.Context, outing *domain.Outing) error {
+	return nil
+}
+
+func (s *stubNotificationService) SendTransferAccepted(ctx context.Context, outing *domain.Outing, previousUserID string) error {
+	return nil
+}
+
 func (s *stubNotificationService) SendExpirationReminder(ctx context.Context, outing *domain.Outing) error {
 	return nil
 }
+
+type stubPartnerService struct{}
+
+// Team checks fail closed until the partner service client is wired: staff
+// actions are refused rather than allowed for anyone.
+func (s *stubPartnerService) IsTeamMember(ctx context.Context, userID, establishmentID string) (bool, error) {
+	return false, nil
+}
+
+func (s *stubPartnerService) IsPartnerMember(ctx context.Context, userID, partnerID string) (bool, error) {
+	return false, nil
+}
+
+// GetOpeningHours knows no opening hours, which leaves the opening hours
+// anomaly rule inactive until the partner service client is wired.
+func (s *stubPartnerService) GetOpeningHours(ctx context.Context, establishmentID string) (*domain.OpeningHours, error) {
+	return nil, nil
+}
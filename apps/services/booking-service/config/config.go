@@ -1,8 +1,10 @@
 package config
 
 import (
+	"errors"
 	"os"
 	"strconv"
+	"strings"
 	"time"
 )
 
@@ -27,8 +29,19 @@ type Config struct {
 	RedisDB       int
 
 	// NATS
-	NatsURL     string
-	NatsCluster string
+	NatsURL        string
+	NatsCluster    string
+	NatsStreamName string
+
+	// Outbox relay
+	OutboxPollInterval time.Duration
+	OutboxBatchSize    int
+
+	// Background jobs
+	ExpireJobInterval time.Duration
+	NoShowJobInterval time.Duration
+	NoShowGracePeriod time.Duration
+	JobBatchSize      int
 
 	// gRPC clients
 	IdentityServiceAddr  string
@@ -37,6 +50,67 @@ type Config struct {
 	// Booking settings
 	BookingExpirationMinutes int
 	MaxBookingsPerUser       int
+	QuotaCounterTTL          time.Duration
+	QuotaTimezone            string
+	WaitlistHoldTTL          time.Duration
+	IdempotencyKeyTTL        time.Duration
+
+	// Time partners have to confirm bookings of offers requiring confirmation
+	// before they are rejected, capped by the slot start or outing expiry
+	ConfirmationTimeout time.Duration
+
+	// Cancellation policy default, overridable per offer
+	FreeCancellationWindow time.Duration
+
+	// Strikes: Threshold strikes within StrikeWindow ban the user for BookingBanDuration
+	StrikeThreshold    int
+	StrikeWindow       time.Duration
+	BookingBanDuration time.Duration
+
+	// QR codes
+	QRSigningKeys    []QRKeyConfig
+	QRCodeWindow     time.Duration
+	QRKeyGracePeriod time.Duration
+
+	// Offline check-in tokens (Ed25519, secrets are base64 32-byte seeds)
+	CheckInSigningKeys    []QRKeyConfig
+	CheckInKeyGracePeriod time.Duration
+
+	// Check-in geofence default (0 disables it), overridable per offer
+	GeofenceRadiusMeters int
+	GeofenceMode         string
+
+	// Short check-in codes: invalid codes tried at an establishment within
+	// the window before lookups are refused
+	ShortCodeMaxFailures   int
+	ShortCodeFailureWindow time.Duration
+
+	// Check-in anomaly detection (a zero threshold disables its rule)
+	AnomalyMaxStaffCheckInsPerMinute int
+	AnomalyOpeningHoursGrace         time.Duration
+	AnomalyTravelWindow              time.Duration
+	AnomalyTravelMinDistanceMeters   int
+
+	// Calendar and wallet pass links, signed so calendar apps can fetch them;
+	// feed links expire after CalendarFeedLinkTTL
+	PublicBaseURL       string
+	PassLinkSecret      string
+	CalendarFeedLinkTTL time.Duration
+
+	// Apple Wallet passes (disabled without a pass type ID); PEM files
+	ApplePassTypeID       string
+	AppleTeamID           string
+	AppleOrganizationName string
+	ApplePassCertFile     string
+	ApplePassKeyFile      string
+	AppleWWDRCertFile     string
+
+	// Google Wallet passes (disabled without an issuer ID); RSA PEM key file
+	GoogleWalletIssuerID       string
+	GoogleWalletClassSuffix    string
+	GoogleWalletServiceAccount string
+	GoogleWalletKeyFile        string
+	GoogleWalletOrigins        []string
 
 	// Observability
 	JaegerEndpoint string
@@ -46,12 +120,22 @@ type Config struct {
 	JWTSecret string
 }
 
+// QRKeyConfig is a QR signing key. A key with RetiredAt is only accepted for
+// verification during the grace period; exactly one key must be active.
+type QRKeyConfig struct {
+	ID        string
+	Secret    string
+	RetiredAt *time.Time
+}
+
 func Load() *Config {
+	environment := getEnv("ENVIRONMENT", "development")
+
 	return &Config{
 		// Server
 		ServerPort:  getEnv("SERVER_PORT", "8083"),
 		ServerHost:  getEnv("SERVER_HOST", "0.0.0.0"),
-		Environment: getEnv("ENVIRONMENT", "development"),
+		Environment: environment,
 		ServiceName: getEnv("SERVICE_NAME", "booking-service"),
 
 		// GraphQL
@@ -68,8 +152,19 @@ func Load() *Config {
 		RedisDB:       getEnvInt("REDIS_DB", 0),
 
 		// NATS
-		NatsURL:     getEnv("NATS_URL", "nats://localhost:4222"),
-		NatsCluster: getEnv("NATS_CLUSTER", "yousoon-cluster"),
+		NatsURL:        getEnv("NATS_URL", "nats://localhost:4222"),
+		NatsCluster:    getEnv("NATS_CLUSTER", "yousoon-cluster"),
+		NatsStreamName: getEnv("NATS_STREAM_NAME", "YOUSOON_EVENTS"),
+
+		// Outbox relay
+		OutboxPollInterval: getEnvDuration("OUTBOX_POLL_INTERVAL", time.Second),
+		OutboxBatchSize:    getEnvInt("OUTBOX_BATCH_SIZE", 100),
+
+		// Background jobs
+		ExpireJobInterval: getEnvDuration("EXPIRE_JOB_INTERVAL", time.Minute),
+		NoShowJobInterval: getEnvDuration("NO_SHOW_JOB_INTERVAL", 5*time.Minute),
+		NoShowGracePeriod: getEnvDuration("NO_SHOW_GRACE_PERIOD", time.Hour),
+		JobBatchSize:      getEnvInt("JOB_BATCH_SIZE", 100),
 
 		// gRPC clients
 		IdentityServiceAddr:  getEnv("IDENTITY_SERVICE_ADDR", "identity-service:50051"),
@@ -78,6 +173,60 @@ func Load() *Config {
 		// Booking settings
 		BookingExpirationMinutes: getEnvInt("BOOKING_EXPIRATION_MINUTES", 30),
 		MaxBookingsPerUser:       getEnvInt("MAX_BOOKINGS_PER_USER", 5),
+		QuotaCounterTTL:          getEnvDuration("QUOTA_COUNTER_TTL", time.Hour),
+		QuotaTimezone:            getEnv("QUOTA_TIMEZONE", "Europe/Paris"),
+		WaitlistHoldTTL:          getEnvDuration("WAITLIST_HOLD_TTL", 15*time.Minute),
+		IdempotencyKeyTTL:        getEnvDuration("IDEMPOTENCY_KEY_TTL", 24*time.Hour),
+		ConfirmationTimeout:      getEnvDuration("CONFIRMATION_TIMEOUT", 15*time.Minute),
+
+		// Cancellation policy
+		FreeCancellationWindow: getEnvDuration("FREE_CANCELLATION_WINDOW", 2*time.Hour),
+
+		// Strikes
+		StrikeThreshold:    getEnvInt("STRIKE_THRESHOLD", 3),
+		StrikeWindow:       getEnvDuration("STRIKE_WINDOW", 90*24*time.Hour),
+		BookingBanDuration: getEnvDuration("BOOKING_BAN_DURATION", 14*24*time.Hour),
+
+		// QR codes
+		QRSigningKeys:    getEnvQRKeys("QR_SIGNING_KEYS", devDefault(environment, "v1:yousoon-qr-secret-key-change-in-prod")),
+		QRCodeWindow:     getEnvDuration("QR_CODE_WINDOW", 30*time.Second),
+		QRKeyGracePeriod: getEnvDuration("QR_KEY_GRACE_PERIOD", 24*time.Hour),
+
+		// Offline check-in tokens
+		CheckInSigningKeys:    getEnvQRKeys("CHECKIN_SIGNING_KEYS", devDefault(environment, "v1:eW91c29vbi1kZXYtY2hlY2tpbi1zZWVkLWNoYW5nZSE=")),
+		CheckInKeyGracePeriod: getEnvDuration("CHECKIN_KEY_GRACE_PERIOD", 7*24*time.Hour),
+
+		// Check-in geofence
+		GeofenceRadiusMeters: getEnvInt("GEOFENCE_RADIUS_METERS", 300),
+		GeofenceMode:         getEnv("GEOFENCE_MODE", "flag"),
+
+		// Short check-in codes
+		ShortCodeMaxFailures:   getEnvInt("SHORT_CODE_MAX_FAILURES", 10),
+		ShortCodeFailureWindow: getEnvDuration("SHORT_CODE_FAILURE_WINDOW", 15*time.Minute),
+
+		// Check-in anomaly detection
+		AnomalyMaxStaffCheckInsPerMinute: getEnvInt("ANOMALY_MAX_STAFF_CHECK_INS_PER_MINUTE", 6),
+		AnomalyOpeningHoursGrace:         getEnvDuration("ANOMALY_OPENING_HOURS_GRACE", 30*time.Minute),
+		AnomalyTravelWindow:              getEnvDuration("ANOMALY_TRAVEL_WINDOW", 20*time.Minute),
+		AnomalyTravelMinDistanceMeters:   getEnvInt("ANOMALY_TRAVEL_MIN_DISTANCE_METERS", 15000),
+
+		// Calendar and wallet passes
+		PublicBaseURL:       getEnv("PUBLIC_BASE_URL", "http://localhost:8083"),
+		PassLinkSecret:      getEnv("PASS_LINK_SECRET", devDefault(environment, "yousoon-pass-link-secret-change-in-prod")),
+		CalendarFeedLinkTTL: getEnvDuration("CALENDAR_FEED_LINK_TTL", 90*24*time.Hour),
+
+		ApplePassTypeID:       getEnv("APPLE_PASS_TYPE_ID", ""),
+		AppleTeamID:           getEnv("APPLE_TEAM_ID", ""),
+		AppleOrganizationName: getEnv("APPLE_ORGANIZATION_NAME", "Yousoon"),
+		ApplePassCertFile:     getEnv("APPLE_PASS_CERT_FILE", ""),
+		ApplePassKeyFile:      getEnv("APPLE_PASS_KEY_FILE", ""),
+		AppleWWDRCertFile:     getEnv("APPLE_WWDR_CERT_FILE", ""),
+
+		GoogleWalletIssuerID:       getEnv("GOOGLE_WALLET_ISSUER_ID", ""),
+		GoogleWalletClassSuffix:    getEnv("GOOGLE_WALLET_CLASS_SUFFIX", "outing"),
+		GoogleWalletServiceAccount: getEnv("GOOGLE_WALLET_SERVICE_ACCOUNT", ""),
+		GoogleWalletKeyFile:        getEnv("GOOGLE_WALLET_KEY_FILE", ""),
+		GoogleWalletOrigins:        getEnvList("GOOGLE_WALLET_ORIGINS"),
 
 		// Observability
 		JaegerEndpoint: getEnv("JAEGER_ENDPOINT", "http://localhost:14268/api/traces"),
@@ -88,6 +237,21 @@ func Load() *Config {
 	}
 }
 
+// Validate rejects a configuration missing secrets that only have a
+// development default.
+func (c *Config) Validate() error {
+	if len(c.QRSigningKeys) == 0 {
+		return errors.New("QR_SIGNING_KEYS is required outside development")
+	}
+	if len(c.CheckInSigningKeys) == 0 {
+		return errors.New("CHECKIN_SIGNING_KEYS is required outside development")
+	}
+	if c.PassLinkSecret == "" {
+		return errors.New("PASS_LINK_SECRET is required outside development")
+	}
+	return nil
+}
+
 func (c *Config) GetServerAddr() string {
 	return c.ServerHost + ":" + c.ServerPort
 }
@@ -96,6 +260,15 @@ func (c *Config) GetBookingExpiration() time.Duration {
 	return time.Duration(c.BookingExpirationMinutes) * time.Minute
 }
 
+// devDefault returns value in development and nothing elsewhere, so that
+// secrets shipped for local runs never reach a deployed environment
+func devDefault(environment, value string) string {
+	if environment == "development" {
+		return value
+	}
+	return ""
+}
+
 func getEnv(key, defaultValue string) string {
 	if value := os.Getenv(key); value != "" {
 		return value
@@ -112,6 +285,15 @@ func getEnvInt(key string, defaultValue int) int {
 	return defaultValue
 }
 
+func getEnvDuration(key string, defaultValue time.Duration) time.Duration {
+	if value := os.Getenv(key); value != "" {
+		if duration, err := time.ParseDuration(value); err == nil {
+			return duration
+		}
+	}
+	return defaultValue
+}
+
 func getEnvBool(key string, defaultValue bool) bool {
 	if value := os.Getenv(key); value != "" {
 		if boolVal, err := strconv.ParseBool(value); err == nil {
@@ -120,3 +302,35 @@ func getEnvBool(key string, defaultValue bool) bool {
 	}
 	return defaultValue
 }
+
+// getEnvList parses a comma-separated list, skipping empty entries
+func getEnvList(key string) []string {
+	var values []string
+	for _, value := range strings.Split(os.Getenv(key), ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
+// getEnvQRKeys parses a comma-separated list of "id:secret" (active key) and
+// "id:secret:retiredAt" (RFC 3339) entries.
+func getEnvQRKeys(key, defaultValue string) []QRKeyConfig {
+	var keys []QRKeyConfig
+	for _, entry := range strings.Split(getEnv(key, defaultValue), ",") {
+		parts := strings.SplitN(strings.TrimSpace(entry), ":", 3)
+		if len(parts) < 2 {
+			continue
+		}
+
+		qrKey := QRKeyConfig{ID: parts[0], Secret: parts[1]}
+		if len(parts) == 3 {
+			if retiredAt, err := time.Parse(time.RFC3339, parts[2]); err == nil {
+				qrKey.RetiredAt = &retiredAt
+			}
+		}
+		keys = append(keys, qrKey)
+	}
+	return keys
+}
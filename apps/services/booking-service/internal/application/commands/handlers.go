@@ -2,7 +2,11 @@ package commands
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/yousoon/apps/services/booking-service/internal/domain"
@@ -15,6 +19,14 @@ import (
 type BookOutingCommand struct {
 	UserID  string
 	OfferID string
+
+	// Optional time slot, in the offer's timezone. Without it the outing is
+	// valid from now for the configured expiration.
+	SlotDate      string // "2006-01-02"
+	SlotStartTime string // "19:00"
+
+	// Optional client key making retries return the original booking
+	IdempotencyKey string
 }
 
 type BookOutingResult struct {
@@ -25,27 +37,54 @@ type BookOutingHandler struct {
 	outingRepo     domain.OutingRepository
 	offerService   domain.OfferService
 	userService    domain.UserService
+	quotaService   domain.QuotaService
+	slotService    domain.SlotCapacityService
 	notifyService  domain.NotificationService
+	idempotency    idempotencyGuard
 	expirationMins int
+
+	// How long partners have to confirm bookings of offers requiring it
+	confirmationTimeout time.Duration
 }
 
+// NewBookOutingHandler creates the handler; idempotencyStore may be nil to
+// ignore idempotency keys.
 func NewBookOutingHandler(
 	outingRepo domain.OutingRepository,
 	offerService domain.OfferService,
 	userService domain.UserService,
+	quotaService domain.QuotaService,
+	slotService domain.SlotCapacityService,
 	notifyService domain.NotificationService,
+	idempotencyStore domain.IdempotencyStore,
 	expirationMins int,
+	confirmationTimeout time.Duration,
 ) *BookOutingHandler {
 	return &BookOutingHandler{
-		outingRepo:     outingRepo,
-		offerService:   offerService,
-		userService:    userService,
-		notifyService:  notifyService,
-		expirationMins: expirationMins,
+		outingRepo:          outingRepo,
+		offerService:        offerService,
+		userService:         userService,
+		quotaService:        quotaService,
+		slotService:         slotService,
+		notifyService:       notifyService,
+		idempotency:         idempotencyGuard{store: idempotencyStore, outingRepo: outingRepo},
+		expirationMins:      expirationMins,
+		confirmationTimeout: confirmationTimeout,
 	}
 }
 
 func (h *BookOutingHandler) Handle(ctx context.Context, cmd BookOutingCommand) (*BookOutingResult, error) {
+	outing, err := h.idempotency.run(ctx, "book:"+cmd.UserID, cmd.IdempotencyKey,
+		[]string{cmd.OfferID, cmd.SlotDate, cmd.SlotStartTime},
+		func() (*domain.Outing, error) { return h.book(ctx, cmd) },
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &BookOutingResult{Outing: outing}, nil
+}
+
+func (h *BookOutingHandler) book(ctx context.Context, cmd BookOutingCommand) (*domain.Outing, error) {
 	// 1. Check if user can book
 	if err := h.userService.CanBook(ctx, cmd.UserID); err != nil {
 		return nil, fmt.Errorf("user cannot book: %w", err)
@@ -77,31 +116,148 @@ func (h *BookOutingHandler) Handle(ctx context.Context, cmd BookOutingCommand) (
 		return nil, fmt.Errorf("failed to get user snapshot: %w", err)
 	}
 
-	// 6. Create outing
-	outing, err := domain.NewOuting(cmd.UserID, *offerSnapshot, *userSnapshot, h.expirationMins)
+	// 6. Get offer quota
+	quota, err := h.offerService.GetQuota(ctx, cmd.OfferID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create outing: %w", err)
+		return nil, fmt.Errorf("failed to get offer quota: %w", err)
+	}
+
+	// 7. Create outing, for the chosen slot if any, pending when the partner
+	// confirms each booking
+	outing, err := h.newOuting(ctx, cmd, *offerSnapshot, *userSnapshot)
+	if err != nil {
+		return nil, err
+	}
+
+	// 8. Atomically reserve capacity (total, per user, per day, then slot seat)
+	if err := h.quotaService.Reserve(ctx, cmd.OfferID, cmd.UserID, *quota, outing.BookedAt()); err != nil {
+		return nil, err
+	}
+	if outing.Slot() != nil {
+		if err := h.slotService.Reserve(ctx, cmd.OfferID, *outing.Slot()); err != nil {
+			h.releaseQuota(ctx, outing)
+			return nil, err
+		}
 	}
 
-	// 7. Persist outing
-	if err := h.outingRepo.Create(ctx, outing); err != nil {
+	// 9. Persist outing, giving the capacity back if it fails
+	if err := createOuting(ctx, h.outingRepo, outing); err != nil {
+		h.releaseQuota(ctx, outing)
+		if releaseErr := h.slotService.Release(ctx, outing); releaseErr != nil {
+			fmt.Printf("warning: failed to release slot: %v\n", releaseErr)
+		}
 		return nil, fmt.Errorf("failed to save outing: %w", err)
 	}
 
-	// 8. Increment offer booking count
+	// 10. Increment offer booking count (display counter, quota is enforced above)
 	if err := h.offerService.IncrementBookingCount(ctx, cmd.OfferID); err != nil {
 		// Log warning but don't fail
 		fmt.Printf("warning: failed to increment booking count: %v\n", err)
 	}
 
-	// 9. Send notification (async, don't block)
+	// 11. Send notification (async, don't block)
+	notifyBooked(h.notifyService, outing)
+
+	return outing, nil
+}
+
+func (h *BookOutingHandler) newOuting(ctx context.Context, cmd BookOutingCommand, offer domain.OfferSnapshot, user domain.UserSnapshot) (*domain.Outing, error) {
+	var slot *domain.SlotSnapshot
+	if cmd.SlotDate != "" || cmd.SlotStartTime != "" {
+		schedule, err := h.offerService.GetSchedule(ctx, cmd.OfferID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get offer schedule: %w", err)
+		}
+
+		resolved, err := schedule.ResolveSlot(cmd.SlotDate, cmd.SlotStartTime, time.Now())
+		if err != nil {
+			return nil, err
+		}
+		slot = &resolved
+	}
+
+	return newBookingOuting(ctx, h.offerService, cmd.UserID, offer, user, slot, h.expirationMins, h.confirmationTimeout)
+}
+
+func (h *BookOutingHandler) releaseQuota(ctx context.Context, outing *domain.Outing) {
+	if err := h.quotaService.Release(ctx, outing); err != nil {
+		fmt.Printf("warning: failed to release quota: %v\n", err)
+	}
+}
+
+// newBookingOuting creates the outing of a booking, for the slot if any. It
+// is pending when the partner confirms each booking of the offer.
+func newBookingOuting(
+	ctx context.Context,
+	offerService domain.OfferService,
+	userID string,
+	offer domain.OfferSnapshot,
+	user domain.UserSnapshot,
+	slot *domain.SlotSnapshot,
+	expirationMins int,
+	confirmationTimeout time.Duration,
+) (*domain.Outing, error) {
+	requiresConfirmation, err := offerService.RequiresConfirmation(ctx, offer.OfferID())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get offer confirmation setting: %w", err)
+	}
+
+	var outing *domain.Outing
+	switch {
+	case requiresConfirmation && slot != nil:
+		outing, err = domain.NewPendingSlotOuting(userID, offer, user, *slot, confirmationTimeout)
+	case requiresConfirmation:
+		outing, err = domain.NewPendingOuting(userID, offer, user, expirationMins, confirmationTimeout)
+	case slot != nil:
+		outing, err = domain.NewSlotOuting(userID, offer, user, *slot)
+	default:
+		outing, err = domain.NewOuting(userID, offer, user, expirationMins)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to create outing: %w", err)
+	}
+	return outing, nil
+}
+
+// maxShortCodeDraws bounds the short codes tried for a new outing before
+// giving up; with 32^6 codes a second collision at one establishment is
+// already unlikely
+const maxShortCodeDraws = 3
+
+// createOuting persists a new outing, drawing another short code when its
+// code is already used by an active outing of the establishment
+func createOuting(ctx context.Context, outingRepo domain.OutingRepository, outing *domain.Outing) error {
+	return withFreshShortCode(outing, func() error { return outingRepo.Create(ctx, outing) })
+}
+
+// withFreshShortCode runs save, drawing another short code for the outing
+// as long as save fails with ErrShortCodeTaken
+func withFreshShortCode(outing *domain.Outing, save func() error) error {
+	for draw := 1; ; draw++ {
+		err := save()
+		if err != domain.ErrShortCodeTaken || draw == maxShortCodeDraws {
+			return err
+		}
+		if err := outing.RegenerateShortCode(); err != nil {
+			return err
+		}
+	}
+}
+
+// notifyBooked sends the booking confirmation, or asks the partner staff to
+// confirm a pending outing (async, don't block)
+func notifyBooked(notifyService domain.NotificationService, outing *domain.Outing) {
 	go func() {
-		if err := h.notifyService.SendBookingConfirmation(context.Background(), outing); err != nil {
+		if outing.Status() == domain.OutingStatusPending {
+			if err := notifyService.SendConfirmationRequest(context.Background(), outing); err != nil {
+				fmt.Printf("warning: failed to send confirmation request: %v\n", err)
+			}
+			return
+		}
+		if err := notifyService.SendBookingConfirmation(context.Background(), outing); err != nil {
 			fmt.Printf("warning: failed to send booking confirmation: %v\n", err)
 		}
 	}()
-
-	return &BookOutingResult{Outing: outing}, nil
 }
 
 // =============================================================================
@@ -114,6 +270,16 @@ type CheckInOutingCommand struct {
 	StaffUserID string
 	Latitude    *float64
 	Longitude   *float64
+
+	// Short code read out by the user, only unique within EstablishmentID
+	ShortCode       string
+	EstablishmentID string
+
+	// Optional bill before discount, in cents, when staff know it already
+	BillAmountCents *int64
+
+	// Optional client key making retries return the original check-in
+	IdempotencyKey string
 }
 
 type CheckInOutingResult struct {
@@ -121,22 +287,59 @@ type CheckInOutingResult struct {
 }
 
 type CheckInOutingHandler struct {
-	outingRepo    domain.OutingRepository
-	notifyService domain.NotificationService
+	outingRepo       domain.OutingRepository
+	offerService     domain.OfferService
+	partnerService   domain.PartnerService
+	notifyService    domain.NotificationService
+	idempotency      idempotencyGuard
+	shortCodeLimiter domain.ShortCodeAttemptLimiter
+	keys             *domain.CheckInKeys
+	defaultGeofence  *domain.Geofence
 }
 
+// NewCheckInOutingHandler creates the handler; defaultGeofence applies to
+// offers without their own geofence and may be nil to disable the check.
+// idempotencyStore may be nil to ignore idempotency keys.
 func NewCheckInOutingHandler(
 	outingRepo domain.OutingRepository,
+	offerService domain.OfferService,
+	partnerService domain.PartnerService,
 	notifyService domain.NotificationService,
+	idempotencyStore domain.IdempotencyStore,
+	shortCodeLimiter domain.ShortCodeAttemptLimiter,
+	keys *domain.CheckInKeys,
+	defaultGeofence *domain.Geofence,
 ) *CheckInOutingHandler {
 	return &CheckInOutingHandler{
-		outingRepo:    outingRepo,
-		notifyService: notifyService,
+		outingRepo:       outingRepo,
+		offerService:     offerService,
+		partnerService:   partnerService,
+		notifyService:    notifyService,
+		idempotency:      idempotencyGuard{store: idempotencyStore, outingRepo: outingRepo},
+		shortCodeLimiter: shortCodeLimiter,
+		keys:             keys,
+		defaultGeofence:  defaultGeofence,
 	}
 }
 
 func (h *CheckInOutingHandler) Handle(ctx context.Context, cmd CheckInOutingCommand) (*CheckInOutingResult, error) {
-	// 1. Get outing by ID or QR code
+	var billAmount string
+	if cmd.BillAmountCents != nil {
+		billAmount = strconv.FormatInt(*cmd.BillAmountCents, 10)
+	}
+
+	outing, err := h.idempotency.run(ctx, "checkin:"+cmd.StaffUserID, cmd.IdempotencyKey,
+		[]string{cmd.OutingID, cmd.QRCode, cmd.EstablishmentID, cmd.ShortCode, billAmount},
+		func() (*domain.Outing, error) { return h.checkIn(ctx, cmd) },
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &CheckInOutingResult{Outing: outing}, nil
+}
+
+func (h *CheckInOutingHandler) checkIn(ctx context.Context, cmd CheckInOutingCommand) (*domain.Outing, error) {
+	// 1. Get outing by ID, QR code or short code
 	var outing *domain.Outing
 	var err error
 
@@ -144,27 +347,63 @@ func (h *CheckInOutingHandler) Handle(ctx context.Context, cmd CheckInOutingComm
 		outing, err = h.outingRepo.GetByID(ctx, cmd.OutingID)
 	} else if cmd.QRCode != "" {
 		outing, err = h.outingRepo.GetByQRCode(ctx, cmd.QRCode)
+	} else if cmd.ShortCode != "" {
+		outing, err = h.getByShortCode(ctx, cmd)
+		if err != nil {
+			return nil, err
+		}
 	} else {
-		return nil, fmt.Errorf("outing ID or QR code is required")
+		return nil, fmt.Errorf("outing ID, QR code or short code is required")
 	}
 
 	if err != nil {
 		return nil, fmt.Errorf("failed to get outing: %w", err)
 	}
 
+	// Only team members of the outing's establishment may check it in; the
+	// short code lookup already checked the establishment it searched
+	if cmd.OutingID != "" || cmd.QRCode != "" {
+		isMember, err := h.partnerService.IsTeamMember(ctx, cmd.StaffUserID, outing.Offer().EstablishmentID())
+		if err != nil {
+			return nil, fmt.Errorf("failed to check partner team membership: %w", err)
+		}
+		if !isMember {
+			return nil, domain.ErrNotPartnerTeamMember
+		}
+	}
+
+	geofence, err := resolveGeofence(ctx, h.offerService, h.defaultGeofence, outing.Offer().OfferID())
+	if err != nil {
+		return nil, err
+	}
+
 	// 2. Perform check-in
+	from := outing.Status()
 	if cmd.QRCode != "" {
-		if err := outing.CheckInWithQR(cmd.QRCode, cmd.StaffUserID, cmd.Latitude, cmd.Longitude); err != nil {
+		if err := outing.CheckInWithQR(h.keys, cmd.QRCode, cmd.StaffUserID, cmd.Latitude, cmd.Longitude, geofence); err != nil {
+			return nil, err
+		}
+	} else if cmd.ShortCode != "" {
+		if err := outing.CheckInWithShortCode(cmd.ShortCode, cmd.StaffUserID, cmd.Latitude, cmd.Longitude, geofence); err != nil {
 			return nil, err
 		}
 	} else {
-		if err := outing.CheckInManual(cmd.StaffUserID, cmd.Latitude, cmd.Longitude); err != nil {
+		if err := outing.CheckInManual(cmd.StaffUserID, cmd.Latitude, cmd.Longitude, geofence); err != nil {
+			return nil, err
+		}
+	}
+	if cmd.BillAmountCents != nil {
+		if err := outing.RecordBill(*cmd.BillAmountCents, cmd.StaffUserID); err != nil {
 			return nil, err
 		}
 	}
 
-	// 3. Update outing
-	if err := h.outingRepo.Update(ctx, outing); err != nil {
+	// 3. Update outing, unless its status changed since it was loaded (e.g. a
+	// concurrent check-in, cancellation or expiry)
+	if err := h.outingRepo.UpdateIfStatus(ctx, outing, from); err != nil {
+		if err == domain.ErrOutingStatusChanged {
+			return nil, err
+		}
 		return nil, fmt.Errorf("failed to update outing: %w", err)
 	}
 
@@ -175,121 +414,1842 @@ func (h *CheckInOutingHandler) Handle(ctx context.Context, cmd CheckInOutingComm
 		}
 	}()
 
-	return &CheckInOutingResult{Outing: outing}, nil
+	return outing, nil
+}
+
+// getByShortCode finds the outing whose short code the staff member typed.
+// Codes are only unique within an establishment, so the staff member must be
+// on its team, and every invalid code counts against the establishment's
+// attempt limit.
+func (h *CheckInOutingHandler) getByShortCode(ctx context.Context, cmd CheckInOutingCommand) (*domain.Outing, error) {
+	if cmd.EstablishmentID == "" {
+		return nil, fmt.Errorf("establishment ID is required with a short code")
+	}
+
+	isMember, err := h.partnerService.IsTeamMember(ctx, cmd.StaffUserID, cmd.EstablishmentID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check partner team membership: %w", err)
+	}
+	if !isMember {
+		return nil, domain.ErrNotPartnerTeamMember
+	}
+
+	// Count the attempt before the lookup, so concurrent guesses cannot all
+	// pass the limit, and refund it unless the code was a wrong guess
+	if err := h.shortCodeLimiter.Attempt(ctx, cmd.EstablishmentID); err != nil {
+		return nil, err
+	}
+
+	var outing *domain.Outing
+	code, err := domain.NormalizeShortCode(cmd.ShortCode)
+	if err == nil {
+		outing, err = h.outingRepo.GetByShortCode(ctx, cmd.EstablishmentID, code)
+	}
+	if err == domain.ErrInvalidShortCode || err == domain.ErrOutingNotFound {
+		return nil, domain.ErrInvalidShortCode
+	}
+	if refundErr := h.shortCodeLimiter.Refund(ctx, cmd.EstablishmentID); refundErr != nil {
+		fmt.Printf("warning: failed to refund short code attempt: %v\n", refundErr)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get outing: %w", err)
+	}
+
+	return outing, nil
 }
 
 // =============================================================================
-// CANCEL OUTING COMMAND
+// IDEMPOTENCY
 // =============================================================================
 
-type CancelOutingCommand struct {
-	OutingID    string
-	CancelledBy domain.CancellationActor
-	Reason      string
+// idempotencyGuard runs a command at most once per client idempotency key. A
+// retry with the same key and request gets the outing the first attempt
+// produced, as currently stored; a failed attempt frees the key for retries.
+type idempotencyGuard struct {
+	store      domain.IdempotencyStore
+	outingRepo domain.OutingRepository
 }
 
-type CancelOutingResult struct {
-	Outing *domain.Outing
+// run executes do unless the key already completed. Without a store or a key
+// do always runs. The scope keeps keys of different commands and callers apart;
+// request holds the command fields a reused key must match.
+func (g idempotencyGuard) run(ctx context.Context, scope, key string, request []string, do func() (*domain.Outing, error)) (*domain.Outing, error) {
+	if g.store == nil || key == "" {
+		return do()
+	}
+	if len(key) > domain.MaxIdempotencyKeyLength {
+		return nil, domain.ErrInvalidIdempotencyKey
+	}
+
+	storeKey := scope + ":" + key
+	sum := sha256.Sum256([]byte(strings.Join(request, "\x00")))
+	fingerprint := hex.EncodeToString(sum[:])
+
+	outcome, err := g.store.Claim(ctx, storeKey, fingerprint)
+	if err != nil {
+		return nil, err
+	}
+	if outcome != nil {
+		outing, err := g.outingRepo.GetByID(ctx, outcome.OutingID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get outing of replayed request: %w", err)
+		}
+		return outing, nil
+	}
+
+	outing, err := do()
+	if err != nil {
+		if releaseErr := g.store.Release(ctx, storeKey); releaseErr != nil {
+			fmt.Printf("warning: failed to release idempotency key: %v\n", releaseErr)
+		}
+		return nil, err
+	}
+
+	if err := g.store.Complete(ctx, storeKey, fingerprint, domain.IdempotentOutcome{OutingID: outing.ID()}); err != nil {
+		// The command succeeded; a retry runs it again once the claim expires
+		fmt.Printf("warning: failed to store idempotency outcome: %v\n", err)
+	}
+
+	return outing, nil
 }
 
-type CancelOutingHandler struct {
-	outingRepo    domain.OutingRepository
-	offerService  domain.OfferService
-	notifyService domain.NotificationService
+// =============================================================================
+// SYNC OFFLINE CHECK-INS COMMAND
+// =============================================================================
+
+// maxOfflineClockSkew bounds how far in the future a scanner's clock may be
+const maxOfflineClockSkew = 5 * time.Minute
+
+type OfflineScan struct {
+	QRCode    string
+	ScannedAt time.Time
+	Latitude  *float64
+	Longitude *float64
 }
 
-func NewCancelOutingHandler(
+type SyncOfflineCheckInsCommand struct {
+	StaffUserID string
+	Scans       []OfflineScan
+}
+
+type OfflineCheckInStatus string
+
+const (
+	OfflineCheckInStatusApplied  OfflineCheckInStatus = "applied"
+	OfflineCheckInStatusConflict OfflineCheckInStatus = "conflict"
+)
+
+type OfflineCheckInConflict string
+
+const (
+	OfflineConflictAlreadyUsed     OfflineCheckInConflict = "already_used"
+	OfflineConflictCancelled       OfflineCheckInConflict = "cancelled"
+	OfflineConflictExpired         OfflineCheckInConflict = "expired"
+	OfflineConflictOutsideGeofence OfflineCheckInConflict = "outside_geofence"
+	OfflineConflictPending         OfflineCheckInConflict = "awaiting_confirmation"
+	OfflineConflictNotTeamMember   OfflineCheckInConflict = "not_team_member"
+	OfflineConflictInvalid         OfflineCheckInConflict = "invalid"
+)
+
+type OfflineCheckInResult struct {
+	QRCode   string
+	Outing   *domain.Outing
+	Status   OfflineCheckInStatus
+	Conflict OfflineCheckInConflict
+}
+
+type SyncOfflineCheckInsResult struct {
+	Results []OfflineCheckInResult
+}
+
+type SyncOfflineCheckInsHandler struct {
+	outingRepo      domain.OutingRepository
+	offerService    domain.OfferService
+	partnerService  domain.PartnerService
+	notifyService   domain.NotificationService
+	keys            *domain.CheckInKeys
+	defaultGeofence *domain.Geofence
+}
+
+func NewSyncOfflineCheckInsHandler(
 	outingRepo domain.OutingRepository,
 	offerService domain.OfferService,
+	partnerService domain.PartnerService,
 	notifyService domain.NotificationService,
-) *CancelOutingHandler {
-	return &CancelOutingHandler{
-		outingRepo:    outingRepo,
-		offerService:  offerService,
-		notifyService: notifyService,
+	keys *domain.CheckInKeys,
+	defaultGeofence *domain.Geofence,
+) *SyncOfflineCheckInsHandler {
+	return &SyncOfflineCheckInsHandler{
+		outingRepo:      outingRepo,
+		offerService:    offerService,
+		partnerService:  partnerService,
+		notifyService:   notifyService,
+		keys:            keys,
+		defaultGeofence: defaultGeofence,
 	}
 }
 
-func (h *CancelOutingHandler) Handle(ctx context.Context, cmd CancelOutingCommand) (*CancelOutingResult, error) {
-	// 1. Get outing
-	outing, err := h.outingRepo.GetByID(ctx, cmd.OutingID)
+// Handle applies each scan independently; a conflicting scan never fails
+// the batch, it is reported so the scanner can surface it to staff. Only
+// scans of establishments the staff user belongs to are applied.
+func (h *SyncOfflineCheckInsHandler) Handle(ctx context.Context, cmd SyncOfflineCheckInsCommand) (*SyncOfflineCheckInsResult, error) {
+	results := make([]OfflineCheckInResult, 0, len(cmd.Scans))
+	memberships := make(map[string]bool)
+
+	for _, scan := range cmd.Scans {
+		result, err := h.apply(ctx, cmd.StaffUserID, scan, memberships)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, result)
+	}
+
+	return &SyncOfflineCheckInsResult{Results: results}, nil
+}
+
+func (h *SyncOfflineCheckInsHandler) apply(ctx context.Context, staffUserID string, scan OfflineScan, memberships map[string]bool) (OfflineCheckInResult, error) {
+	result := OfflineCheckInResult{QRCode: scan.QRCode, Status: OfflineCheckInStatusConflict}
+
+	if scan.ScannedAt.After(time.Now().Add(maxOfflineClockSkew)) {
+		result.Conflict = OfflineConflictInvalid
+		return result, nil
+	}
+
+	outing, err := h.outingRepo.GetByQRCode(ctx, scan.QRCode)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get outing: %w", err)
+		if err == domain.ErrOutingNotFound {
+			result.Conflict = OfflineConflictInvalid
+			return result, nil
+		}
+		return result, fmt.Errorf("failed to get outing: %w", err)
 	}
 
-	// 2. Cancel outing
-	if err := outing.Cancel(cmd.CancelledBy, cmd.Reason); err != nil {
-		return nil, err
+	establishmentID := outing.Offer().EstablishmentID()
+	isMember, checked := memberships[establishmentID]
+	if !checked {
+		isMember, err = h.partnerService.IsTeamMember(ctx, staffUserID, establishmentID)
+		if err != nil {
+			return result, fmt.Errorf("failed to check partner team membership: %w", err)
+		}
+		memberships[establishmentID] = isMember
 	}
+	if !isMember {
+		result.Conflict = OfflineConflictNotTeamMember
+		return result, nil
+	}
+	result.Outing = outing
 
-	// 3. Update outing
-	if err := h.outingRepo.Update(ctx, outing); err != nil {
-		return nil, fmt.Errorf("failed to update outing: %w", err)
+	// A scanner retrying a sync whose response it never received
+	if isSameOfflineCheckIn(outing, scan) {
+		result.Status = OfflineCheckInStatusApplied
+		return result, nil
 	}
 
-	// 4. Decrement offer booking count
-	if err := h.offerService.DecrementBookingCount(ctx, outing.Offer().OfferID()); err != nil {
-		fmt.Printf("warning: failed to decrement booking count: %v\n", err)
+	geofence, err := resolveGeofence(ctx, h.offerService, h.defaultGeofence, outing.Offer().OfferID())
+	if err != nil {
+		return result, err
+	}
+
+	from := outing.Status()
+	if err := outing.CheckInWithQROffline(h.keys, scan.QRCode, staffUserID, scan.ScannedAt, scan.Latitude, scan.Longitude, geofence); err != nil {
+		switch err {
+		case domain.ErrOutingAlreadyUsed:
+			result.Conflict = OfflineConflictAlreadyUsed
+		case domain.ErrOutingCancelled:
+			result.Conflict = OfflineConflictCancelled
+		case domain.ErrOutingExpired:
+			result.Conflict = OfflineConflictExpired
+		case domain.ErrOutsideGeofence:
+			result.Conflict = OfflineConflictOutsideGeofence
+		case domain.ErrAwaitingConfirmation:
+			result.Conflict = OfflineConflictPending
+		case domain.ErrCheckInKeysNotConfigured, domain.ErrQRKeysNotConfigured:
+			return result, err
+		default:
+			result.Conflict = OfflineConflictInvalid
+		}
+		return result, nil
 	}
 
-	// 5. Send notification (async)
+	if err := h.outingRepo.UpdateIfStatus(ctx, outing, from); err != nil {
+		if err == domain.ErrOutingStatusChanged {
+			// Changed since it was loaded: apply the scan again to the stored
+			// outing, which reports the matching conflict
+			return h.apply(ctx, staffUserID, scan, memberships)
+		}
+		return result, fmt.Errorf("failed to update outing: %w", err)
+	}
+	result.Status = OfflineCheckInStatusApplied
+
 	go func() {
-		if err := h.notifyService.SendCancellationNotification(context.Background(), outing); err != nil {
-			fmt.Printf("warning: failed to send cancellation notification: %v\n", err)
+		if err := h.notifyService.SendCheckInConfirmation(context.Background(), outing); err != nil {
+			fmt.Printf("warning: failed to send check-in confirmation: %v\n", err)
 		}
 	}()
 
-	return &CancelOutingResult{Outing: outing}, nil
+	return result, nil
+}
+
+// resolveGeofence returns the offer's own geofence or the default one
+func resolveGeofence(ctx context.Context, offerService domain.OfferService, defaultGeofence *domain.Geofence, offerID string) (*domain.Geofence, error) {
+	geofence, err := offerService.GetGeofence(ctx, offerID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get offer geofence: %w", err)
+	}
+	if geofence == nil {
+		return defaultGeofence, nil
+	}
+	return geofence, nil
+}
+
+func isSameOfflineCheckIn(outing *domain.Outing, scan OfflineScan) bool {
+	checkIn := outing.CheckIn()
+	return checkIn != nil &&
+		checkIn.Method() == domain.CheckInMethodOfflineScan &&
+		checkIn.CheckedInAt().UnixMilli() == scan.ScannedAt.UnixMilli()
 }
 
 // =============================================================================
-// EXPIRE OUTINGS COMMAND (CRON JOB)
+// RECORD OUTING BILL COMMAND
 // =============================================================================
 
-type ExpireOutingsCommand struct {
-	BatchSize int
+// RecordOutingBillCommand declares, or corrects, the bill of a checked-in
+// outing once the user has paid. Only team members of the establishment may.
+type RecordOutingBillCommand struct {
+	OutingID        string
+	StaffUserID     string
+	BillAmountCents int64
 }
 
-type ExpireOutingsResult struct {
-	ExpiredCount int
+type RecordOutingBillResult struct {
+	Outing *domain.Outing
 }
 
-type ExpireOutingsHandler struct {
-	outingRepo domain.OutingRepository
+type RecordOutingBillHandler struct {
+	outingRepo     domain.OutingRepository
+	partnerService domain.PartnerService
 }
 
-func NewExpireOutingsHandler(outingRepo domain.OutingRepository) *ExpireOutingsHandler {
-	return &ExpireOutingsHandler{
-		outingRepo: outingRepo,
+func NewRecordOutingBillHandler(outingRepo domain.OutingRepository, partnerService domain.PartnerService) *RecordOutingBillHandler {
+	return &RecordOutingBillHandler{
+		outingRepo:     outingRepo,
+		partnerService: partnerService,
 	}
 }
 
-func (h *ExpireOutingsHandler) Handle(ctx context.Context, cmd ExpireOutingsCommand) (*ExpireOutingsResult, error) {
-	batchSize := cmd.BatchSize
-	if batchSize <= 0 {
-		batchSize = 100
+func (h *RecordOutingBillHandler) Handle(ctx context.Context, cmd RecordOutingBillCommand) (*RecordOutingBillResult, error) {
+	// 1. Get outing, as a team member of its establishment
+	outing, err := getOutingForStaff(ctx, h.outingRepo, h.partnerService, cmd.OutingID, cmd.StaffUserID)
+	if err != nil {
+		return nil, err
 	}
 
-	// Get expired outings
-	outings, err := h.outingRepo.GetExpiredOutings(ctx, time.Now(), batchSize)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get expired outings: %w", err)
+	// 2. Record bill
+	if err := outing.RecordBill(cmd.BillAmountCents, cmd.StaffUserID); err != nil {
+		return nil, err
 	}
 
-	expiredCount := 0
-	for _, outing := range outings {
-		if err := outing.MarkAsExpired(); err != nil {
-			fmt.Printf("warning: failed to mark outing %s as expired: %v\n", outing.ID(), err)
-			continue
-		}
+	// 3. Update outing
+	if err := h.outingRepo.Update(ctx, outing); err != nil {
+		return nil, fmt.Errorf("failed to update outing: %w", err)
+	}
 
-		if err := h.outingRepo.Update(ctx, outing); err != nil {
-			fmt.Printf("warning: failed to update expired outing %s: %v\n", outing.ID(), err)
-			continue
+	return &RecordOutingBillResult{Outing: outing}, nil
+}
+
+// =============================================================================
+// DETECT CHECK-IN ANOMALIES COMMAND
+// =============================================================================
+
+// DetectCheckInAnomaliesCommand screens a recorded check-in against the
+// anomaly rules. Running it again for the same outing flags nothing new.
+type DetectCheckInAnomaliesCommand struct {
+	OutingID string
+}
+
+type DetectCheckInAnomaliesResult struct {
+	// Flagged holds the reasons the outing was flagged for by this run
+	Flagged []domain.AnomalyReason
+}
+
+type DetectCheckInAnomaliesHandler struct {
+	outingRepo     domain.OutingRepository
+	partnerService domain.PartnerService
+	rules          domain.AnomalyRules
+}
+
+func NewDetectCheckInAnomaliesHandler(
+	outingRepo domain.OutingRepository,
+	partnerService domain.PartnerService,
+	rules domain.AnomalyRules,
+) *DetectCheckInAnomaliesHandler {
+	return &DetectCheckInAnomaliesHandler{
+		outingRepo:     outingRepo,
+		partnerService: partnerService,
+		rules:          rules,
+	}
+}
+
+func (h *DetectCheckInAnomaliesHandler) Handle(ctx context.Context, cmd DetectCheckInAnomaliesCommand) (*DetectCheckInAnomaliesResult, error) {
+	// 1. Get outing
+	outing, err := h.outingRepo.GetByID(ctx, cmd.OutingID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get outing: %w", err)
+	}
+	if outing.CheckIn() == nil {
+		return &DetectCheckInAnomaliesResult{}, nil
+	}
+
+	result := &DetectCheckInAnomaliesResult{}
+	flag := func(reason domain.AnomalyReason, detail string) {
+		if outing.FlagAnomaly(reason, detail) {
+			result.Flagged = append(result.Flagged, reason)
+		}
+	}
+
+	// 2. Apply rules
+	if err := h.checkStaffBurst(ctx, outing, flag); err != nil {
+		return nil, err
+	}
+	if err := h.checkOpeningHours(ctx, outing, flag); err != nil {
+		return nil, err
+	}
+	counterparts, err := h.checkTravel(ctx, outing, flag)
+	if err != nil {
+		return nil, err
+	}
+
+	// 3. Save the anomalies alone, leaving concurrent changes to the outings
+	// in place; a detection racing this one may already have flagged them
+	saved, err := h.outingRepo.SaveAnomalies(ctx, outing, result.Flagged)
+	if err != nil && err != domain.ErrOutingStatusChanged {
+		return nil, fmt.Errorf("failed to save anomalies: %w", err)
+	}
+	result.Flagged = saved
+	for _, other := range counterparts {
+		_, err := h.outingRepo.SaveAnomalies(ctx, other, []domain.AnomalyReason{domain.AnomalyImpossibleTravel})
+		if err != nil && err != domain.ErrOutingStatusChanged {
+			fmt.Printf("warning: failed to flag outing %s: %v\n", other.ID(), err)
+		}
+	}
+
+	return result, nil
+}
+
+// checkStaffBurst flags check-ins made by a staff member faster than the rules
+// allow, counting the check-ins of the minute up to this one
+func (h *DetectCheckInAnomaliesHandler) checkStaffBurst(ctx context.Context, outing *domain.Outing, flag func(domain.AnomalyReason, string)) error {
+	if h.rules.MaxStaffCheckInsPerMinute <= 0 {
+		return nil
+	}
+
+	checkIn := outing.CheckIn()
+	at := checkIn.CheckedInAt()
+	count, err := h.outingRepo.CountStaffCheckIns(ctx, checkIn.CheckedInBy(), at.Add(-time.Minute), at)
+	if err != nil {
+		return err
+	}
+	if count > int64(h.rules.MaxStaffCheckInsPerMinute) {
+		flag(domain.AnomalyStaffCheckInBurst, fmt.Sprintf("%d check-ins by staff member %s within a minute", count, checkIn.CheckedInBy()))
+	}
+	return nil
+}
+
+// checkOpeningHours flags check-ins while the establishment is closed. Without
+// known opening hours there is nothing to check.
+func (h *DetectCheckInAnomaliesHandler) checkOpeningHours(ctx context.Context, outing *domain.Outing, flag func(domain.AnomalyReason, string)) error {
+	hours, err := h.partnerService.GetOpeningHours(ctx, outing.Offer().EstablishmentID())
+	if err != nil {
+		return fmt.Errorf("failed to get opening hours: %w", err)
+	}
+	if hours == nil || len(hours.Periods()) == 0 {
+		return nil
+	}
+
+	at := outing.CheckIn().CheckedInAt()
+	if !hours.IsOpenAt(at, h.rules.OpeningHoursGrace) {
+		flag(domain.AnomalyOutsideOpeningHours, fmt.Sprintf("checked in %s while the establishment was closed", at.In(hours.Location()).Format("Monday 15:04")))
+	}
+	return nil
+}
+
+// checkTravel flags the user's check-ins at distant establishments within the
+// travel window, on both sides: a shared account is used in both places. It
+// returns the other outings it flagged, which the caller must save.
+func (h *DetectCheckInAnomaliesHandler) checkTravel(ctx context.Context, outing *domain.Outing, flag func(domain.AnomalyReason, string)) ([]*domain.Outing, error) {
+	if h.rules.TravelWindow <= 0 {
+		return nil, nil
+	}
+
+	at := outing.CheckIn().CheckedInAt()
+	others, err := h.outingRepo.GetUserCheckIns(ctx, outing.UserID(), at.Add(-h.rules.TravelWindow), at.Add(h.rules.TravelWindow))
+	if err != nil {
+		return nil, err
+	}
+
+	var flagged []*domain.Outing
+	for _, other := range others {
+		if other.ID() == outing.ID() {
+			continue
+		}
+		distance, ok := h.rules.ImpossibleTravel(outing, other)
+		if !ok {
+			continue
+		}
+
+		apart := other.CheckIn().CheckedInAt().Sub(at).Abs().Round(time.Minute)
+		flag(domain.AnomalyImpossibleTravel, fmt.Sprintf("also checked in at %s, %.1f km away, %s apart", other.Offer().EstablishmentName(), distance/1000, apart))
+		if other.FlagAnomaly(domain.AnomalyImpossibleTravel, fmt.Sprintf("also checked in at %s, %.1f km away, %s apart", outing.Offer().EstablishmentName(), distance/1000, apart)) {
+			flagged = append(flagged, other)
+		}
+	}
+	return flagged, nil
+}
+
+// =============================================================================
+// CANCEL OUTING COMMAND
+// =============================================================================
+
+// CancelOutingCommand cancels an outing for its owner. A team member of the
+// establishment cancels it as the partner and an admin as the system; anyone
+// else is told the outing does not exist.
+type CancelOutingCommand struct {
+	OutingID string
+	UserID   string
+	IsAdmin  bool
+	Reason   string
+}
+
+type CancelOutingResult struct {
+	Outing *domain.Outing
+}
+
+type CancelOutingHandler struct {
+	outingRepo     domain.OutingRepository
+	offerService   domain.OfferService
+	partnerService domain.PartnerService
+	quotaService   domain.QuotaService
+	slotService    domain.SlotCapacityService
+	notifyService  domain.NotificationService
+	promoter       *WaitlistPromoter
+	strikes        *StrikeRecorder
+	defaultPolicy  *domain.CancellationPolicy
+}
+
+func NewCancelOutingHandler(
+	outingRepo domain.OutingRepository,
+	offerService domain.OfferService,
+	partnerService domain.PartnerService,
+	quotaService domain.QuotaService,
+	slotService domain.SlotCapacityService,
+	notifyService domain.NotificationService,
+	promoter *WaitlistPromoter,
+	strikes *StrikeRecorder,
+	defaultPolicy *domain.CancellationPolicy,
+) *CancelOutingHandler {
+	return &CancelOutingHandler{
+		outingRepo:     outingRepo,
+		offerService:   offerService,
+		partnerService: partnerService,
+		quotaService:   quotaService,
+		slotService:    slotService,
+		notifyService:  notifyService,
+		promoter:       promoter,
+		strikes:        strikes,
+		defaultPolicy:  defaultPolicy,
+	}
+}
+
+func (h *CancelOutingHandler) Handle(ctx context.Context, cmd CancelOutingCommand) (*CancelOutingResult, error) {
+	// 1. Get outing
+	outing, err := h.outingRepo.GetByID(ctx, cmd.OutingID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get outing: %w", err)
+	}
+	actor, err := h.cancellationActor(ctx, outing, cmd)
+	if err != nil {
+		return nil, err
+	}
+
+	// 2. Cancel outing under the offer's cancellation policy
+	policy, err := h.offerService.GetCancellationPolicy(ctx, outing.Offer().OfferID())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get cancellation policy: %w", err)
+	}
+	if policy == nil {
+		policy = h.defaultPolicy
+	}
+
+	from := outing.Status()
+	if err := outing.Cancel(actor, cmd.Reason, policy); err != nil {
+		return nil, err
+	}
+
+	// 3. Update outing, unless its status changed since it was loaded (e.g. a
+	// concurrent cancellation or the expiry job); only the winning write may
+	// release what the outing holds
+	if err := h.outingRepo.UpdateIfStatus(ctx, outing, from); err != nil {
+		if err == domain.ErrOutingStatusChanged {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to update outing: %w", err)
+	}
+
+	// 4. Release quota and slot seat, decrement offer booking count
+	if err := h.quotaService.Release(ctx, outing); err != nil {
+		fmt.Printf("warning: failed to release quota: %v\n", err)
+	}
+	if err := h.slotService.Release(ctx, outing); err != nil {
+		fmt.Printf("warning: failed to release slot: %v\n", err)
+	}
+	if err := h.offerService.DecrementBookingCount(ctx, outing.Offer().OfferID()); err != nil {
+		fmt.Printf("warning: failed to decrement booking count: %v\n", err)
+	}
+
+	// 5. A late cancellation counts against the user
+	if outing.Cancellation().IsLate() {
+		h.strikes.Record(ctx, outing, domain.StrikeKindLateCancellation)
+	}
+
+	// 6. Offer the freed place to the waitlist
+	promoteWaitlist(ctx, h.promoter, outing)
+
+	// 7. Send notification (async)
+	go func() {
+		if err := h.notifyService.SendCancellationNotification(context.Background(), outing); err != nil {
+			fmt.Printf("warning: failed to send cancellation notification: %v\n", err)
+		}
+	}()
+
+	return &CancelOutingResult{Outing: outing}, nil
+}
+
+// cancellationActor tells on whose behalf the caller cancels the outing
+func (h *CancelOutingHandler) cancellationActor(ctx context.Context, outing *domain.Outing, cmd CancelOutingCommand) (domain.CancellationActor, error) {
+	if outing.UserID() == cmd.UserID {
+		return domain.CancellationActorUser, nil
+	}
+	if cmd.IsAdmin {
+		return domain.CancellationActorSystem, nil
+	}
+
+	isMember, err := h.partnerService.IsTeamMember(ctx, cmd.UserID, outing.Offer().EstablishmentID())
+	if err != nil {
+		return "", fmt.Errorf("failed to check partner team membership: %w", err)
+	}
+	if !isMember {
+		return "", domain.ErrOutingNotFound
+	}
+	return domain.CancellationActorPartner, nil
+}
+
+// =============================================================================
+// CONFIRM / REJECT OUTING COMMANDS
+// =============================================================================
+
+// Partners of offers requiring confirmation approve each booking. Only team
+// members of the partner owning the establishment may decide.
+
+type ConfirmOutingCommand struct {
+	OutingID    string
+	StaffUserID string
+}
+
+type ConfirmOutingResult struct {
+	Outing *domain.Outing
+}
+
+type ConfirmOutingHandler struct {
+	outingRepo     domain.OutingRepository
+	partnerService domain.PartnerService
+	notifyService  domain.NotificationService
+}
+
+func NewConfirmOutingHandler(
+	outingRepo domain.OutingRepository,
+	partnerService domain.PartnerService,
+	notifyService domain.NotificationService,
+) *ConfirmOutingHandler {
+	return &ConfirmOutingHandler{
+		outingRepo:     outingRepo,
+		partnerService: partnerService,
+		notifyService:  notifyService,
+	}
+}
+
+func (h *ConfirmOutingHandler) Handle(ctx context.Context, cmd ConfirmOutingCommand) (*ConfirmOutingResult, error) {
+	// 1. Get outing, as a team member of its establishment
+	outing, err := getOutingForStaff(ctx, h.outingRepo, h.partnerService, cmd.OutingID, cmd.StaffUserID)
+	if err != nil {
+		return nil, err
+	}
+
+	// 2. Confirm
+	if err := outing.Confirm(cmd.StaffUserID); err != nil {
+		return nil, err
+	}
+
+	// 3. Update outing, unless it left pending meanwhile (e.g. the
+	// unconfirmed outings job or another staff member decided first)
+	if err := h.outingRepo.UpdateIfStatus(ctx, outing, domain.OutingStatusPending); err != nil {
+		if err == domain.ErrOutingStatusChanged {
+			return nil, domain.ErrOutingNotPending
+		}
+		return nil, fmt.Errorf("failed to update outing: %w", err)
+	}
+
+	// 4. Tell the user (async)
+	notifyBookingDecision(h.notifyService, outing)
+
+	return &ConfirmOutingResult{Outing: outing}, nil
+}
+
+type RejectOutingCommand struct {
+	OutingID    string
+	StaffUserID string
+	Reason      string
+}
+
+type RejectOutingResult struct {
+	Outing *domain.Outing
+}
+
+type RejectOutingHandler struct {
+	outingRepo     domain.OutingRepository
+	offerService   domain.OfferService
+	partnerService domain.PartnerService
+	quotaService   domain.QuotaService
+	slotService    domain.SlotCapacityService
+	notifyService  domain.NotificationService
+	promoter       *WaitlistPromoter
+}
+
+func NewRejectOutingHandler(
+	outingRepo domain.OutingRepository,
+	offerService domain.OfferService,
+	partnerService domain.PartnerService,
+	quotaService domain.QuotaService,
+	slotService domain.SlotCapacityService,
+	notifyService domain.NotificationService,
+	promoter *WaitlistPromoter,
+) *RejectOutingHandler {
+	return &RejectOutingHandler{
+		outingRepo:     outingRepo,
+		offerService:   offerService,
+		partnerService: partnerService,
+		quotaService:   quotaService,
+		slotService:    slotService,
+		notifyService:  notifyService,
+		promoter:       promoter,
+	}
+}
+
+func (h *RejectOutingHandler) Handle(ctx context.Context, cmd RejectOutingCommand) (*RejectOutingResult, error) {
+	// 1. Get outing, as a team member of its establishment
+	outing, err := getOutingForStaff(ctx, h.outingRepo, h.partnerService, cmd.OutingID, cmd.StaffUserID)
+	if err != nil {
+		return nil, err
+	}
+
+	// 2. Reject
+	if err := outing.Reject(cmd.StaffUserID, cmd.Reason); err != nil {
+		return nil, err
+	}
+
+	// 3. Update outing, unless it left pending meanwhile (e.g. the
+	// unconfirmed outings job or another staff member decided first)
+	if err := h.outingRepo.UpdateIfStatus(ctx, outing, domain.OutingStatusPending); err != nil {
+		if err == domain.ErrOutingStatusChanged {
+			return nil, domain.ErrOutingNotPending
+		}
+		return nil, fmt.Errorf("failed to update outing: %w", err)
+	}
+
+	// 4. Release quota and slot seat, decrement offer booking count
+	if err := h.quotaService.Release(ctx, outing); err != nil {
+		fmt.Printf("warning: failed to release quota: %v\n", err)
+	}
+	if err := h.slotService.Release(ctx, outing); err != nil {
+		fmt.Printf("warning: failed to release slot: %v\n", err)
+	}
+	if err := h.offerService.DecrementBookingCount(ctx, outing.Offer().OfferID()); err != nil {
+		fmt.Printf("warning: failed to decrement booking count: %v\n", err)
+	}
+
+	// 5. Offer the freed place to the waitlist
+	promoteWaitlist(ctx, h.promoter, outing)
+
+	// 6. Tell the user (async)
+	notifyBookingDecision(h.notifyService, outing)
+
+	return &RejectOutingResult{Outing: outing}, nil
+}
+
+// getOutingForStaff loads an outing a partner staff member acts on
+func getOutingForStaff(ctx context.Context, outingRepo domain.OutingRepository, partnerService domain.PartnerService, outingID, staffUserID string) (*domain.Outing, error) {
+	outing, err := outingRepo.GetByID(ctx, outingID)
+	if err != nil {
+		return nil, err
+	}
+
+	isMember, err := partnerService.IsTeamMember(ctx, staffUserID, outing.Offer().EstablishmentID())
+	if err != nil {
+		return nil, fmt.Errorf("failed to check partner team membership: %w", err)
+	}
+	if !isMember {
+		return nil, domain.ErrNotPartnerTeamMember
+	}
+
+	return outing, nil
+}
+
+func notifyBookingDecision(notifyService domain.NotificationService, outing *domain.Outing) {
+	go func() {
+		if err := notifyService.SendBookingDecision(context.Background(), outing); err != nil {
+			fmt.Printf("warning: failed to send booking decision: %v\n", err)
+		}
+	}()
+}
+
+// =============================================================================
+// TRANSFER OUTING COMMANDS
+// =============================================================================
+
+// A user who cannot go can hand the outing over: the owner offers it to
+// another user, who becomes the owner once they accept. The recipient must
+// be allowed to book the offer themselves.
+
+type TransferOutingCommand struct {
+	OutingID string
+	UserID   string
+
+	// Recipient, by ID or else by email
+	RecipientUserID string
+	RecipientEmail  string
+}
+
+type TransferOutingResult struct {
+	Outing *domain.Outing
+}
+
+type TransferOutingHandler struct {
+	outingRepo    domain.OutingRepository
+	userService   domain.UserService
+	notifyService domain.NotificationService
+}
+
+func NewTransferOutingHandler(
+	outingRepo domain.OutingRepository,
+	userService domain.UserService,
+	notifyService domain.NotificationService,
+) *TransferOutingHandler {
+	return &TransferOutingHandler{
+		outingRepo:    outingRepo,
+		userService:   userService,
+		notifyService: notifyService,
+	}
+}
+
+func (h *TransferOutingHandler) Handle(ctx context.Context, cmd TransferOutingCommand) (*TransferOutingResult, error) {
+	// 1. Resolve the recipient
+	recipientID := cmd.RecipientUserID
+	if recipientID == "" {
+		email := strings.TrimSpace(cmd.RecipientEmail)
+		if email == "" {
+			return nil, fmt.Errorf("recipient user ID or email is required")
+		}
+
+		var err error
+		recipientID, err = h.userService.GetUserIDByEmail(ctx, email)
+		if err == domain.ErrUserNotFound {
+			return nil, err
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to find recipient: %w", err)
+		}
+	}
+
+	// 2. Offer the outing
+	outing, err := h.outingRepo.GetByID(ctx, cmd.OutingID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := outing.InitiateTransfer(cmd.UserID, recipientID); err != nil {
+		return nil, err
+	}
+
+	if err := h.outingRepo.Update(ctx, outing); err != nil {
+		return nil, fmt.Errorf("failed to update outing: %w", err)
+	}
+
+	// 3. Let the recipient know (async)
+	go func() {
+		if err := h.notifyService.SendTransferOffer(context.Background(), outing); err != nil {
+			fmt.Printf("warning: failed to send transfer offer: %v\n", err)
+		}
+	}()
+
+	return &TransferOutingResult{Outing: outing}, nil
+}
+
+// AcceptOutingTransferCommand makes the recipient of a pending transfer the
+// outing's owner
+type AcceptOutingTransferCommand struct {
+	OutingID string
+	UserID   string
+}
+
+type AcceptOutingTransferResult struct {
+	Outing *domain.Outing
+}
+
+type AcceptOutingTransferHandler struct {
+	outingRepo    domain.OutingRepository
+	offerService  domain.OfferService
+	userService   domain.UserService
+	quotaService  domain.QuotaService
+	notifyService domain.NotificationService
+}
+
+func NewAcceptOutingTransferHandler(
+	outingRepo domain.OutingRepository,
+	offerService domain.OfferService,
+	userService domain.UserService,
+	quotaService domain.QuotaService,
+	notifyService domain.NotificationService,
+) *AcceptOutingTransferHandler {
+	return &AcceptOutingTransferHandler{
+		outingRepo:    outingRepo,
+		offerService:  offerService,
+		userService:   userService,
+		quotaService:  quotaService,
+		notifyService: notifyService,
+	}
+}
+
+func (h *AcceptOutingTransferHandler) Handle(ctx context.Context, cmd AcceptOutingTransferCommand) (*AcceptOutingTransferResult, error) {
+	// 1. Get outing and check the transfer is for this user
+	outing, err := h.outingRepo.GetByID(ctx, cmd.OutingID)
+	if err != nil {
+		return nil, err
+	}
+	if err := outing.CanAcceptTransfer(cmd.UserID); err != nil {
+		return nil, err
+	}
+
+	// 2. Check the recipient could book the offer themselves
+	if err := h.userService.CanBook(ctx, cmd.UserID); err != nil {
+		return nil, fmt.Errorf("%w: %w", domain.ErrRecipientNotEligible, err)
+	}
+
+	offerID := outing.Offer().OfferID()
+	existing, err := h.outingRepo.GetActiveByUserAndOffer(ctx, cmd.UserID, offerID)
+	if err != nil && err != domain.ErrOutingNotFound {
+		return nil, fmt.Errorf("failed to check existing booking: %w", err)
+	}
+	if existing != nil {
+		return nil, domain.ErrOutingAlreadyExists
+	}
+
+	userSnapshot, err := h.userService.GetUserSnapshot(ctx, cmd.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user snapshot: %w", err)
+	}
+
+	// 3. Move the per-user quota to the recipient
+	quota, err := h.offerService.GetQuota(ctx, offerID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get offer quota: %w", err)
+	}
+
+	previousUserID := outing.UserID()
+	if err := h.quotaService.Transfer(ctx, offerID, previousUserID, cmd.UserID, outing.BookedAt(), *quota); err != nil {
+		return nil, err
+	}
+
+	// 4. Hand the outing over, giving the quota back if it fails
+	err = outing.AcceptTransfer(*userSnapshot)
+	if err == nil {
+		err = withFreshShortCode(outing, func() error { return h.outingRepo.Update(ctx, outing) })
+	}
+	if err != nil {
+		if revertErr := h.quotaService.Transfer(ctx, offerID, cmd.UserID, previousUserID, outing.BookedAt(), *quota); revertErr != nil {
+			fmt.Printf("warning: failed to give quota back after failed transfer: %v\n", revertErr)
+		}
+		return nil, fmt.Errorf("failed to transfer outing: %w", err)
+	}
+
+	// 5. Let the previous owner know (async)
+	go func() {
+		if err := h.notifyService.SendTransferAccepted(context.Background(), outing, previousUserID); err != nil {
+			fmt.Printf("warning: failed to send transfer acceptance: %v\n", err)
+		}
+	}()
+
+	return &AcceptOutingTransferResult{Outing: outing}, nil
+}
+
+// CancelOutingTransferCommand withdraws a pending transfer (owner) or
+// declines it (recipient)
+type CancelOutingTransferCommand struct {
+	OutingID string
+	UserID   string
+}
+
+type CancelOutingTransferResult struct {
+	Outing *domain.Outing
+}
+
+type CancelOutingTransferHandler struct {
+	outingRepo domain.OutingRepository
+}
+
+func NewCancelOutingTransferHandler(outingRepo domain.OutingRepository) *CancelOutingTransferHandler {
+	return &CancelOutingTransferHandler{outingRepo: outingRepo}
+}
+
+func (h *CancelOutingTransferHandler) Handle(ctx context.Context, cmd CancelOutingTransferCommand) (*CancelOutingTransferResult, error) {
+	outing, err := h.outingRepo.GetByID(ctx, cmd.OutingID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := outing.CancelTransfer(cmd.UserID); err != nil {
+		return nil, err
+	}
+
+	if err := h.outingRepo.Update(ctx, outing); err != nil {
+		return nil, fmt.Errorf("failed to update outing: %w", err)
+	}
+
+	return &CancelOutingTransferResult{Outing: outing}, nil
+}
+
+// =============================================================================
+// REJECT UNCONFIRMED OUTINGS COMMAND (CRON JOB)
+// =============================================================================
+
+// RejectUnconfirmedOutingsCommand rejects pending outings the partner did not
+// confirm before their deadline
+type RejectUnconfirmedOutingsCommand struct {
+	BatchSize int
+}
+
+type RejectUnconfirmedOutingsResult struct {
+	RejectedCount int
+	FailedCount   int
+}
+
+type RejectUnconfirmedOutingsHandler struct {
+	outingRepo    domain.OutingRepository
+	offerService  domain.OfferService
+	quotaService  domain.QuotaService
+	slotService   domain.SlotCapacityService
+	notifyService domain.NotificationService
+	promoter      *WaitlistPromoter
+}
+
+func NewRejectUnconfirmedOutingsHandler(
+	outingRepo domain.OutingRepository,
+	offerService domain.OfferService,
+	quotaService domain.QuotaService,
+	slotService domain.SlotCapacityService,
+	notifyService domain.NotificationService,
+	promoter *WaitlistPromoter,
+) *RejectUnconfirmedOutingsHandler {
+	return &RejectUnconfirmedOutingsHandler{
+		outingRepo:    outingRepo,
+		offerService:  offerService,
+		quotaService:  quotaService,
+		slotService:   slotService,
+		notifyService: notifyService,
+		promoter:      promoter,
+	}
+}
+
+func (h *RejectUnconfirmedOutingsHandler) Handle(ctx context.Context, cmd RejectUnconfirmedOutingsCommand) (*RejectUnconfirmedOutingsResult, error) {
+	batchSize := cmd.BatchSize
+	if batchSize <= 0 {
+		batchSize = 100
+	}
+
+	outings, err := h.outingRepo.GetUnconfirmedOutings(ctx, time.Now(), batchSize)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get unconfirmed outings: %w", err)
+	}
+
+	rejectedCount, failedCount := 0, 0
+	for _, outing := range outings {
+		if err := outing.RejectUnconfirmed(); err != nil {
+			fmt.Printf("warning: failed to reject unconfirmed outing %s: %v\n", outing.ID(), err)
+			failedCount++
+			continue
+		}
+
+		if err := h.outingRepo.UpdateIfStatus(ctx, outing, domain.OutingStatusPending); err != nil {
+			if err == domain.ErrOutingStatusChanged {
+				// Confirmed, rejected or cancelled since it was loaded
+				continue
+			}
+			fmt.Printf("warning: failed to update unconfirmed outing %s: %v\n", outing.ID(), err)
+			failedCount++
+			continue
+		}
+
+		if err := h.quotaService.Release(ctx, outing); err != nil {
+			fmt.Printf("warning: failed to release quota for outing %s: %v\n", outing.ID(), err)
+		}
+		if err := h.slotService.Release(ctx, outing); err != nil {
+			fmt.Printf("warning: failed to release slot for outing %s: %v\n", outing.ID(), err)
+		}
+		if err := h.offerService.DecrementBookingCount(ctx, outing.Offer().OfferID()); err != nil {
+			fmt.Printf("warning: failed to decrement booking count for outing %s: %v\n", outing.ID(), err)
+		}
+		promoteWaitlist(ctx, h.promoter, outing)
+		notifyBookingDecision(h.notifyService, outing)
+
+		rejectedCount++
+	}
+
+	return &RejectUnconfirmedOutingsResult{RejectedCount: rejectedCount, FailedCount: failedCount}, nil
+}
+
+// =============================================================================
+// EXPIRE OUTINGS COMMAND (CRON JOB)
+// =============================================================================
+
+type ExpireOutingsCommand struct {
+	BatchSize int
+}
+
+type ExpireOutingsResult struct {
+	ExpiredCount int
+	FailedCount  int
+}
+
+type ExpireOutingsHandler struct {
+	outingRepo   domain.OutingRepository
+	quotaService domain.QuotaService
+	slotService  domain.SlotCapacityService
+	promoter     *WaitlistPromoter
+}
+
+func NewExpireOutingsHandler(
+	outingRepo domain.OutingRepository,
+	quotaService domain.QuotaService,
+	slotService domain.SlotCapacityService,
+	promoter *WaitlistPromoter,
+) *ExpireOutingsHandler {
+	return &ExpireOutingsHandler{
+		outingRepo:   outingRepo,
+		quotaService: quotaService,
+		slotService:  slotService,
+		promoter:     promoter,
+	}
+}
+
+func (h *ExpireOutingsHandler) Handle(ctx context.Context, cmd ExpireOutingsCommand) (*ExpireOutingsResult, error) {
+	batchSize := cmd.BatchSize
+	if batchSize <= 0 {
+		batchSize = 100
+	}
+
+	// Get expired outings
+	outings, err := h.outingRepo.GetExpiredOutings(ctx, time.Now(), batchSize)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get expired outings: %w", err)
+	}
+
+	expiredCount, failedCount := 0, 0
+	for _, outing := range outings {
+		from := outing.Status()
+		if err := outing.MarkAsExpired(); err != nil {
+			fmt.Printf("warning: failed to mark outing %s as expired: %v\n", outing.ID(), err)
+			failedCount++
+			continue
+		}
+
+		if err := h.outingRepo.UpdateIfStatus(ctx, outing, from); err != nil {
+			if err == domain.ErrOutingStatusChanged {
+				// Checked in or cancelled since it was loaded
+				continue
+			}
+			fmt.Printf("warning: failed to update expired outing %s: %v\n", outing.ID(), err)
+			failedCount++
+			continue
+		}
+
+		if err := h.quotaService.Release(ctx, outing); err != nil {
+			fmt.Printf("warning: failed to release quota for outing %s: %v\n", outing.ID(), err)
+		}
+		if err := h.slotService.Release(ctx, outing); err != nil {
+			fmt.Printf("warning: failed to release slot for outing %s: %v\n", outing.ID(), err)
+		}
+		promoteWaitlist(ctx, h.promoter, outing)
+
+		expiredCount++
+	}
+
+	return &ExpireOutingsResult{ExpiredCount: expiredCount, FailedCount: failedCount}, nil
+}
+
+// =============================================================================
+// MARK NO-SHOWS COMMAND (CRON JOB)
+// =============================================================================
+
+// MarkNoShowsCommand turns expired outings into no-shows once the grace period
+// (left for late partner-side reconciliation) has elapsed.
+type MarkNoShowsCommand struct {
+	GracePeriod time.Duration
+	BatchSize   int
+}
+
+type MarkNoShowsResult struct {
+	NoShowCount int
+	FailedCount int
+}
+
+type MarkNoShowsHandler struct {
+	outingRepo domain.OutingRepository
+	strikes    *StrikeRecorder
+}
+
+func NewMarkNoShowsHandler(outingRepo domain.OutingRepository, strikes *StrikeRecorder) *MarkNoShowsHandler {
+	return &MarkNoShowsHandler{
+		outingRepo: outingRepo,
+		strikes:    strikes,
+	}
+}
+
+func (h *MarkNoShowsHandler) Handle(ctx context.Context, cmd MarkNoShowsCommand) (*MarkNoShowsResult, error) {
+	batchSize := cmd.BatchSize
+	if batchSize <= 0 {
+		batchSize = 100
+	}
+
+	outings, err := h.outingRepo.GetNoShowCandidates(ctx, time.Now().Add(-cmd.GracePeriod), batchSize)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get no-show candidates: %w", err)
+	}
+
+	noShowCount, failedCount := 0, 0
+	for _, outing := range outings {
+		from := outing.Status()
+		if err := outing.MarkAsNoShow(); err != nil {
+			fmt.Printf("warning: failed to mark outing %s as no-show: %v\n", outing.ID(), err)
+			failedCount++
+			continue
+		}
+
+		if err := h.outingRepo.UpdateIfStatus(ctx, outing, from); err != nil {
+			if err == domain.ErrOutingStatusChanged {
+				// Reconciled by a late check-in or already marked by another run
+				continue
+			}
+			fmt.Printf("warning: failed to update no-show outing %s: %v\n", outing.ID(), err)
+			failedCount++
+			continue
+		}
+
+		h.strikes.Record(ctx, outing, domain.StrikeKindNoShow)
+
+		noShowCount++
+	}
+
+	return &MarkNoShowsResult{NoShowCount: noShowCount, FailedCount: failedCount}, nil
+}
+
+// =============================================================================
+// USER STANDING
+// =============================================================================
+
+// StrikeRecorder records late cancellations and no-shows against users and
+// bans them according to the strike policy
+type StrikeRecorder struct {
+	standingRepo domain.UserStandingRepository
+	policy       domain.StrikePolicy
+}
+
+func NewStrikeRecorder(standingRepo domain.UserStandingRepository, policy domain.StrikePolicy) *StrikeRecorder {
+	return &StrikeRecorder{
+		standingRepo: standingRepo,
+		policy:       policy,
+	}
+}
+
+// maxStrikeAttempts bounds how often a strike is retried against a standing
+// changed concurrently, e.g. by another strike for the same user
+const maxStrikeAttempts = 3
+
+// Record adds a strike for the outing; a failure must not fail the operation
+// that caused it
+func (r *StrikeRecorder) Record(ctx context.Context, outing *domain.Outing, kind domain.StrikeKind) {
+	for attempt := 1; ; attempt++ {
+		standing, err := r.standingRepo.Get(ctx, outing.UserID())
+		if err != nil {
+			fmt.Printf("warning: failed to get standing of user %s: %v\n", outing.UserID(), err)
+			return
+		}
+
+		standing.AddStrike(kind, outing.ID(), time.Now(), r.policy)
+
+		err = r.standingRepo.Save(ctx, standing)
+		if err == domain.ErrUserStandingChanged && attempt < maxStrikeAttempts {
+			continue
+		}
+		if err != nil {
+			fmt.Printf("warning: failed to record %s strike for outing %s: %v\n", kind, outing.ID(), err)
+		}
+		return
+	}
+}
+
+// StandingUserService adds the booking ban check to a UserService, so every
+// caller of CanBook honours bans
+type StandingUserService struct {
+	domain.UserService
+	standingRepo domain.UserStandingRepository
+}
+
+func NewStandingUserService(userService domain.UserService, standingRepo domain.UserStandingRepository) *StandingUserService {
+	return &StandingUserService{
+		UserService:  userService,
+		standingRepo: standingRepo,
+	}
+}
+
+func (s *StandingUserService) CanBook(ctx context.Context, userID string) error {
+	standing, err := s.standingRepo.Get(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("failed to get user standing: %w", err)
+	}
+	if err := standing.CanBook(time.Now()); err != nil {
+		return err
+	}
+
+	return s.UserService.CanBook(ctx, userID)
+}
+
+// =============================================================================
+// WAITLIST
+// =============================================================================
+
+// maxPromoteAttempts bounds how many unservable entries one promotion skips
+const maxPromoteAttempts = 10
+
+// WaitlistPromoter hands freed capacity to the next waiting user as a hold.
+// The capacity is reserved for the hold so nobody else can take it before the
+// user accepts or the hold expires.
+type WaitlistPromoter struct {
+	waitlistRepo domain.WaitlistRepository
+	outingRepo   domain.OutingRepository
+	offerService domain.OfferService
+	quotaService domain.QuotaService
+	slotService  domain.SlotCapacityService
+	holdTTL      time.Duration
+}
+
+func NewWaitlistPromoter(
+	waitlistRepo domain.WaitlistRepository,
+	outingRepo domain.OutingRepository,
+	offerService domain.OfferService,
+	quotaService domain.QuotaService,
+	slotService domain.SlotCapacityService,
+	holdTTL time.Duration,
+) *WaitlistPromoter {
+	return &WaitlistPromoter{
+		waitlistRepo: waitlistRepo,
+		outingRepo:   outingRepo,
+		offerService: offerService,
+		quotaService: quotaService,
+		slotService:  slotService,
+		holdTTL:      holdTTL,
+	}
+}
+
+// Promote offers a hold to the next waiting user of the offer, or of the slot
+// when one is given. It returns the entry given the hold, or nil when nobody
+// is waiting or the capacity was taken in the meantime.
+func (p *WaitlistPromoter) Promote(ctx context.Context, offerID string, slot *domain.SlotSnapshot) (*domain.WaitlistEntry, error) {
+	var slotStartsAt *time.Time
+	if slot != nil {
+		startsAt := slot.StartsAt()
+		slotStartsAt = &startsAt
+	}
+
+	for attempt := 0; attempt < maxPromoteAttempts; attempt++ {
+		entry, err := p.waitlistRepo.GetNextWaiting(ctx, offerID, slotStartsAt)
+		if err == domain.ErrWaitlistEntryNotFound {
+			return nil, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to get next waitlist entry: %w", err)
+		}
+
+		held, err := p.hold(ctx, entry)
+		if err == domain.ErrWaitlistEntryConflict {
+			// Another promotion or the user got to the entry first
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		if held {
+			return entry, nil
+		}
+		if entry.Status().IsActive() {
+			// Capacity is gone, the entry keeps its place
+			return nil, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// hold reserves capacity for the entry and offers it the hold. Entries that
+// cannot be served are removed from the line.
+func (p *WaitlistPromoter) hold(ctx context.Context, entry *domain.WaitlistEntry) (bool, error) {
+	now := time.Now()
+
+	if entry.Slot() != nil && !now.Before(entry.Slot().EndsAt()) {
+		return false, p.remove(ctx, entry, "slot_ended")
+	}
+
+	existing, err := p.outingRepo.GetActiveByUserAndOffer(ctx, entry.UserID(), entry.OfferID())
+	if err != nil && err != domain.ErrOutingNotFound {
+		return false, fmt.Errorf("failed to check existing booking: %w", err)
+	}
+	if existing != nil {
+		return false, p.remove(ctx, entry, "already_booked")
+	}
+
+	quota, err := p.offerService.GetQuota(ctx, entry.OfferID())
+	if err != nil {
+		return false, fmt.Errorf("failed to get offer quota: %w", err)
+	}
+
+	switch err := p.quotaService.Reserve(ctx, entry.OfferID(), entry.UserID(), *quota, now); err {
+	case nil:
+	case domain.ErrUserQuotaExceeded:
+		return false, p.remove(ctx, entry, "user_quota_exceeded")
+	case domain.ErrOfferQuotaExceeded, domain.ErrDailyQuotaExceeded:
+		return false, nil
+	default:
+		return false, err
+	}
+
+	if entry.Slot() != nil {
+		if err := p.slotService.Reserve(ctx, entry.OfferID(), *entry.Slot()); err != nil {
+			p.releaseQuota(ctx, entry.OfferID(), entry.UserID(), now)
+			if err == domain.ErrSlotFull {
+				return false, nil
+			}
+			return false, err
+		}
+	}
+
+	if err := entry.OfferHold(p.holdTTL); err != nil {
+		p.ReleaseHold(ctx, entry)
+		return false, err
+	}
+	if err := p.waitlistRepo.Update(ctx, entry, domain.WaitlistStatusWaiting); err != nil {
+		p.ReleaseHold(ctx, entry)
+		if err == domain.ErrWaitlistEntryConflict {
+			return false, err
+		}
+		return false, fmt.Errorf("failed to save waitlist hold: %w", err)
+	}
+
+	return true, nil
+}
+
+// ReleaseHold gives back the capacity reserved for an entry's hold
+func (p *WaitlistPromoter) ReleaseHold(ctx context.Context, entry *domain.WaitlistEntry) {
+	if entry.HeldAt() == nil {
+		return
+	}
+
+	p.releaseQuota(ctx, entry.OfferID(), entry.UserID(), *entry.HeldAt())
+	if entry.Slot() != nil {
+		if err := p.slotService.ReleaseSeat(ctx, entry.OfferID(), *entry.Slot()); err != nil {
+			fmt.Printf("warning: failed to release slot for waitlist entry %s: %v\n", entry.ID(), err)
+		}
+	}
+}
+
+func (p *WaitlistPromoter) releaseQuota(ctx context.Context, offerID, userID string, at time.Time) {
+	if err := p.quotaService.ReleaseReservation(ctx, offerID, userID, at); err != nil {
+		fmt.Printf("warning: failed to release quota: %v\n", err)
+	}
+}
+
+// remove takes the entry out of the line. An entry changed concurrently is
+// left to whoever changed it.
+func (p *WaitlistPromoter) remove(ctx context.Context, entry *domain.WaitlistEntry, reason string) error {
+	from := entry.Status()
+	if err := entry.Remove(reason); err != nil {
+		return err
+	}
+	if err := p.waitlistRepo.Update(ctx, entry, from); err != nil {
+		if err == domain.ErrWaitlistEntryConflict {
+			return nil
+		}
+		return fmt.Errorf("failed to remove waitlist entry: %w", err)
+	}
+	return nil
+}
+
+// promoteWaitlist offers capacity freed by an outing to the waitlist; a
+// failure must not fail the operation that freed it
+func promoteWaitlist(ctx context.Context, promoter *WaitlistPromoter, outing *domain.Outing) {
+	if _, err := promoter.Promote(ctx, outing.Offer().OfferID(), outing.Slot()); err != nil {
+		fmt.Printf("warning: failed to promote waitlist for offer %s: %v\n", outing.Offer().OfferID(), err)
+	}
+}
+
+// =============================================================================
+// JOIN WAITLIST COMMAND
+// =============================================================================
+
+type JoinWaitlistCommand struct {
+	UserID  string
+	OfferID string
+
+	// Optional slot, as in BookOutingCommand
+	SlotDate      string
+	SlotStartTime string
+}
+
+type JoinWaitlistResult struct {
+	Entry *domain.WaitlistEntry
+	// Position is 1 for the first waiting user
+	Position int
+}
+
+type JoinWaitlistHandler struct {
+	waitlistRepo domain.WaitlistRepository
+	outingRepo   domain.OutingRepository
+	offerService domain.OfferService
+	userService  domain.UserService
+}
+
+func NewJoinWaitlistHandler(
+	waitlistRepo domain.WaitlistRepository,
+	outingRepo domain.OutingRepository,
+	offerService domain.OfferService,
+	userService domain.UserService,
+) *JoinWaitlistHandler {
+	return &JoinWaitlistHandler{
+		waitlistRepo: waitlistRepo,
+		outingRepo:   outingRepo,
+		offerService: offerService,
+		userService:  userService,
+	}
+}
+
+func (h *JoinWaitlistHandler) Handle(ctx context.Context, cmd JoinWaitlistCommand) (*JoinWaitlistResult, error) {
+	// 1. Check if user can book
+	if err := h.userService.CanBook(ctx, cmd.UserID); err != nil {
+		return nil, fmt.Errorf("user cannot book: %w", err)
+	}
+
+	// 2. A user with a booking has nothing to wait for
+	existing, err := h.outingRepo.GetActiveByUserAndOffer(ctx, cmd.UserID, cmd.OfferID)
+	if err != nil && err != domain.ErrOutingNotFound {
+		return nil, fmt.Errorf("failed to check existing booking: %w", err)
+	}
+	if existing != nil {
+		return nil, domain.ErrOutingAlreadyExists
+	}
+
+	// 3. Resolve the slot waited for, if any
+	var slot *domain.SlotSnapshot
+	var slotStartsAt *time.Time
+	if cmd.SlotDate != "" || cmd.SlotStartTime != "" {
+		schedule, err := h.offerService.GetSchedule(ctx, cmd.OfferID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get offer schedule: %w", err)
+		}
+		resolved, err := schedule.ResolveSlot(cmd.SlotDate, cmd.SlotStartTime, time.Now())
+		if err != nil {
+			return nil, err
+		}
+		startsAt := resolved.StartsAt()
+		slot, slotStartsAt = &resolved, &startsAt
+	}
+
+	// 4. Only one place in line per user
+	current, err := h.waitlistRepo.GetActiveByUserAndOffer(ctx, cmd.UserID, cmd.OfferID, slotStartsAt)
+	if err != nil && err != domain.ErrWaitlistEntryNotFound {
+		return nil, fmt.Errorf("failed to check waitlist: %w", err)
+	}
+	if current != nil {
+		return nil, domain.ErrAlreadyOnWaitlist
+	}
+
+	// 5. Join
+	entry := domain.NewWaitlistEntry(cmd.UserID, cmd.OfferID, slot)
+	if err := h.waitlistRepo.Create(ctx, entry); err != nil {
+		if err == domain.ErrAlreadyOnWaitlist {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to save waitlist entry: %w", err)
+	}
+
+	ahead, err := h.waitlistRepo.CountWaitingAhead(ctx, entry)
+	if err != nil {
+		return nil, err
+	}
+
+	return &JoinWaitlistResult{Entry: entry, Position: int(ahead) + 1}, nil
+}
+
+// =============================================================================
+// LEAVE WAITLIST COMMAND
+// =============================================================================
+
+type LeaveWaitlistCommand struct {
+	EntryID string
+	UserID  string
+}
+
+type LeaveWaitlistResult struct {
+	Entry *domain.WaitlistEntry
+}
+
+type LeaveWaitlistHandler struct {
+	waitlistRepo domain.WaitlistRepository
+	promoter     *WaitlistPromoter
+}
+
+func NewLeaveWaitlistHandler(waitlistRepo domain.WaitlistRepository, promoter *WaitlistPromoter) *LeaveWaitlistHandler {
+	return &LeaveWaitlistHandler{
+		waitlistRepo: waitlistRepo,
+		promoter:     promoter,
+	}
+}
+
+func (h *LeaveWaitlistHandler) Handle(ctx context.Context, cmd LeaveWaitlistCommand) (*LeaveWaitlistResult, error) {
+	entry, err := h.waitlistRepo.GetByID(ctx, cmd.EntryID)
+	if err != nil {
+		return nil, err
+	}
+	if entry.UserID() != cmd.UserID {
+		return nil, domain.ErrWaitlistEntryNotFound
+	}
+
+	from := entry.Status()
+	wasHeld := from == domain.WaitlistStatusHeld
+	if err := entry.Leave(); err != nil {
+		return nil, err
+	}
+	if err := h.waitlistRepo.Update(ctx, entry, from); err != nil {
+		if err == domain.ErrWaitlistEntryConflict {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to update waitlist entry: %w", err)
+	}
+
+	// A declined hold goes to the next user in line
+	if wasHeld {
+		h.promoter.ReleaseHold(ctx, entry)
+		if _, err := h.promoter.Promote(ctx, entry.OfferID(), entry.Slot()); err != nil {
+			fmt.Printf("warning: failed to promote waitlist for offer %s: %v\n", entry.OfferID(), err)
+		}
+	}
+
+	return &LeaveWaitlistResult{Entry: entry}, nil
+}
+
+// =============================================================================
+// ACCEPT WAITLIST HOLD COMMAND
+// =============================================================================
+
+type AcceptWaitlistHoldCommand struct {
+	EntryID string
+	UserID  string
+}
+
+type AcceptWaitlistHoldResult struct {
+	Entry  *domain.WaitlistEntry
+	Outing *domain.Outing
+}
+
+// AcceptWaitlistHoldHandler turns a hold into an outing. The capacity was
+// reserved when the hold was offered, so it is not reserved again.
+type AcceptWaitlistHoldHandler struct {
+	waitlistRepo        domain.WaitlistRepository
+	outingRepo          domain.OutingRepository
+	promoter            *WaitlistPromoter
+	offerService        domain.OfferService
+	userService         domain.UserService
+	notifyService       domain.NotificationService
+	expirationMins      int
+	confirmationTimeout time.Duration
+}
+
+func NewAcceptWaitlistHoldHandler(
+	waitlistRepo domain.WaitlistRepository,
+	outingRepo domain.OutingRepository,
+	promoter *WaitlistPromoter,
+	offerService domain.OfferService,
+	userService domain.UserService,
+	notifyService domain.NotificationService,
+	expirationMins int,
+	confirmationTimeout time.Duration,
+) *AcceptWaitlistHoldHandler {
+	return &AcceptWaitlistHoldHandler{
+		waitlistRepo:        waitlistRepo,
+		outingRepo:          outingRepo,
+		promoter:            promoter,
+		offerService:        offerService,
+		userService:         userService,
+		notifyService:       notifyService,
+		expirationMins:      expirationMins,
+		confirmationTimeout: confirmationTimeout,
+	}
+}
+
+func (h *AcceptWaitlistHoldHandler) Handle(ctx context.Context, cmd AcceptWaitlistHoldCommand) (*AcceptWaitlistHoldResult, error) {
+	// 1. Get the held entry
+	entry, err := h.waitlistRepo.GetByID(ctx, cmd.EntryID)
+	if err != nil {
+		return nil, err
+	}
+	if entry.UserID() != cmd.UserID {
+		return nil, domain.ErrWaitlistEntryNotFound
+	}
+	if err := entry.CanAccept(); err != nil {
+		return nil, err
+	}
+
+	// 2. Get snapshots
+	offerSnapshot, err := h.offerService.GetOfferSnapshot(ctx, entry.OfferID())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get offer snapshot: %w", err)
+	}
+	userSnapshot, err := h.userService.GetUserSnapshot(ctx, entry.UserID())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user snapshot: %w", err)
+	}
+
+	// 3. Build the outing for the held offer or slot
+	outing, err := newBookingOuting(ctx, h.offerService, entry.UserID(), *offerSnapshot, *userSnapshot, entry.Slot(), h.expirationMins, h.confirmationTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	// 4. Claim the hold before saving the outing: when the expiry job got
+	// there first the capacity is already released and offered to someone
+	// else, so the hold is lost
+	if err := entry.Convert(outing.ID()); err != nil {
+		return nil, err
+	}
+	if err := h.waitlistRepo.Update(ctx, entry, domain.WaitlistStatusHeld); err != nil {
+		if err == domain.ErrWaitlistEntryConflict {
+			return nil, domain.ErrWaitlistHoldExpired
+		}
+		return nil, fmt.Errorf("failed to convert waitlist entry: %w", err)
+	}
+
+	if err := createOuting(ctx, h.outingRepo, outing); err != nil {
+		// The entry is closed, so nothing else gives the capacity back
+		h.promoter.ReleaseHold(ctx, entry)
+		return nil, fmt.Errorf("failed to save outing: %w", err)
+	}
+
+	// 5. Increment offer booking count and notify
+	if err := h.offerService.IncrementBookingCount(ctx, entry.OfferID()); err != nil {
+		fmt.Printf("warning: failed to increment booking count: %v\n", err)
+	}
+
+	notifyBooked(h.notifyService, outing)
+
+	return &AcceptWaitlistHoldResult{Entry: entry, Outing: outing}, nil
+}
+
+// =============================================================================
+// EXPIRE WAITLIST HOLDS COMMAND (CRON JOB)
+// =============================================================================
+
+type ExpireWaitlistHoldsCommand struct {
+	BatchSize int
+}
+
+type ExpireWaitlistHoldsResult struct {
+	ExpiredCount int
+	FailedCount  int
+}
+
+type ExpireWaitlistHoldsHandler struct {
+	waitlistRepo domain.WaitlistRepository
+	outingRepo   domain.OutingRepository
+	promoter     *WaitlistPromoter
+}
+
+func NewExpireWaitlistHoldsHandler(
+	waitlistRepo domain.WaitlistRepository,
+	outingRepo domain.OutingRepository,
+	promoter *WaitlistPromoter,
+) *ExpireWaitlistHoldsHandler {
+	return &ExpireWaitlistHoldsHandler{
+		waitlistRepo: waitlistRepo,
+		outingRepo:   outingRepo,
+		promoter:     promoter,
+	}
+}
+
+func (h *ExpireWaitlistHoldsHandler) Handle(ctx context.Context, cmd ExpireWaitlistHoldsCommand) (*ExpireWaitlistHoldsResult, error) {
+	batchSize := cmd.BatchSize
+	if batchSize <= 0 {
+		batchSize = 100
+	}
+
+	entries, err := h.waitlistRepo.GetExpiredHolds(ctx, time.Now(), batchSize)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get expired waitlist holds: %w", err)
+	}
+
+	expiredCount, failedCount := 0, 0
+	for _, entry := range entries {
+		// An outing booked during the hold already took its capacity
+		outing, err := h.outingRepo.GetActiveByUserAndOffer(ctx, entry.UserID(), entry.OfferID())
+		if err != nil && err != domain.ErrOutingNotFound {
+			fmt.Printf("warning: failed to check booking for waitlist entry %s: %v\n", entry.ID(), err)
+			failedCount++
+			continue
+		}
+		if outing != nil && !outing.BookedAt().Before(*entry.HeldAt()) {
+			if err := h.promoter.remove(ctx, entry, "already_booked"); err != nil {
+				fmt.Printf("warning: failed to close waitlist entry %s: %v\n", entry.ID(), err)
+				failedCount++
+			}
+			continue
+		}
+
+		if err := entry.ExpireHold(); err != nil {
+			fmt.Printf("warning: failed to expire waitlist hold %s: %v\n", entry.ID(), err)
+			failedCount++
+			continue
+		}
+		if err := h.waitlistRepo.Update(ctx, entry, domain.WaitlistStatusHeld); err != nil {
+			if err == domain.ErrWaitlistEntryConflict {
+				// Accepted or left meanwhile; its capacity is no longer ours
+				continue
+			}
+			fmt.Printf("warning: failed to update waitlist entry %s: %v\n", entry.ID(), err)
+			failedCount++
+			continue
+		}
+
+		h.promoter.ReleaseHold(ctx, entry)
+		if _, err := h.promoter.Promote(ctx, entry.OfferID(), entry.Slot()); err != nil {
+			fmt.Printf("warning: failed to promote waitlist for offer %s: %v\n", entry.OfferID(), err)
 		}
 
 		expiredCount++
 	}
 
-	return &ExpireOutingsResult{ExpiredCount: expiredCount}, nil
+	return &ExpireWaitlistHoldsResult{ExpiredCount: expiredCount, FailedCount: failedCount}, nil
 }
@@ -0,0 +1,369 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/yousoon/apps/services/booking-service/internal/domain"
+)
+
+// =============================================================================
+// Idempotency Tests
+// =============================================================================
+
+// memoryIdempotencyStore follows the contract of domain.IdempotencyStore
+// in memory
+type memoryIdempotencyStore struct {
+	records map[string]memoryIdempotencyRecord
+}
+
+type memoryIdempotencyRecord struct {
+	fingerprint string
+	outcome     *domain.IdempotentOutcome
+}
+
+func newMemoryIdempotencyStore() *memoryIdempotencyStore {
+	return &memoryIdempotencyStore{records: make(map[string]memoryIdempotencyRecord)}
+}
+
+func (s *memoryIdempotencyStore) Claim(ctx context.Context, key, fingerprint string) (*domain.IdempotentOutcome, error) {
+	record, ok := s.records[key]
+	if !ok {
+		s.records[key] = memoryIdempotencyRecord{fingerprint: fingerprint}
+		return nil, nil
+	}
+	if record.fingerprint != fingerprint {
+		return nil, domain.ErrIdempotencyKeyMismatch
+	}
+	if record.outcome == nil {
+		return nil, domain.ErrIdempotencyKeyInUse
+	}
+	return record.outcome, nil
+}
+
+func (s *memoryIdempotencyStore) Complete(ctx context.Context, key, fingerprint string, outcome domain.IdempotentOutcome) error {
+	s.records[key] = memoryIdempotencyRecord{fingerprint: fingerprint, outcome: &outcome}
+	return nil
+}
+
+func (s *memoryIdempotencyStore) Release(ctx context.Context, key string) error {
+	if record, ok := s.records[key]; ok && record.outcome == nil {
+		delete(s.records, key)
+	}
+	return nil
+}
+
+// outingByIDRepository serves GetByID from a map; other methods are unused
+type outingByIDRepository struct {
+	domain.OutingRepository
+	outings map[string]*domain.Outing
+}
+
+func (r *outingByIDRepository) GetByID(ctx context.Context, id string) (*domain.Outing, error) {
+	if outing, ok := r.outings[id]; ok {
+		return outing, nil
+	}
+	return nil, domain.ErrOutingNotFound
+}
+
+func TestIdempotencyGuard_FingerprintMismatch(t *testing.T) {
+	outing := &domain.Outing{}
+	guard := idempotencyGuard{
+		store:      newMemoryIdempotencyStore(),
+		outingRepo: &outingByIDRepository{outings: map[string]*domain.Outing{outing.ID(): outing}},
+	}
+	ctx := context.Background()
+
+	runs := 0
+	do := func() (*domain.Outing, error) {
+		runs++
+		return outing, nil
+	}
+
+	if _, err := guard.run(ctx, "checkin:staff-1", "key-1", []string{"outing-1", "qr-a"}, do); err != nil {
+		t.Fatalf("run() first attempt error = %v, want nil", err)
+	}
+
+	// Same key, different request: refused without running the command
+	if _, err := guard.run(ctx, "checkin:staff-1", "key-1", []string{"outing-1", "qr-b"}, do); err != domain.ErrIdempotencyKeyMismatch {
+		t.Errorf("run() reused key error = %v, want %v", err, domain.ErrIdempotencyKeyMismatch)
+	}
+
+	// Fields are separated, so moving a boundary changes the fingerprint
+	if _, err := guard.run(ctx, "checkin:staff-1", "key-1", []string{"outing-1qr-a", ""}, do); err != domain.ErrIdempotencyKeyMismatch {
+		t.Errorf("run() shifted fields error = %v, want %v", err, domain.ErrIdempotencyKeyMismatch)
+	}
+
+	// Same key and request: replayed
+	replayed, err := guard.run(ctx, "checkin:staff-1", "key-1", []string{"outing-1", "qr-a"}, do)
+	if err != nil {
+		t.Fatalf("run() replay error = %v, want nil", err)
+	}
+	if replayed != outing {
+		t.Errorf("run() replay = %p, want the first outcome %p", replayed, outing)
+	}
+	if runs != 1 {
+		t.Errorf("command ran %d times, want 1", runs)
+	}
+
+	// Keys are scoped per caller
+	if _, err := guard.run(ctx, "checkin:staff-2", "key-1", []string{"outing-1", "qr-b"}, do); err != nil {
+		t.Errorf("run() other scope error = %v, want nil", err)
+	}
+}
+
+func TestIdempotencyGuard_FailedAttemptFreesKey(t *testing.T) {
+	guard := idempotencyGuard{store: newMemoryIdempotencyStore()}
+	ctx := context.Background()
+	failure := errors.New("boom")
+
+	_, err := guard.run(ctx, "book:user-1", "key-1", []string{"offer-1"}, func() (*domain.Outing, error) {
+		return nil, failure
+	})
+	if err != failure {
+		t.Fatalf("run() error = %v, want %v", err, failure)
+	}
+
+	outing := &domain.Outing{}
+	got, err := guard.run(ctx, "book:user-1", "key-1", []string{"offer-1"}, func() (*domain.Outing, error) {
+		return outing, nil
+	})
+	if err != nil || got != outing {
+		t.Errorf("run() retry = %v, %v, want the new outcome", got, err)
+	}
+}
+
+func TestIdempotencyGuard_KeyTooLong(t *testing.T) {
+	guard := idempotencyGuard{store: newMemoryIdempotencyStore()}
+	key := string(make([]byte, domain.MaxIdempotencyKeyLength+1))
+
+	_, err := guard.run(context.Background(), "book:user-1", key, nil, func() (*domain.Outing, error) {
+		t.Error("command ran with an invalid key")
+		return nil, nil
+	})
+	if err != domain.ErrInvalidIdempotencyKey {
+		t.Errorf("run() error = %v, want %v", err, domain.ErrInvalidIdempotencyKey)
+	}
+}
+
+// =============================================================================
+// Cancellation Tests
+// =============================================================================
+
+// conditionalOutingRepository serves GetByID from a map and fails
+// UpdateIfStatus when the outing's status changed since it was loaded
+type conditionalOutingRepository struct {
+	outingByIDRepository
+	changed bool
+	updated int
+}
+
+func (r *conditionalOutingRepository) UpdateIfStatus(ctx context.Context, outing *domain.Outing, from domain.OutingStatus) error {
+	if r.changed {
+		return domain.ErrOutingStatusChanged
+	}
+	r.updated++
+	return nil
+}
+
+// noPolicyOfferService has no cancellation policy and counts decrements
+type noPolicyOfferService struct {
+	domain.OfferService
+	decremented int
+}
+
+func (s *noPolicyOfferService) GetCancellationPolicy(ctx context.Context, offerID string) (*domain.CancellationPolicy, error) {
+	return nil, nil
+}
+
+func (s *noPolicyOfferService) DecrementBookingCount(ctx context.Context, offerID string) error {
+	s.decremented++
+	return nil
+}
+
+// countingQuotaService counts quota releases
+type countingQuotaService struct {
+	domain.QuotaService
+	released int
+}
+
+func (s *countingQuotaService) Release(ctx context.Context, outing *domain.Outing) error {
+	s.released++
+	return nil
+}
+
+func newTestOuting(t *testing.T) *domain.Outing {
+	t.Helper()
+	offer := domain.NewOfferSnapshot(
+		"offer-123", "partner-456", "est-789",
+		"Test Offer", "Test Description",
+		"percentage", 20,
+		"restaurant",
+		"Test Restaurant", "123 Test St",
+		48.8566, 2.3522,
+		"",
+	)
+	user := domain.NewUserSnapshot("user-123", "John", "Doe", "john@example.com")
+	outing, err := domain.NewOuting("user-123", offer, user, 30)
+	if err != nil {
+		t.Fatalf("NewOuting() error = %v", err)
+	}
+	return outing
+}
+
+func TestCancelOutingHandler_LostRaceReleasesNothing(t *testing.T) {
+	outing := newTestOuting(t)
+	repo := &conditionalOutingRepository{
+		outingByIDRepository: outingByIDRepository{outings: map[string]*domain.Outing{outing.ID(): outing}},
+		changed:              true,
+	}
+	offers := &noPolicyOfferService{}
+	quota := &countingQuotaService{}
+	handler := NewCancelOutingHandler(repo, offers, nil, quota, nil, nil, nil, nil, nil)
+
+	_, err := handler.Handle(context.Background(), CancelOutingCommand{
+		OutingID: outing.ID(),
+		UserID:   outing.UserID(),
+	})
+
+	if err != domain.ErrOutingStatusChanged {
+		t.Fatalf("Handle() error = %v, want %v", err, domain.ErrOutingStatusChanged)
+	}
+	if quota.released != 0 || offers.decremented != 0 {
+		t.Errorf("Handle() released quota %d times and decremented %d times, want none", quota.released, offers.decremented)
+	}
+}
+
+// noTeamPartnerService has no team members
+type noTeamPartnerService struct {
+	domain.PartnerService
+}
+
+func (s *noTeamPartnerService) IsTeamMember(ctx context.Context, userID, establishmentID string) (bool, error) {
+	return false, nil
+}
+
+func TestCancelOutingHandler_OwnerOnly(t *testing.T) {
+	outing := newTestOuting(t)
+	repo := &conditionalOutingRepository{
+		outingByIDRepository: outingByIDRepository{outings: map[string]*domain.Outing{outing.ID(): outing}},
+	}
+	handler := NewCancelOutingHandler(repo, &noPolicyOfferService{}, &noTeamPartnerService{}, &countingQuotaService{}, nil, nil, nil, nil, nil)
+
+	_, err := handler.Handle(context.Background(), CancelOutingCommand{
+		OutingID: outing.ID(),
+		UserID:   "user-999",
+	})
+
+	if err != domain.ErrOutingNotFound {
+		t.Fatalf("Handle() by stranger error = %v, want %v", err, domain.ErrOutingNotFound)
+	}
+	if repo.updated != 0 || outing.Status() == domain.OutingStatusCancelled {
+		t.Error("Handle() by stranger cancelled the outing")
+	}
+}
+
+// =============================================================================
+// Expiry Tests
+// =============================================================================
+
+// expiredOutingRepository returns its outings as expired
+type expiredOutingRepository struct {
+	conditionalOutingRepository
+}
+
+func (r *expiredOutingRepository) GetExpiredOutings(ctx context.Context, before time.Time, limit int) ([]*domain.Outing, error) {
+	outings := make([]*domain.Outing, 0, len(r.outings))
+	for _, outing := range r.outings {
+		outings = append(outings, outing)
+	}
+	return outings, nil
+}
+
+func TestExpireOutingsHandler_SkipsChangedOutings(t *testing.T) {
+	outing := newTestOuting(t)
+	repo := &expiredOutingRepository{conditionalOutingRepository{
+		outingByIDRepository: outingByIDRepository{outings: map[string]*domain.Outing{outing.ID(): outing}},
+		changed:              true,
+	}}
+	quota := &countingQuotaService{}
+	handler := NewExpireOutingsHandler(repo, quota, nil, nil)
+
+	result, err := handler.Handle(context.Background(), ExpireOutingsCommand{})
+
+	if err != nil {
+		t.Fatalf("Handle() error = %v, want nil", err)
+	}
+	if result.ExpiredCount != 0 || result.FailedCount != 0 {
+		t.Errorf("Handle() expired %d and failed %d, want a skip", result.ExpiredCount, result.FailedCount)
+	}
+	if quota.released != 0 {
+		t.Errorf("Handle() released quota %d times, want none", quota.released)
+	}
+}
+
+// =============================================================================
+// Strike Tests
+// =============================================================================
+
+// racingStandingRepository fails the first saves as if another strike had
+// been saved meanwhile
+type racingStandingRepository struct {
+	conflicts int
+	loads     int
+	saved     *domain.UserStanding
+}
+
+func (r *racingStandingRepository) Get(ctx context.Context, userID string) (*domain.UserStanding, error) {
+	r.loads++
+	return domain.NewUserStanding(userID), nil
+}
+
+func (r *racingStandingRepository) Save(ctx context.Context, standing *domain.UserStanding) error {
+	if r.conflicts > 0 {
+		r.conflicts--
+		return domain.ErrUserStandingChanged
+	}
+	r.saved = standing
+	return nil
+}
+
+func TestStrikeRecorder_RetriesConcurrentChange(t *testing.T) {
+	policy, _ := domain.NewStrikePolicy(3, 24*time.Hour, 24*time.Hour)
+	repo := &racingStandingRepository{conflicts: 1}
+	recorder := NewStrikeRecorder(repo, policy)
+	outing := newTestOuting(t)
+
+	recorder.Record(context.Background(), outing, domain.StrikeKindNoShow)
+
+	if repo.loads != 2 {
+		t.Errorf("Record() loaded the standing %d times, want 2", repo.loads)
+	}
+	if repo.saved == nil || len(repo.saved.Strikes()) != 1 {
+		t.Fatal("Record() did not save the strike after the conflict")
+	}
+}
+
+// =============================================================================
+// Check-in Tests
+// =============================================================================
+
+func TestCheckInOutingHandler_ByIDRequiresTeamMember(t *testing.T) {
+	outing := newTestOuting(t)
+	repo := &outingByIDRepository{outings: map[string]*domain.Outing{outing.ID(): outing}}
+	handler := NewCheckInOutingHandler(repo, nil, &noTeamPartnerService{}, nil, nil, nil, nil, nil)
+
+	_, err := handler.Handle(context.Background(), CheckInOutingCommand{
+		OutingID:    outing.ID(),
+		StaffUserID: "staff-1",
+	})
+
+	if err != domain.ErrNotPartnerTeamMember {
+		t.Fatalf("Handle() error = %v, want %v", err, domain.ErrNotPartnerTeamMember)
+	}
+	if outing.CheckIn() != nil {
+		t.Error("Handle() checked in the outing for a stranger")
+	}
+}
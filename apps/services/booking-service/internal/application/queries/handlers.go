@@ -2,7 +2,10 @@ package queries
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"strconv"
+	"time"
 
 	"github.com/yousoon/apps/services/booking-service/internal/domain"
 )
@@ -77,8 +80,7 @@ type ListUserOutingsQuery struct {
 }
 
 type ListUserOutingsResult struct {
-	Outings    []*domain.Outing
-	TotalCount int64
+	Page *domain.OutingPage
 }
 
 type ListUserOutingsHandler struct {
@@ -92,14 +94,11 @@ func NewListUserOutingsHandler(outingRepo domain.OutingRepository) *ListUserOuti
 }
 
 func (h *ListUserOutingsHandler) Handle(ctx context.Context, query ListUserOutingsQuery) (*ListUserOutingsResult, error) {
-	outings, count, err := h.outingRepo.GetByUserID(ctx, query.UserID, query.Filter)
+	page, err := h.outingRepo.GetByUserID(ctx, query.UserID, query.Filter)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list user outings: %w", err)
 	}
-	return &ListUserOutingsResult{
-		Outings:    outings,
-		TotalCount: count,
-	}, nil
+	return &ListUserOutingsResult{Page: page}, nil
 }
 
 // =============================================================================
@@ -112,8 +111,7 @@ type ListPartnerOutingsQuery struct {
 }
 
 type ListPartnerOutingsResult struct {
-	Outings    []*domain.Outing
-	TotalCount int64
+	Page *domain.OutingPage
 }
 
 type ListPartnerOutingsHandler struct {
@@ -127,14 +125,11 @@ func NewListPartnerOutingsHandler(outingRepo domain.OutingRepository) *ListPartn
 }
 
 func (h *ListPartnerOutingsHandler) Handle(ctx context.Context, query ListPartnerOutingsQuery) (*ListPartnerOutingsResult, error) {
-	outings, count, err := h.outingRepo.GetByPartnerID(ctx, query.PartnerID, query.Filter)
+	page, err := h.outingRepo.GetByPartnerID(ctx, query.PartnerID, query.Filter)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list partner outings: %w", err)
 	}
-	return &ListPartnerOutingsResult{
-		Outings:    outings,
-		TotalCount: count,
-	}, nil
+	return &ListPartnerOutingsResult{Page: page}, nil
 }
 
 // =============================================================================
@@ -147,8 +142,7 @@ type ListEstablishmentOutingsQuery struct {
 }
 
 type ListEstablishmentOutingsResult struct {
-	Outings    []*domain.Outing
-	TotalCount int64
+	Page *domain.OutingPage
 }
 
 type ListEstablishmentOutingsHandler struct {
@@ -162,14 +156,46 @@ func NewListEstablishmentOutingsHandler(outingRepo domain.OutingRepository) *Lis
 }
 
 func (h *ListEstablishmentOutingsHandler) Handle(ctx context.Context, query ListEstablishmentOutingsQuery) (*ListEstablishmentOutingsResult, error) {
-	outings, count, err := h.outingRepo.GetByEstablishmentID(ctx, query.EstablishmentID, query.Filter)
+	page, err := h.outingRepo.GetByEstablishmentID(ctx, query.EstablishmentID, query.Filter)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list establishment outings: %w", err)
 	}
-	return &ListEstablishmentOutingsResult{
-		Outings:    outings,
-		TotalCount: count,
-	}, nil
+	return &ListEstablishmentOutingsResult{Page: page}, nil
+}
+
+// =============================================================================
+// LIST FLAGGED OUTINGS
+// =============================================================================
+
+type ListFlaggedOutingsQuery struct {
+	Scope  domain.FlaggedOutingScope
+	Filter domain.OutingFilter
+}
+
+type ListFlaggedOutingsResult struct {
+	Page *domain.OutingPage
+}
+
+type ListFlaggedOutingsHandler struct {
+	outingRepo domain.OutingRepository
+}
+
+func NewListFlaggedOutingsHandler(outingRepo domain.OutingRepository) *ListFlaggedOutingsHandler {
+	return &ListFlaggedOutingsHandler{
+		outingRepo: outingRepo,
+	}
+}
+
+func (h *ListFlaggedOutingsHandler) Handle(ctx context.Context, query ListFlaggedOutingsQuery) (*ListFlaggedOutingsResult, error) {
+	if query.Scope.Reason != "" && !query.Scope.Reason.IsValid() {
+		return nil, fmt.Errorf("invalid anomaly reason: %s", query.Scope.Reason)
+	}
+
+	page, err := h.outingRepo.GetFlagged(ctx, query.Scope, query.Filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list flagged outings: %w", err)
+	}
+	return &ListFlaggedOutingsResult{Page: page}, nil
 }
 
 // =============================================================================
@@ -180,19 +206,14 @@ type GetBookingStatsQuery struct {
 	PartnerID       string
 	EstablishmentID string
 	OfferID         string
-}
-
-type BookingStats struct {
-	TotalBookings      int64
-	TotalCheckIns      int64
-	TotalCancelled     int64
-	TotalExpired       int64
-	ConversionRate     float64
-	AverageCheckInTime float64
+	StartDate       *time.Time
+	EndDate         *time.Time
+	Granularity     domain.StatsGranularity
+	Timezone        string
 }
 
 type GetBookingStatsResult struct {
-	Stats *BookingStats
+	Stats *domain.BookingStats
 }
 
 type GetBookingStatsHandler struct {
@@ -206,16 +227,426 @@ func NewGetBookingStatsHandler(outingRepo domain.OutingRepository) *GetBookingSt
 }
 
 func (h *GetBookingStatsHandler) Handle(ctx context.Context, query GetBookingStatsQuery) (*GetBookingStatsResult, error) {
-	// This would typically involve aggregation queries
-	// For now, return placeholder stats
-	stats := &BookingStats{
-		TotalBookings:      0,
-		TotalCheckIns:      0,
-		TotalCancelled:     0,
-		TotalExpired:       0,
-		ConversionRate:     0.0,
-		AverageCheckInTime: 0.0,
+	if query.Granularity != "" && !query.Granularity.IsValid() {
+		return nil, fmt.Errorf("invalid stats granularity: %s", query.Granularity)
+	}
+	if query.StartDate != nil && query.EndDate != nil && query.EndDate.Before(*query.StartDate) {
+		return nil, fmt.Errorf("end date must be after start date")
+	}
+
+	stats, err := h.outingRepo.GetStats(ctx, domain.StatsFilter{
+		PartnerID:       query.PartnerID,
+		EstablishmentID: query.EstablishmentID,
+		OfferID:         query.OfferID,
+		StartDate:       query.StartDate,
+		EndDate:         query.EndDate,
+		Granularity:     query.Granularity,
+		Timezone:        query.Timezone,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get booking stats: %w", err)
 	}
 
 	return &GetBookingStatsResult{Stats: stats}, nil
 }
+
+// =============================================================================
+// GET USER SAVINGS
+// =============================================================================
+
+type GetUserSavingsQuery struct {
+	UserID    string
+	StartDate *time.Time
+	EndDate   *time.Time
+	Timezone  string
+}
+
+type GetUserSavingsResult struct {
+	Savings *domain.SavingsSummary
+}
+
+type GetUserSavingsHandler struct {
+	outingRepo domain.OutingRepository
+}
+
+func NewGetUserSavingsHandler(outingRepo domain.OutingRepository) *GetUserSavingsHandler {
+	return &GetUserSavingsHandler{
+		outingRepo: outingRepo,
+	}
+}
+
+func (h *GetUserSavingsHandler) Handle(ctx context.Context, query GetUserSavingsQuery) (*GetUserSavingsResult, error) {
+	if query.StartDate != nil && query.EndDate != nil && query.EndDate.Before(*query.StartDate) {
+		return nil, fmt.Errorf("end date must be after start date")
+	}
+
+	savings, err := h.outingRepo.GetSavings(ctx, domain.SavingsFilter{
+		UserID:    query.UserID,
+		StartDate: query.StartDate,
+		EndDate:   query.EndDate,
+		Timezone:  query.Timezone,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get savings: %w", err)
+	}
+
+	return &GetUserSavingsResult{Savings: savings}, nil
+}
+
+// =============================================================================
+// GET SLOT AVAILABILITY
+// =============================================================================
+
+type GetSlotAvailabilityQuery struct {
+	OfferID string
+	Date    string // "2006-01-02", in the offer's timezone
+}
+
+// SlotAvailability is a slot instance with its remaining seats (nil = unlimited)
+type SlotAvailability struct {
+	Slot      domain.SlotSnapshot
+	Remaining *int
+}
+
+type GetSlotAvailabilityResult struct {
+	Slots []SlotAvailability
+}
+
+type GetSlotAvailabilityHandler struct {
+	offerService domain.OfferService
+	slotService  domain.SlotCapacityService
+}
+
+func NewGetSlotAvailabilityHandler(offerService domain.OfferService, slotService domain.SlotCapacityService) *GetSlotAvailabilityHandler {
+	return &GetSlotAvailabilityHandler{
+		offerService: offerService,
+		slotService:  slotService,
+	}
+}
+
+func (h *GetSlotAvailabilityHandler) Handle(ctx context.Context, query GetSlotAvailabilityQuery) (*GetSlotAvailabilityResult, error) {
+	schedule, err := h.offerService.GetSchedule(ctx, query.OfferID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get offer schedule: %w", err)
+	}
+
+	slots, err := schedule.SlotsOn(query.Date)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	result := &GetSlotAvailabilityResult{Slots: make([]SlotAvailability, 0, len(slots))}
+	for _, slot := range slots {
+		if !now.Before(slot.EndsAt()) {
+			continue
+		}
+
+		remaining, err := h.slotService.Remaining(ctx, query.OfferID, slot)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get slot availability: %w", err)
+		}
+		result.Slots = append(result.Slots, SlotAvailability{Slot: slot, Remaining: remaining})
+	}
+
+	return result, nil
+}
+
+// =============================================================================
+// LIST USER WAITLIST
+// =============================================================================
+
+type ListUserWaitlistQuery struct {
+	UserID string
+}
+
+// WaitlistPosition is an entry with its place in line (0 while it holds capacity)
+type WaitlistPosition struct {
+	Entry    *domain.WaitlistEntry
+	Position int
+}
+
+type ListUserWaitlistResult struct {
+	Entries []WaitlistPosition
+}
+
+type ListUserWaitlistHandler struct {
+	waitlistRepo domain.WaitlistRepository
+}
+
+func NewListUserWaitlistHandler(waitlistRepo domain.WaitlistRepository) *ListUserWaitlistHandler {
+	return &ListUserWaitlistHandler{
+		waitlistRepo: waitlistRepo,
+	}
+}
+
+func (h *ListUserWaitlistHandler) Handle(ctx context.Context, query ListUserWaitlistQuery) (*ListUserWaitlistResult, error) {
+	entries, err := h.waitlistRepo.GetActiveByUserID(ctx, query.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list user waitlist: %w", err)
+	}
+
+	result := &ListUserWaitlistResult{Entries: make([]WaitlistPosition, 0, len(entries))}
+	for _, entry := range entries {
+		position := 0
+		if entry.Status() == domain.WaitlistStatusWaiting {
+			ahead, err := h.waitlistRepo.CountWaitingAhead(ctx, entry)
+			if err != nil {
+				return nil, err
+			}
+			position = int(ahead) + 1
+		}
+		result.Entries = append(result.Entries, WaitlistPosition{Entry: entry, Position: position})
+	}
+
+	return result, nil
+}
+
+// =============================================================================
+// LIST INCOMING TRANSFERS
+// =============================================================================
+
+// ListIncomingTransfersQuery lists the outings offered to the user that they
+// can still accept
+type ListIncomingTransfersQuery struct {
+	UserID string
+}
+
+type ListIncomingTransfersResult struct {
+	Outings []*domain.Outing
+}
+
+type ListIncomingTransfersHandler struct {
+	outingRepo domain.OutingRepository
+}
+
+func NewListIncomingTransfersHandler(outingRepo domain.OutingRepository) *ListIncomingTransfersHandler {
+	return &ListIncomingTransfersHandler{
+		outingRepo: outingRepo,
+	}
+}
+
+func (h *ListIncomingTransfersHandler) Handle(ctx context.Context, query ListIncomingTransfersQuery) (*ListIncomingTransfersResult, error) {
+	outings, err := h.outingRepo.GetPendingTransfersTo(ctx, query.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list incoming transfers: %w", err)
+	}
+	return &ListIncomingTransfersResult{Outings: outings}, nil
+}
+
+// =============================================================================
+// WATCH ESTABLISHMENT OUTINGS
+// =============================================================================
+
+type WatchEstablishmentOutingsQuery struct {
+	UserID          string
+	EstablishmentID string
+}
+
+type WatchEstablishmentOutingsHandler struct {
+	feed           domain.OutingUpdateFeed
+	partnerService domain.PartnerService
+}
+
+func NewWatchEstablishmentOutingsHandler(feed domain.OutingUpdateFeed, partnerService domain.PartnerService) *WatchEstablishmentOutingsHandler {
+	return &WatchEstablishmentOutingsHandler{
+		feed:           feed,
+		partnerService: partnerService,
+	}
+}
+
+// Handle streams live updates of the establishment's outings to a team
+// member of its partner until ctx is done. Membership is checked when the
+// subscription starts.
+func (h *WatchEstablishmentOutingsHandler) Handle(ctx context.Context, query WatchEstablishmentOutingsQuery) (<-chan domain.OutingUpdate, error) {
+	member, err := h.partnerService.IsTeamMember(ctx, query.UserID, query.EstablishmentID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check partner team: %w", err)
+	}
+	if !member {
+		return nil, domain.ErrNotPartnerTeamMember
+	}
+
+	return h.feed.SubscribeEstablishment(ctx, query.EstablishmentID)
+}
+
+// =============================================================================
+// EXPORT OUTINGS
+// =============================================================================
+
+// exportBatchSize is how many outings are read per page while exporting
+const exportBatchSize = 500
+
+var ErrExportScopeRequired = errors.New("a partner or an establishment is required")
+
+// ExportOutingsQuery exports the outings of an establishment, or of a partner
+// when no establishment is given, booked within the optional date range
+type ExportOutingsQuery struct {
+	UserID          string
+	PartnerID       string
+	EstablishmentID string
+	StartDate       *time.Time
+	EndDate         *time.Time
+}
+
+// ExportRowWriter receives the export one row at a time
+type ExportRowWriter interface {
+	WriteRow(values []string) error
+}
+
+type ExportOutingsHandler struct {
+	outingRepo     domain.OutingRepository
+	partnerService domain.PartnerService
+}
+
+func NewExportOutingsHandler(outingRepo domain.OutingRepository, partnerService domain.PartnerService) *ExportOutingsHandler {
+	return &ExportOutingsHandler{
+		outingRepo:     outingRepo,
+		partnerService: partnerService,
+	}
+}
+
+// Authorize checks that the user belongs to the team of the exported
+// establishment or partner. It is separate from Handle so a refusal can be
+// reported before the download starts.
+func (h *ExportOutingsHandler) Authorize(ctx context.Context, query ExportOutingsQuery) error {
+	if query.PartnerID == "" && query.EstablishmentID == "" {
+		return ErrExportScopeRequired
+	}
+
+	var member bool
+	var err error
+	if query.EstablishmentID != "" {
+		member, err = h.partnerService.IsTeamMember(ctx, query.UserID, query.EstablishmentID)
+	} else {
+		member, err = h.partnerService.IsPartnerMember(ctx, query.UserID, query.PartnerID)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to check partner team: %w", err)
+	}
+	if !member {
+		return domain.ErrNotPartnerTeamMember
+	}
+	return nil
+}
+
+// Handle writes a header row then one row per outing, oldest first, reading
+// the outings page by page. It returns the number of outings written.
+func (h *ExportOutingsHandler) Handle(ctx context.Context, query ExportOutingsQuery, w ExportRowWriter) (int, error) {
+	if query.PartnerID == "" && query.EstablishmentID == "" {
+		return 0, ErrExportScopeRequired
+	}
+
+	if err := w.WriteRow(outingExportHeader); err != nil {
+		return 0, fmt.Errorf("failed to write export header: %w", err)
+	}
+
+	filter := domain.DefaultOutingFilter()
+	filter.StartDate = query.StartDate
+	filter.EndDate = query.EndDate
+	filter.Limit = exportBatchSize
+	filter.SortOrder = "asc"
+
+	written := 0
+	for {
+		var page *domain.OutingPage
+		var err error
+		if query.EstablishmentID != "" {
+			page, err = h.outingRepo.GetByEstablishmentID(ctx, query.EstablishmentID, filter)
+		} else {
+			page, err = h.outingRepo.GetByPartnerID(ctx, query.PartnerID, filter)
+		}
+		if err != nil {
+			return written, fmt.Errorf("failed to read outings: %w", err)
+		}
+
+		for _, outing := range page.Outings {
+			if err := w.WriteRow(outingExportRow(outing)); err != nil {
+				return written, fmt.Errorf("failed to write export row: %w", err)
+			}
+			written++
+		}
+
+		if !page.HasNextPage || len(page.Outings) == 0 {
+			return written, nil
+		}
+		cursor := domain.NewOutingCursor(page.Outings[len(page.Outings)-1], filter.SortBy)
+		filter.After = &cursor
+	}
+}
+
+var outingExportHeader = []string{
+	"outing_id",
+	"status",
+	"booked_at",
+	"expires_at",
+	"slot_starts_at",
+	"user_id",
+	"offer_id",
+	"offer_title",
+	"category",
+	"discount_type",
+	"discount_value",
+	"partner_id",
+	"establishment_id",
+	"establishment_name",
+	"establishment_address",
+	"checked_in_at",
+	"check_in_method",
+	"checked_in_by",
+	"flagged_for_review",
+	"bill_amount_cents",
+	"savings_cents",
+	"cancelled_at",
+	"cancelled_by",
+	"cancellation_reason",
+}
+
+func outingExportRow(o *domain.Outing) []string {
+	offer := o.Offer()
+	row := []string{
+		o.ID(),
+		string(o.Status()),
+		formatExportTime(o.BookedAt()),
+		formatExportTime(o.ExpiresAt()),
+		"",
+		o.UserID(),
+		offer.OfferID(),
+		offer.Title(),
+		offer.Category(),
+		offer.DiscountType(),
+		strconv.Itoa(offer.DiscountValue()),
+		offer.PartnerID(),
+		offer.EstablishmentID(),
+		offer.EstablishmentName(),
+		offer.EstablishmentAddress(),
+		"", "", "", "",
+		"", "",
+		"", "", "",
+	}
+
+	if slot := o.Slot(); slot != nil {
+		row[4] = formatExportTime(slot.StartsAt())
+	}
+	if checkIn := o.CheckIn(); checkIn != nil {
+		row[15] = formatExportTime(checkIn.CheckedInAt())
+		row[16] = string(checkIn.Method())
+		row[17] = checkIn.CheckedInBy()
+		row[18] = strconv.FormatBool(checkIn.FlaggedForReview())
+		if bill := checkIn.Bill(); bill != nil {
+			row[19] = strconv.FormatInt(bill.AmountCents(), 10)
+			row[20] = strconv.FormatInt(bill.SavingsCents(), 10)
+		}
+	}
+	if cancellation := o.Cancellation(); cancellation != nil {
+		row[21] = formatExportTime(cancellation.CancelledAt())
+		row[22] = string(cancellation.CancelledBy())
+		row[23] = cancellation.Reason()
+	}
+
+	return row
+}
+
+func formatExportTime(t time.Time) string {
+	return t.UTC().Format(time.RFC3339)
+}
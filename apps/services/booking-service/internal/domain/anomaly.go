@@ -0,0 +1,187 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// =============================================================================
+// CHECK-IN ANOMALIES
+// =============================================================================
+
+// Check-ins are screened for signs of fraud once recorded: staff checking in
+// outings faster than customers walk in, check-ins while the establishment is
+// closed or away from it, and accounts used at distant establishments minutes
+// apart. An anomaly never undoes a check-in, it flags the outing for review.
+
+var ErrInvalidOpeningHours = errors.New("invalid opening hours, expected HH:MM")
+
+type AnomalyReason string
+
+const (
+	AnomalyOutsideGeofence     AnomalyReason = ReviewReasonOutsideGeofence
+	AnomalyLocationMissing     AnomalyReason = ReviewReasonLocationMissing
+	AnomalyStaffCheckInBurst   AnomalyReason = "staff_check_in_burst"
+	AnomalyOutsideOpeningHours AnomalyReason = "outside_opening_hours"
+	AnomalyImpossibleTravel    AnomalyReason = "impossible_travel"
+)
+
+func (r AnomalyReason) IsValid() bool {
+	switch r {
+	case AnomalyOutsideGeofence, AnomalyLocationMissing, AnomalyStaffCheckInBurst,
+		AnomalyOutsideOpeningHours, AnomalyImpossibleTravel:
+		return true
+	}
+	return false
+}
+
+// CheckInAnomaly is one reason a check-in was flagged for review
+type CheckInAnomaly struct {
+	reason     AnomalyReason
+	detail     string
+	detectedAt time.Time
+}
+
+func NewCheckInAnomaly(reason AnomalyReason, detail string) CheckInAnomaly {
+	return CheckInAnomaly{
+		reason:     reason,
+		detail:     detail,
+		detectedAt: time.Now(),
+	}
+}
+
+func ReconstructCheckInAnomaly(reason AnomalyReason, detail string, detectedAt time.Time) CheckInAnomaly {
+	return CheckInAnomaly{
+		reason:     reason,
+		detail:     detail,
+		detectedAt: detectedAt,
+	}
+}
+
+func (a CheckInAnomaly) Reason() AnomalyReason { return a.reason }
+func (a CheckInAnomaly) Detail() string        { return a.detail }
+func (a CheckInAnomaly) DetectedAt() time.Time { return a.detectedAt }
+
+// AnomalyRules holds the thresholds of the check-in anomaly detector. A zero
+// threshold disables its rule.
+type AnomalyRules struct {
+	// More check-ins than this by one staff member within a minute
+	MaxStaffCheckInsPerMinute int
+	// Tolerance around opening hours, for early arrivals and late closings
+	OpeningHoursGrace time.Duration
+	// Check-ins of one user at establishments at least TravelMinDistanceMeters
+	// apart within TravelWindow
+	TravelWindow            time.Duration
+	TravelMinDistanceMeters float64
+}
+
+// ImpossibleTravel reports whether checking in at both outings' establishments
+// means the account was used in two distant places within the travel window,
+// with the distance between them
+func (r AnomalyRules) ImpossibleTravel(a, b *Outing) (float64, bool) {
+	if r.TravelWindow <= 0 || a.CheckIn() == nil || b.CheckIn() == nil {
+		return 0, false
+	}
+
+	elapsed := a.CheckIn().CheckedInAt().Sub(b.CheckIn().CheckedInAt())
+	if elapsed < 0 {
+		elapsed = -elapsed
+	}
+	if elapsed > r.TravelWindow {
+		return 0, false
+	}
+
+	distance := DistanceMeters(
+		a.Offer().Latitude(), a.Offer().Longitude(),
+		b.Offer().Latitude(), b.Offer().Longitude(),
+	)
+	return distance, distance >= r.TravelMinDistanceMeters
+}
+
+// =============================================================================
+// OPENING HOURS
+// =============================================================================
+
+// OpeningPeriod is when an establishment opens on a day of the week. A close
+// time before the open time ends the period the next day.
+type OpeningPeriod struct {
+	day         time.Weekday
+	openMinute  int
+	closeMinute int
+}
+
+// NewOpeningPeriod creates a period from "HH:MM" times
+func NewOpeningPeriod(day time.Weekday, open, close string) (OpeningPeriod, error) {
+	openMinute, err := parseMinuteOfDay(open)
+	if err != nil {
+		return OpeningPeriod{}, err
+	}
+	closeMinute, err := parseMinuteOfDay(close)
+	if err != nil {
+		return OpeningPeriod{}, err
+	}
+	return OpeningPeriod{day: day, openMinute: openMinute, closeMinute: closeMinute}, nil
+}
+
+func (p OpeningPeriod) Day() time.Weekday { return p.day }
+
+// on returns the period's bounds when it opens on the given day
+func (p OpeningPeriod) on(day time.Time) (time.Time, time.Time) {
+	year, month, date := day.Date()
+	start := time.Date(year, month, date, p.openMinute/60, p.openMinute%60, 0, 0, day.Location())
+	end := time.Date(year, month, date, p.closeMinute/60, p.closeMinute%60, 0, 0, day.Location())
+	if !end.After(start) {
+		end = time.Date(year, month, date+1, p.closeMinute/60, p.closeMinute%60, 0, 0, day.Location())
+	}
+	return start, end
+}
+
+// OpeningHours is the weekly opening schedule of an establishment, in its
+// timezone. Days without a period are closed.
+type OpeningHours struct {
+	periods  []OpeningPeriod
+	location *time.Location
+}
+
+func NewOpeningHours(periods []OpeningPeriod, location *time.Location) OpeningHours {
+	if location == nil {
+		location = time.UTC
+	}
+	return OpeningHours{
+		periods:  periods,
+		location: location,
+	}
+}
+
+func (h OpeningHours) Periods() []OpeningPeriod { return h.periods }
+func (h OpeningHours) Location() *time.Location { return h.location }
+
+// IsOpenAt reports whether at falls within an opening period widened by grace
+// on both ends
+func (h OpeningHours) IsOpenAt(at time.Time, grace time.Duration) bool {
+	local := at.In(h.location)
+	for _, period := range h.periods {
+		// With grace or past midnight, a period opening the day before or
+		// after may cover at
+		for _, offset := range []int{-1, 0, 1} {
+			day := local.AddDate(0, 0, offset)
+			if day.Weekday() != period.day {
+				continue
+			}
+			start, end := period.on(day)
+			if !at.Before(start.Add(-grace)) && !at.After(end.Add(grace)) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func parseMinuteOfDay(value string) (int, error) {
+	t, err := time.Parse("15:04", value)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %q", ErrInvalidOpeningHours, value)
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
@@ -0,0 +1,157 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+// =============================================================================
+// Anomaly Tests
+// =============================================================================
+
+func TestOpeningHours_IsOpenAt(t *testing.T) {
+	paris, err := time.LoadLocation("Europe/Paris")
+	if err != nil {
+		t.Skipf("timezone data unavailable: %v", err)
+	}
+	lunch, _ := NewOpeningPeriod(time.Monday, "12:00", "14:30")
+	night, _ := NewOpeningPeriod(time.Friday, "22:00", "02:00")
+	hours := NewOpeningHours([]OpeningPeriod{lunch, night}, paris)
+
+	// 2026-03-02 is a Monday
+	at := func(day, hour, minute int) time.Time {
+		return time.Date(2026, 3, day, hour, minute, 0, 0, paris)
+	}
+
+	tests := []struct {
+		name  string
+		at    time.Time
+		grace time.Duration
+		want  bool
+	}{
+		{"during lunch", at(2, 13, 0), 0, true},
+		{"before opening", at(2, 11, 0), 0, false},
+		{"early within grace", at(2, 11, 45), 30 * time.Minute, true},
+		{"late within grace", at(2, 14, 50), 30 * time.Minute, true},
+		{"late beyond grace", at(2, 15, 10), 30 * time.Minute, false},
+		{"closed day", at(3, 13, 0), 0, false},
+		{"past midnight", at(7, 1, 30), 0, true},
+		{"after closing past midnight", at(7, 3, 0), 0, false},
+		{"in another timezone", at(2, 13, 0).UTC(), 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := hours.IsOpenAt(tt.at, tt.grace); got != tt.want {
+				t.Errorf("IsOpenAt(%v) = %v, want %v", tt.at, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewOpeningPeriod_Invalid(t *testing.T) {
+	for _, value := range []string{"", "25:00", "12h30", "noon"} {
+		if _, err := NewOpeningPeriod(time.Monday, value, "14:00"); !errors.Is(err, ErrInvalidOpeningHours) {
+			t.Errorf("NewOpeningPeriod(%q) error = %v, want %v", value, err, ErrInvalidOpeningHours)
+		}
+	}
+}
+
+func TestAnomalyRules_ImpossibleTravel(t *testing.T) {
+	rules := AnomalyRules{TravelWindow: 20 * time.Minute, TravelMinDistanceMeters: 15000}
+
+	paris := createTestOuting()
+	_ = paris.CheckInManual("staff-123", nil, nil, nil)
+
+	lyonOffer := NewOfferSnapshot(
+		"offer-999", "partner-999", "est-999",
+		"Lyon Offer", "Test Description",
+		"percentage", 20,
+		"restaurant",
+		"Lyon Restaurant", "1 Rue de Lyon",
+		45.7640, 4.8357,
+		"",
+	)
+	lyon, _ := NewOuting("user-123", lyonOffer, createTestUserSnapshot(), 30)
+	_ = lyon.CheckInManual("staff-999", nil, nil, nil)
+
+	distance, ok := rules.ImpossibleTravel(paris, lyon)
+	if !ok {
+		t.Errorf("ImpossibleTravel() = false, want true for check-ins %.0f m apart", distance)
+	}
+	if distance < 390000 || distance > 400000 {
+		t.Errorf("ImpossibleTravel() distance = %.0f, want ~392 km", distance)
+	}
+
+	nearby := createTestOuting()
+	_ = nearby.CheckInManual("staff-123", nil, nil, nil)
+	if _, ok := rules.ImpossibleTravel(paris, nearby); ok {
+		t.Error("ImpossibleTravel() should not flag check-ins at the same place")
+	}
+
+	if _, ok := rules.ImpossibleTravel(paris, createTestOuting()); ok {
+		t.Error("ImpossibleTravel() should ignore outings not checked in")
+	}
+	if _, ok := (AnomalyRules{}).ImpossibleTravel(paris, lyon); ok {
+		t.Error("ImpossibleTravel() should be disabled without a travel window")
+	}
+}
+
+func TestOuting_FlagAnomaly(t *testing.T) {
+	outing := createTestOuting()
+
+	if outing.FlagAnomaly(AnomalyStaffCheckInBurst, "") {
+		t.Error("FlagAnomaly() should not flag an outing not checked in")
+	}
+
+	_ = outing.CheckInManual("staff-123", nil, nil, nil)
+	outing.ClearDomainEvents()
+	timelineLen := len(outing.Timeline())
+
+	if !outing.FlagAnomaly(AnomalyStaffCheckInBurst, "7 check-ins within a minute") {
+		t.Fatal("FlagAnomaly() = false, want true")
+	}
+	checkIn := outing.CheckIn()
+	if !checkIn.FlaggedForReview() || checkIn.ReviewReason() != string(AnomalyStaffCheckInBurst) {
+		t.Errorf("FlagAnomaly() flagged = %v (%q), want true (%q)",
+			checkIn.FlaggedForReview(), checkIn.ReviewReason(), AnomalyStaffCheckInBurst)
+	}
+	if len(outing.Timeline()) != timelineLen+1 {
+		t.Fatalf("FlagAnomaly() should add a timeline entry")
+	}
+	entry := outing.Timeline()[len(outing.Timeline())-1]
+	if entry.Actor() != "system" || entry.Metadata()["reason"] != string(AnomalyStaffCheckInBurst) {
+		t.Errorf("FlagAnomaly() timeline entry = %s %v", entry.Actor(), entry.Metadata())
+	}
+	if len(outing.GetDomainEvents()) != 1 {
+		t.Errorf("FlagAnomaly() should emit 1 event, got %d", len(outing.GetDomainEvents()))
+	}
+
+	// Redelivered events must not flag twice
+	if outing.FlagAnomaly(AnomalyStaffCheckInBurst, "7 check-ins within a minute") {
+		t.Error("FlagAnomaly() should not flag the same reason twice")
+	}
+
+	// The first reason stays the review reason
+	if !outing.FlagAnomaly(AnomalyImpossibleTravel, "") {
+		t.Fatal("FlagAnomaly() with another reason = false, want true")
+	}
+	if len(checkIn.Anomalies()) != 2 || checkIn.ReviewReason() != string(AnomalyStaffCheckInBurst) {
+		t.Errorf("FlagAnomaly() anomalies = %d, review reason = %q", len(checkIn.Anomalies()), checkIn.ReviewReason())
+	}
+}
+
+func TestOuting_CheckIn_GeofenceAnomaly(t *testing.T) {
+	reject, _ := NewGeofence(200, GeofenceModeReject)
+	outing := createTestOuting()
+
+	_ = outing.CheckInManual("staff-123", nil, nil, &reject)
+
+	if !outing.CheckIn().HasAnomaly(AnomalyLocationMissing) {
+		t.Errorf("CheckInManual() anomalies = %v, want %s", outing.CheckIn().Anomalies(), AnomalyLocationMissing)
+	}
+	if outing.FlagAnomaly(AnomalyLocationMissing, "") {
+		t.Error("FlagAnomaly() should not repeat the anomaly found at check-in")
+	}
+}
@@ -0,0 +1,61 @@
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+// =============================================================================
+// BILL
+// =============================================================================
+
+// Partner staff may declare what the user's bill came to before the discount,
+// at check-in or once the user has paid. The savings are what the offer's
+// discount took off that bill; they feed the user's savings and partner
+// reporting.
+
+var (
+	ErrInvalidBillAmount  = errors.New("bill amount must be positive and at most 50,000.00")
+	ErrOutingNotCheckedIn = errors.New("outing has not been checked in")
+)
+
+// MaxBillAmountCents bounds declared bills so a mistyped amount cannot inflate
+// savings and reports
+const MaxBillAmountCents = 5_000_000
+
+// Bill is the amount declared for a checked-in outing, in cents
+type Bill struct {
+	amountCents  int64 // before discount
+	savingsCents int64
+	recordedAt   time.Time
+	recordedBy   string // UserID of staff member
+}
+
+// NewBill computes the savings of a bill of amountCents with the offer's
+// discount
+func NewBill(amountCents int64, offer OfferSnapshot, recordedBy string) (Bill, error) {
+	if amountCents <= 0 || amountCents > MaxBillAmountCents {
+		return Bill{}, ErrInvalidBillAmount
+	}
+	return Bill{
+		amountCents:  amountCents,
+		savingsCents: amountCents - offer.ApplyDiscount(amountCents),
+		recordedAt:   time.Now(),
+		recordedBy:   recordedBy,
+	}, nil
+}
+
+func ReconstructBill(amountCents, savingsCents int64, recordedAt time.Time, recordedBy string) Bill {
+	return Bill{
+		amountCents:  amountCents,
+		savingsCents: savingsCents,
+		recordedAt:   recordedAt,
+		recordedBy:   recordedBy,
+	}
+}
+
+func (b Bill) AmountCents() int64    { return b.amountCents }
+func (b Bill) SavingsCents() int64   { return b.savingsCents }
+func (b Bill) FinalCents() int64     { return b.amountCents - b.savingsCents }
+func (b Bill) RecordedAt() time.Time { return b.recordedAt }
+func (b Bill) RecordedBy() string    { return b.recordedBy }
@@ -0,0 +1,86 @@
+package domain
+
+import (
+	"testing"
+)
+
+// =============================================================================
+// Bill Tests
+// =============================================================================
+
+func TestOfferSnapshot_ApplyDiscount(t *testing.T) {
+	tests := []struct {
+		name          string
+		discountType  string
+		discountValue int
+		price         int64
+		want          int64
+	}{
+		{"percentage", "percentage", 20, 5000, 4000},
+		{"percentage rounds reduction down", "percentage", 15, 999, 850},
+		{"fixed", "fixed", 1000, 5000, 4000},
+		{"fixed above price", "fixed", 1000, 600, 0},
+		{"formula", "formula", 0, 5000, 5000},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			offer := NewOfferSnapshot(
+				"offer-123", "partner-456", "est-789",
+				"Test Offer", "Test Description",
+				tt.discountType, tt.discountValue,
+				"restaurant",
+				"Test Restaurant", "123 Test St",
+				48.8566, 2.3522,
+				"",
+			)
+			if got := offer.ApplyDiscount(tt.price); got != tt.want {
+				t.Errorf("OfferSnapshot.ApplyDiscount(%d) = %v, want %v", tt.price, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOuting_RecordBill(t *testing.T) {
+	outing := createTestOuting()
+
+	if err := outing.RecordBill(5000, "staff-123"); err != ErrOutingNotCheckedIn {
+		t.Errorf("RecordBill() before check-in error = %v, want %v", err, ErrOutingNotCheckedIn)
+	}
+
+	_ = outing.CheckInManual("staff-123", nil, nil, nil)
+	outing.ClearDomainEvents()
+
+	for _, amount := range []int64{0, -100, MaxBillAmountCents + 1} {
+		if err := outing.RecordBill(amount, "staff-123"); err != ErrInvalidBillAmount {
+			t.Errorf("RecordBill(%d) error = %v, want %v", amount, err, ErrInvalidBillAmount)
+		}
+	}
+
+	if err := outing.RecordBill(5000, "staff-123"); err != nil {
+		t.Fatalf("RecordBill() error = %v, want nil", err)
+	}
+	bill := outing.CheckIn().Bill()
+	if bill == nil {
+		t.Fatal("RecordBill() should store the bill on the check-in")
+	}
+	if bill.AmountCents() != 5000 || bill.SavingsCents() != 1000 || bill.FinalCents() != 4000 {
+		t.Errorf("RecordBill() bill = %d/%d/%d, want 5000/1000/4000",
+			bill.AmountCents(), bill.SavingsCents(), bill.FinalCents())
+	}
+	if len(outing.GetDomainEvents()) != 1 {
+		t.Errorf("RecordBill() should emit 1 event, got %d", len(outing.GetDomainEvents()))
+	}
+
+	// Correction replaces the amount and keeps track of the previous one
+	if err := outing.RecordBill(6000, "staff-456"); err != nil {
+		t.Fatalf("RecordBill() correction error = %v, want nil", err)
+	}
+	if outing.CheckIn().Bill().SavingsCents() != 1200 {
+		t.Errorf("RecordBill() corrected savings = %d, want 1200", outing.CheckIn().Bill().SavingsCents())
+	}
+	last := outing.Timeline()[len(outing.Timeline())-1]
+	if last.Metadata()["previous_amount_cents"] != int64(5000) {
+		t.Errorf("RecordBill() previous amount = %v, want 5000", last.Metadata()["previous_amount_cents"])
+	}
+}
@@ -0,0 +1,307 @@
+package domain
+
+import (
+	"crypto/ed25519"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// =============================================================================
+// OFFLINE CHECK-IN TOKENS
+// =============================================================================
+
+// Offline check-in tokens let partner scanners validate an outing without
+// connectivity. They are signed with Ed25519 so scanners only need the public
+// keys, and are embedded in the QR payload after the rotating online part.
+
+var ErrCheckInKeysNotConfigured = errors.New("check-in signing keys are not configured")
+
+const (
+	checkInTokenPrefix = "yst1"
+
+	// qrTokenSeparator separates the rotating QR payload from the offline token
+	qrTokenSeparator = "~"
+)
+
+// CheckInSigningKey is a versioned Ed25519 key. A retired key is still
+// accepted for the key set's grace period after RetiredAt.
+type CheckInSigningKey struct {
+	ID         string
+	PrivateKey ed25519.PrivateKey
+	RetiredAt  *time.Time
+}
+
+// CheckInPublicKey is what scanners need to verify offline tokens
+type CheckInPublicKey struct {
+	ID        string
+	Key       ed25519.PublicKey
+	RetiredAt *time.Time
+}
+
+// CheckInKeySet signs tokens with the active key and verifies them with any
+// key that is active or still within its grace period
+type CheckInKeySet struct {
+	active      CheckInSigningKey
+	keys        map[string]CheckInSigningKey
+	gracePeriod time.Duration
+}
+
+func NewCheckInKeySet(keys []CheckInSigningKey, gracePeriod time.Duration) (*CheckInKeySet, error) {
+	set := &CheckInKeySet{
+		keys:        make(map[string]CheckInSigningKey, len(keys)),
+		gracePeriod: gracePeriod,
+	}
+	for _, key := range keys {
+		if key.ID == "" || len(key.PrivateKey) != ed25519.PrivateKeySize {
+			return nil, fmt.Errorf("invalid check-in key %q", key.ID)
+		}
+		if _, exists := set.keys[key.ID]; exists {
+			return nil, fmt.Errorf("duplicate check-in key %q", key.ID)
+		}
+		if key.RetiredAt == nil {
+			if set.active.ID != "" {
+				return nil, fmt.Errorf("check-in keys %q and %q are both active", set.active.ID, key.ID)
+			}
+			set.active = key
+		}
+		set.keys[key.ID] = key
+	}
+	if set.active.ID == "" {
+		return nil, fmt.Errorf("no active check-in key")
+	}
+
+	return set, nil
+}
+
+// PublicKeys returns the public keys scanners should trust at the given time
+func (s *CheckInKeySet) PublicKeys(at time.Time) []CheckInPublicKey {
+	keys := make([]CheckInPublicKey, 0, len(s.keys))
+	for _, key := range s.keys {
+		if _, ok := s.acceptedKey(key.ID, at); !ok {
+			continue
+		}
+		keys = append(keys, CheckInPublicKey{
+			ID:        key.ID,
+			Key:       key.PrivateKey.Public().(ed25519.PublicKey),
+			RetiredAt: key.RetiredAt,
+		})
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i].ID < keys[j].ID })
+	return keys
+}
+
+func (s *CheckInKeySet) acceptedKey(id string, at time.Time) (CheckInSigningKey, bool) {
+	key, ok := s.keys[id]
+	if !ok {
+		return CheckInSigningKey{}, false
+	}
+	if key.RetiredAt != nil && at.After(key.RetiredAt.Add(s.gracePeriod)) {
+		return CheckInSigningKey{}, false
+	}
+	return key, true
+}
+
+// CheckInKeys are the keys behind what users present at check-in: the ring
+// signing rotating QR payloads and the set signing offline tokens. They are
+// built once at startup and handed to whatever renders or verifies them.
+type CheckInKeys struct {
+	qr     *QRKeyRing
+	tokens *CheckInKeySet
+}
+
+func NewCheckInKeys(qr *QRKeyRing, tokens *CheckInKeySet) *CheckInKeys {
+	return &CheckInKeys{qr: qr, tokens: tokens}
+}
+
+// QR returns the QR key ring, nil when keys is nil
+func (k *CheckInKeys) QR() *QRKeyRing {
+	if k == nil {
+		return nil
+	}
+	return k.qr
+}
+
+// Tokens returns the offline token key set, nil when keys is nil
+func (k *CheckInKeys) Tokens() *CheckInKeySet {
+	if k == nil {
+		return nil
+	}
+	return k.tokens
+}
+
+// CheckInTokenClaims is the signed content of an offline token
+type CheckInTokenClaims struct {
+	KeyID           string `json:"kid"`
+	OutingID        string `json:"oid"`
+	OfferID         string `json:"ofr"`
+	EstablishmentID string `json:"est"`
+	NotBefore       int64  `json:"nbf"`
+	ExpiresAt       int64  `json:"exp"`
+
+	// UserID and QRCodeHash bind the token to the outing's owner and QR code
+	// when it was issued: a transfer issues a new code, so the previous
+	// owner's tokens no longer match. Scanners can also compare the hash with
+	// the code in front of the token.
+	UserID     string `json:"sub"`
+	QRCodeHash string `json:"qrh"`
+
+	// Pass marks the token of a wallet pass, which stands in for the
+	// rotating payload a pass cannot display
+	Pass bool `json:"pass,omitempty"`
+}
+
+// IssueToken signs a token valid over the outing's check-in window.
+// Format: "yst1.<base64url claims>.<base64url signature>".
+func (s *CheckInKeySet) IssueToken(outing *Outing) (string, error) {
+	return s.issue(outing, false)
+}
+
+// IssuePassToken signs the token of the outing's wallet pass
+func (s *CheckInKeySet) IssuePassToken(outing *Outing) (string, error) {
+	return s.issue(outing, true)
+}
+
+func (s *CheckInKeySet) issue(outing *Outing, pass bool) (string, error) {
+	if s == nil {
+		return "", ErrCheckInKeysNotConfigured
+	}
+
+	notBefore := outing.BookedAt()
+	if outing.Slot() != nil {
+		notBefore = outing.Slot().StartsAt().Add(-slotCheckInLeadTime)
+	}
+
+	claims, err := json.Marshal(CheckInTokenClaims{
+		KeyID:           s.active.ID,
+		OutingID:        outing.ID(),
+		OfferID:         outing.Offer().OfferID(),
+		EstablishmentID: outing.Offer().EstablishmentID(),
+		NotBefore:       notBefore.Unix(),
+		ExpiresAt:       outing.ExpiresAt().Unix(),
+		UserID:          outing.UserID(),
+		QRCodeHash:      qrCodeHash(outing.QRCode().Code()),
+		Pass:            pass,
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to encode check-in token: %w", err)
+	}
+
+	signed := checkInTokenPrefix + "." + base64.RawURLEncoding.EncodeToString(claims)
+	signature := ed25519.Sign(s.active.PrivateKey, []byte(signed))
+
+	return signed + "." + base64.RawURLEncoding.EncodeToString(signature), nil
+}
+
+// ParseToken verifies the token signature and returns its claims. The
+// validity window is left to the caller, which knows when it was scanned.
+func (s *CheckInKeySet) ParseToken(token string, at time.Time) (*CheckInTokenClaims, error) {
+	if s == nil {
+		return nil, ErrCheckInKeysNotConfigured
+	}
+
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 || parts[0] != checkInTokenPrefix {
+		return nil, ErrInvalidQRCode
+	}
+
+	rawClaims, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return nil, ErrInvalidQRCode
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, ErrInvalidQRCode
+	}
+
+	var claims CheckInTokenClaims
+	if err := json.Unmarshal(rawClaims, &claims); err != nil {
+		return nil, ErrInvalidQRCode
+	}
+
+	key, ok := s.acceptedKey(claims.KeyID, at)
+	if !ok {
+		return nil, ErrInvalidQRCode
+	}
+	publicKey := key.PrivateKey.Public().(ed25519.PublicKey)
+	if !ed25519.Verify(publicKey, []byte(parts[0]+"."+parts[1]), signature) {
+		return nil, ErrInvalidQRCode
+	}
+
+	return &claims, nil
+}
+
+// splitQRPayload separates the rotating online payload from the offline token
+func splitQRPayload(scanned string) (online, token string) {
+	online, token, _ = strings.Cut(scanned, qrTokenSeparator)
+	return online, token
+}
+
+// QRPayload returns the full value to render in the outing's QR code: the
+// rotating online payload followed by the offline token.
+func (o *Outing) QRPayload(keys *CheckInKeys, at time.Time) (string, time.Time, error) {
+	online, refreshAt, err := o.qrCode.Payload(keys.QR(), at)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	token, err := keys.Tokens().IssueToken(o)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	return online + qrTokenSeparator + token, refreshAt, nil
+}
+
+// PassPayload returns the static value to put in a wallet pass barcode: the
+// bare code followed by a pass token. Passes cannot rotate their barcode, so
+// the signed pass token replaces the rotating payload.
+func (o *Outing) PassPayload(keys *CheckInKeys) (string, error) {
+	token, err := keys.Tokens().IssuePassToken(o)
+	if err != nil {
+		return "", err
+	}
+
+	return o.qrCode.code + qrTokenSeparator + token, nil
+}
+
+// verifyScan checks a scanned value at the given time, known within skew:
+// either the rotating QR payload or the static credential of a wallet pass
+func (o *Outing) verifyScan(keys *CheckInKeys, scanned string, at time.Time, skew time.Duration) error {
+	online, token := splitQRPayload(scanned)
+	if online != o.qrCode.code || token == "" {
+		return o.qrCode.verify(keys.QR(), scanned, at, skew)
+	}
+
+	claims, err := keys.Tokens().ParseToken(token, at)
+	if err != nil {
+		return err
+	}
+	if !claims.Pass || !o.matchesToken(claims) {
+		return ErrInvalidQRCode
+	}
+	return nil
+}
+
+// matchesToken reports whether the token was issued for this outing, its
+// current owner and its current QR code
+func (o *Outing) matchesToken(claims *CheckInTokenClaims) bool {
+	return claims.OutingID == o.id &&
+		claims.OfferID == o.offer.OfferID() &&
+		claims.EstablishmentID == o.offer.EstablishmentID() &&
+		claims.UserID == o.userID &&
+		subtle.ConstantTimeCompare([]byte(claims.QRCodeHash), []byte(qrCodeHash(o.qrCode.code))) == 1
+}
+
+// qrCodeHash identifies a QR code in a token without revealing it: the
+// base64url of the first 16 bytes of its SHA-256
+func qrCodeHash(code string) string {
+	sum := sha256.Sum256([]byte(code))
+	return base64.RawURLEncoding.EncodeToString(sum[:16])
+}
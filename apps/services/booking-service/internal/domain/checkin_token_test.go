@@ -0,0 +1,245 @@
+package domain
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// =============================================================================
+// Offline Check-in Tests
+// =============================================================================
+
+func TestCheckInToken_RoundTrip(t *testing.T) {
+	keys := newTestCheckInKeys(t)
+	outing := createTestOuting()
+
+	token, err := keys.Tokens().IssueToken(outing)
+	if err != nil {
+		t.Fatalf("keys.Tokens().IssueToken() error = %v, want nil", err)
+	}
+
+	claims, err := keys.Tokens().ParseToken(token, time.Now())
+	if err != nil {
+		t.Fatalf("keys.Tokens().ParseToken() error = %v, want nil", err)
+	}
+	if claims.KeyID != "k2" {
+		t.Errorf("keys.Tokens().ParseToken() kid = %v, want k2", claims.KeyID)
+	}
+	if claims.OutingID != outing.ID() || claims.OfferID != "offer-123" || claims.EstablishmentID != "est-789" {
+		t.Errorf("keys.Tokens().ParseToken() claims = %+v, do not match outing", claims)
+	}
+	if claims.ExpiresAt != outing.ExpiresAt().Unix() {
+		t.Errorf("keys.Tokens().ParseToken() exp = %v, want %v", claims.ExpiresAt, outing.ExpiresAt().Unix())
+	}
+}
+
+func TestCheckInToken_Tampered(t *testing.T) {
+	keys := newTestCheckInKeys(t)
+	token, _ := keys.Tokens().IssueToken(createTestOuting())
+	other, _ := keys.Tokens().IssueToken(createTestOuting())
+
+	parts := strings.Split(token, ".")
+	otherParts := strings.Split(other, ".")
+	swapped := parts[0] + "." + otherParts[1] + "." + parts[2]
+
+	if _, err := keys.Tokens().ParseToken(swapped, time.Now()); err != ErrInvalidQRCode {
+		t.Errorf("keys.Tokens().ParseToken() error = %v, want %v", err, ErrInvalidQRCode)
+	}
+	if _, err := keys.Tokens().ParseToken("yst1.garbage", time.Now()); err != ErrInvalidQRCode {
+		t.Errorf("keys.Tokens().ParseToken() error = %v, want %v", err, ErrInvalidQRCode)
+	}
+}
+
+func TestCheckInKeySet_PublicKeys(t *testing.T) {
+	keys := newTestCheckInKeys(t)
+	publicKeys := keys.Tokens().PublicKeys(time.Now())
+	if len(publicKeys) != 2 || publicKeys[0].ID != "k1" || publicKeys[1].ID != "k2" {
+		t.Fatalf("CheckInKeySet.PublicKeys() = %v, want k1 and k2", publicKeys)
+	}
+
+	// The retired key drops out once its grace period ends
+	publicKeys = keys.Tokens().PublicKeys(time.Now().Add(48 * time.Hour))
+	if len(publicKeys) != 1 || publicKeys[0].ID != "k2" {
+		t.Errorf("CheckInKeySet.PublicKeys() after grace = %v, want only k2", publicKeys)
+	}
+}
+
+func TestOuting_CheckInWithQROffline(t *testing.T) {
+	keys := newTestCheckInKeys(t)
+	outing := createTestOuting()
+	payload, _, err := outing.QRPayload(keys, time.Now())
+	if err != nil {
+		t.Fatalf("QRPayload() error = %v, want nil", err)
+	}
+	scannedAt := outing.BookedAt().Add(time.Second).Truncate(time.Second)
+
+	if err := outing.CheckInWithQROffline(keys, payload, "staff-123", scannedAt, nil, nil, nil); err != nil {
+		t.Fatalf("CheckInWithQROffline() error = %v, want nil", err)
+	}
+	if outing.CheckIn().Method() != CheckInMethodOfflineScan {
+		t.Errorf("CheckInWithQROffline() method = %v, want %v", outing.CheckIn().Method(), CheckInMethodOfflineScan)
+	}
+	if !outing.CheckIn().CheckedInAt().Equal(scannedAt) {
+		t.Errorf("CheckInWithQROffline() checkedInAt = %v, want %v", outing.CheckIn().CheckedInAt(), scannedAt)
+	}
+
+	if err := outing.CheckInWithQROffline(keys, payload, "staff-456", scannedAt, nil, nil, nil); err != ErrOutingAlreadyUsed {
+		t.Errorf("CheckInWithQROffline() second scan error = %v, want %v", err, ErrOutingAlreadyUsed)
+	}
+}
+
+func TestOuting_CheckInWithQROffline_Conflicts(t *testing.T) {
+	keys := newTestCheckInKeys(t)
+
+	t.Run("token of another outing", func(t *testing.T) {
+		outing := createTestOuting()
+		payload, _, _ := createTestOuting().QRPayload(keys, time.Now())
+
+		if err := outing.CheckInWithQROffline(keys, payload, "staff-123", time.Now(), nil, nil, nil); err != ErrInvalidQRCode {
+			t.Errorf("CheckInWithQROffline() error = %v, want %v", err, ErrInvalidQRCode)
+		}
+	})
+
+	t.Run("scanned after expiry", func(t *testing.T) {
+		outing := createTestOuting()
+		scannedAt := outing.ExpiresAt().Add(time.Minute)
+		payload, _, _ := outing.QRPayload(keys, scannedAt)
+
+		if err := outing.CheckInWithQROffline(keys, payload, "staff-123", scannedAt, nil, nil, nil); err != ErrOutingExpired {
+			t.Errorf("CheckInWithQROffline() error = %v, want %v", err, ErrOutingExpired)
+		}
+	})
+
+	t.Run("cancelled before sync", func(t *testing.T) {
+		outing := createTestOuting()
+		payload, _, _ := outing.QRPayload(keys, time.Now())
+		_ = outing.Cancel(CancellationActorUser, "", nil)
+
+		if err := outing.CheckInWithQROffline(keys, payload, "staff-123", time.Now(), nil, nil, nil); err != ErrOutingCancelled {
+			t.Errorf("CheckInWithQROffline() error = %v, want %v", err, ErrOutingCancelled)
+		}
+	})
+}
+
+func TestOuting_CheckInWithQROffline_RotatingPayload(t *testing.T) {
+	keys := newTestCheckInKeys(t)
+	scannedAt := time.Now()
+
+	tests := []struct {
+		name       string
+		renderedAt time.Time
+		want       error
+	}{
+		{"rendered at scan time", scannedAt, nil},
+		{"scanner clock behind", scannedAt.Add(90 * time.Second), nil},
+		{"scanner clock ahead", scannedAt.Add(-90 * time.Second), nil},
+		{"old screenshot", scannedAt.Add(-10 * time.Minute), ErrQRCodeStale},
+		{"rendered in the future", scannedAt.Add(10 * time.Minute), ErrQRCodeStale},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			outing := createTestOuting()
+			payload, _, _ := outing.QRPayload(keys, tt.renderedAt)
+
+			if err := outing.CheckInWithQROffline(keys, payload, "staff-123", scannedAt, nil, nil, nil); err != tt.want {
+				t.Errorf("CheckInWithQROffline() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestOuting_CheckInWithQR_CombinedPayload(t *testing.T) {
+	keys := newTestCheckInKeys(t)
+	outing := createTestOuting()
+	payload, _, _ := outing.QRPayload(keys, time.Now())
+
+	if err := outing.CheckInWithQR(keys, payload, "staff-123", nil, nil, nil); err != nil {
+		t.Errorf("CheckInWithQR() error = %v, want nil", err)
+	}
+}
+
+func TestOuting_CheckInWithPassPayload(t *testing.T) {
+	keys := newTestCheckInKeys(t)
+
+	t.Run("online", func(t *testing.T) {
+		outing := createTestOuting()
+		payload, err := outing.PassPayload(keys)
+		if err != nil {
+			t.Fatalf("PassPayload() error = %v, want nil", err)
+		}
+
+		if err := outing.CheckInWithQR(keys, payload, "staff-123", nil, nil, nil); err != nil {
+			t.Errorf("CheckInWithQR() error = %v, want nil", err)
+		}
+	})
+
+	t.Run("offline", func(t *testing.T) {
+		outing := createTestOuting()
+		payload, _ := outing.PassPayload(keys)
+
+		if err := outing.CheckInWithQROffline(keys, payload, "staff-123", time.Now(), nil, nil, nil); err != nil {
+			t.Errorf("CheckInWithQROffline() error = %v, want nil", err)
+		}
+	})
+
+	t.Run("offline token is not a pass", func(t *testing.T) {
+		outing := createTestOuting()
+		token, _ := keys.Tokens().IssueToken(outing)
+
+		if err := outing.CheckInWithQR(keys, outing.QRCode().Code()+qrTokenSeparator+token, "staff-123", nil, nil, nil); err != ErrInvalidQRCode {
+			t.Errorf("CheckInWithQR() error = %v, want %v", err, ErrInvalidQRCode)
+		}
+	})
+
+	t.Run("pass of another outing", func(t *testing.T) {
+		outing := createTestOuting()
+		other, _ := createTestOuting().PassPayload(keys)
+		_, token := splitQRPayload(other)
+
+		if err := outing.CheckInWithQR(keys, outing.QRCode().Code()+qrTokenSeparator+token, "staff-123", nil, nil, nil); err != ErrInvalidQRCode {
+			t.Errorf("CheckInWithQR() error = %v, want %v", err, ErrInvalidQRCode)
+		}
+	})
+}
+
+func TestOuting_CheckInAfterTransfer(t *testing.T) {
+	keys := newTestCheckInKeys(t)
+	outing := createTestOuting()
+	payload, _, _ := outing.QRPayload(keys, time.Now())
+	passPayload, _ := outing.PassPayload(keys)
+
+	if err := outing.InitiateTransfer("user-123", "user-456"); err != nil {
+		t.Fatalf("InitiateTransfer() error = %v, want nil", err)
+	}
+	if err := outing.AcceptTransfer(NewUserSnapshot("user-456", "Jane", "Doe", "jane@example.com")); err != nil {
+		t.Fatalf("AcceptTransfer() error = %v, want nil", err)
+	}
+
+	t.Run("previous owner's payload", func(t *testing.T) {
+		if err := outing.CheckInWithQROffline(keys, payload, "staff-123", time.Now(), nil, nil, nil); err != ErrInvalidQRCode {
+			t.Errorf("CheckInWithQROffline() error = %v, want %v", err, ErrInvalidQRCode)
+		}
+		if err := outing.CheckInWithQR(keys, passPayload, "staff-123", nil, nil, nil); err != ErrInvalidQRCode {
+			t.Errorf("CheckInWithQR() with pass error = %v, want %v", err, ErrInvalidQRCode)
+		}
+	})
+
+	t.Run("previous owner's token on the new code", func(t *testing.T) {
+		_, token := splitQRPayload(passPayload)
+		spliced := outing.QRCode().Code() + qrTokenSeparator + token
+
+		if err := outing.CheckInWithQR(keys, spliced, "staff-123", nil, nil, nil); err != ErrInvalidQRCode {
+			t.Errorf("CheckInWithQR() error = %v, want %v", err, ErrInvalidQRCode)
+		}
+	})
+
+	t.Run("new owner's payload", func(t *testing.T) {
+		payload, _, _ := outing.QRPayload(keys, time.Now())
+
+		if err := outing.CheckInWithQROffline(keys, payload, "staff-123", time.Now(), nil, nil, nil); err != nil {
+			t.Errorf("CheckInWithQROffline() error = %v, want nil", err)
+		}
+	})
+}
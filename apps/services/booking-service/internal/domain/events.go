@@ -13,15 +13,17 @@ import (
 
 // OutingBooked is emitted when a new outing is created
 type OutingBooked struct {
-	ID              string    `json:"event_id"`
-	OutingID        string    `json:"outing_id"`
-	UserID          string    `json:"user_id"`
-	OfferID         string    `json:"offer_id"`
-	PartnerID       string    `json:"partner_id"`
-	EstablishmentID string    `json:"establishment_id"`
-	QRCode          string    `json:"qr_code"`
-	ExpiresAt       time.Time `json:"expires_at"`
-	Timestamp       time.Time `json:"timestamp"`
+	ID              string     `json:"event_id"`
+	OutingID        string     `json:"outing_id"`
+	UserID          string     `json:"user_id"`
+	OfferID         string     `json:"offer_id"`
+	PartnerID       string     `json:"partner_id"`
+	EstablishmentID string     `json:"establishment_id"`
+	QRCode          string     `json:"qr_code"`
+	ExpiresAt       time.Time  `json:"expires_at"`
+	SlotStartsAt    *time.Time `json:"slot_starts_at,omitempty"`
+	ConfirmBy       *time.Time `json:"confirm_by,omitempty"` // pending outings only
+	Timestamp       time.Time  `json:"timestamp"`
 }
 
 func NewOutingBookedEvent(outingID, userID, offerID, partnerID, establishmentID, qrCode string, expiresAt time.Time) OutingBooked {
@@ -46,6 +48,143 @@ func (e OutingBooked) AggregateType() string    { return "Outing" }
 func (e OutingBooked) Version() int             { return 1 }
 func (e OutingBooked) Payload() ([]byte, error) { return json.Marshal(e) }
 
+// OutingConfirmed is emitted when the partner approves a pending outing
+type OutingConfirmed struct {
+	ID              string    `json:"event_id"`
+	OutingID        string    `json:"outing_id"`
+	UserID          string    `json:"user_id"`
+	OfferID         string    `json:"offer_id"`
+	PartnerID       string    `json:"partner_id"`
+	EstablishmentID string    `json:"establishment_id"`
+	ConfirmedBy     string    `json:"confirmed_by"`
+	Timestamp       time.Time `json:"timestamp"`
+}
+
+func NewOutingConfirmedEvent(outingID, userID, offerID, partnerID, establishmentID, confirmedBy string) OutingConfirmed {
+	return OutingConfirmed{
+		ID:              uuid.New().String(),
+		OutingID:        outingID,
+		UserID:          userID,
+		OfferID:         offerID,
+		PartnerID:       partnerID,
+		EstablishmentID: establishmentID,
+		ConfirmedBy:     confirmedBy,
+		Timestamp:       time.Now().UTC(),
+	}
+}
+
+func (e OutingConfirmed) EventID() string          { return e.ID }
+func (e OutingConfirmed) EventName() string        { return "outing.confirmed" }
+func (e OutingConfirmed) OccurredAt() time.Time    { return e.Timestamp }
+func (e OutingConfirmed) AggregateID() string      { return e.OutingID }
+func (e OutingConfirmed) AggregateType() string    { return "Outing" }
+func (e OutingConfirmed) Version() int             { return 1 }
+func (e OutingConfirmed) Payload() ([]byte, error) { return json.Marshal(e) }
+
+// OutingRejected is emitted when a pending outing is turned down by the
+// partner, or automatically when the partner did not answer in time
+type OutingRejected struct {
+	ID              string    `json:"event_id"`
+	OutingID        string    `json:"outing_id"`
+	UserID          string    `json:"user_id"`
+	OfferID         string    `json:"offer_id"`
+	PartnerID       string    `json:"partner_id"`
+	EstablishmentID string    `json:"establishment_id"`
+	RejectedBy      string    `json:"rejected_by"`
+	Reason          string    `json:"reason"`
+	Automatic       bool      `json:"automatic"`
+	Timestamp       time.Time `json:"timestamp"`
+}
+
+func NewOutingRejectedEvent(outingID, userID, offerID, partnerID, establishmentID, rejectedBy, reason string, automatic bool) OutingRejected {
+	return OutingRejected{
+		ID:              uuid.New().String(),
+		OutingID:        outingID,
+		UserID:          userID,
+		OfferID:         offerID,
+		PartnerID:       partnerID,
+		EstablishmentID: establishmentID,
+		RejectedBy:      rejectedBy,
+		Reason:          reason,
+		Automatic:       automatic,
+		Timestamp:       time.Now().UTC(),
+	}
+}
+
+func (e OutingRejected) EventID() string          { return e.ID }
+func (e OutingRejected) EventName() string        { return "outing.rejected" }
+func (e OutingRejected) OccurredAt() time.Time    { return e.Timestamp }
+func (e OutingRejected) AggregateID() string      { return e.OutingID }
+func (e OutingRejected) AggregateType() string    { return "Outing" }
+func (e OutingRejected) Version() int             { return 1 }
+func (e OutingRejected) Payload() ([]byte, error) { return json.Marshal(e) }
+
+// OutingTransferInitiated is emitted when the owner offers the outing to
+// another user, who has to accept it
+type OutingTransferInitiated struct {
+	ID          string    `json:"event_id"`
+	OutingID    string    `json:"outing_id"`
+	UserID      string    `json:"user_id"`
+	OfferID     string    `json:"offer_id"`
+	PartnerID   string    `json:"partner_id"`
+	RecipientID string    `json:"recipient_id"`
+	Timestamp   time.Time `json:"timestamp"`
+}
+
+func NewOutingTransferInitiatedEvent(outingID, userID, offerID, partnerID, recipientID string) OutingTransferInitiated {
+	return OutingTransferInitiated{
+		ID:          uuid.New().String(),
+		OutingID:    outingID,
+		UserID:      userID,
+		OfferID:     offerID,
+		PartnerID:   partnerID,
+		RecipientID: recipientID,
+		Timestamp:   time.Now().UTC(),
+	}
+}
+
+func (e OutingTransferInitiated) EventID() string          { return e.ID }
+func (e OutingTransferInitiated) EventName() string        { return "outing.transfer_initiated" }
+func (e OutingTransferInitiated) OccurredAt() time.Time    { return e.Timestamp }
+func (e OutingTransferInitiated) AggregateID() string      { return e.OutingID }
+func (e OutingTransferInitiated) AggregateType() string    { return "Outing" }
+func (e OutingTransferInitiated) Version() int             { return 1 }
+func (e OutingTransferInitiated) Payload() ([]byte, error) { return json.Marshal(e) }
+
+// OutingTransferred is emitted when the recipient accepted a transfer and
+// became the outing's owner
+type OutingTransferred struct {
+	ID              string    `json:"event_id"`
+	OutingID        string    `json:"outing_id"`
+	FromUserID      string    `json:"from_user_id"`
+	ToUserID        string    `json:"to_user_id"`
+	OfferID         string    `json:"offer_id"`
+	PartnerID       string    `json:"partner_id"`
+	EstablishmentID string    `json:"establishment_id"`
+	Timestamp       time.Time `json:"timestamp"`
+}
+
+func NewOutingTransferredEvent(outingID, fromUserID, toUserID, offerID, partnerID, establishmentID string) OutingTransferred {
+	return OutingTransferred{
+		ID:              uuid.New().String(),
+		OutingID:        outingID,
+		FromUserID:      fromUserID,
+		ToUserID:        toUserID,
+		OfferID:         offerID,
+		PartnerID:       partnerID,
+		EstablishmentID: establishmentID,
+		Timestamp:       time.Now().UTC(),
+	}
+}
+
+func (e OutingTransferred) EventID() string          { return e.ID }
+func (e OutingTransferred) EventName() string        { return "outing.transferred" }
+func (e OutingTransferred) OccurredAt() time.Time    { return e.Timestamp }
+func (e OutingTransferred) AggregateID() string      { return e.OutingID }
+func (e OutingTransferred) AggregateType() string    { return "Outing" }
+func (e OutingTransferred) Version() int             { return 1 }
+func (e OutingTransferred) Payload() ([]byte, error) { return json.Marshal(e) }
+
 // OutingCheckedIn is emitted when an outing is checked in
 type OutingCheckedIn struct {
 	ID              string    `json:"event_id"`
@@ -81,6 +220,77 @@ func (e OutingCheckedIn) AggregateType() string    { return "Outing" }
 func (e OutingCheckedIn) Version() int             { return 1 }
 func (e OutingCheckedIn) Payload() ([]byte, error) { return json.Marshal(e) }
 
+// OutingBillRecorded is emitted when staff declare or correct the bill of a
+// checked-in outing
+type OutingBillRecorded struct {
+	ID              string    `json:"event_id"`
+	OutingID        string    `json:"outing_id"`
+	UserID          string    `json:"user_id"`
+	OfferID         string    `json:"offer_id"`
+	PartnerID       string    `json:"partner_id"`
+	EstablishmentID string    `json:"establishment_id"`
+	AmountCents     int64     `json:"amount_cents"`
+	SavingsCents    int64     `json:"savings_cents"`
+	Timestamp       time.Time `json:"timestamp"`
+}
+
+func NewOutingBillRecordedEvent(outingID, userID, offerID, partnerID, establishmentID string, amountCents, savingsCents int64) OutingBillRecorded {
+	return OutingBillRecorded{
+		ID:              uuid.New().String(),
+		OutingID:        outingID,
+		UserID:          userID,
+		OfferID:         offerID,
+		PartnerID:       partnerID,
+		EstablishmentID: establishmentID,
+		AmountCents:     amountCents,
+		SavingsCents:    savingsCents,
+		Timestamp:       time.Now().UTC(),
+	}
+}
+
+func (e OutingBillRecorded) EventID() string          { return e.ID }
+func (e OutingBillRecorded) EventName() string        { return "outing.bill_recorded" }
+func (e OutingBillRecorded) OccurredAt() time.Time    { return e.Timestamp }
+func (e OutingBillRecorded) AggregateID() string      { return e.OutingID }
+func (e OutingBillRecorded) AggregateType() string    { return "Outing" }
+func (e OutingBillRecorded) Version() int             { return 1 }
+func (e OutingBillRecorded) Payload() ([]byte, error) { return json.Marshal(e) }
+
+// OutingFlagged is emitted when a check-in anomaly flags an outing for review
+type OutingFlagged struct {
+	ID              string    `json:"event_id"`
+	OutingID        string    `json:"outing_id"`
+	UserID          string    `json:"user_id"`
+	OfferID         string    `json:"offer_id"`
+	PartnerID       string    `json:"partner_id"`
+	EstablishmentID string    `json:"establishment_id"`
+	Reason          string    `json:"reason"`
+	Detail          string    `json:"detail,omitempty"`
+	Timestamp       time.Time `json:"timestamp"`
+}
+
+func NewOutingFlaggedEvent(outingID, userID, offerID, partnerID, establishmentID, reason, detail string) OutingFlagged {
+	return OutingFlagged{
+		ID:              uuid.New().String(),
+		OutingID:        outingID,
+		UserID:          userID,
+		OfferID:         offerID,
+		PartnerID:       partnerID,
+		EstablishmentID: establishmentID,
+		Reason:          reason,
+		Detail:          detail,
+		Timestamp:       time.Now().UTC(),
+	}
+}
+
+func (e OutingFlagged) EventID() string          { return e.ID }
+func (e OutingFlagged) EventName() string        { return "outing.flagged" }
+func (e OutingFlagged) OccurredAt() time.Time    { return e.Timestamp }
+func (e OutingFlagged) AggregateID() string      { return e.OutingID }
+func (e OutingFlagged) AggregateType() string    { return "Outing" }
+func (e OutingFlagged) Version() int             { return 1 }
+func (e OutingFlagged) Payload() ([]byte, error) { return json.Marshal(e) }
+
 // OutingCancelled is emitted when an outing is cancelled
 type OutingCancelled struct {
 	ID          string    `json:"event_id"`
@@ -90,10 +300,11 @@ type OutingCancelled struct {
 	PartnerID   string    `json:"partner_id"`
 	CancelledBy string    `json:"cancelled_by"`
 	Reason      string    `json:"reason"`
+	Late        bool      `json:"late"`
 	Timestamp   time.Time `json:"timestamp"`
 }
 
-func NewOutingCancelledEvent(outingID, userID, offerID, partnerID, cancelledBy, reason string) OutingCancelled {
+func NewOutingCancelledEvent(outingID, userID, offerID, partnerID, cancelledBy, reason string, late bool) OutingCancelled {
 	return OutingCancelled{
 		ID:          uuid.New().String(),
 		OutingID:    outingID,
@@ -102,6 +313,7 @@ func NewOutingCancelledEvent(outingID, userID, offerID, partnerID, cancelledBy,
 		PartnerID:   partnerID,
 		CancelledBy: cancelledBy,
 		Reason:      reason,
+		Late:        late,
 		Timestamp:   time.Now().UTC(),
 	}
 }
@@ -142,3 +354,211 @@ func (e OutingExpired) AggregateID() string      { return e.OutingID }
 func (e OutingExpired) AggregateType() string    { return "Outing" }
 func (e OutingExpired) Version() int             { return 1 }
 func (e OutingExpired) Payload() ([]byte, error) { return json.Marshal(e) }
+
+// OutingNoShow is emitted when a user did not show up for an outing
+type OutingNoShow struct {
+	ID              string    `json:"event_id"`
+	OutingID        string    `json:"outing_id"`
+	UserID          string    `json:"user_id"`
+	OfferID         string    `json:"offer_id"`
+	PartnerID       string    `json:"partner_id"`
+	EstablishmentID string    `json:"establishment_id"`
+	Timestamp       time.Time `json:"timestamp"`
+}
+
+func NewOutingNoShowEvent(outingID, userID, offerID, partnerID, establishmentID string) OutingNoShow {
+	return OutingNoShow{
+		ID:              uuid.New().String(),
+		OutingID:        outingID,
+		UserID:          userID,
+		OfferID:         offerID,
+		PartnerID:       partnerID,
+		EstablishmentID: establishmentID,
+		Timestamp:       time.Now().UTC(),
+	}
+}
+
+func (e OutingNoShow) EventID() string          { return e.ID }
+func (e OutingNoShow) EventName() string        { return "outing.no_show" }
+func (e OutingNoShow) OccurredAt() time.Time    { return e.Timestamp }
+func (e OutingNoShow) AggregateID() string      { return e.OutingID }
+func (e OutingNoShow) AggregateType() string    { return "Outing" }
+func (e OutingNoShow) Version() int             { return 1 }
+func (e OutingNoShow) Payload() ([]byte, error) { return json.Marshal(e) }
+
+// WaitlistJoined is emitted when a user joins the waitlist of an offer or slot
+type WaitlistJoined struct {
+	ID           string     `json:"event_id"`
+	EntryID      string     `json:"entry_id"`
+	UserID       string     `json:"user_id"`
+	OfferID      string     `json:"offer_id"`
+	SlotStartsAt *time.Time `json:"slot_starts_at,omitempty"`
+	Timestamp    time.Time  `json:"timestamp"`
+}
+
+func NewWaitlistJoinedEvent(entryID, userID, offerID string, slotStartsAt *time.Time) WaitlistJoined {
+	return WaitlistJoined{
+		ID:           uuid.New().String(),
+		EntryID:      entryID,
+		UserID:       userID,
+		OfferID:      offerID,
+		SlotStartsAt: slotStartsAt,
+		Timestamp:    time.Now().UTC(),
+	}
+}
+
+func (e WaitlistJoined) EventID() string          { return e.ID }
+func (e WaitlistJoined) EventName() string        { return "waitlist.joined" }
+func (e WaitlistJoined) OccurredAt() time.Time    { return e.Timestamp }
+func (e WaitlistJoined) AggregateID() string      { return e.EntryID }
+func (e WaitlistJoined) AggregateType() string    { return "WaitlistEntry" }
+func (e WaitlistJoined) Version() int             { return 1 }
+func (e WaitlistJoined) Payload() ([]byte, error) { return json.Marshal(e) }
+
+// WaitlistHoldOffered is emitted when freed capacity is held for a waiting
+// user, who must accept it before HoldExpiresAt
+type WaitlistHoldOffered struct {
+	ID            string     `json:"event_id"`
+	EntryID       string     `json:"entry_id"`
+	UserID        string     `json:"user_id"`
+	OfferID       string     `json:"offer_id"`
+	SlotStartsAt  *time.Time `json:"slot_starts_at,omitempty"`
+	HoldExpiresAt time.Time  `json:"hold_expires_at"`
+	Timestamp     time.Time  `json:"timestamp"`
+}
+
+func NewWaitlistHoldOfferedEvent(entryID, userID, offerID string, slotStartsAt *time.Time, holdExpiresAt time.Time) WaitlistHoldOffered {
+	return WaitlistHoldOffered{
+		ID:            uuid.New().String(),
+		EntryID:       entryID,
+		UserID:        userID,
+		OfferID:       offerID,
+		SlotStartsAt:  slotStartsAt,
+		HoldExpiresAt: holdExpiresAt,
+		Timestamp:     time.Now().UTC(),
+	}
+}
+
+func (e WaitlistHoldOffered) EventID() string          { return e.ID }
+func (e WaitlistHoldOffered) EventName() string        { return "waitlist.hold_offered" }
+func (e WaitlistHoldOffered) OccurredAt() time.Time    { return e.Timestamp }
+func (e WaitlistHoldOffered) AggregateID() string      { return e.EntryID }
+func (e WaitlistHoldOffered) AggregateType() string    { return "WaitlistEntry" }
+func (e WaitlistHoldOffered) Version() int             { return 1 }
+func (e WaitlistHoldOffered) Payload() ([]byte, error) { return json.Marshal(e) }
+
+// WaitlistConverted is emitted when a held entry is accepted and booked
+type WaitlistConverted struct {
+	ID        string    `json:"event_id"`
+	EntryID   string    `json:"entry_id"`
+	UserID    string    `json:"user_id"`
+	OfferID   string    `json:"offer_id"`
+	OutingID  string    `json:"outing_id"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+func NewWaitlistConvertedEvent(entryID, userID, offerID, outingID string) WaitlistConverted {
+	return WaitlistConverted{
+		ID:        uuid.New().String(),
+		EntryID:   entryID,
+		UserID:    userID,
+		OfferID:   offerID,
+		OutingID:  outingID,
+		Timestamp: time.Now().UTC(),
+	}
+}
+
+func (e WaitlistConverted) EventID() string          { return e.ID }
+func (e WaitlistConverted) EventName() string        { return "waitlist.converted" }
+func (e WaitlistConverted) OccurredAt() time.Time    { return e.Timestamp }
+func (e WaitlistConverted) AggregateID() string      { return e.EntryID }
+func (e WaitlistConverted) AggregateType() string    { return "WaitlistEntry" }
+func (e WaitlistConverted) Version() int             { return 1 }
+func (e WaitlistConverted) Payload() ([]byte, error) { return json.Marshal(e) }
+
+// WaitlistClosed is emitted when an entry leaves the line without being
+// booked (hold expired, user left or entry removed)
+type WaitlistClosed struct {
+	ID        string    `json:"event_id"`
+	EntryID   string    `json:"entry_id"`
+	UserID    string    `json:"user_id"`
+	OfferID   string    `json:"offer_id"`
+	Status    string    `json:"status"`
+	Reason    string    `json:"reason"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+func NewWaitlistClosedEvent(entryID, userID, offerID, status, reason string) WaitlistClosed {
+	return WaitlistClosed{
+		ID:        uuid.New().String(),
+		EntryID:   entryID,
+		UserID:    userID,
+		OfferID:   offerID,
+		Status:    status,
+		Reason:    reason,
+		Timestamp: time.Now().UTC(),
+	}
+}
+
+func (e WaitlistClosed) EventID() string          { return e.ID }
+func (e WaitlistClosed) EventName() string        { return "waitlist.closed" }
+func (e WaitlistClosed) OccurredAt() time.Time    { return e.Timestamp }
+func (e WaitlistClosed) AggregateID() string      { return e.EntryID }
+func (e WaitlistClosed) AggregateType() string    { return "WaitlistEntry" }
+func (e WaitlistClosed) Version() int             { return 1 }
+func (e WaitlistClosed) Payload() ([]byte, error) { return json.Marshal(e) }
+
+// StrikeRecorded is emitted when a late cancellation or a no-show counts
+// against a user
+type StrikeRecorded struct {
+	ID            string    `json:"event_id"`
+	UserID        string    `json:"user_id"`
+	OutingID      string    `json:"outing_id"`
+	Kind          string    `json:"kind"`
+	ActiveStrikes int       `json:"active_strikes"`
+	Timestamp     time.Time `json:"timestamp"`
+}
+
+func NewStrikeRecordedEvent(userID, outingID, kind string, activeStrikes int) StrikeRecorded {
+	return StrikeRecorded{
+		ID:            uuid.New().String(),
+		UserID:        userID,
+		OutingID:      outingID,
+		Kind:          kind,
+		ActiveStrikes: activeStrikes,
+		Timestamp:     time.Now().UTC(),
+	}
+}
+
+func (e StrikeRecorded) EventID() string          { return e.ID }
+func (e StrikeRecorded) EventName() string        { return "user.strike_recorded" }
+func (e StrikeRecorded) OccurredAt() time.Time    { return e.Timestamp }
+func (e StrikeRecorded) AggregateID() string      { return e.UserID }
+func (e StrikeRecorded) AggregateType() string    { return "UserStanding" }
+func (e StrikeRecorded) Version() int             { return 1 }
+func (e StrikeRecorded) Payload() ([]byte, error) { return json.Marshal(e) }
+
+// UserBookingBanned is emitted when strikes get a user temporarily banned
+type UserBookingBanned struct {
+	ID          string    `json:"event_id"`
+	UserID      string    `json:"user_id"`
+	BannedUntil time.Time `json:"banned_until"`
+	Timestamp   time.Time `json:"timestamp"`
+}
+
+func NewUserBookingBannedEvent(userID string, bannedUntil time.Time) UserBookingBanned {
+	return UserBookingBanned{
+		ID:          uuid.New().String(),
+		UserID:      userID,
+		BannedUntil: bannedUntil,
+		Timestamp:   time.Now().UTC(),
+	}
+}
+
+func (e UserBookingBanned) EventID() string          { return e.ID }
+func (e UserBookingBanned) EventName() string        { return "user.booking_banned" }
+func (e UserBookingBanned) OccurredAt() time.Time    { return e.Timestamp }
+func (e UserBookingBanned) AggregateID() string      { return e.UserID }
+func (e UserBookingBanned) AggregateType() string    { return "UserStanding" }
+func (e UserBookingBanned) Version() int             { return 1 }
+func (e UserBookingBanned) Payload() ([]byte, error) { return json.Marshal(e) }
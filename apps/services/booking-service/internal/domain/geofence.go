@@ -0,0 +1,101 @@
+package domain
+
+import (
+	"fmt"
+	"math"
+)
+
+// =============================================================================
+// GEOFENCE
+// =============================================================================
+
+// earthRadiusMeters is the mean Earth radius used by the haversine formula
+const earthRadiusMeters = 6371000
+
+type GeofenceMode string
+
+const (
+	// GeofenceModeReject refuses check-ins outside the radius
+	GeofenceModeReject GeofenceMode = "reject"
+	// GeofenceModeFlag accepts them but flags the check-in for review
+	GeofenceModeFlag GeofenceMode = "flag"
+)
+
+func (m GeofenceMode) IsValid() bool {
+	return m == GeofenceModeReject || m == GeofenceModeFlag
+}
+
+// Review reasons recorded on flagged check-ins
+const (
+	ReviewReasonOutsideGeofence = "outside_geofence"
+	ReviewReasonLocationMissing = "location_missing"
+)
+
+// Geofence is the perimeter around an establishment within which check-ins
+// are expected to happen
+type Geofence struct {
+	radiusMeters float64
+	mode         GeofenceMode
+}
+
+func NewGeofence(radiusMeters float64, mode GeofenceMode) (Geofence, error) {
+	if radiusMeters <= 0 {
+		return Geofence{}, fmt.Errorf("geofence radius must be positive, got %v", radiusMeters)
+	}
+	if !mode.IsValid() {
+		return Geofence{}, fmt.Errorf("invalid geofence mode %q", mode)
+	}
+	return Geofence{radiusMeters: radiusMeters, mode: mode}, nil
+}
+
+func (g Geofence) RadiusMeters() float64 { return g.radiusMeters }
+func (g Geofence) Mode() GeofenceMode    { return g.mode }
+
+// LocationCheck is the outcome of checking a check-in location
+type LocationCheck struct {
+	distanceMeters *float64
+	flagged        bool
+	reviewReason   string
+}
+
+func (c LocationCheck) DistanceMeters() *float64 { return c.distanceMeters }
+func (c LocationCheck) Flagged() bool            { return c.flagged }
+func (c LocationCheck) ReviewReason() string     { return c.reviewReason }
+
+// CheckLocation measures how far a check-in happened from the establishment.
+// A nil geofence only measures. A missing location is never rejected, since
+// scanners may be denied location access, but it is flagged for review.
+func (g *Geofence) CheckLocation(offer OfferSnapshot, lat, lng *float64) (LocationCheck, error) {
+	if lat == nil || lng == nil {
+		if g == nil {
+			return LocationCheck{}, nil
+		}
+		return LocationCheck{flagged: true, reviewReason: ReviewReasonLocationMissing}, nil
+	}
+
+	distance := DistanceMeters(*lat, *lng, offer.Latitude(), offer.Longitude())
+	check := LocationCheck{distanceMeters: &distance}
+
+	if g == nil || distance <= g.radiusMeters {
+		return check, nil
+	}
+	if g.mode == GeofenceModeReject {
+		return check, ErrOutsideGeofence
+	}
+
+	check.flagged = true
+	check.reviewReason = ReviewReasonOutsideGeofence
+	return check, nil
+}
+
+// DistanceMeters returns the great-circle distance between two coordinates
+func DistanceMeters(lat1, lng1, lat2, lng2 float64) float64 {
+	toRadians := func(deg float64) float64 { return deg * math.Pi / 180 }
+
+	dLat := toRadians(lat2 - lat1)
+	dLng := toRadians(lng2 - lng1)
+	a := math.Sin(dLat/2)*math.Sin(dLat/2) +
+		math.Cos(toRadians(lat1))*math.Cos(toRadians(lat2))*math.Sin(dLng/2)*math.Sin(dLng/2)
+
+	return earthRadiusMeters * 2 * math.Atan2(math.Sqrt(a), math.Sqrt(1-a))
+}
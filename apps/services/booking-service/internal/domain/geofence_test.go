@@ -0,0 +1,83 @@
+package domain
+
+import (
+	"testing"
+)
+
+// =============================================================================
+// Geofence Tests
+// =============================================================================
+
+func TestDistanceMeters(t *testing.T) {
+	// Paris to London is roughly 344 km
+	distance := DistanceMeters(48.8566, 2.3522, 51.5074, -0.1278)
+	if distance < 340000 || distance > 348000 {
+		t.Errorf("DistanceMeters() = %v, want about 344km", distance)
+	}
+	if d := DistanceMeters(48.8566, 2.3522, 48.8566, 2.3522); d != 0 {
+		t.Errorf("DistanceMeters() same point = %v, want 0", d)
+	}
+}
+
+func TestNewGeofence_Errors(t *testing.T) {
+	if _, err := NewGeofence(0, GeofenceModeReject); err == nil {
+		t.Error("NewGeofence() with zero radius should fail")
+	}
+	if _, err := NewGeofence(100, GeofenceMode("ignore")); err == nil {
+		t.Error("NewGeofence() with unknown mode should fail")
+	}
+}
+
+func TestOuting_CheckIn_Geofence(t *testing.T) {
+	// Test offer is at 48.8566, 2.3522; 0.009 degrees of latitude is ~1km
+	nearLat, nearLng := 48.8570, 2.3522
+	farLat, farLng := 48.8656, 2.3522
+	reject, _ := NewGeofence(200, GeofenceModeReject)
+	flag, _ := NewGeofence(200, GeofenceModeFlag)
+
+	tests := []struct {
+		name       string
+		geofence   *Geofence
+		lat, lng   *float64
+		wantErr    error
+		wantFlag   bool
+		wantReason string
+	}{
+		{"inside radius", &reject, &nearLat, &nearLng, nil, false, ""},
+		{"outside radius rejected", &reject, &farLat, &farLng, ErrOutsideGeofence, false, ""},
+		{"outside radius flagged", &flag, &farLat, &farLng, nil, true, ReviewReasonOutsideGeofence},
+		{"missing location flagged", &reject, nil, nil, nil, true, ReviewReasonLocationMissing},
+		{"no geofence", nil, &farLat, &farLng, nil, false, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			outing := createTestOuting()
+
+			err := outing.CheckInManual("staff-123", tt.lat, tt.lng, tt.geofence)
+			if err != tt.wantErr {
+				t.Fatalf("CheckInManual() error = %v, want %v", err, tt.wantErr)
+			}
+			if err != nil {
+				if outing.Status() != OutingStatusConfirmed {
+					t.Errorf("CheckInManual() status should remain %v", OutingStatusConfirmed)
+				}
+				return
+			}
+
+			checkIn := outing.CheckIn()
+			if checkIn.FlaggedForReview() != tt.wantFlag || checkIn.ReviewReason() != tt.wantReason {
+				t.Errorf("CheckInManual() flagged = %v (%q), want %v (%q)",
+					checkIn.FlaggedForReview(), checkIn.ReviewReason(), tt.wantFlag, tt.wantReason)
+			}
+			if (tt.lat != nil) != (checkIn.DistanceMeters() != nil) {
+				t.Errorf("CheckInManual() distance = %v, want it set only with a location", checkIn.DistanceMeters())
+			}
+
+			entry := outing.Timeline()[len(outing.Timeline())-1]
+			if _, ok := entry.Metadata()["distance_meters"]; ok != (tt.lat != nil) {
+				t.Errorf("CheckInManual() timeline metadata = %v, distance recorded = %v", entry.Metadata(), ok)
+			}
+		})
+	}
+}
@@ -4,10 +4,14 @@ import (
 	"crypto/hmac"
 	"crypto/rand"
 	"crypto/sha256"
+	"crypto/subtle"
 	"encoding/base64"
 	"encoding/hex"
 	"errors"
 	"fmt"
+	"math"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/yousoon/shared/domain"
@@ -24,14 +28,35 @@ var (
 	ErrOutingAlreadyUsed    = errors.New("outing has already been used")
 	ErrOutingCancelled      = errors.New("outing has been cancelled")
 	ErrInvalidQRCode        = errors.New("invalid QR code")
+	ErrQRCodeStale          = errors.New("QR code is no longer current, refresh it")
+	ErrQRKeysNotConfigured  = errors.New("QR signing keys are not configured")
 	ErrInvalidOutingStatus  = errors.New("invalid outing status")
 	ErrCannotCancelUsed     = errors.New("cannot cancel used outing")
 	ErrOfferNotBookable     = errors.New("offer is not bookable")
 	ErrUserQuotaExceeded    = errors.New("user booking quota exceeded")
 	ErrOfferQuotaExceeded   = errors.New("offer booking quota exceeded")
+	ErrDailyQuotaExceeded   = errors.New("offer daily booking quota exceeded")
 	ErrInvalidCheckInWindow = errors.New("check-in window has not started or has expired")
+	ErrSlotNotAvailable     = errors.New("time slot is not available for this offer")
+	ErrSlotEnded            = errors.New("time slot has already ended")
+	ErrSlotFull             = errors.New("time slot is fully booked")
+	ErrOutsideGeofence      = errors.New("check-in location is too far from the establishment")
+	ErrNotPartnerTeamMember = errors.New("user is not a team member of this partner")
+	ErrAwaitingConfirmation = errors.New("outing is awaiting partner confirmation")
+	ErrOutingNotPending     = errors.New("outing is not awaiting partner confirmation")
+	ErrOutingStatusChanged  = errors.New("outing status was changed concurrently")
+	ErrConfirmationTimedOut = errors.New("partner confirmation deadline has passed")
+	ErrNotOutingOwner       = errors.New("user does not own this outing")
+	ErrTransferToSelf       = errors.New("cannot transfer an outing to its owner")
+	ErrNoPendingTransfer    = errors.New("outing has no pending transfer")
+	ErrNotTransferRecipient = errors.New("outing is not being transferred to this user")
+	ErrRecipientNotEligible = errors.New("transfer recipient cannot book this offer")
+	ErrUserNotFound         = errors.New("user not found")
 )
 
+// slotCheckInLeadTime is how early before a booked slot starts check-in opens
+const slotCheckInLeadTime = 30 * time.Minute
+
 // =============================================================================
 // ENUMS
 // =============================================================================
@@ -71,24 +96,109 @@ const (
 type CheckInMethod string
 
 const (
-	CheckInMethodQRScan CheckInMethod = "qr_scan"
-	CheckInMethodManual CheckInMethod = "manual"
+	CheckInMethodQRScan      CheckInMethod = "qr_scan"
+	CheckInMethodManual      CheckInMethod = "manual"
+	CheckInMethodOfflineScan CheckInMethod = "offline_scan"
+	CheckInMethodShortCode   CheckInMethod = "short_code"
 )
 
 // =============================================================================
 // VALUE OBJECTS
 // =============================================================================
 
-// QRCode represents the unique QR code for check-in
+// QRKey is a versioned secret used to sign QR payloads. A retired key keeps
+// being accepted for the key ring's grace period after RetiredAt.
+type QRKey struct {
+	ID        string
+	Secret    []byte
+	RetiredAt *time.Time
+}
+
+// QRKeyRing signs QR payloads with the active key and verifies them with any
+// key that is active or still within its grace period
+type QRKeyRing struct {
+	active      QRKey
+	keys        map[string]QRKey
+	window      time.Duration
+	gracePeriod time.Duration
+}
+
+// qrWindowTolerance is how many past windows are still accepted, to absorb
+// clock skew and the delay between rendering and scanning
+const qrWindowTolerance = 1
+
+// offlineScanClockSkew bounds the disagreement tolerated between the clock of
+// an offline scanner and the one of the phone that rendered the QR code
+const offlineScanClockSkew = 2 * time.Minute
+
+func NewQRKeyRing(keys []QRKey, window, gracePeriod time.Duration) (*QRKeyRing, error) {
+	if window < time.Second {
+		return nil, fmt.Errorf("QR window must be at least one second")
+	}
+
+	ring := &QRKeyRing{
+		keys:        make(map[string]QRKey, len(keys)),
+		window:      window,
+		gracePeriod: gracePeriod,
+	}
+	for _, key := range keys {
+		if key.ID == "" || strings.Contains(key.ID, ".") || len(key.Secret) == 0 {
+			return nil, fmt.Errorf("invalid QR key %q", key.ID)
+		}
+		if _, exists := ring.keys[key.ID]; exists {
+			return nil, fmt.Errorf("duplicate QR key %q", key.ID)
+		}
+		if key.RetiredAt == nil {
+			if ring.active.ID != "" {
+				return nil, fmt.Errorf("QR keys %q and %q are both active", ring.active.ID, key.ID)
+			}
+			ring.active = key
+		}
+		ring.keys[key.ID] = key
+	}
+	if ring.active.ID == "" {
+		return nil, fmt.Errorf("no active QR key")
+	}
+
+	return ring, nil
+}
+
+func (r *QRKeyRing) ActiveKeyID() string        { return r.active.ID }
+func (r *QRKeyRing) Window() time.Duration      { return r.window }
+func (r *QRKeyRing) GracePeriod() time.Duration { return r.gracePeriod }
+
+func (r *QRKeyRing) windowAt(at time.Time) int64 {
+	return at.Unix() / int64(r.window/time.Second)
+}
+
+// acceptedKey returns the key with the given ID if it may still verify payloads
+func (r *QRKeyRing) acceptedKey(id string, at time.Time) (QRKey, bool) {
+	key, ok := r.keys[id]
+	if !ok {
+		return QRKey{}, false
+	}
+	if key.RetiredAt != nil && at.After(key.RetiredAt.Add(r.gracePeriod)) {
+		return QRKey{}, false
+	}
+	return key, true
+}
+
+func (r *QRKeyRing) sign(key QRKey, code string, window int64) string {
+	h := hmac.New(sha256.New, key.Secret)
+	fmt.Fprintf(h, "%s.%s.%d", code, key.ID, window)
+	return base64.RawURLEncoding.EncodeToString(h.Sum(nil))
+}
+
+// QRCode represents the unique QR code for check-in. The code identifies the
+// outing; what is displayed and scanned is a payload derived from it that
+// embeds the signing key ID and a short time window, so a screenshot stops
+// working once the window has passed.
 type QRCode struct {
 	code      string
-	signature string
 	createdAt time.Time
 	expiresAt time.Time
 }
 
-var qrSecretKey = []byte("yousoon-qr-secret-key-change-in-prod")
-
 func NewQRCode(expiresAt time.Time) (QRCode, error) {
 	// Generate random code
 	codeBytes := make([]byte, 16)
@@ -97,56 +207,82 @@ func NewQRCode(expiresAt time.Time) (QRCode, error) {
 	}
 	code := hex.EncodeToString(codeBytes)
 
-	// Create HMAC signature
-	h := hmac.New(sha256.New, qrSecretKey)
-	h.Write([]byte(code))
-	signature := base64.URLEncoding.EncodeToString(h.Sum(nil))
-
 	return QRCode{
 		code:      code,
-		signature: signature,
 		createdAt: time.Now(),
 		expiresAt: expiresAt,
 	}, nil
 }
 
-func ReconstructQRCode(code, signature string, createdAt, expiresAt time.Time) QRCode {
+func ReconstructQRCode(code string, createdAt, expiresAt time.Time) QRCode {
 	return QRCode{
 		code:      code,
-		signature: signature,
 		createdAt: createdAt,
 		expiresAt: expiresAt,
 	}
 }
 
 func (q QRCode) Code() string         { return q.code }
-func (q QRCode) Signature() string    { return q.signature }
 func (q QRCode) CreatedAt() time.Time { return q.createdAt }
 func (q QRCode) ExpiresAt() time.Time { return q.expiresAt }
 
-func (q QRCode) FullCode() string {
-	return fmt.Sprintf("%s.%s", q.code, q.signature)
-}
-
 func (q QRCode) IsExpired() bool {
 	return time.Now().After(q.expiresAt)
 }
 
-func (q QRCode) Matches(scanned string) bool {
-	return q.code == scanned || q.FullCode() == scanned
+// Payload returns the value to render in the QR code at the given time
+// ("code.keyID.window.signature") and when it should be refreshed.
+func (q QRCode) Payload(ring *QRKeyRing, at time.Time) (string, time.Time, error) {
+	if ring == nil {
+		return "", time.Time{}, ErrQRKeysNotConfigured
+	}
+
+	window := ring.windowAt(at)
+	signature := ring.sign(ring.active, q.code, window)
+	refreshAt := time.Unix((window+1)*int64(ring.window/time.Second), 0)
+
+	return fmt.Sprintf("%s.%s.%d.%s", q.code, ring.active.ID, window, signature), refreshAt, nil
 }
 
-func (q QRCode) Verify(scanned string) bool {
-	if !q.Matches(scanned) {
-		return false
+// Verify checks a scanned payload: it must belong to this code, be signed by
+// an accepted key and fall within the current or a tolerated past window.
+func (q QRCode) Verify(ring *QRKeyRing, scanned string, at time.Time) error {
+	return q.verify(ring, scanned, at, 0)
+}
+
+// verify is Verify with the scan time known only within skew, widening the
+// accepted windows by that much on both sides
+func (q QRCode) verify(ring *QRKeyRing, scanned string, at time.Time, skew time.Duration) error {
+	if ring == nil {
+		return ErrQRKeysNotConfigured
 	}
 
-	// Verify signature
-	h := hmac.New(sha256.New, qrSecretKey)
-	h.Write([]byte(q.code))
-	expectedSignature := base64.URLEncoding.EncodeToString(h.Sum(nil))
+	online, _ := splitQRPayload(scanned)
+	parts := strings.Split(online, ".")
+	if len(parts) != 4 || parts[0] != q.code {
+		return ErrInvalidQRCode
+	}
 
-	return hmac.Equal([]byte(q.signature), []byte(expectedSignature))
+	key, ok := ring.acceptedKey(parts[1], at)
+	if !ok {
+		return ErrInvalidQRCode
+	}
+
+	window, err := strconv.ParseInt(parts[2], 10, 64)
+	if err != nil {
+		return ErrInvalidQRCode
+	}
+
+	expected := ring.sign(key, q.code, window)
+	if !hmac.Equal([]byte(parts[3]), []byte(expected)) {
+		return ErrInvalidQRCode
+	}
+
+	if window > ring.windowAt(at.Add(skew)) || window < ring.windowAt(at.Add(-skew))-qrWindowTolerance {
+		return ErrQRCodeStale
+	}
+
+	return nil
 }
 
 // OfferSnapshot captures offer details at booking time (immutable)
@@ -237,6 +373,24 @@ func (s OfferSnapshot) Longitude() float64           { return s.longitude }
 func (s OfferSnapshot) ImageURL() string             { return s.imageURL }
 func (s OfferSnapshot) CapturedAt() time.Time        { return s.capturedAt }
 
+// ApplyDiscount returns the price left to pay once the offer's discount is
+// applied, the way discovery's Discount.Apply computes it: percentages round
+// the reduction down, fixed reductions never go below zero and formula
+// discounts ("1 bought = 1 free") have no numeric effect.
+func (s OfferSnapshot) ApplyDiscount(priceCents int64) int64 {
+	switch s.discountType {
+	case "percentage":
+		return priceCents - priceCents*int64(s.discountValue)/100
+	case "fixed":
+		result := priceCents - int64(s.discountValue)
+		if result < 0 {
+			return 0
+		}
+		return result
+	}
+	return priceCents
+}
+
 // UserSnapshot captures user details at booking time
 type UserSnapshot struct {
 	userID    string
@@ -259,13 +413,209 @@ func (s UserSnapshot) FirstName() string { return s.firstName }
 func (s UserSnapshot) LastName() string  { return s.lastName }
 func (s UserSnapshot) Email() string     { return s.email }
 
+// OfferQuota holds the booking limits of an offer. A nil limit means unlimited.
+type OfferQuota struct {
+	total   *int
+	perUser *int
+	perDay  *int
+}
+
+func NewOfferQuota(total, perUser, perDay *int) OfferQuota {
+	return OfferQuota{
+		total:   total,
+		perUser: perUser,
+		perDay:  perDay,
+	}
+}
+
+func NewUnlimitedOfferQuota() OfferQuota {
+	return OfferQuota{}
+}
+
+func (q OfferQuota) Total() *int   { return q.total }
+func (q OfferQuota) PerUser() *int { return q.perUser }
+func (q OfferQuota) PerDay() *int  { return q.perDay }
+
+func (q OfferQuota) IsUnlimited() bool {
+	return q.total == nil && q.perUser == nil && q.perDay == nil
+}
+
+// OfferTimeSlot is a weekly recurring slot of an offer's schedule.
+// Times are "15:04" wall-clock times; an end of "24:00" lasts until midnight
+// and an end before the start crosses midnight.
+type OfferTimeSlot struct {
+	dayOfWeek time.Weekday
+	startTime string
+	endTime   string
+	capacity  *int // nil = unlimited
+}
+
+func NewOfferTimeSlot(dayOfWeek time.Weekday, startTime, endTime string, capacity *int) OfferTimeSlot {
+	return OfferTimeSlot{
+		dayOfWeek: dayOfWeek,
+		startTime: startTime,
+		endTime:   endTime,
+		capacity:  capacity,
+	}
+}
+
+func (t OfferTimeSlot) DayOfWeek() time.Weekday { return t.dayOfWeek }
+func (t OfferTimeSlot) StartTime() string       { return t.startTime }
+func (t OfferTimeSlot) EndTime() string         { return t.endTime }
+func (t OfferTimeSlot) Capacity() *int          { return t.capacity }
+
+// OfferSchedule holds when an offer can be booked, in the establishment's timezone
+type OfferSchedule struct {
+	allDay   bool
+	slots    []OfferTimeSlot
+	location *time.Location
+}
+
+func NewOfferSchedule(allDay bool, slots []OfferTimeSlot, location *time.Location) OfferSchedule {
+	if location == nil {
+		location = time.UTC
+	}
+	return OfferSchedule{
+		allDay:   allDay,
+		slots:    slots,
+		location: location,
+	}
+}
+
+func (s OfferSchedule) AllDay() bool             { return s.allDay }
+func (s OfferSchedule) Slots() []OfferTimeSlot   { return s.slots }
+func (s OfferSchedule) Location() *time.Location { return s.location }
+
+// SlotsOn returns the slot instances of the schedule on the given date ("2006-01-02").
+// An all-day schedule has a single unlimited slot covering the whole day.
+func (s OfferSchedule) SlotsOn(date string) ([]SlotSnapshot, error) {
+	day, err := time.ParseInLocation("2006-01-02", date, s.location)
+	if err != nil {
+		return nil, ErrSlotNotAvailable
+	}
+
+	if s.allDay {
+		slot, err := newSlotSnapshot(day, NewOfferTimeSlot(day.Weekday(), "00:00", "24:00", nil))
+		if err != nil {
+			return nil, err
+		}
+		return []SlotSnapshot{slot}, nil
+	}
+
+	result := make([]SlotSnapshot, 0)
+	for _, timeSlot := range s.slots {
+		if timeSlot.dayOfWeek != day.Weekday() {
+			continue
+		}
+		slot, err := newSlotSnapshot(day, timeSlot)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, slot)
+	}
+	return result, nil
+}
+
+// ResolveSlot finds the slot starting at startTime on date and checks it has not ended
+func (s OfferSchedule) ResolveSlot(date, startTime string, now time.Time) (SlotSnapshot, error) {
+	slots, err := s.SlotsOn(date)
+	if err != nil {
+		return SlotSnapshot{}, err
+	}
+
+	for _, slot := range slots {
+		if slot.startTime != startTime {
+			continue
+		}
+		if !now.Before(slot.endsAt) {
+			return SlotSnapshot{}, ErrSlotEnded
+		}
+		return slot, nil
+	}
+
+	return SlotSnapshot{}, ErrSlotNotAvailable
+}
+
+// SlotSnapshot captures the booked slot instance at booking time (immutable)
+type SlotSnapshot struct {
+	date      string
+	startTime string
+	endTime   string
+	startsAt  time.Time
+	endsAt    time.Time
+	capacity  *int
+}
+
+func newSlotSnapshot(day time.Time, timeSlot OfferTimeSlot) (SlotSnapshot, error) {
+	start, err := slotMinute(timeSlot.startTime, false)
+	if err != nil {
+		return SlotSnapshot{}, err
+	}
+	end, err := slotMinute(timeSlot.endTime, true)
+	if err != nil {
+		return SlotSnapshot{}, err
+	}
+
+	loc := day.Location()
+	startsAt := time.Date(day.Year(), day.Month(), day.Day(), 0, start, 0, 0, loc)
+	endsAt := time.Date(day.Year(), day.Month(), day.Day(), 0, end, 0, 0, loc)
+	if !endsAt.After(startsAt) {
+		endsAt = time.Date(day.Year(), day.Month(), day.Day()+1, 0, end, 0, 0, loc)
+	}
+
+	return SlotSnapshot{
+		date:      day.Format("2006-01-02"),
+		startTime: timeSlot.startTime,
+		endTime:   timeSlot.endTime,
+		startsAt:  startsAt,
+		endsAt:    endsAt,
+		capacity:  timeSlot.capacity,
+	}, nil
+}
+
+// slotMinute parses a slot time into minutes since midnight. "24:00" is only
+// accepted as an end, for a slot lasting until midnight.
+func slotMinute(value string, isEnd bool) (int, error) {
+	if isEnd && value == "24:00" {
+		return 24 * 60, nil
+	}
+	t, err := time.Parse("15:04", value)
+	if err != nil {
+		return 0, ErrSlotNotAvailable
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+func ReconstructSlotSnapshot(date, startTime, endTime string, startsAt, endsAt time.Time, capacity *int) SlotSnapshot {
+	return SlotSnapshot{
+		date:      date,
+		startTime: startTime,
+		endTime:   endTime,
+		startsAt:  startsAt,
+		endsAt:    endsAt,
+		capacity:  capacity,
+	}
+}
+
+func (s SlotSnapshot) Date() string        { return s.date }
+func (s SlotSnapshot) StartTime() string   { return s.startTime }
+func (s SlotSnapshot) EndTime() string     { return s.endTime }
+func (s SlotSnapshot) StartsAt() time.Time { return s.startsAt }
+func (s SlotSnapshot) EndsAt() time.Time   { return s.endsAt }
+func (s SlotSnapshot) Capacity() *int      { return s.capacity }
+
 // CheckInInfo contains check-in details
 type CheckInInfo struct {
-	checkedInAt time.Time
-	checkedInBy string // UserID of staff member
-	method      CheckInMethod
-	latitude    *float64
-	longitude   *float64
+	checkedInAt      time.Time
+	checkedInBy      string // UserID of staff member
+	method           CheckInMethod
+	latitude         *float64
+	longitude        *float64
+	distanceMeters   *float64 // Distance from the establishment
+	flaggedForReview bool
+	reviewReason     string
+	anomalies        []CheckInAnomaly
+	bill             *Bill // declared by staff, optional
 }
 
 func NewCheckInInfo(checkedInBy string, method CheckInMethod, lat, lng *float64) CheckInInfo {
@@ -278,27 +628,69 @@ func NewCheckInInfo(checkedInBy string, method CheckInMethod, lat, lng *float64)
 	}
 }
 
-func ReconstructCheckInInfo(checkedInAt time.Time, checkedInBy string, method CheckInMethod, lat, lng *float64) CheckInInfo {
+func ReconstructCheckInInfo(
+	checkedInAt time.Time,
+	checkedInBy string,
+	method CheckInMethod,
+	lat, lng *float64,
+	distanceMeters *float64,
+	flaggedForReview bool,
+	reviewReason string,
+	anomalies []CheckInAnomaly,
+	bill *Bill,
+) CheckInInfo {
 	return CheckInInfo{
-		checkedInAt: checkedInAt,
-		checkedInBy: checkedInBy,
-		method:      method,
-		latitude:    lat,
-		longitude:   lng,
+		checkedInAt:      checkedInAt,
+		checkedInBy:      checkedInBy,
+		method:           method,
+		latitude:         lat,
+		longitude:        lng,
+		distanceMeters:   distanceMeters,
+		flaggedForReview: flaggedForReview,
+		reviewReason:     reviewReason,
+		anomalies:        anomalies,
+		bill:             bill,
+	}
+}
+
+func (c CheckInInfo) withLocationCheck(check LocationCheck) CheckInInfo {
+	c.distanceMeters = check.DistanceMeters()
+	c.flaggedForReview = check.Flagged()
+	c.reviewReason = check.ReviewReason()
+	if check.Flagged() {
+		c.anomalies = append(c.anomalies, ReconstructCheckInAnomaly(AnomalyReason(check.ReviewReason()), "", c.checkedInAt))
 	}
+	return c
 }
 
-func (c CheckInInfo) CheckedInAt() time.Time { return c.checkedInAt }
-func (c CheckInInfo) CheckedInBy() string    { return c.checkedInBy }
-func (c CheckInInfo) Method() CheckInMethod  { return c.method }
-func (c CheckInInfo) Latitude() *float64     { return c.latitude }
-func (c CheckInInfo) Longitude() *float64    { return c.longitude }
+func (c CheckInInfo) CheckedInAt() time.Time   { return c.checkedInAt }
+func (c CheckInInfo) CheckedInBy() string      { return c.checkedInBy }
+func (c CheckInInfo) Method() CheckInMethod    { return c.method }
+func (c CheckInInfo) Latitude() *float64       { return c.latitude }
+func (c CheckInInfo) Longitude() *float64      { return c.longitude }
+func (c CheckInInfo) DistanceMeters() *float64 { return c.distanceMeters }
+func (c CheckInInfo) FlaggedForReview() bool   { return c.flaggedForReview }
+func (c CheckInInfo) ReviewReason() string     { return c.reviewReason }
+func (c CheckInInfo) Bill() *Bill              { return c.bill }
+
+// Anomalies returns why the check-in was flagged for review, oldest first
+func (c CheckInInfo) Anomalies() []CheckInAnomaly { return c.anomalies }
+
+func (c CheckInInfo) HasAnomaly(reason AnomalyReason) bool {
+	for _, anomaly := range c.anomalies {
+		if anomaly.Reason() == reason {
+			return true
+		}
+	}
+	return false
+}
 
 // CancellationInfo contains cancellation details
 type CancellationInfo struct {
 	cancelledAt time.Time
 	cancelledBy CancellationActor
 	reason      string
+	late        bool // user cancellation outside the free cancellation window
 }
 
 func NewCancellationInfo(cancelledBy CancellationActor, reason string) CancellationInfo {
@@ -309,17 +701,39 @@ func NewCancellationInfo(cancelledBy CancellationActor, reason string) Cancellat
 	}
 }
 
-func ReconstructCancellationInfo(cancelledAt time.Time, cancelledBy CancellationActor, reason string) CancellationInfo {
+func ReconstructCancellationInfo(cancelledAt time.Time, cancelledBy CancellationActor, reason string, late bool) CancellationInfo {
 	return CancellationInfo{
 		cancelledAt: cancelledAt,
 		cancelledBy: cancelledBy,
 		reason:      reason,
+		late:        late,
 	}
 }
 
 func (c CancellationInfo) CancelledAt() time.Time         { return c.cancelledAt }
 func (c CancellationInfo) CancelledBy() CancellationActor { return c.cancelledBy }
 func (c CancellationInfo) Reason() string                 { return c.reason }
+func (c CancellationInfo) IsLate() bool                   { return c.late }
+
+// OutingTransfer is the owner's offer of the outing to another user, pending
+// until the recipient accepts it
+type OutingTransfer struct {
+	fromUserID  string
+	toUserID    string
+	initiatedAt time.Time
+}
+
+func ReconstructOutingTransfer(fromUserID, toUserID string, initiatedAt time.Time) OutingTransfer {
+	return OutingTransfer{
+		fromUserID:  fromUserID,
+		toUserID:    toUserID,
+		initiatedAt: initiatedAt,
+	}
+}
+
+func (t OutingTransfer) FromUserID() string     { return t.fromUserID }
+func (t OutingTransfer) ToUserID() string       { return t.toUserID }
+func (t OutingTransfer) InitiatedAt() time.Time { return t.initiatedAt }
 
 // TimelineEntry represents a status change in the outing lifecycle
 type TimelineEntry struct {
@@ -365,9 +779,11 @@ type Outing struct {
 	// Snapshots (immutable at booking time)
 	offer OfferSnapshot
 	user  UserSnapshot
+	slot  *SlotSnapshot // nil for "book now" outings
 
-	// QR Code
-	qrCode QRCode
+	// QR Code, and the short code typed in when it cannot be scanned
+	qrCode    QRCode
+	shortCode string
 
 	// Status & Timeline
 	status   OutingStatus
@@ -379,6 +795,12 @@ type Outing struct {
 	// Cancellation details (optional)
 	cancellation *CancellationInfo
 
+	// Partner confirmation deadline, set for outings booked pending
+	confirmBy *time.Time
+
+	// Transfer to another user awaiting their acceptance (optional)
+	transfer *OutingTransfer
+
 	// Timing
 	bookedAt  time.Time
 	expiresAt time.Time
@@ -394,44 +816,123 @@ func NewOuting(
 	offer OfferSnapshot,
 	user UserSnapshot,
 	expirationMinutes int,
+) (*Outing, error) {
+	expiresAt := time.Now().Add(time.Duration(expirationMinutes) * time.Minute)
+	return newOuting(userID, offer, user, nil, expiresAt, 0)
+}
+
+// NewSlotOuting books a specific slot instance. The outing stays valid until
+// the slot ends.
+func NewSlotOuting(
+	userID string,
+	offer OfferSnapshot,
+	user UserSnapshot,
+	slot SlotSnapshot,
+) (*Outing, error) {
+	return newOuting(userID, offer, user, &slot, slot.EndsAt(), 0)
+}
+
+// NewPendingOuting books an offer whose partner approves each booking. The
+// outing stays pending until the partner confirms or rejects it; it is
+// rejected automatically once confirmationTimeout has elapsed.
+func NewPendingOuting(
+	userID string,
+	offer OfferSnapshot,
+	user UserSnapshot,
+	expirationMinutes int,
+	confirmationTimeout time.Duration,
+) (*Outing, error) {
+	expiresAt := time.Now().Add(time.Duration(expirationMinutes) * time.Minute)
+	return newOuting(userID, offer, user, nil, expiresAt, confirmationTimeout)
+}
+
+// NewPendingSlotOuting is NewPendingOuting for a specific slot instance
+func NewPendingSlotOuting(
+	userID string,
+	offer OfferSnapshot,
+	user UserSnapshot,
+	slot SlotSnapshot,
+	confirmationTimeout time.Duration,
+) (*Outing, error) {
+	return newOuting(userID, offer, user, &slot, slot.EndsAt(), confirmationTimeout)
+}
+
+// newOuting creates a confirmed outing, or a pending one when a confirmation
+// timeout is given. The confirmation deadline never goes past the start of
+// the slot or the outing's expiry.
+func newOuting(
+	userID string,
+	offer OfferSnapshot,
+	user UserSnapshot,
+	slot *SlotSnapshot,
+	expiresAt time.Time,
+	confirmationTimeout time.Duration,
 ) (*Outing, error) {
 	now := time.Now()
-	expiresAt := now.Add(time.Duration(expirationMinutes) * time.Minute)
 
 	qrCode, err := NewQRCode(expiresAt)
 	if err != nil {
 		return nil, fmt.Errorf("failed to generate QR code: %w", err)
 	}
 
-	id := domain.NewID()
+	shortCode, err := NewShortCode()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate short code: %w", err)
+	}
+
+	// ObjectID-backed so the ID survives persistence and matches published events
+	id := domain.NewBaseID().String()
+
+	status := OutingStatusConfirmed
+	var confirmBy *time.Time
+	if confirmationTimeout > 0 {
+		deadline := now.Add(confirmationTimeout)
+		if slot != nil && slot.StartsAt().Before(deadline) {
+			deadline = slot.StartsAt()
+		}
+		if expiresAt.Before(deadline) {
+			deadline = expiresAt
+		}
+		status = OutingStatusPending
+		confirmBy = &deadline
+	}
 
 	outing := &Outing{
-		id:     id,
-		userID: userID,
-		offer:  offer,
-		user:   user,
-		qrCode: qrCode,
-		status: OutingStatusConfirmed,
+		id:        id,
+		userID:    userID,
+		offer:     offer,
+		user:      user,
+		slot:      slot,
+		qrCode:    qrCode,
+		shortCode: shortCode,
+		status:    status,
 		timeline: []TimelineEntry{
-			NewTimelineEntry(OutingStatusConfirmed, "system", map[string]interface{}{
+			NewTimelineEntry(status, "system", map[string]interface{}{
 				"action": "booking_created",
 			}),
 		},
+		confirmBy: confirmBy,
 		bookedAt:  now,
 		expiresAt: expiresAt,
 		createdAt: now,
 		updatedAt: now,
 	}
 
-	outing.AddDomainEvent(NewOutingBookedEvent(
+	event := NewOutingBookedEvent(
 		id,
 		userID,
 		offer.OfferID(),
 		offer.PartnerID(),
 		offer.EstablishmentID(),
-		qrCode.FullCode(),
+		qrCode.Code(),
 		expiresAt,
-	))
+	)
+	if slot != nil {
+		startsAt := slot.StartsAt()
+		event.SlotStartsAt = &startsAt
+	}
+	event.ConfirmBy = confirmBy
+	outing.AddDomainEvent(event)
 
 	return outing, nil
 }
@@ -441,11 +942,15 @@ func ReconstructOuting(
 	id, userID string,
 	offer OfferSnapshot,
 	user UserSnapshot,
+	slot *SlotSnapshot,
 	qrCode QRCode,
+	shortCode string,
 	status OutingStatus,
 	timeline []TimelineEntry,
 	checkIn *CheckInInfo,
 	cancellation *CancellationInfo,
+	confirmBy *time.Time,
+	transfer *OutingTransfer,
 	bookedAt, expiresAt time.Time,
 	createdAt, updatedAt time.Time,
 ) *Outing {
@@ -454,11 +959,15 @@ func ReconstructOuting(
 		userID:       userID,
 		offer:        offer,
 		user:         user,
+		slot:         slot,
 		qrCode:       qrCode,
+		shortCode:    shortCode,
 		status:       status,
 		timeline:     timeline,
 		checkIn:      checkIn,
 		cancellation: cancellation,
+		confirmBy:    confirmBy,
+		transfer:     transfer,
 		bookedAt:     bookedAt,
 		expiresAt:    expiresAt,
 		createdAt:    createdAt,
@@ -471,11 +980,15 @@ func (o *Outing) ID() string                      { return o.id }
 func (o *Outing) UserID() string                  { return o.userID }
 func (o *Outing) Offer() OfferSnapshot            { return o.offer }
 func (o *Outing) User() UserSnapshot              { return o.user }
+func (o *Outing) Slot() *SlotSnapshot             { return o.slot }
 func (o *Outing) QRCode() QRCode                  { return o.qrCode }
+func (o *Outing) ShortCode() string               { return o.shortCode }
 func (o *Outing) Status() OutingStatus            { return o.status }
 func (o *Outing) Timeline() []TimelineEntry       { return o.timeline }
 func (o *Outing) CheckIn() *CheckInInfo           { return o.checkIn }
 func (o *Outing) Cancellation() *CancellationInfo { return o.cancellation }
+func (o *Outing) ConfirmBy() *time.Time           { return o.confirmBy }
+func (o *Outing) Transfer() *OutingTransfer       { return o.transfer }
 func (o *Outing) BookedAt() time.Time             { return o.bookedAt }
 func (o *Outing) ExpiresAt() time.Time            { return o.expiresAt }
 func (o *Outing) CreatedAt() time.Time            { return o.createdAt }
@@ -483,39 +996,237 @@ func (o *Outing) UpdatedAt() time.Time            { return o.updatedAt }
 
 // Business Logic
 func (o *Outing) CanCheckIn() error {
+	return o.canCheckInAt(time.Now())
+}
+
+func (o *Outing) canCheckInAt(at time.Time) error {
 	if o.status == OutingStatusCancelled {
 		return ErrOutingCancelled
 	}
+	if o.status == OutingStatusPending {
+		return ErrAwaitingConfirmation
+	}
 	if o.status == OutingStatusCheckedIn {
 		return ErrOutingAlreadyUsed
 	}
 	if o.status == OutingStatusExpired || o.status == OutingStatusNoShow {
 		return ErrOutingExpired
 	}
-	if time.Now().After(o.expiresAt) {
+	if at.After(o.expiresAt) {
 		return ErrOutingExpired
 	}
+	if o.slot != nil && at.Before(o.slot.StartsAt().Add(-slotCheckInLeadTime)) {
+		return ErrInvalidCheckInWindow
+	}
 	return nil
 }
 
-func (o *Outing) CheckInWithQR(scannedQR string, staffUserID string, lat, lng *float64) error {
+// CheckInWithQR checks in with a scanned QR payload or wallet pass. The
+// location is checked against the geofence, if any, once the QR code itself
+// is valid.
+func (o *Outing) CheckInWithQR(keys *CheckInKeys, scannedQR string, staffUserID string, lat, lng *float64, geofence *Geofence) error {
 	if err := o.CanCheckIn(); err != nil {
 		return err
 	}
 
-	if !o.qrCode.Verify(scannedQR) {
+	if err := o.verifyScan(keys, scannedQR, time.Now(), 0); err != nil {
+		return err
+	}
+
+	location, err := geofence.CheckLocation(o.offer, lat, lng)
+	if err != nil {
+		return err
+	}
+
+	o.recordCheckIn(NewCheckInInfo(staffUserID, CheckInMethodQRScan, lat, lng).withLocationCheck(location))
+
+	return nil
+}
+
+// CheckInWithQROffline applies a scan made by a partner scanner without
+// connectivity. The rotating payload, or the wallet pass, is verified against
+// scannedAt rather than the sync time, so an old screenshot is still refused,
+// and the signed offline token against the outing; the check-in is recorded
+// at scannedAt.
+func (o *Outing) CheckInWithQROffline(keys *CheckInKeys, scannedQR string, staffUserID string, scannedAt time.Time, lat, lng *float64, geofence *Geofence) error {
+	if err := o.verifyScan(keys, scannedQR, scannedAt, offlineScanClockSkew); err != nil {
+		return err
+	}
+
+	_, token := splitQRPayload(scannedQR)
+	claims, err := keys.Tokens().ParseToken(token, scannedAt)
+	if err != nil {
+		return err
+	}
+	if !o.matchesToken(claims) {
 		return ErrInvalidQRCode
 	}
+	if scannedAt.Unix() > claims.ExpiresAt {
+		return ErrOutingExpired
+	}
+	if scannedAt.Unix() < claims.NotBefore {
+		return ErrInvalidCheckInWindow
+	}
 
-	now := time.Now()
+	if err := o.canCheckInAt(scannedAt); err != nil {
+		return err
+	}
+
+	location, err := geofence.CheckLocation(o.offer, lat, lng)
+	if err != nil {
+		return err
+	}
+
+	checkIn := CheckInInfo{
+		checkedInAt: scannedAt,
+		checkedInBy: staffUserID,
+		method:      CheckInMethodOfflineScan,
+		latitude:    lat,
+		longitude:   lng,
+	}
+	o.recordCheckIn(checkIn.withLocationCheck(location))
+
+	return nil
+}
+
+// CheckInWithShortCode checks in with the short code read out by the user,
+// for when the QR code cannot be scanned
+func (o *Outing) CheckInWithShortCode(code string, staffUserID string, lat, lng *float64, geofence *Geofence) error {
+	if err := o.CanCheckIn(); err != nil {
+		return err
+	}
+
+	normalized, err := NormalizeShortCode(code)
+	if err != nil {
+		return err
+	}
+	if subtle.ConstantTimeCompare([]byte(normalized), []byte(o.shortCode)) != 1 {
+		return ErrInvalidShortCode
+	}
+
+	location, err := geofence.CheckLocation(o.offer, lat, lng)
+	if err != nil {
+		return err
+	}
+
+	o.recordCheckIn(NewCheckInInfo(staffUserID, CheckInMethodShortCode, lat, lng).withLocationCheck(location))
+
+	return nil
+}
+
+func (o *Outing) CheckInManual(staffUserID string, lat, lng *float64, geofence *Geofence) error {
+	if err := o.CanCheckIn(); err != nil {
+		return err
+	}
+
+	location, err := geofence.CheckLocation(o.offer, lat, lng)
+	if err != nil {
+		return err
+	}
+
+	o.recordCheckIn(NewCheckInInfo(staffUserID, CheckInMethodManual, lat, lng).withLocationCheck(location))
+
+	return nil
+}
+
+// FlagAnomaly flags a checked-in outing for review. It reports false, leaving
+// the outing unchanged, when the outing is not checked in or was already
+// flagged for the reason.
+func (o *Outing) FlagAnomaly(reason AnomalyReason, detail string) bool {
+	if o.status != OutingStatusCheckedIn || o.checkIn == nil || o.checkIn.HasAnomaly(reason) {
+		return false
+	}
+
+	o.checkIn.anomalies = append(o.checkIn.anomalies, NewCheckInAnomaly(reason, detail))
+	o.checkIn.flaggedForReview = true
+	if o.checkIn.reviewReason == "" {
+		o.checkIn.reviewReason = string(reason)
+	}
+	o.updatedAt = time.Now()
+	o.timeline = append(o.timeline, NewTimelineEntry(o.status, "system", map[string]interface{}{
+		"action": "flagged",
+		"reason": string(reason),
+		"detail": detail,
+	}))
+
+	o.AddDomainEvent(NewOutingFlaggedEvent(
+		o.id,
+		o.userID,
+		o.offer.OfferID(),
+		o.offer.PartnerID(),
+		o.offer.EstablishmentID(),
+		string(reason),
+		detail,
+	))
+
+	return true
+}
+
+// RecordBill declares what the bill came to before the discount, replacing any
+// amount declared before so staff can correct a mistake
+func (o *Outing) RecordBill(amountCents int64, staffUserID string) error {
+	if o.status != OutingStatusCheckedIn || o.checkIn == nil {
+		return ErrOutingNotCheckedIn
+	}
+
+	bill, err := NewBill(amountCents, o.offer, staffUserID)
+	if err != nil {
+		return err
+	}
+
+	metadata := map[string]interface{}{
+		"action":        "bill_recorded",
+		"amount_cents":  bill.AmountCents(),
+		"savings_cents": bill.SavingsCents(),
+	}
+	if previous := o.checkIn.Bill(); previous != nil {
+		metadata["previous_amount_cents"] = previous.AmountCents()
+	}
+
+	o.checkIn.bill = &bill
+	o.updatedAt = time.Now()
+	o.timeline = append(o.timeline, NewTimelineEntry(o.status, staffUserID, metadata))
+
+	o.AddDomainEvent(NewOutingBillRecordedEvent(
+		o.id,
+		o.userID,
+		o.offer.OfferID(),
+		o.offer.PartnerID(),
+		o.offer.EstablishmentID(),
+		bill.AmountCents(),
+		bill.SavingsCents(),
+	))
+
+	return nil
+}
+
+// RegenerateShortCode draws a new short code, e.g. when the outing's code
+// turned out to be taken at the establishment
+func (o *Outing) RegenerateShortCode() error {
+	shortCode, err := NewShortCode()
+	if err != nil {
+		return fmt.Errorf("failed to generate short code: %w", err)
+	}
+	o.shortCode = shortCode
+	return nil
+}
+
+func (o *Outing) recordCheckIn(checkIn CheckInInfo) {
 	o.status = OutingStatusCheckedIn
-	checkIn := NewCheckInInfo(staffUserID, CheckInMethodQRScan, lat, lng)
 	o.checkIn = &checkIn
-	o.updatedAt = now
+	o.updatedAt = time.Now()
 
-	o.timeline = append(o.timeline, NewTimelineEntry(OutingStatusCheckedIn, staffUserID, map[string]interface{}{
-		"method": string(CheckInMethodQRScan),
-	}))
+	metadata := map[string]interface{}{
+		"method": string(checkIn.Method()),
+	}
+	if checkIn.DistanceMeters() != nil {
+		metadata["distance_meters"] = math.Round(*checkIn.DistanceMeters())
+	}
+	if checkIn.FlaggedForReview() {
+		metadata["flagged_for_review"] = true
+		metadata["review_reason"] = checkIn.ReviewReason()
+	}
+	o.timeline = append(o.timeline, NewTimelineEntry(OutingStatusCheckedIn, checkIn.CheckedInBy(), metadata))
 
 	o.AddDomainEvent(NewOutingCheckedInEvent(
 		o.id,
@@ -523,47 +1234,96 @@ func (o *Outing) CheckInWithQR(scannedQR string, staffUserID string, lat, lng *f
 		o.offer.OfferID(),
 		o.offer.PartnerID(),
 		o.offer.EstablishmentID(),
-		staffUserID,
-		string(CheckInMethodQRScan),
+		checkIn.CheckedInBy(),
+		string(checkIn.Method()),
+	))
+}
+
+// Cancel cancels the outing. A user cancellation is late when the policy, if
+// any, says so; partner and system cancellations never are.
+func (o *Outing) Cancel(actor CancellationActor, reason string, policy *CancellationPolicy) error {
+	switch o.status {
+	case OutingStatusCheckedIn:
+		return ErrCannotCancelUsed
+	case OutingStatusCancelled:
+		return ErrOutingCancelled
+	case OutingStatusExpired, OutingStatusNoShow:
+		return ErrOutingExpired
+	}
+
+	now := time.Now()
+	o.status = OutingStatusCancelled
+	cancellation := NewCancellationInfo(actor, reason)
+	cancellation.late = actor == CancellationActorUser && policy != nil && policy.IsLate(o, now)
+	o.cancellation = &cancellation
+	o.updatedAt = now
+
+	metadata := map[string]interface{}{
+		"reason": reason,
+	}
+	if cancellation.late {
+		metadata["late"] = true
+	}
+	o.timeline = append(o.timeline, NewTimelineEntry(OutingStatusCancelled, string(actor), metadata))
+
+	o.AddDomainEvent(NewOutingCancelledEvent(
+		o.id,
+		o.userID,
+		o.offer.OfferID(),
+		o.offer.PartnerID(),
+		string(actor),
+		reason,
+		cancellation.late,
 	))
 
 	return nil
 }
 
-func (o *Outing) CheckInManual(staffUserID string, lat, lng *float64) error {
-	if err := o.CanCheckIn(); err != nil {
-		return err
+// Confirm records the partner's approval of a pending outing
+func (o *Outing) Confirm(staffUserID string) error {
+	if o.status != OutingStatusPending {
+		return ErrOutingNotPending
 	}
 
 	now := time.Now()
-	o.status = OutingStatusCheckedIn
-	checkIn := NewCheckInInfo(staffUserID, CheckInMethodManual, lat, lng)
-	o.checkIn = &checkIn
+	if o.confirmBy != nil && now.After(*o.confirmBy) {
+		return ErrConfirmationTimedOut
+	}
+
+	o.status = OutingStatusConfirmed
 	o.updatedAt = now
 
-	o.timeline = append(o.timeline, NewTimelineEntry(OutingStatusCheckedIn, staffUserID, map[string]interface{}{
-		"method": string(CheckInMethodManual),
+	o.timeline = append(o.timeline, NewTimelineEntry(OutingStatusConfirmed, staffUserID, map[string]interface{}{
+		"action": "partner_confirmed",
 	}))
 
-	o.AddDomainEvent(NewOutingCheckedInEvent(
+	o.AddDomainEvent(NewOutingConfirmedEvent(
 		o.id,
 		o.userID,
 		o.offer.OfferID(),
 		o.offer.PartnerID(),
 		o.offer.EstablishmentID(),
 		staffUserID,
-		string(CheckInMethodManual),
 	))
 
 	return nil
 }
 
-func (o *Outing) Cancel(actor CancellationActor, reason string) error {
-	if o.status == OutingStatusCheckedIn {
-		return ErrCannotCancelUsed
-	}
-	if o.status == OutingStatusCancelled {
-		return ErrOutingCancelled
+// Reject turns down a pending outing on behalf of the partner; the outing
+// ends up cancelled by the partner
+func (o *Outing) Reject(staffUserID, reason string) error {
+	return o.reject(CancellationActorPartner, staffUserID, reason, "partner_rejected")
+}
+
+// RejectUnconfirmed turns down a pending outing the partner did not answer
+// before the confirmation deadline
+func (o *Outing) RejectUnconfirmed() error {
+	return o.reject(CancellationActorSystem, "system", "confirmation_timeout", "auto_rejected")
+}
+
+func (o *Outing) reject(actor CancellationActor, rejectedBy, reason, action string) error {
+	if o.status != OutingStatusPending {
+		return ErrOutingNotPending
 	}
 
 	now := time.Now()
@@ -572,28 +1332,165 @@ func (o *Outing) Cancel(actor CancellationActor, reason string) error {
 	o.cancellation = &cancellation
 	o.updatedAt = now
 
-	o.timeline = append(o.timeline, NewTimelineEntry(OutingStatusCancelled, string(actor), map[string]interface{}{
+	o.timeline = append(o.timeline, NewTimelineEntry(OutingStatusCancelled, rejectedBy, map[string]interface{}{
+		"action": action,
 		"reason": reason,
 	}))
 
-	o.AddDomainEvent(NewOutingCancelledEvent(
+	o.AddDomainEvent(NewOutingRejectedEvent(
 		o.id,
 		o.userID,
 		o.offer.OfferID(),
 		o.offer.PartnerID(),
-		string(actor),
+		o.offer.EstablishmentID(),
+		rejectedBy,
 		reason,
+		actor == CancellationActorSystem,
 	))
 
 	return nil
 }
 
-func (o *Outing) MarkAsExpired() error {
-	if o.status == OutingStatusCheckedIn {
+// InitiateTransfer offers the outing to another user, replacing any transfer
+// still pending. The owner keeps the outing until the recipient accepts.
+func (o *Outing) InitiateTransfer(ownerUserID, recipientUserID string) error {
+	if ownerUserID != o.userID {
+		return ErrNotOutingOwner
+	}
+	if recipientUserID == o.userID {
+		return ErrTransferToSelf
+	}
+	if err := o.canTransfer(); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	o.transfer = &OutingTransfer{
+		fromUserID:  o.userID,
+		toUserID:    recipientUserID,
+		initiatedAt: now,
+	}
+	o.updatedAt = now
+
+	o.timeline = append(o.timeline, NewTimelineEntry(o.status, ownerUserID, map[string]interface{}{
+		"action":     "transfer_initiated",
+		"to_user_id": recipientUserID,
+	}))
+
+	o.AddDomainEvent(NewOutingTransferInitiatedEvent(
+		o.id,
+		o.userID,
+		o.offer.OfferID(),
+		o.offer.PartnerID(),
+		recipientUserID,
+	))
+
+	return nil
+}
+
+// CancelTransfer withdraws the pending transfer, by the owner, or declines it,
+// by the recipient
+func (o *Outing) CancelTransfer(userID string) error {
+	if o.transfer == nil {
+		return ErrNoPendingTransfer
+	}
+
+	action := "transfer_cancelled"
+	switch userID {
+	case o.userID:
+	case o.transfer.toUserID:
+		action = "transfer_declined"
+	default:
+		return ErrNotOutingOwner
+	}
+
+	o.transfer = nil
+	o.updatedAt = time.Now()
+
+	o.timeline = append(o.timeline, NewTimelineEntry(o.status, userID, map[string]interface{}{
+		"action": action,
+	}))
+
+	return nil
+}
+
+// CanAcceptTransfer checks that the user may accept the pending transfer
+func (o *Outing) CanAcceptTransfer(userID string) error {
+	if o.transfer == nil {
+		return ErrNoPendingTransfer
+	}
+	if userID != o.transfer.toUserID {
+		return ErrNotTransferRecipient
+	}
+	return o.canTransfer()
+}
+
+// AcceptTransfer hands the outing over to the recipient. A new QR code and
+// short code are issued, so the previous owner's credentials are refused by
+// online check-in and by offline sync; a scanner that has been offline since
+// the transfer may still accept them until its scans are synced.
+func (o *Outing) AcceptTransfer(recipient UserSnapshot) error {
+	if err := o.CanAcceptTransfer(recipient.UserID()); err != nil {
+		return err
+	}
+
+	qrCode, err := NewQRCode(o.expiresAt)
+	if err != nil {
+		return fmt.Errorf("failed to generate QR code: %w", err)
+	}
+	if err := o.RegenerateShortCode(); err != nil {
+		return err
+	}
+
+	fromUserID := o.userID
+	o.userID = recipient.UserID()
+	o.user = recipient
+	o.qrCode = qrCode
+	o.transfer = nil
+	o.updatedAt = time.Now()
+
+	o.timeline = append(o.timeline, NewTimelineEntry(o.status, recipient.UserID(), map[string]interface{}{
+		"action":       "transferred",
+		"from_user_id": fromUserID,
+		"to_user_id":   recipient.UserID(),
+	}))
+
+	o.AddDomainEvent(NewOutingTransferredEvent(
+		o.id,
+		fromUserID,
+		recipient.UserID(),
+		o.offer.OfferID(),
+		o.offer.PartnerID(),
+		o.offer.EstablishmentID(),
+	))
+
+	return nil
+}
+
+// canTransfer checks that the outing is booked, not yet used and not expired
+func (o *Outing) canTransfer() error {
+	switch o.status {
+	case OutingStatusCancelled:
+		return ErrOutingCancelled
+	case OutingStatusCheckedIn:
 		return ErrOutingAlreadyUsed
+	case OutingStatusExpired, OutingStatusNoShow:
+		return ErrOutingExpired
 	}
-	if o.status == OutingStatusCancelled {
+	if time.Now().After(o.expiresAt) {
+		return ErrOutingExpired
+	}
+	return nil
+}
+
+func (o *Outing) MarkAsExpired() error {
+	switch o.status {
+	case OutingStatusCheckedIn:
+		return ErrOutingAlreadyUsed
+	case OutingStatusCancelled:
 		return ErrOutingCancelled
+	case OutingStatusExpired, OutingStatusNoShow:
+		return ErrOutingExpired
 	}
 
 	now := time.Now()
@@ -615,6 +1512,9 @@ func (o *Outing) MarkAsExpired() error {
 }
 
 func (o *Outing) MarkAsNoShow() error {
+	if o.status == OutingStatusNoShow {
+		return ErrInvalidOutingStatus
+	}
 	if o.status == OutingStatusCheckedIn {
 		return ErrOutingAlreadyUsed
 	}
@@ -630,6 +1530,14 @@ func (o *Outing) MarkAsNoShow() error {
 		"action": "marked_no_show",
 	}))
 
+	o.AddDomainEvent(NewOutingNoShowEvent(
+		o.id,
+		o.userID,
+		o.offer.OfferID(),
+		o.offer.PartnerID(),
+		o.offer.EstablishmentID(),
+	))
+
 	return nil
 }
 
@@ -1,10 +1,40 @@
 package domain
 
 import (
+	"crypto/ed25519"
+	"strconv"
+	"strings"
 	"testing"
 	"time"
 )
 
+const testQRWindow = 15 * time.Second
+
+// newTestCheckInKeys returns keys with an active and a retired key in both
+// the QR ring and the offline token set
+func newTestCheckInKeys(t *testing.T) *CheckInKeys {
+	t.Helper()
+
+	retiredAt := time.Now().Add(-time.Hour)
+	ring, err := NewQRKeyRing([]QRKey{
+		{ID: "v2", Secret: []byte("test-secret-v2")},
+		{ID: "v1", Secret: []byte("test-secret-v1"), RetiredAt: &retiredAt},
+	}, testQRWindow, 24*time.Hour)
+	if err != nil {
+		t.Fatalf("NewQRKeyRing() error = %v, want nil", err)
+	}
+
+	keySet, err := NewCheckInKeySet([]CheckInSigningKey{
+		{ID: "k2", PrivateKey: ed25519.NewKeyFromSeed([]byte("test-checkin-seed-v2-32-bytes-ok"))},
+		{ID: "k1", PrivateKey: ed25519.NewKeyFromSeed([]byte("test-checkin-seed-v1-32-bytes-ok")), RetiredAt: &retiredAt},
+	}, 24*time.Hour)
+	if err != nil {
+		t.Fatalf("NewCheckInKeySet() error = %v, want nil", err)
+	}
+
+	return NewCheckInKeys(ring, keySet)
+}
+
 // =============================================================================
 // Outing Creation Tests
 // =============================================================================
@@ -83,22 +113,27 @@ func TestNewQRCode(t *testing.T) {
 	if qr.Code() == "" {
 		t.Error("NewQRCode() code should not be empty")
 	}
-	if qr.Signature() == "" {
-		t.Error("NewQRCode() signature should not be empty")
-	}
 	if qr.ExpiresAt() != expiresAt {
 		t.Errorf("NewQRCode() expiresAt = %v, want %v", qr.ExpiresAt(), expiresAt)
 	}
 }
 
-func TestQRCode_FullCode(t *testing.T) {
+func TestQRCode_Payload(t *testing.T) {
+	ring := newTestCheckInKeys(t).QR()
 	qr, _ := NewQRCode(time.Now().Add(30 * time.Minute))
+	at := time.Unix(1_000_000_005, 0)
 
-	fullCode := qr.FullCode()
-	expected := qr.Code() + "." + qr.Signature()
+	payload, refreshAt, err := qr.Payload(ring, at)
+	if err != nil {
+		t.Fatalf("QRCode.Payload() error = %v, want nil", err)
+	}
 
-	if fullCode != expected {
-		t.Errorf("QRCode.FullCode() = %v, want %v", fullCode, expected)
+	parts := strings.Split(payload, ".")
+	if len(parts) != 4 || parts[0] != qr.Code() || parts[1] != "v2" {
+		t.Errorf("QRCode.Payload() = %v, want code.v2.window.signature", payload)
+	}
+	if want := time.Unix(1_000_000_020, 0); !refreshAt.Equal(want) {
+		t.Errorf("QRCode.Payload() refreshAt = %v, want %v", refreshAt, want)
 	}
 }
 
@@ -122,7 +157,7 @@ func TestQRCode_IsExpired(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			qr := ReconstructQRCode("code", "sig", time.Now(), tt.expiresAt)
+			qr := ReconstructQRCode("code", time.Now(), tt.expiresAt)
 			if got := qr.IsExpired(); got != tt.want {
 				t.Errorf("QRCode.IsExpired() = %v, want %v", got, tt.want)
 			}
@@ -130,51 +165,86 @@ func TestQRCode_IsExpired(t *testing.T) {
 	}
 }
 
-func TestQRCode_Matches(t *testing.T) {
+func TestQRCode_Verify(t *testing.T) {
+	ring := newTestCheckInKeys(t).QR()
 	qr, _ := NewQRCode(time.Now().Add(30 * time.Minute))
+	other, _ := NewQRCode(time.Now().Add(30 * time.Minute))
+	now := time.Now()
+
+	current, _, _ := qr.Payload(ring, now)
+	previous, _, _ := qr.Payload(ring, now.Add(-testQRWindow))
+	stale, _, _ := qr.Payload(ring, now.Add(-3*testQRWindow))
+	otherPayload, _, _ := other.Payload(ring, now)
+	parts := strings.Split(current, ".")
 
 	tests := []struct {
 		name    string
 		scanned string
-		want    bool
+		want    error
 	}{
-		{
-			name:    "matches code only",
-			scanned: qr.Code(),
-			want:    true,
-		},
-		{
-			name:    "matches full code",
-			scanned: qr.FullCode(),
-			want:    true,
-		},
-		{
-			name:    "does not match wrong code",
-			scanned: "wrong-code",
-			want:    false,
-		},
+		{"current window", current, nil},
+		{"previous window", previous, nil},
+		{"stale window", stale, ErrQRCodeStale},
+		{"bare code", qr.Code(), ErrInvalidQRCode},
+		{"other outing", otherPayload, ErrInvalidQRCode},
+		{"tampered window", strings.Join([]string{parts[0], parts[1], "1", parts[3]}, "."), ErrInvalidQRCode},
+		{"unknown key", strings.Join([]string{parts[0], "v9", parts[2], parts[3]}, "."), ErrInvalidQRCode},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			if got := qr.Matches(tt.scanned); got != tt.want {
-				t.Errorf("QRCode.Matches(%q) = %v, want %v", tt.scanned, got, tt.want)
+			if err := qr.Verify(ring, tt.scanned, now); err != tt.want {
+				t.Errorf("QRCode.Verify() error = %v, want %v", err, tt.want)
 			}
 		})
 	}
 }
 
-func TestQRCode_Verify(t *testing.T) {
+func TestQRCode_Verify_RetiredKey(t *testing.T) {
+	ring := newTestCheckInKeys(t).QR()
 	qr, _ := NewQRCode(time.Now().Add(30 * time.Minute))
+	now := time.Now()
+
+	// Sign with the retired key the way a replica not yet rotated would
+	window := ring.windowAt(now)
+	retired := ring.keys["v1"]
+	payload := strings.Join([]string{qr.Code(), "v1", strconv.FormatInt(window, 10), ring.sign(retired, qr.Code(), window)}, ".")
 
-	// Valid code should verify
-	if !qr.Verify(qr.Code()) {
-		t.Error("QRCode.Verify() should return true for valid code")
+	// Accepted within the grace period...
+	if err := qr.Verify(ring, payload, now); err != nil {
+		t.Errorf("QRCode.Verify() retired key within grace error = %v, want nil", err)
+	}
+
+	// ...and rejected after it
+	afterGrace := retired.RetiredAt.Add(ring.GracePeriod() + time.Minute)
+	window = ring.windowAt(afterGrace)
+	payload = strings.Join([]string{qr.Code(), "v1", strconv.FormatInt(window, 10), ring.sign(retired, qr.Code(), window)}, ".")
+	if err := qr.Verify(ring, payload, afterGrace); err != ErrInvalidQRCode {
+		t.Errorf("QRCode.Verify() retired key after grace error = %v, want %v", err, ErrInvalidQRCode)
+	}
+}
+
+func TestNewQRKeyRing_Errors(t *testing.T) {
+	retiredAt := time.Now()
+
+	tests := []struct {
+		name string
+		keys []QRKey
+	}{
+		{"no keys", nil},
+		{"no active key", []QRKey{{ID: "v1", Secret: []byte("s"), RetiredAt: &retiredAt}}},
+		{"two active keys", []QRKey{{ID: "v1", Secret: []byte("s")}, {ID: "v2", Secret: []byte("s")}}},
+		{"duplicate key", []QRKey{{ID: "v1", Secret: []byte("s")}, {ID: "v1", Secret: []byte("s"), RetiredAt: &retiredAt}}},
+		{"dot in key ID", []QRKey{{ID: "v.1", Secret: []byte("s")}}},
+		{"empty secret", []QRKey{{ID: "v1"}}},
 	}
 
-	// Invalid code should not verify
-	if qr.Verify("wrong-code") {
-		t.Error("QRCode.Verify() should return false for invalid code")
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewQRKeyRing(tt.keys, testQRWindow, time.Hour); err == nil {
+				t.Error("NewQRKeyRing() error = nil, want error")
+			}
+		})
 	}
 }
 
@@ -237,11 +307,12 @@ func TestOuting_CanCheckIn(t *testing.T) {
 }
 
 func TestOuting_CheckInWithQR(t *testing.T) {
+	keys := newTestCheckInKeys(t)
 	outing := createTestOuting()
-	qrCode := outing.QRCode().Code()
+	qrCode, _, _ := outing.QRCode().Payload(keys.QR(), time.Now())
 	staffID := "staff-123"
 
-	err := outing.CheckInWithQR(qrCode, staffID, nil, nil)
+	err := outing.CheckInWithQR(keys, qrCode, staffID, nil, nil, nil)
 
 	if err != nil {
 		t.Fatalf("CheckInWithQR() error = %v, want nil", err)
@@ -261,9 +332,10 @@ func TestOuting_CheckInWithQR(t *testing.T) {
 }
 
 func TestOuting_CheckInWithQR_InvalidQR(t *testing.T) {
+	keys := newTestCheckInKeys(t)
 	outing := createTestOuting()
 
-	err := outing.CheckInWithQR("invalid-qr-code", "staff-123", nil, nil)
+	err := outing.CheckInWithQR(keys, "invalid-qr-code", "staff-123", nil, nil, nil)
 
 	if err != ErrInvalidQRCode {
 		t.Errorf("CheckInWithQR() error = %v, want %v", err, ErrInvalidQRCode)
@@ -274,14 +346,15 @@ func TestOuting_CheckInWithQR_InvalidQR(t *testing.T) {
 }
 
 func TestOuting_CheckInWithQR_AlreadyCheckedIn(t *testing.T) {
+	keys := newTestCheckInKeys(t)
 	outing := createTestOuting()
-	qrCode := outing.QRCode().Code()
+	qrCode, _, _ := outing.QRCode().Payload(keys.QR(), time.Now())
 
 	// First check-in should succeed
-	_ = outing.CheckInWithQR(qrCode, "staff-123", nil, nil)
+	_ = outing.CheckInWithQR(keys, qrCode, "staff-123", nil, nil, nil)
 
 	// Second check-in should fail
-	err := outing.CheckInWithQR(qrCode, "staff-456", nil, nil)
+	err := outing.CheckInWithQR(keys, qrCode, "staff-456", nil, nil, nil)
 
 	if err != ErrOutingAlreadyUsed {
 		t.Errorf("CheckInWithQR() second attempt error = %v, want %v", err, ErrOutingAlreadyUsed)
@@ -292,7 +365,7 @@ func TestOuting_CheckInManual(t *testing.T) {
 	outing := createTestOuting()
 	staffID := "staff-123"
 
-	err := outing.CheckInManual(staffID, nil, nil)
+	err := outing.CheckInManual(staffID, nil, nil, nil)
 
 	if err != nil {
 		t.Fatalf("CheckInManual() error = %v, want nil", err)
@@ -313,7 +386,7 @@ func TestOuting_Cancel(t *testing.T) {
 	outing := createTestOuting()
 	reason := "User requested cancellation"
 
-	err := outing.Cancel(CancellationActorUser, reason)
+	err := outing.Cancel(CancellationActorUser, reason, nil)
 
 	if err != nil {
 		t.Fatalf("Cancel() error = %v, want nil", err)
@@ -336,13 +409,333 @@ func TestOuting_Cancel_AlreadyCheckedIn(t *testing.T) {
 	outing := createTestOuting()
 	outing.status = OutingStatusCheckedIn
 
-	err := outing.Cancel(CancellationActorUser, "reason")
+	err := outing.Cancel(CancellationActorUser, "reason", nil)
 
 	if err != ErrCannotCancelUsed {
 		t.Errorf("Cancel() error = %v, want %v", err, ErrCannotCancelUsed)
 	}
 }
 
+func TestOuting_Cancel_Terminal(t *testing.T) {
+	tests := []struct {
+		status OutingStatus
+		want   error
+	}{
+		{OutingStatusCheckedIn, ErrCannotCancelUsed},
+		{OutingStatusCancelled, ErrOutingCancelled},
+		{OutingStatusExpired, ErrOutingExpired},
+		{OutingStatusNoShow, ErrOutingExpired},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.status), func(t *testing.T) {
+			outing := createTestOuting()
+			outing.status = tt.status
+
+			if err := outing.Cancel(CancellationActorUser, "reason", nil); err != tt.want {
+				t.Errorf("Cancel() error = %v, want %v", err, tt.want)
+			}
+			if outing.Status() != tt.status {
+				t.Errorf("Cancel() status = %v, want %v", outing.Status(), tt.status)
+			}
+		})
+	}
+}
+
+func TestOuting_Cancel_Policy(t *testing.T) {
+	policy, err := NewCancellationPolicy(2 * time.Hour)
+	if err != nil {
+		t.Fatalf("NewCancellationPolicy() error = %v", err)
+	}
+
+	newSlotOuting := func(startsIn time.Duration) *Outing {
+		startsAt := time.Now().Add(startsIn)
+		slot := ReconstructSlotSnapshot("2026-10-16", "19:00", "21:00", startsAt, startsAt.Add(2*time.Hour), nil)
+		outing, _ := NewSlotOuting("user-123", createTestOfferSnapshot(), createTestUserSnapshot(), slot)
+		return outing
+	}
+
+	tests := []struct {
+		name     string
+		outing   *Outing
+		actor    CancellationActor
+		policy   *CancellationPolicy
+		wantLate bool
+	}{
+		{"before the window", newSlotOuting(3 * time.Hour), CancellationActorUser, &policy, false},
+		{"within the window", newSlotOuting(time.Hour), CancellationActorUser, &policy, true},
+		{"partner cancellation", newSlotOuting(time.Hour), CancellationActorPartner, &policy, false},
+		{"immediate booking", createTestOuting(), CancellationActorUser, &policy, false},
+		{"no policy", newSlotOuting(time.Hour), CancellationActorUser, nil, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.outing.Cancel(tt.actor, "", tt.policy); err != nil {
+				t.Fatalf("Cancel() error = %v, want nil", err)
+			}
+			if got := tt.outing.Cancellation().IsLate(); got != tt.wantLate {
+				t.Errorf("Cancel() late = %v, want %v", got, tt.wantLate)
+			}
+		})
+	}
+}
+
+// =============================================================================
+// No-Show Tests
+// =============================================================================
+
+func TestOuting_MarkAsNoShow(t *testing.T) {
+	outing := createTestOuting()
+	_ = outing.MarkAsExpired()
+	outing.ClearDomainEvents()
+
+	err := outing.MarkAsNoShow()
+
+	if err != nil {
+		t.Fatalf("MarkAsNoShow() error = %v, want nil", err)
+	}
+	if outing.Status() != OutingStatusNoShow {
+		t.Errorf("MarkAsNoShow() status = %v, want %v", outing.Status(), OutingStatusNoShow)
+	}
+	events := outing.GetDomainEvents()
+	if len(events) != 1 || events[0].EventName() != "outing.no_show" {
+		t.Errorf("MarkAsNoShow() events = %v, want one outing.no_show event", events)
+	}
+}
+
+func TestOuting_MarkAsNoShow_Twice(t *testing.T) {
+	outing := createTestOuting()
+	_ = outing.MarkAsNoShow()
+
+	err := outing.MarkAsNoShow()
+
+	if err != ErrInvalidOutingStatus {
+		t.Errorf("MarkAsNoShow() error = %v, want %v", err, ErrInvalidOutingStatus)
+	}
+}
+
+// =============================================================================
+// Partner Confirmation Tests
+// =============================================================================
+
+func TestNewPendingOuting(t *testing.T) {
+	outing, err := NewPendingOuting("user-123", createTestOfferSnapshot(), createTestUserSnapshot(), 30, 10*time.Minute)
+	if err != nil {
+		t.Fatalf("NewPendingOuting() error = %v, want nil", err)
+	}
+	if outing.Status() != OutingStatusPending {
+		t.Errorf("NewPendingOuting() status = %v, want %v", outing.Status(), OutingStatusPending)
+	}
+	if outing.ConfirmBy() == nil || outing.ConfirmBy().After(time.Now().Add(10*time.Minute)) {
+		t.Errorf("NewPendingOuting() confirmBy = %v, want within 10 minutes", outing.ConfirmBy())
+	}
+	if outing.Timeline()[0].Status() != OutingStatusPending {
+		t.Errorf("NewPendingOuting() initial timeline status = %v, want %v", outing.Timeline()[0].Status(), OutingStatusPending)
+	}
+	if err := outing.CanCheckIn(); err != ErrAwaitingConfirmation {
+		t.Errorf("CanCheckIn() error = %v, want %v", err, ErrAwaitingConfirmation)
+	}
+}
+
+func TestNewPendingOuting_DeadlineCapped(t *testing.T) {
+	// Capped at the outing's expiry
+	outing, _ := NewPendingOuting("user-123", createTestOfferSnapshot(), createTestUserSnapshot(), 5, time.Hour)
+	if !outing.ConfirmBy().Equal(outing.ExpiresAt()) {
+		t.Errorf("NewPendingOuting() confirmBy = %v, want expiry %v", outing.ConfirmBy(), outing.ExpiresAt())
+	}
+
+	// Capped at the slot start
+	startsAt := time.Now().Add(30 * time.Minute)
+	slot := ReconstructSlotSnapshot("2026-10-16", "19:00", "21:00", startsAt, startsAt.Add(2*time.Hour), nil)
+	outing, _ = NewPendingSlotOuting("user-123", createTestOfferSnapshot(), createTestUserSnapshot(), slot, time.Hour)
+	if !outing.ConfirmBy().Equal(startsAt) {
+		t.Errorf("NewPendingSlotOuting() confirmBy = %v, want slot start %v", outing.ConfirmBy(), startsAt)
+	}
+}
+
+func TestOuting_Confirm(t *testing.T) {
+	outing, _ := NewPendingOuting("user-123", createTestOfferSnapshot(), createTestUserSnapshot(), 30, 10*time.Minute)
+	outing.ClearDomainEvents()
+
+	if err := outing.Confirm("staff-123"); err != nil {
+		t.Fatalf("Confirm() error = %v, want nil", err)
+	}
+	if outing.Status() != OutingStatusConfirmed {
+		t.Errorf("Confirm() status = %v, want %v", outing.Status(), OutingStatusConfirmed)
+	}
+	last := outing.Timeline()[len(outing.Timeline())-1]
+	if last.Status() != OutingStatusConfirmed || last.Actor() != "staff-123" {
+		t.Errorf("Confirm() timeline entry = %v by %v, want %v by staff-123", last.Status(), last.Actor(), OutingStatusConfirmed)
+	}
+	events := outing.GetDomainEvents()
+	if len(events) != 1 || events[0].EventName() != "outing.confirmed" {
+		t.Errorf("Confirm() events = %v, want one outing.confirmed event", events)
+	}
+
+	if err := outing.Confirm("staff-123"); err != ErrOutingNotPending {
+		t.Errorf("Confirm() twice error = %v, want %v", err, ErrOutingNotPending)
+	}
+}
+
+func TestOuting_Confirm_NotPending(t *testing.T) {
+	outing := createTestOuting()
+
+	if err := outing.Confirm("staff-123"); err != ErrOutingNotPending {
+		t.Errorf("Confirm() error = %v, want %v", err, ErrOutingNotPending)
+	}
+}
+
+func TestOuting_Confirm_TimedOut(t *testing.T) {
+	outing, _ := NewPendingOuting("user-123", createTestOfferSnapshot(), createTestUserSnapshot(), 30, 10*time.Minute)
+	deadline := time.Now().Add(-time.Minute)
+	outing.confirmBy = &deadline
+
+	if err := outing.Confirm("staff-123"); err != ErrConfirmationTimedOut {
+		t.Errorf("Confirm() error = %v, want %v", err, ErrConfirmationTimedOut)
+	}
+}
+
+func TestOuting_Reject(t *testing.T) {
+	outing, _ := NewPendingOuting("user-123", createTestOfferSnapshot(), createTestUserSnapshot(), 30, 10*time.Minute)
+	outing.ClearDomainEvents()
+
+	if err := outing.Reject("staff-123", "fully booked tonight"); err != nil {
+		t.Fatalf("Reject() error = %v, want nil", err)
+	}
+	if outing.Status() != OutingStatusCancelled {
+		t.Errorf("Reject() status = %v, want %v", outing.Status(), OutingStatusCancelled)
+	}
+	if outing.Cancellation().CancelledBy() != CancellationActorPartner || outing.Cancellation().IsLate() {
+		t.Errorf("Reject() cancellation = %v late %v, want partner, not late",
+			outing.Cancellation().CancelledBy(), outing.Cancellation().IsLate())
+	}
+	events := outing.GetDomainEvents()
+	if len(events) != 1 || events[0].EventName() != "outing.rejected" {
+		t.Fatalf("Reject() events = %v, want one outing.rejected event", events)
+	}
+	if event := events[0].(OutingRejected); event.Automatic || event.RejectedBy != "staff-123" {
+		t.Errorf("Reject() event = %+v, want manual rejection by staff-123", event)
+	}
+}
+
+func TestOuting_RejectUnconfirmed(t *testing.T) {
+	outing, _ := NewPendingOuting("user-123", createTestOfferSnapshot(), createTestUserSnapshot(), 30, 10*time.Minute)
+	outing.ClearDomainEvents()
+
+	if err := outing.RejectUnconfirmed(); err != nil {
+		t.Fatalf("RejectUnconfirmed() error = %v, want nil", err)
+	}
+	if outing.Cancellation().CancelledBy() != CancellationActorSystem {
+		t.Errorf("RejectUnconfirmed() cancelled by = %v, want %v", outing.Cancellation().CancelledBy(), CancellationActorSystem)
+	}
+	events := outing.GetDomainEvents()
+	if len(events) != 1 || !events[0].(OutingRejected).Automatic {
+		t.Errorf("RejectUnconfirmed() events = %v, want one automatic outing.rejected event", events)
+	}
+
+	if err := outing.RejectUnconfirmed(); err != ErrOutingNotPending {
+		t.Errorf("RejectUnconfirmed() twice error = %v, want %v", err, ErrOutingNotPending)
+	}
+}
+
+// =============================================================================
+// Transfer Tests
+// =============================================================================
+
+func TestOuting_InitiateTransfer(t *testing.T) {
+	outing := createTestOuting()
+
+	if err := outing.InitiateTransfer("user-456", "user-789"); err != ErrNotOutingOwner {
+		t.Errorf("InitiateTransfer() by non-owner error = %v, want %v", err, ErrNotOutingOwner)
+	}
+	if err := outing.InitiateTransfer("user-123", "user-123"); err != ErrTransferToSelf {
+		t.Errorf("InitiateTransfer() to owner error = %v, want %v", err, ErrTransferToSelf)
+	}
+
+	if err := outing.InitiateTransfer("user-123", "user-456"); err != nil {
+		t.Fatalf("InitiateTransfer() error = %v, want nil", err)
+	}
+	if outing.Transfer() == nil || outing.Transfer().ToUserID() != "user-456" {
+		t.Errorf("InitiateTransfer() transfer = %v, want to user-456", outing.Transfer())
+	}
+	if outing.UserID() != "user-123" {
+		t.Errorf("InitiateTransfer() userID = %v, want user-123 until accepted", outing.UserID())
+	}
+	if len(outing.GetDomainEvents()) != 1 {
+		t.Errorf("InitiateTransfer() should emit 1 event, got %d", len(outing.GetDomainEvents()))
+	}
+}
+
+func TestOuting_AcceptTransfer(t *testing.T) {
+	outing := createTestOuting()
+	previousQR := outing.QRCode().Code()
+	_ = outing.InitiateTransfer("user-123", "user-456")
+	outing.ClearDomainEvents()
+
+	if err := outing.CanAcceptTransfer("user-789"); err != ErrNotTransferRecipient {
+		t.Errorf("CanAcceptTransfer() by other user error = %v, want %v", err, ErrNotTransferRecipient)
+	}
+
+	recipient := NewUserSnapshot("user-456", "Jane", "Roe", "jane@example.com")
+	if err := outing.AcceptTransfer(recipient); err != nil {
+		t.Fatalf("AcceptTransfer() error = %v, want nil", err)
+	}
+	if outing.UserID() != "user-456" || outing.User().Email() != "jane@example.com" {
+		t.Errorf("AcceptTransfer() owner = %v (%v), want user-456", outing.UserID(), outing.User().Email())
+	}
+	if outing.QRCode().Code() == previousQR {
+		t.Error("AcceptTransfer() should issue a new QR code")
+	}
+	if outing.Transfer() != nil {
+		t.Error("AcceptTransfer() should clear the pending transfer")
+	}
+	last := outing.Timeline()[len(outing.Timeline())-1]
+	if last.Metadata()["action"] != "transferred" || last.Metadata()["from_user_id"] != "user-123" {
+		t.Errorf("AcceptTransfer() timeline entry = %v, want transferred from user-123", last.Metadata())
+	}
+	if len(outing.GetDomainEvents()) != 1 {
+		t.Errorf("AcceptTransfer() should emit 1 event, got %d", len(outing.GetDomainEvents()))
+	}
+
+	if err := outing.AcceptTransfer(recipient); err != ErrNoPendingTransfer {
+		t.Errorf("AcceptTransfer() twice error = %v, want %v", err, ErrNoPendingTransfer)
+	}
+}
+
+func TestOuting_AcceptTransfer_NotTransferable(t *testing.T) {
+	outing := createTestOuting()
+	_ = outing.InitiateTransfer("user-123", "user-456")
+	_ = outing.CheckInManual("staff-123", nil, nil, nil)
+
+	if err := outing.CanAcceptTransfer("user-456"); err != ErrOutingAlreadyUsed {
+		t.Errorf("CanAcceptTransfer() after check-in error = %v, want %v", err, ErrOutingAlreadyUsed)
+	}
+}
+
+func TestOuting_CancelTransfer(t *testing.T) {
+	outing := createTestOuting()
+
+	if err := outing.CancelTransfer("user-123"); err != ErrNoPendingTransfer {
+		t.Errorf("CancelTransfer() without transfer error = %v, want %v", err, ErrNoPendingTransfer)
+	}
+
+	_ = outing.InitiateTransfer("user-123", "user-456")
+	if err := outing.CancelTransfer("user-789"); err != ErrNotOutingOwner {
+		t.Errorf("CancelTransfer() by stranger error = %v, want %v", err, ErrNotOutingOwner)
+	}
+	if err := outing.CancelTransfer("user-456"); err != nil {
+		t.Fatalf("CancelTransfer() by recipient error = %v, want nil", err)
+	}
+	if outing.Transfer() != nil {
+		t.Error("CancelTransfer() should clear the pending transfer")
+	}
+	last := outing.Timeline()[len(outing.Timeline())-1]
+	if last.Metadata()["action"] != "transfer_declined" {
+		t.Errorf("CancelTransfer() by recipient action = %v, want transfer_declined", last.Metadata()["action"])
+	}
+}
+
 // =============================================================================
 // Status Tests
 // =============================================================================
@@ -370,6 +763,152 @@ func TestOutingStatus_IsValid(t *testing.T) {
 	}
 }
 
+// =============================================================================
+// Quota Tests
+// =============================================================================
+
+func TestOfferQuota_IsUnlimited(t *testing.T) {
+	limit := 10
+
+	tests := []struct {
+		name  string
+		quota OfferQuota
+		want  bool
+	}{
+		{"unlimited", NewUnlimitedOfferQuota(), true},
+		{"no limits set", NewOfferQuota(nil, nil, nil), true},
+		{"total only", NewOfferQuota(&limit, nil, nil), false},
+		{"per user only", NewOfferQuota(nil, &limit, nil), false},
+		{"per day only", NewOfferQuota(nil, nil, &limit), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.quota.IsUnlimited(); got != tt.want {
+				t.Errorf("OfferQuota.IsUnlimited() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// =============================================================================
+// Time Slot Tests
+// =============================================================================
+
+func createTestSchedule(t *testing.T) OfferSchedule {
+	t.Helper()
+	paris, err := time.LoadLocation("Europe/Paris")
+	if err != nil {
+		t.Skipf("timezone data unavailable: %v", err)
+	}
+	capacity := 20
+	return NewOfferSchedule(false, []OfferTimeSlot{
+		NewOfferTimeSlot(time.Friday, "19:00", "21:00", &capacity),
+		NewOfferTimeSlot(time.Friday, "23:00", "02:00", nil),
+	}, paris)
+}
+
+func TestOfferSchedule_ResolveSlot(t *testing.T) {
+	schedule := createTestSchedule(t)
+	now := time.Date(2026, 10, 12, 12, 0, 0, 0, time.UTC) // Monday
+
+	// 2026-10-16 is a Friday
+	slot, err := schedule.ResolveSlot("2026-10-16", "19:00", now)
+	if err != nil {
+		t.Fatalf("ResolveSlot() error = %v, want nil", err)
+	}
+
+	wantStart := time.Date(2026, 10, 16, 19, 0, 0, 0, schedule.Location())
+	wantEnd := time.Date(2026, 10, 16, 21, 0, 0, 0, schedule.Location())
+	if !slot.StartsAt().Equal(wantStart) || !slot.EndsAt().Equal(wantEnd) {
+		t.Errorf("ResolveSlot() = %v - %v, want %v - %v", slot.StartsAt(), slot.EndsAt(), wantStart, wantEnd)
+	}
+	if slot.Capacity() == nil || *slot.Capacity() != 20 {
+		t.Errorf("ResolveSlot() capacity = %v, want 20", slot.Capacity())
+	}
+}
+
+func TestOfferSchedule_ResolveSlot_CrossesMidnight(t *testing.T) {
+	schedule := createTestSchedule(t)
+	now := time.Date(2026, 10, 12, 12, 0, 0, 0, time.UTC)
+
+	slot, err := schedule.ResolveSlot("2026-10-16", "23:00", now)
+	if err != nil {
+		t.Fatalf("ResolveSlot() error = %v, want nil", err)
+	}
+
+	wantEnd := time.Date(2026, 10, 17, 2, 0, 0, 0, schedule.Location())
+	if !slot.EndsAt().Equal(wantEnd) {
+		t.Errorf("ResolveSlot() endsAt = %v, want %v", slot.EndsAt(), wantEnd)
+	}
+}
+
+func TestOfferSchedule_SlotsOn_AllDay(t *testing.T) {
+	schedule := NewOfferSchedule(true, nil, createTestSchedule(t).Location())
+
+	slots, err := schedule.SlotsOn("2026-10-16")
+	if err != nil {
+		t.Fatalf("SlotsOn() error = %v, want nil", err)
+	}
+	if len(slots) != 1 {
+		t.Fatalf("SlotsOn() slots = %d, want 1", len(slots))
+	}
+
+	wantStart := time.Date(2026, 10, 16, 0, 0, 0, 0, schedule.Location())
+	wantEnd := time.Date(2026, 10, 17, 0, 0, 0, 0, schedule.Location())
+	if slots[0].EndTime() != "24:00" || !slots[0].StartsAt().Equal(wantStart) || !slots[0].EndsAt().Equal(wantEnd) {
+		t.Errorf("SlotsOn() = %s %v - %v, want 24:00 %v - %v", slots[0].EndTime(), slots[0].StartsAt(), slots[0].EndsAt(), wantStart, wantEnd)
+	}
+}
+
+func TestOfferSchedule_ResolveSlot_Errors(t *testing.T) {
+	schedule := createTestSchedule(t)
+	now := time.Date(2026, 10, 12, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name      string
+		date      string
+		startTime string
+		now       time.Time
+		wantErr   error
+	}{
+		{"wrong day", "2026-10-15", "19:00", now, ErrSlotNotAvailable},
+		{"wrong start time", "2026-10-16", "20:00", now, ErrSlotNotAvailable},
+		{"invalid date", "16/10/2026", "19:00", now, ErrSlotNotAvailable},
+		{"slot ended", "2026-10-16", "19:00", time.Date(2026, 10, 16, 22, 0, 0, 0, time.UTC), ErrSlotEnded},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := schedule.ResolveSlot(tt.date, tt.startTime, tt.now)
+			if err != tt.wantErr {
+				t.Errorf("ResolveSlot() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestNewSlotOuting(t *testing.T) {
+	startsAt := time.Now().Add(2 * time.Hour)
+	slot := ReconstructSlotSnapshot("2026-10-16", "19:00", "21:00", startsAt, startsAt.Add(2*time.Hour), nil)
+
+	outing, err := NewSlotOuting("user-123", createTestOfferSnapshot(), createTestUserSnapshot(), slot)
+	if err != nil {
+		t.Fatalf("NewSlotOuting() error = %v, want nil", err)
+	}
+	if !outing.ExpiresAt().Equal(slot.EndsAt()) {
+		t.Errorf("NewSlotOuting() expiresAt = %v, want slot end %v", outing.ExpiresAt(), slot.EndsAt())
+	}
+	if outing.Slot() == nil {
+		t.Fatal("NewSlotOuting() slot should be set")
+	}
+
+	// Check-in does not open until shortly before the slot starts
+	if err := outing.CanCheckIn(); err != ErrInvalidCheckInWindow {
+		t.Errorf("CanCheckIn() error = %v, want %v", err, ErrInvalidCheckInWindow)
+	}
+}
+
 // =============================================================================
 // OfferSnapshot Tests
 // =============================================================================
@@ -2,6 +2,11 @@ package domain
 
 import (
 	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
 	"time"
 )
 
@@ -17,60 +22,351 @@ type OutingRepository interface {
 	// Update updates an existing outing
 	Update(ctx context.Context, outing *Outing) error
 
+	// UpdateIfStatus updates the outing only if its stored status is still
+	// from, and returns ErrOutingStatusChanged otherwise
+	UpdateIfStatus(ctx context.Context, outing *Outing, from OutingStatus) error
+
+	// SaveAnomalies saves the anomalies just flagged on a checked-in outing,
+	// and their timeline entries, without rewriting its other fields. It
+	// skips the anomalies the stored outing already has and returns those it
+	// saved, or ErrOutingStatusChanged when it saved none (e.g. the stored
+	// outing is no longer checked in).
+	SaveAnomalies(ctx context.Context, outing *Outing, reasons []AnomalyReason) ([]AnomalyReason, error)
+
 	// GetByID retrieves an outing by ID
 	GetByID(ctx context.Context, id string) (*Outing, error)
 
 	// GetByQRCode retrieves an outing by QR code
 	GetByQRCode(ctx context.Context, qrCode string) (*Outing, error)
 
-	// GetByUserID retrieves all outings for a user with pagination
-	GetByUserID(ctx context.Context, userID string, filter OutingFilter) ([]*Outing, int64, error)
+	// GetByShortCode retrieves the pending or confirmed outing of the
+	// establishment with the given short code
+	GetByShortCode(ctx context.Context, establishmentID, shortCode string) (*Outing, error)
+
+	// GetByUserID retrieves a page of a user's outings
+	GetByUserID(ctx context.Context, userID string, filter OutingFilter) (*OutingPage, error)
 
-	// GetByOfferID retrieves all outings for an offer
-	GetByOfferID(ctx context.Context, offerID string, filter OutingFilter) ([]*Outing, int64, error)
+	// GetByOfferID retrieves a page of an offer's outings
+	GetByOfferID(ctx context.Context, offerID string, filter OutingFilter) (*OutingPage, error)
 
-	// GetByPartnerID retrieves all outings for a partner
-	GetByPartnerID(ctx context.Context, partnerID string, filter OutingFilter) ([]*Outing, int64, error)
+	// GetByPartnerID retrieves a page of a partner's outings
+	GetByPartnerID(ctx context.Context, partnerID string, filter OutingFilter) (*OutingPage, error)
 
-	// GetByEstablishmentID retrieves all outings for an establishment
-	GetByEstablishmentID(ctx context.Context, establishmentID string, filter OutingFilter) ([]*Outing, int64, error)
+	// GetByEstablishmentID retrieves a page of an establishment's outings
+	GetByEstablishmentID(ctx context.Context, establishmentID string, filter OutingFilter) (*OutingPage, error)
 
 	// GetActiveByUserAndOffer checks if user has an active outing for the offer
 	GetActiveByUserAndOffer(ctx context.Context, userID, offerID string) (*Outing, error)
 
-	// CountByUserAndOffer counts user bookings for an offer (for quota check)
+	// CountByUserAndOffer counts user bookings consuming quota for an offer
+	// (cancelled, expired and no-show outings have released their capacity)
 	CountByUserAndOffer(ctx context.Context, userID, offerID string) (int64, error)
 
-	// CountByOfferAndPeriod counts bookings for an offer in a period (for quota check)
+	// CountByOfferAndPeriod counts bookings consuming quota for an offer in a period
 	CountByOfferAndPeriod(ctx context.Context, offerID string, start, end time.Time) (int64, error)
 
+	// CountBySlot counts bookings consuming capacity of the offer slot starting at slotStartsAt
+	CountBySlot(ctx context.Context, offerID string, slotStartsAt time.Time) (int64, error)
+
+	// GetStats aggregates booking statistics, optionally bucketed over time
+	GetStats(ctx context.Context, filter StatsFilter) (*BookingStats, error)
+
+	// GetSavings aggregates the bills and savings of a user's billed check-ins
+	GetSavings(ctx context.Context, filter SavingsFilter) (*SavingsSummary, error)
+
+	// CountStaffCheckIns counts the check-ins made by a staff member between
+	// from and to, inclusive
+	CountStaffCheckIns(ctx context.Context, staffUserID string, from, to time.Time) (int64, error)
+
+	// GetUserCheckIns retrieves the user's outings checked in between from
+	// and to, inclusive
+	GetUserCheckIns(ctx context.Context, userID string, from, to time.Time) ([]*Outing, error)
+
+	// GetFlagged retrieves outings whose check-in was flagged for review
+	GetFlagged(ctx context.Context, scope FlaggedOutingScope, filter OutingFilter) (*OutingPage, error)
+
 	// GetExpiredOutings retrieves outings that have expired but not marked
 	GetExpiredOutings(ctx context.Context, before time.Time, limit int) ([]*Outing, error)
 
+	// GetNoShowCandidates retrieves expired outings whose expiry is older than before
+	GetNoShowCandidates(ctx context.Context, before time.Time, limit int) ([]*Outing, error)
+
+	// GetUnconfirmedOutings retrieves pending outings whose confirmation
+	// deadline is before the given time
+	GetUnconfirmedOutings(ctx context.Context, before time.Time, limit int) ([]*Outing, error)
+
+	// GetPendingTransfersTo retrieves the outings offered to the user and not
+	// accepted yet
+	GetPendingTransfersTo(ctx context.Context, userID string) ([]*Outing, error)
+
 	// Delete removes an outing (soft delete via status)
 	Delete(ctx context.Context, id string) error
 }
 
-// OutingFilter contains filter options for listing outings
+// WaitlistRepository defines the interface for waitlist persistence.
+// slotStartsAt identifies the slot instance waited for; nil means the offer.
+type WaitlistRepository interface {
+	// Create persists a new entry
+	Create(ctx context.Context, entry *WaitlistEntry) error
+
+	// Update saves the entry only if its stored status is still from, and
+	// returns ErrWaitlistEntryConflict otherwise
+	Update(ctx context.Context, entry *WaitlistEntry, from WaitlistStatus) error
+
+	// GetByID retrieves an entry by ID
+	GetByID(ctx context.Context, id string) (*WaitlistEntry, error)
+
+	// GetActiveByUserAndOffer retrieves the user's waiting or held entry
+	GetActiveByUserAndOffer(ctx context.Context, userID, offerID string, slotStartsAt *time.Time) (*WaitlistEntry, error)
+
+	// GetActiveByUserID lists the user's waiting or held entries
+	GetActiveByUserID(ctx context.Context, userID string) ([]*WaitlistEntry, error)
+
+	// GetNextWaiting retrieves the oldest waiting entry, or ErrWaitlistEntryNotFound
+	GetNextWaiting(ctx context.Context, offerID string, slotStartsAt *time.Time) (*WaitlistEntry, error)
+
+	// CountWaitingAhead counts waiting entries that joined before the given one
+	CountWaitingAhead(ctx context.Context, entry *WaitlistEntry) (int64, error)
+
+	// GetExpiredHolds retrieves held entries whose hold ended before the given time
+	GetExpiredHolds(ctx context.Context, before time.Time, limit int) ([]*WaitlistEntry, error)
+}
+
+// UserStandingRepository defines the interface for user strike persistence
+type UserStandingRepository interface {
+	// Get retrieves the user's standing, or a standing without strikes
+	Get(ctx context.Context, userID string) (*UserStanding, error)
+
+	// Save creates or updates the standing, or returns ErrUserStandingChanged
+	// when the stored standing is no longer at the version it was loaded at
+	Save(ctx context.Context, standing *UserStanding) error
+}
+
+// OutingFilter contains filter and paging options for listing outings
 type OutingFilter struct {
 	Status    []OutingStatus
 	StartDate *time.Time
 	EndDate   *time.Time
-	Offset    int
 	Limit     int
-	SortBy    string
+	SortBy    OutingSortField
 	SortOrder string
+
+	// Cursor paging: the page holds outings strictly after After and before
+	// Before. Backward takes the last Limit of them instead of the first.
+	After    *OutingCursor
+	Before   *OutingCursor
+	Backward bool
+}
+
+// FlaggedOutingScope narrows a flagged outing listing. Empty fields are not
+// filtered on.
+type FlaggedOutingScope struct {
+	Reason          AnomalyReason
+	PartnerID       string
+	EstablishmentID string
 }
 
 func DefaultOutingFilter() OutingFilter {
 	return OutingFilter{
-		Offset:    0,
 		Limit:     20,
-		SortBy:    "created_at",
+		SortBy:    OutingSortCreatedAt,
 		SortOrder: "desc",
 	}
 }
 
+// =============================================================================
+// PAGINATION
+// =============================================================================
+
+var ErrInvalidCursor = errors.New("invalid pagination cursor")
+
+// OutingSortField is a time field outings can be listed by
+type OutingSortField string
+
+const (
+	OutingSortCreatedAt OutingSortField = "created_at"
+	OutingSortBookedAt  OutingSortField = "booked_at"
+	OutingSortExpiresAt OutingSortField = "expires_at"
+	OutingSortUpdatedAt OutingSortField = "updated_at"
+)
+
+func (f OutingSortField) IsValid() bool {
+	switch f {
+	case OutingSortCreatedAt, OutingSortBookedAt, OutingSortExpiresAt, OutingSortUpdatedAt:
+		return true
+	}
+	return false
+}
+
+// ValueOf returns the outing's value for the sort field
+func (f OutingSortField) ValueOf(o *Outing) time.Time {
+	switch f {
+	case OutingSortBookedAt:
+		return o.BookedAt()
+	case OutingSortExpiresAt:
+		return o.ExpiresAt()
+	case OutingSortUpdatedAt:
+		return o.UpdatedAt()
+	default:
+		return o.CreatedAt()
+	}
+}
+
+// OutingCursor is a stable position in a sorted outing list: the outing's
+// sort value, with its ID to break ties. Unlike an offset it does not shift
+// when outings are added before it.
+type OutingCursor struct {
+	SortBy    OutingSortField
+	SortValue time.Time
+	ID        string
+}
+
+func NewOutingCursor(o *Outing, sortBy OutingSortField) OutingCursor {
+	return OutingCursor{SortBy: sortBy, SortValue: sortBy.ValueOf(o), ID: o.ID()}
+}
+
+// Encode returns the opaque cursor handed to clients. Values are kept to the
+// millisecond, the precision they are stored with.
+func (c OutingCursor) Encode() string {
+	raw := fmt.Sprintf("%s|%d|%s", c.SortBy, c.SortValue.UnixMilli(), c.ID)
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+func DecodeOutingCursor(cursor string) (*OutingCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	parts := strings.Split(string(raw), "|")
+	if len(parts) != 3 || parts[2] == "" {
+		return nil, ErrInvalidCursor
+	}
+	sortBy := OutingSortField(parts[0])
+	if !sortBy.IsValid() {
+		return nil, ErrInvalidCursor
+	}
+	millis, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	return &OutingCursor{SortBy: sortBy, SortValue: time.UnixMilli(millis).UTC(), ID: parts[2]}, nil
+}
+
+// OutingPage is one page of a sorted outing list
+type OutingPage struct {
+	Outings         []*Outing
+	TotalCount      int64
+	HasNextPage     bool
+	HasPreviousPage bool
+}
+
+// =============================================================================
+// STATISTICS
+// =============================================================================
+
+// StatsGranularity is the width of a time bucket in a stats series
+type StatsGranularity string
+
+const (
+	StatsGranularityDay   StatsGranularity = "day"
+	StatsGranularityWeek  StatsGranularity = "week"
+	StatsGranularityMonth StatsGranularity = "month"
+)
+
+func (g StatsGranularity) IsValid() bool {
+	switch g {
+	case StatsGranularityDay, StatsGranularityWeek, StatsGranularityMonth:
+		return true
+	}
+	return false
+}
+
+// StatsFilter scopes a stats aggregation. Empty IDs are not filtered on.
+// A zero Granularity disables the time series.
+type StatsFilter struct {
+	PartnerID       string
+	EstablishmentID string
+	OfferID         string
+	StartDate       *time.Time
+	EndDate         *time.Time
+	Granularity     StatsGranularity
+	Timezone        string
+}
+
+// StatsCounts holds outing totals per status
+type StatsCounts struct {
+	TotalBookings  int64
+	TotalCheckIns  int64
+	TotalCancelled int64
+	TotalExpired   int64
+	TotalNoShow    int64
+	// AverageCheckInTime is the mean delay between booking and check-in, in minutes
+	AverageCheckInTime float64
+	// Check-ins whose bill was declared, with the sums of their bills and savings
+	TotalBilled       int64
+	TotalBillCents    int64
+	TotalSavingsCents int64
+}
+
+// ConversionRate returns the share of bookings that were checked in (0..1)
+func (c StatsCounts) ConversionRate() float64 {
+	if c.TotalBookings == 0 {
+		return 0
+	}
+	return float64(c.TotalCheckIns) / float64(c.TotalBookings)
+}
+
+// BookingStatsBucket holds the counts for one time bucket
+type BookingStatsBucket struct {
+	PeriodStart time.Time
+	StatsCounts
+}
+
+// BookingStats is the result of a stats aggregation
+type BookingStats struct {
+	StatsCounts
+	Series []BookingStatsBucket
+}
+
+// SavingsFilter scopes a savings aggregation to the billed check-ins of a
+// user. The dates bound the check-in time; months are cut in Timezone (UTC
+// when empty).
+type SavingsFilter struct {
+	UserID    string
+	StartDate *time.Time
+	EndDate   *time.Time
+	Timezone  string
+}
+
+// SavingsTotals sums the declared bills of billed outings
+type SavingsTotals struct {
+	Outings      int64
+	BillCents    int64
+	SavingsCents int64
+}
+
+// SavingsByMonth holds the totals of the check-ins of one month
+type SavingsByMonth struct {
+	MonthStart time.Time
+	SavingsTotals
+}
+
+// SavingsByCategory holds the totals of the check-ins of one offer category
+type SavingsByCategory struct {
+	Category string
+	SavingsTotals
+}
+
+// SavingsSummary is the result of a savings aggregation, months oldest first
+// and categories by decreasing savings
+type SavingsSummary struct {
+	SavingsTotals
+	ByMonth    []SavingsByMonth
+	ByCategory []SavingsByCategory
+}
+
 // =============================================================================
 // DOMAIN SERVICE INTERFACES
 // =============================================================================
@@ -88,6 +384,145 @@ type OfferService interface {
 
 	// DecrementBookingCount decrements the offer's booking count (on cancel)
 	DecrementBookingCount(ctx context.Context, offerID string) error
+
+	// GetQuota retrieves the offer's booking limits
+	GetQuota(ctx context.Context, offerID string) (*OfferQuota, error)
+
+	// GetSchedule retrieves the offer's bookable time slots
+	GetSchedule(ctx context.Context, offerID string) (*OfferSchedule, error)
+
+	// GetGeofence retrieves the offer's check-in geofence; nil means the
+	// service-wide default applies
+	GetGeofence(ctx context.Context, offerID string) (*Geofence, error)
+
+	// GetCancellationPolicy retrieves the offer's cancellation policy; nil
+	// means the service-wide default applies
+	GetCancellationPolicy(ctx context.Context, offerID string) (*CancellationPolicy, error)
+
+	// RequiresConfirmation reports whether the partner approves each booking
+	// of the offer, which then stays pending until confirmed
+	RequiresConfirmation(ctx context.Context, offerID string) (bool, error)
+}
+
+// QuotaService atomically reserves and releases offer capacity so concurrent
+// bookings cannot exceed the offer's limits
+type QuotaService interface {
+	// Reserve takes one unit of capacity for the user on the offer, or returns
+	// ErrOfferQuotaExceeded, ErrUserQuotaExceeded or ErrDailyQuotaExceeded
+	Reserve(ctx context.Context, offerID, userID string, quota OfferQuota, at time.Time) error
+
+	// Release gives back the capacity taken when the outing was booked. It
+	// releases at most once per outing, so racing callers cannot double-release.
+	Release(ctx context.Context, outing *Outing) error
+
+	// ReleaseReservation gives back capacity reserved for the user at the
+	// given time without an outing, e.g. an unused waitlist hold
+	ReleaseReservation(ctx context.Context, offerID, userID string, at time.Time) error
+
+	// Transfer moves the per-user capacity of a booking made at the given time
+	// from one user to another, or returns ErrUserQuotaExceeded. Offer and
+	// daily capacity are unchanged.
+	Transfer(ctx context.Context, offerID, fromUserID, toUserID string, at time.Time, quota OfferQuota) error
+}
+
+// SlotCapacityService atomically reserves and releases seats of a slot instance
+type SlotCapacityService interface {
+	// Reserve takes one seat of the slot, or returns ErrSlotFull.
+	// Slots without capacity are not tracked.
+	Reserve(ctx context.Context, offerID string, slot SlotSnapshot) error
+
+	// Release gives back the seat taken when the outing was booked, at most
+	// once per outing (no-op without a slot)
+	Release(ctx context.Context, outing *Outing) error
+
+	// ReleaseSeat gives back a seat reserved without an outing
+	ReleaseSeat(ctx context.Context, offerID string, slot SlotSnapshot) error
+
+	// Remaining returns the seats left in the slot, or nil when it is unlimited
+	Remaining(ctx context.Context, offerID string, slot SlotSnapshot) (*int, error)
+}
+
+// =============================================================================
+// IDEMPOTENCY
+// =============================================================================
+
+var (
+	ErrInvalidIdempotencyKey  = errors.New("idempotency key must be 1 to 128 characters")
+	ErrIdempotencyKeyInUse    = errors.New("a request with this idempotency key is still in progress")
+	ErrIdempotencyKeyMismatch = errors.New("idempotency key was already used for a different request")
+)
+
+// MaxIdempotencyKeyLength bounds client-supplied idempotency keys
+const MaxIdempotencyKeyLength = 128
+
+// IdempotentOutcome is what a completed request produced
+type IdempotentOutcome struct {
+	OutingID string
+}
+
+// IdempotencyStore remembers completed requests by client-supplied key so a
+// retried request returns the original outcome instead of running again. The
+// fingerprint identifies the request the key was first used for.
+type IdempotencyStore interface {
+	// Claim reserves the key for a request. It returns the stored outcome when
+	// the request already completed and nil when the caller should run it, or
+	// ErrIdempotencyKeyInUse / ErrIdempotencyKeyMismatch
+	Claim(ctx context.Context, key, fingerprint string) (*IdempotentOutcome, error)
+
+	// Complete stores the outcome of a claimed request
+	Complete(ctx context.Context, key, fingerprint string, outcome IdempotentOutcome) error
+
+	// Release frees a claimed key after a failed attempt so it can be retried
+	Release(ctx context.Context, key string) error
+}
+
+// =============================================================================
+// SHORT CODE ATTEMPTS
+// =============================================================================
+
+// ShortCodeAttemptLimiter bounds the invalid short codes tried at an
+// establishment over a time window, so codes cannot be found by guessing
+type ShortCodeAttemptLimiter interface {
+	// Attempt counts an attempt at the establishment, before the code is
+	// looked up, and returns ErrTooManyShortCodeAttempts once the
+	// establishment has used up its attempts for the window
+	Attempt(ctx context.Context, establishmentID string) error
+
+	// Refund gives back an attempt whose code was not a wrong guess
+	Refund(ctx context.Context, establishmentID string) error
+}
+
+// =============================================================================
+// LIVE UPDATES
+// =============================================================================
+
+// OutingUpdate is a change to an outing pushed to live subscribers.
+// EventType is the name of the domain event that caused it.
+type OutingUpdate struct {
+	EventType  string
+	Outing     *Outing
+	OccurredAt time.Time
+}
+
+// OutingUpdateFeed delivers outing updates to live subscribers
+type OutingUpdateFeed interface {
+	// SubscribeEstablishment streams updates of the establishment's outings.
+	// The channel is closed once ctx is done.
+	SubscribeEstablishment(ctx context.Context, establishmentID string) (<-chan OutingUpdate, error)
+}
+
+// PartnerService answers partner team questions
+type PartnerService interface {
+	// IsTeamMember reports whether the user is a team member of the partner
+	// owning the establishment
+	IsTeamMember(ctx context.Context, userID, establishmentID string) (bool, error)
+
+	// IsPartnerMember reports whether the user is a team member of the partner
+	IsPartnerMember(ctx context.Context, userID, partnerID string) (bool, error)
+
+	// GetOpeningHours retrieves the establishment's weekly opening hours; nil
+	// means they are unknown
+	GetOpeningHours(ctx context.Context, establishmentID string) (*OpeningHours, error)
 }
 
 // UserService provides user information for booking
@@ -95,8 +530,12 @@ type UserService interface {
 	// GetUserSnapshot retrieves user details for creating a snapshot
 	GetUserSnapshot(ctx context.Context, userID string) (*UserSnapshot, error)
 
-	// CanBook checks if a user can book (verified, active subscription, etc.)
+	// CanBook checks if a user can book (verified, active subscription, not
+	// banned, etc.)
 	CanBook(ctx context.Context, userID string) error
+
+	// GetUserIDByEmail finds a user by email, or returns ErrUserNotFound
+	GetUserIDByEmail(ctx context.Context, email string) (string, error)
 }
 
 // NotificationService sends booking notifications
@@ -110,6 +549,20 @@ type NotificationService interface {
 	// SendCancellationNotification sends cancellation notification
 	SendCancellationNotification(ctx context.Context, outing *Outing) error
 
+	// SendConfirmationRequest asks the partner staff to confirm a pending outing
+	SendConfirmationRequest(ctx context.Context, outing *Outing) error
+
+	// SendBookingDecision tells the user whether the partner confirmed or
+	// rejected their pending outing
+	SendBookingDecision(ctx context.Context, outing *Outing) error
+
+	// SendTransferOffer tells the recipient an outing is being transferred to them
+	SendTransferOffer(ctx context.Context, outing *Outing) error
+
+	// SendTransferAccepted tells the previous owner the recipient accepted
+	// the outing
+	SendTransferAccepted(ctx context.Context, outing *Outing, previousUserID string) error
+
 	// SendExpirationReminder sends reminder before expiration
 	SendExpirationReminder(ctx context.Context, outing *Outing) error
 }
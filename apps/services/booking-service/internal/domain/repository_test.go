@@ -0,0 +1,85 @@
+package domain
+
+import (
+	"testing"
+)
+
+// =============================================================================
+// Stats Tests
+// =============================================================================
+
+func TestStatsCounts_ConversionRate(t *testing.T) {
+	tests := []struct {
+		name   string
+		counts StatsCounts
+		want   float64
+	}{
+		{"no bookings", StatsCounts{}, 0},
+		{"half checked in", StatsCounts{TotalBookings: 10, TotalCheckIns: 5}, 0.5},
+		{"all checked in", StatsCounts{TotalBookings: 4, TotalCheckIns: 4}, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.counts.ConversionRate(); got != tt.want {
+				t.Errorf("StatsCounts.ConversionRate() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStatsGranularity_IsValid(t *testing.T) {
+	tests := []struct {
+		granularity StatsGranularity
+		want        bool
+	}{
+		{StatsGranularityDay, true},
+		{StatsGranularityWeek, true},
+		{StatsGranularityMonth, true},
+		{StatsGranularity("year"), false},
+		{StatsGranularity(""), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.granularity), func(t *testing.T) {
+			if got := tt.granularity.IsValid(); got != tt.want {
+				t.Errorf("StatsGranularity.IsValid() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// =============================================================================
+// Pagination Tests
+// =============================================================================
+
+func TestOutingCursor_EncodeDecode(t *testing.T) {
+	outing := createTestOuting()
+	cursor := NewOutingCursor(outing, OutingSortBookedAt)
+
+	decoded, err := DecodeOutingCursor(cursor.Encode())
+	if err != nil {
+		t.Fatalf("DecodeOutingCursor() error = %v, want nil", err)
+	}
+	if decoded.SortBy != OutingSortBookedAt || decoded.ID != outing.ID() {
+		t.Errorf("DecodeOutingCursor() = %+v, want booked_at cursor for %s", decoded, outing.ID())
+	}
+	if decoded.SortValue.UnixMilli() != outing.BookedAt().UnixMilli() {
+		t.Errorf("DecodeOutingCursor() sortValue = %v, want %v", decoded.SortValue, outing.BookedAt())
+	}
+}
+
+func TestDecodeOutingCursor_Invalid(t *testing.T) {
+	tests := []string{
+		"",
+		"not base64!",
+		"Y3Vyc29yOjIw", // legacy offset cursor "cursor:20"
+		NewOutingCursor(createTestOuting(), OutingSortField("status")).Encode(),
+	}
+
+	for _, cursor := range tests {
+		if _, err := DecodeOutingCursor(cursor); err != ErrInvalidCursor {
+			t.Errorf("DecodeOutingCursor(%q) error = %v, want %v", cursor, err, ErrInvalidCursor)
+		}
+	}
+}
@@ -0,0 +1,61 @@
+package domain
+
+import (
+	"crypto/rand"
+	"errors"
+	"strings"
+)
+
+// =============================================================================
+// SHORT CHECK-IN CODES
+// =============================================================================
+
+// Short codes are the fallback when the QR code cannot be scanned: staff type
+// the few characters the user reads out. They are unique among the active
+// outings of an establishment only, so a lookup always needs the
+// establishment and guesses are limited per establishment.
+
+var (
+	ErrInvalidShortCode         = errors.New("invalid check-in code")
+	ErrShortCodeTaken           = errors.New("check-in code is already used by an active outing of the establishment")
+	ErrTooManyShortCodeAttempts = errors.New("too many invalid check-in codes, try again later")
+)
+
+const (
+	ShortCodeLength = 6
+
+	// shortCodeAlphabet leaves out 0, O, 1 and I, which are easily mistaken
+	// for each other. Its 32 characters divide 256, so picking one from a
+	// random byte is unbiased.
+	shortCodeAlphabet = "23456789ABCDEFGHJKLMNPQRSTUVWXYZ"
+)
+
+// NewShortCode generates a random short code
+func NewShortCode() (string, error) {
+	randomBytes := make([]byte, ShortCodeLength)
+	if _, err := rand.Read(randomBytes); err != nil {
+		return "", err
+	}
+
+	code := make([]byte, ShortCodeLength)
+	for i, b := range randomBytes {
+		code[i] = shortCodeAlphabet[int(b)%len(shortCodeAlphabet)]
+	}
+	return string(code), nil
+}
+
+// NormalizeShortCode turns a typed code into its canonical form: upper case,
+// without the spaces and dashes it may be displayed with. It returns
+// ErrInvalidShortCode when the result cannot be a short code.
+func NormalizeShortCode(input string) (string, error) {
+	code := strings.ToUpper(strings.NewReplacer(" ", "", "-", "").Replace(strings.TrimSpace(input)))
+	if len(code) != ShortCodeLength {
+		return "", ErrInvalidShortCode
+	}
+	for _, c := range code {
+		if !strings.ContainsRune(shortCodeAlphabet, c) {
+			return "", ErrInvalidShortCode
+		}
+	}
+	return code, nil
+}
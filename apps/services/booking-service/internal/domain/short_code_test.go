@@ -0,0 +1,104 @@
+package domain
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// =============================================================================
+// Short Code Tests
+// =============================================================================
+
+func TestNewShortCode(t *testing.T) {
+	for i := 0; i < 100; i++ {
+		code, err := NewShortCode()
+		if err != nil {
+			t.Fatalf("NewShortCode() error = %v, want nil", err)
+		}
+		if len(code) != ShortCodeLength {
+			t.Fatalf("NewShortCode() = %q, want %d characters", code, ShortCodeLength)
+		}
+		if strings.ContainsAny(code, "0O1I") {
+			t.Fatalf("NewShortCode() = %q, should not contain ambiguous characters", code)
+		}
+	}
+}
+
+func TestNormalizeShortCode(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    string
+		wantErr bool
+	}{
+		{input: "K7M2QX", want: "K7M2QX"},
+		{input: " k7m-2qx ", want: "K7M2QX"},
+		{input: "K7M 2QX", want: "K7M2QX"},
+		{input: "K7M2Q", wantErr: true},
+		{input: "K7M2QXZ", wantErr: true},
+		{input: "K7M0QX", wantErr: true},
+		{input: "K7MOQX", wantErr: true},
+		{input: "K7M1QX", wantErr: true},
+		{input: "K7MIQX", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := NormalizeShortCode(tt.input)
+		if tt.wantErr {
+			if err != ErrInvalidShortCode {
+				t.Errorf("NormalizeShortCode(%q) error = %v, want %v", tt.input, err, ErrInvalidShortCode)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("NormalizeShortCode(%q) = %q, %v, want %q", tt.input, got, err, tt.want)
+		}
+	}
+}
+
+func TestOuting_CheckInWithShortCode(t *testing.T) {
+	outing := createTestOuting()
+	if _, err := NormalizeShortCode(outing.ShortCode()); err != nil {
+		t.Fatalf("NewOuting() short code = %q, want a valid short code", outing.ShortCode())
+	}
+
+	typed := strings.ToLower(outing.ShortCode()[:3] + "-" + outing.ShortCode()[3:])
+	if err := outing.CheckInWithShortCode(typed, "staff-123", nil, nil, nil); err != nil {
+		t.Fatalf("CheckInWithShortCode() error = %v, want nil", err)
+	}
+	if outing.CheckIn().Method() != CheckInMethodShortCode {
+		t.Errorf("CheckInWithShortCode() method = %v, want %v", outing.CheckIn().Method(), CheckInMethodShortCode)
+	}
+}
+
+func TestOuting_CheckInWithShortCode_Rejected(t *testing.T) {
+	outing := createTestOuting()
+	other := "222222"
+	if outing.ShortCode() == other {
+		other = "333333"
+	}
+	if err := outing.CheckInWithShortCode(other, "staff-123", nil, nil, nil); err != ErrInvalidShortCode {
+		t.Errorf("CheckInWithShortCode() error = %v, want %v", err, ErrInvalidShortCode)
+	}
+
+	pending, _ := NewPendingOuting("user-123", createTestOfferSnapshot(), createTestUserSnapshot(), 30, 10*time.Minute)
+	if err := pending.CheckInWithShortCode(pending.ShortCode(), "staff-123", nil, nil, nil); err != ErrAwaitingConfirmation {
+		t.Errorf("CheckInWithShortCode() on pending outing error = %v, want %v", err, ErrAwaitingConfirmation)
+	}
+}
+
+func TestOuting_RegenerateShortCode(t *testing.T) {
+	outing := createTestOuting()
+	seen := map[string]bool{outing.ShortCode(): true}
+
+	// A fresh draw may repeat a code; three in a row practically never do
+	for i := 0; i < 3; i++ {
+		if err := outing.RegenerateShortCode(); err != nil {
+			t.Fatalf("RegenerateShortCode() error = %v, want nil", err)
+		}
+		seen[outing.ShortCode()] = true
+	}
+	if len(seen) == 1 {
+		t.Error("RegenerateShortCode() should draw a new code")
+	}
+}
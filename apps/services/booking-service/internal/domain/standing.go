@@ -0,0 +1,184 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/yousoon/shared/domain"
+)
+
+// =============================================================================
+// CANCELLATION POLICY
+// =============================================================================
+
+// CancellationPolicy decides whether a user cancellation is free or late.
+// Cancelling a slot booking less than FreeCancellationWindow before the slot
+// starts is late. Immediate bookings are short-lived and can always be
+// cancelled freely; not showing up is what counts against them.
+type CancellationPolicy struct {
+	FreeCancellationWindow time.Duration
+}
+
+func NewCancellationPolicy(freeCancellationWindow time.Duration) (CancellationPolicy, error) {
+	if freeCancellationWindow < 0 {
+		return CancellationPolicy{}, fmt.Errorf("free cancellation window must not be negative")
+	}
+	return CancellationPolicy{FreeCancellationWindow: freeCancellationWindow}, nil
+}
+
+// IsLate reports whether cancelling the outing at the given time is late
+func (p CancellationPolicy) IsLate(outing *Outing, at time.Time) bool {
+	if outing.Slot() == nil {
+		return false
+	}
+	return !at.Before(outing.Slot().StartsAt().Add(-p.FreeCancellationWindow))
+}
+
+// =============================================================================
+// STRIKES AND BANS
+// =============================================================================
+
+var (
+	ErrUserBookingBanned   = errors.New("user is temporarily banned from booking")
+	ErrUserStandingChanged = errors.New("user standing was changed concurrently")
+)
+
+// BookingBanError is returned by CanBook while the user is banned. It matches
+// ErrUserBookingBanned with errors.Is.
+type BookingBanError struct {
+	Until time.Time
+}
+
+func (e *BookingBanError) Error() string {
+	return fmt.Sprintf("%s until %s", ErrUserBookingBanned, e.Until.UTC().Format(time.RFC3339))
+}
+
+func (e *BookingBanError) Unwrap() error { return ErrUserBookingBanned }
+
+type StrikeKind string
+
+const (
+	StrikeKindLateCancellation StrikeKind = "late_cancellation"
+	StrikeKindNoShow           StrikeKind = "no_show"
+)
+
+// Strike is a penalty recorded against a user for one outing
+type Strike struct {
+	kind       StrikeKind
+	outingID   string
+	recordedAt time.Time
+}
+
+func ReconstructStrike(kind StrikeKind, outingID string, recordedAt time.Time) Strike {
+	return Strike{kind: kind, outingID: outingID, recordedAt: recordedAt}
+}
+
+func (s Strike) Kind() StrikeKind      { return s.kind }
+func (s Strike) OutingID() string      { return s.outingID }
+func (s Strike) RecordedAt() time.Time { return s.recordedAt }
+
+// StrikePolicy bans a user for BanDuration once Threshold strikes were
+// recorded within Window. The strikes that led to a ban are cleared.
+type StrikePolicy struct {
+	Threshold   int
+	Window      time.Duration
+	BanDuration time.Duration
+}
+
+func NewStrikePolicy(threshold int, window, banDuration time.Duration) (StrikePolicy, error) {
+	if threshold <= 0 {
+		return StrikePolicy{}, fmt.Errorf("strike threshold must be positive")
+	}
+	if window <= 0 || banDuration <= 0 {
+		return StrikePolicy{}, fmt.Errorf("strike window and ban duration must be positive")
+	}
+	return StrikePolicy{Threshold: threshold, Window: window, BanDuration: banDuration}, nil
+}
+
+// =============================================================================
+// AGGREGATE ROOT: UserStanding
+// =============================================================================
+
+// UserStanding tracks a user's recent strikes and booking ban. There is one
+// per user, identified by the user ID. Its version is the one it was loaded
+// at (0 when never saved), so a save can detect a concurrent change.
+type UserStanding struct {
+	domain.AggregateRoot
+
+	userID      string
+	strikes     []Strike
+	bannedUntil *time.Time
+	updatedAt   time.Time
+	version     int64
+}
+
+// NewUserStanding returns the standing of a user without any strike
+func NewUserStanding(userID string) *UserStanding {
+	return &UserStanding{userID: userID, updatedAt: time.Now()}
+}
+
+func ReconstructUserStanding(userID string, strikes []Strike, bannedUntil *time.Time, updatedAt time.Time, version int64) *UserStanding {
+	return &UserStanding{
+		userID:      userID,
+		strikes:     strikes,
+		bannedUntil: bannedUntil,
+		updatedAt:   updatedAt,
+		version:     version,
+	}
+}
+
+// Getters
+func (s *UserStanding) UserID() string          { return s.userID }
+func (s *UserStanding) Strikes() []Strike       { return s.strikes }
+func (s *UserStanding) BannedUntil() *time.Time { return s.bannedUntil }
+func (s *UserStanding) UpdatedAt() time.Time    { return s.updatedAt }
+func (s *UserStanding) Version() int64          { return s.version }
+
+// Business Logic
+
+// CanBook returns a BookingBanError while the user is banned
+func (s *UserStanding) CanBook(at time.Time) error {
+	if s.bannedUntil != nil && at.Before(*s.bannedUntil) {
+		return &BookingBanError{Until: *s.bannedUntil}
+	}
+	return nil
+}
+
+// AddStrike records a strike for the outing and bans the user once the policy
+// threshold is reached. A second strike for the same outing is ignored.
+// It reports whether the strike caused a ban.
+func (s *UserStanding) AddStrike(kind StrikeKind, outingID string, at time.Time, policy StrikePolicy) bool {
+	for _, strike := range s.strikes {
+		if strike.outingID == outingID {
+			return false
+		}
+	}
+
+	// Only strikes within the policy window count
+	active := s.strikes[:0]
+	for _, strike := range s.strikes {
+		if at.Sub(strike.recordedAt) < policy.Window {
+			active = append(active, strike)
+		}
+	}
+	s.strikes = append(active, Strike{kind: kind, outingID: outingID, recordedAt: at})
+	s.updatedAt = time.Now()
+
+	s.AddDomainEvent(NewStrikeRecordedEvent(s.userID, outingID, string(kind), len(s.strikes)))
+
+	if len(s.strikes) < policy.Threshold {
+		return false
+	}
+
+	bannedUntil := at.Add(policy.BanDuration)
+	if s.bannedUntil != nil && s.bannedUntil.After(bannedUntil) {
+		bannedUntil = *s.bannedUntil
+	}
+	s.bannedUntil = &bannedUntil
+	s.strikes = nil
+
+	s.AddDomainEvent(NewUserBookingBannedEvent(s.userID, bannedUntil))
+
+	return true
+}
@@ -0,0 +1,59 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+// =============================================================================
+// Strike Tests
+// =============================================================================
+
+func TestUserStanding_AddStrike(t *testing.T) {
+	policy, err := NewStrikePolicy(3, 30*24*time.Hour, 7*24*time.Hour)
+	if err != nil {
+		t.Fatalf("NewStrikePolicy() error = %v", err)
+	}
+	standing := NewUserStanding("user-123")
+	now := time.Now()
+
+	if banned := standing.AddStrike(StrikeKindNoShow, "outing-1", now, policy); banned {
+		t.Error("AddStrike() first strike should not ban")
+	}
+	if banned := standing.AddStrike(StrikeKindNoShow, "outing-1", now, policy); banned || len(standing.Strikes()) != 1 {
+		t.Errorf("AddStrike() same outing twice strikes = %d, want 1", len(standing.Strikes()))
+	}
+	_ = standing.AddStrike(StrikeKindLateCancellation, "outing-2", now, policy)
+	if err := standing.CanBook(now); err != nil {
+		t.Errorf("CanBook() before threshold error = %v, want nil", err)
+	}
+
+	if banned := standing.AddStrike(StrikeKindNoShow, "outing-3", now, policy); !banned {
+		t.Fatal("AddStrike() third strike should ban")
+	}
+	if err := standing.CanBook(now.Add(time.Hour)); !errors.Is(err, ErrUserBookingBanned) {
+		t.Errorf("CanBook() during ban error = %v, want %v", err, ErrUserBookingBanned)
+	}
+	if err := standing.CanBook(now.Add(8 * 24 * time.Hour)); err != nil {
+		t.Errorf("CanBook() after ban error = %v, want nil", err)
+	}
+	if len(standing.Strikes()) != 0 {
+		t.Errorf("AddStrike() strikes after ban = %d, want 0", len(standing.Strikes()))
+	}
+}
+
+func TestUserStanding_AddStrike_OutsideWindow(t *testing.T) {
+	policy, _ := NewStrikePolicy(2, 24*time.Hour, 7*24*time.Hour)
+	now := time.Now()
+	standing := ReconstructUserStanding("user-123", []Strike{
+		ReconstructStrike(StrikeKindNoShow, "outing-1", now.Add(-48*time.Hour)),
+	}, nil, now, 1)
+
+	if banned := standing.AddStrike(StrikeKindNoShow, "outing-2", now, policy); banned {
+		t.Error("AddStrike() should not count strikes outside the window")
+	}
+	if len(standing.Strikes()) != 1 {
+		t.Errorf("AddStrike() strikes = %d, want 1", len(standing.Strikes()))
+	}
+}
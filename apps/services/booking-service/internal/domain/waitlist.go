@@ -0,0 +1,225 @@
+package domain
+
+import (
+	"errors"
+	"time"
+
+	"github.com/yousoon/shared/domain"
+)
+
+// =============================================================================
+// WAITLIST ERRORS
+// =============================================================================
+
+var (
+	ErrWaitlistEntryNotFound = errors.New("waitlist entry not found")
+	ErrAlreadyOnWaitlist     = errors.New("user is already on the waitlist")
+	ErrWaitlistHoldNotActive = errors.New("no active hold on this waitlist entry")
+	ErrWaitlistHoldExpired   = errors.New("waitlist hold has expired")
+	ErrWaitlistEntryClosed   = errors.New("waitlist entry is no longer active")
+	ErrWaitlistEntryConflict = errors.New("waitlist entry was changed concurrently")
+)
+
+// =============================================================================
+// ENUMS
+// =============================================================================
+
+type WaitlistStatus string
+
+const (
+	WaitlistStatusWaiting   WaitlistStatus = "waiting"
+	WaitlistStatusHeld      WaitlistStatus = "held"
+	WaitlistStatusConverted WaitlistStatus = "converted"
+	WaitlistStatusExpired   WaitlistStatus = "expired"
+	WaitlistStatusLeft      WaitlistStatus = "left"
+	WaitlistStatusRemoved   WaitlistStatus = "removed"
+)
+
+// IsActive reports whether the entry still counts as being on the waitlist
+func (s WaitlistStatus) IsActive() bool {
+	return s == WaitlistStatusWaiting || s == WaitlistStatusHeld
+}
+
+// =============================================================================
+// AGGREGATE ROOT: WaitlistEntry
+// =============================================================================
+
+// WaitlistEntry is a user's place in line for a fully booked offer, or for one
+// slot instance of it. When capacity frees up the first waiting entry is given
+// a hold: the capacity is reserved for it until holdExpiresAt, and accepting
+// the hold turns it into an Outing.
+type WaitlistEntry struct {
+	domain.AggregateRoot
+
+	id      string
+	userID  string
+	offerID string
+	slot    *SlotSnapshot // nil to wait for the offer itself
+
+	status        WaitlistStatus
+	heldAt        *time.Time
+	holdExpiresAt *time.Time
+	outingID      string // set once converted
+	closeReason   string
+
+	joinedAt  time.Time
+	updatedAt time.Time
+}
+
+func NewWaitlistEntry(userID, offerID string, slot *SlotSnapshot) *WaitlistEntry {
+	now := time.Now()
+
+	entry := &WaitlistEntry{
+		id:        domain.NewBaseID().String(),
+		userID:    userID,
+		offerID:   offerID,
+		slot:      slot,
+		status:    WaitlistStatusWaiting,
+		joinedAt:  now,
+		updatedAt: now,
+	}
+
+	entry.AddDomainEvent(NewWaitlistJoinedEvent(entry.id, userID, offerID, entry.slotStartsAt()))
+
+	return entry
+}
+
+func ReconstructWaitlistEntry(
+	id, userID, offerID string,
+	slot *SlotSnapshot,
+	status WaitlistStatus,
+	heldAt, holdExpiresAt *time.Time,
+	outingID, closeReason string,
+	joinedAt, updatedAt time.Time,
+) *WaitlistEntry {
+	return &WaitlistEntry{
+		id:            id,
+		userID:        userID,
+		offerID:       offerID,
+		slot:          slot,
+		status:        status,
+		heldAt:        heldAt,
+		holdExpiresAt: holdExpiresAt,
+		outingID:      outingID,
+		closeReason:   closeReason,
+		joinedAt:      joinedAt,
+		updatedAt:     updatedAt,
+	}
+}
+
+// Getters
+func (w *WaitlistEntry) ID() string                { return w.id }
+func (w *WaitlistEntry) UserID() string            { return w.userID }
+func (w *WaitlistEntry) OfferID() string           { return w.offerID }
+func (w *WaitlistEntry) Slot() *SlotSnapshot       { return w.slot }
+func (w *WaitlistEntry) Status() WaitlistStatus    { return w.status }
+func (w *WaitlistEntry) HeldAt() *time.Time        { return w.heldAt }
+func (w *WaitlistEntry) HoldExpiresAt() *time.Time { return w.holdExpiresAt }
+func (w *WaitlistEntry) OutingID() string          { return w.outingID }
+func (w *WaitlistEntry) CloseReason() string       { return w.closeReason }
+func (w *WaitlistEntry) JoinedAt() time.Time       { return w.joinedAt }
+func (w *WaitlistEntry) UpdatedAt() time.Time      { return w.updatedAt }
+
+// Business Logic
+
+// OfferHold gives the entry the freed capacity until now + ttl
+func (w *WaitlistEntry) OfferHold(ttl time.Duration) error {
+	if w.status != WaitlistStatusWaiting {
+		return ErrWaitlistEntryClosed
+	}
+
+	now := time.Now()
+	expiresAt := now.Add(ttl)
+	if w.slot != nil && w.slot.EndsAt().Before(expiresAt) {
+		expiresAt = w.slot.EndsAt()
+	}
+
+	w.status = WaitlistStatusHeld
+	w.heldAt = &now
+	w.holdExpiresAt = &expiresAt
+	w.updatedAt = now
+
+	w.AddDomainEvent(NewWaitlistHoldOfferedEvent(w.id, w.userID, w.offerID, w.slotStartsAt(), expiresAt))
+
+	return nil
+}
+
+// HasActiveHold reports whether the entry holds capacity at the given time
+func (w *WaitlistEntry) HasActiveHold(at time.Time) bool {
+	return w.status == WaitlistStatusHeld && w.holdExpiresAt != nil && !at.After(*w.holdExpiresAt)
+}
+
+// CanAccept checks that the hold can still be turned into an outing
+func (w *WaitlistEntry) CanAccept() error {
+	if w.status != WaitlistStatusHeld {
+		return ErrWaitlistHoldNotActive
+	}
+	if !w.HasActiveHold(time.Now()) {
+		return ErrWaitlistHoldExpired
+	}
+	return nil
+}
+
+// Convert records the outing the accepted hold became
+func (w *WaitlistEntry) Convert(outingID string) error {
+	if err := w.CanAccept(); err != nil {
+		return err
+	}
+
+	w.status = WaitlistStatusConverted
+	w.outingID = outingID
+	w.updatedAt = time.Now()
+
+	w.AddDomainEvent(NewWaitlistConvertedEvent(w.id, w.userID, w.offerID, outingID))
+
+	return nil
+}
+
+// ExpireHold closes an entry whose hold was not accepted in time
+func (w *WaitlistEntry) ExpireHold() error {
+	if w.status != WaitlistStatusHeld {
+		return ErrWaitlistHoldNotActive
+	}
+
+	w.status = WaitlistStatusExpired
+	w.closeReason = "hold_expired"
+	w.updatedAt = time.Now()
+
+	w.AddDomainEvent(NewWaitlistClosedEvent(w.id, w.userID, w.offerID, string(w.status), w.closeReason))
+
+	return nil
+}
+
+// Leave removes the entry at the user's request. The caller must release the
+// capacity if the entry was holding some.
+func (w *WaitlistEntry) Leave() error {
+	return w.close(WaitlistStatusLeft, "user_left")
+}
+
+// Remove takes the entry out of line when it cannot be served, e.g. the slot
+// has passed or the user has reached their own quota
+func (w *WaitlistEntry) Remove(reason string) error {
+	return w.close(WaitlistStatusRemoved, reason)
+}
+
+func (w *WaitlistEntry) close(status WaitlistStatus, reason string) error {
+	if !w.status.IsActive() {
+		return ErrWaitlistEntryClosed
+	}
+
+	w.status = status
+	w.closeReason = reason
+	w.updatedAt = time.Now()
+
+	w.AddDomainEvent(NewWaitlistClosedEvent(w.id, w.userID, w.offerID, string(status), reason))
+
+	return nil
+}
+
+func (w *WaitlistEntry) slotStartsAt() *time.Time {
+	if w.slot == nil {
+		return nil
+	}
+	startsAt := w.slot.StartsAt()
+	return &startsAt
+}
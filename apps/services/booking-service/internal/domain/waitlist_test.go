@@ -0,0 +1,117 @@
+package domain
+
+import (
+	"testing"
+	"time"
+)
+
+// =============================================================================
+// Waitlist Tests
+// =============================================================================
+
+func TestNewWaitlistEntry(t *testing.T) {
+	entry := NewWaitlistEntry("user-123", "offer-123", nil)
+
+	if entry.ID() == "" {
+		t.Error("NewWaitlistEntry() ID should not be empty")
+	}
+	if entry.Status() != WaitlistStatusWaiting {
+		t.Errorf("NewWaitlistEntry() status = %v, want %v", entry.Status(), WaitlistStatusWaiting)
+	}
+	if events := entry.GetDomainEvents(); len(events) != 1 || events[0].EventName() != "waitlist.joined" {
+		t.Errorf("NewWaitlistEntry() events = %v, want one waitlist.joined", events)
+	}
+}
+
+func TestWaitlistEntry_OfferHold(t *testing.T) {
+	entry := NewWaitlistEntry("user-123", "offer-123", nil)
+	entry.ClearDomainEvents()
+
+	if err := entry.OfferHold(15 * time.Minute); err != nil {
+		t.Fatalf("OfferHold() error = %v, want nil", err)
+	}
+	if entry.Status() != WaitlistStatusHeld {
+		t.Errorf("OfferHold() status = %v, want %v", entry.Status(), WaitlistStatusHeld)
+	}
+	if !entry.HasActiveHold(time.Now()) {
+		t.Error("HasActiveHold() should be true right after OfferHold()")
+	}
+	if entry.HasActiveHold(time.Now().Add(16 * time.Minute)) {
+		t.Error("HasActiveHold() should be false after the TTL")
+	}
+	if events := entry.GetDomainEvents(); len(events) != 1 || events[0].EventName() != "waitlist.hold_offered" {
+		t.Errorf("OfferHold() events = %v, want one waitlist.hold_offered", events)
+	}
+
+	if err := entry.OfferHold(15 * time.Minute); err != ErrWaitlistEntryClosed {
+		t.Errorf("OfferHold() twice error = %v, want %v", err, ErrWaitlistEntryClosed)
+	}
+}
+
+func TestWaitlistEntry_OfferHold_CappedAtSlotEnd(t *testing.T) {
+	startsAt := time.Now().Add(-time.Hour)
+	slot := ReconstructSlotSnapshot("2026-10-16", "19:00", "21:00", startsAt, time.Now().Add(5*time.Minute), nil)
+	entry := NewWaitlistEntry("user-123", "offer-123", &slot)
+
+	if err := entry.OfferHold(15 * time.Minute); err != nil {
+		t.Fatalf("OfferHold() error = %v, want nil", err)
+	}
+	if !entry.HoldExpiresAt().Equal(slot.EndsAt()) {
+		t.Errorf("OfferHold() holdExpiresAt = %v, want slot end %v", entry.HoldExpiresAt(), slot.EndsAt())
+	}
+}
+
+func TestWaitlistEntry_Convert(t *testing.T) {
+	entry := NewWaitlistEntry("user-123", "offer-123", nil)
+
+	if err := entry.Convert("outing-1"); err != ErrWaitlistHoldNotActive {
+		t.Errorf("Convert() without hold error = %v, want %v", err, ErrWaitlistHoldNotActive)
+	}
+
+	_ = entry.OfferHold(15 * time.Minute)
+	if err := entry.Convert("outing-1"); err != nil {
+		t.Fatalf("Convert() error = %v, want nil", err)
+	}
+	if entry.Status() != WaitlistStatusConverted || entry.OutingID() != "outing-1" {
+		t.Errorf("Convert() = %v/%q, want %v/outing-1", entry.Status(), entry.OutingID(), WaitlistStatusConverted)
+	}
+}
+
+func TestWaitlistEntry_Convert_HoldExpired(t *testing.T) {
+	heldAt := time.Now().Add(-20 * time.Minute)
+	expiresAt := time.Now().Add(-5 * time.Minute)
+	entry := ReconstructWaitlistEntry(
+		"entry-1", "user-123", "offer-123", nil,
+		WaitlistStatusHeld, &heldAt, &expiresAt, "", "",
+		heldAt, heldAt,
+	)
+
+	if err := entry.Convert("outing-1"); err != ErrWaitlistHoldExpired {
+		t.Errorf("Convert() error = %v, want %v", err, ErrWaitlistHoldExpired)
+	}
+
+	if err := entry.ExpireHold(); err != nil {
+		t.Fatalf("ExpireHold() error = %v, want nil", err)
+	}
+	if entry.Status() != WaitlistStatusExpired || entry.CloseReason() != "hold_expired" {
+		t.Errorf("ExpireHold() = %v/%q, want %v/hold_expired", entry.Status(), entry.CloseReason(), WaitlistStatusExpired)
+	}
+}
+
+func TestWaitlistEntry_Close(t *testing.T) {
+	entry := NewWaitlistEntry("user-123", "offer-123", nil)
+
+	if err := entry.Leave(); err != nil {
+		t.Fatalf("Leave() error = %v, want nil", err)
+	}
+	if entry.Status() != WaitlistStatusLeft || entry.Status().IsActive() {
+		t.Errorf("Leave() status = %v, want inactive %v", entry.Status(), WaitlistStatusLeft)
+	}
+
+	if err := entry.Remove("slot_ended"); err != ErrWaitlistEntryClosed {
+		t.Errorf("Remove() after Leave() error = %v, want %v", err, ErrWaitlistEntryClosed)
+	}
+	if err := entry.ExpireHold(); err != ErrWaitlistHoldNotActive {
+		t.Errorf("ExpireHold() after Leave() error = %v, want %v", err, ErrWaitlistHoldNotActive)
+	}
+}
@@ -0,0 +1,183 @@
+package calendar
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/yousoon/apps/services/booking-service/internal/domain"
+)
+
+// =============================================================================
+// ICALENDAR (RFC 5545)
+// =============================================================================
+
+const (
+	prodID = "-//Yousoon//Booking Service//EN"
+
+	// maxLineOctets is the longest content line before it must be folded
+	maxLineOctets = 75
+
+	// feedRefreshInterval is the polling interval suggested to subscribers
+	feedRefreshInterval = "PT1H"
+)
+
+// WriteEvent writes a calendar holding a single event for the outing
+func WriteEvent(w io.Writer, outing *domain.Outing, now time.Time) error {
+	cw := newContentWriter(w)
+	cw.line("BEGIN:VCALENDAR")
+	cw.line("VERSION:2.0")
+	cw.line("PRODID:" + prodID)
+	cw.line("CALSCALE:GREGORIAN")
+	cw.line("METHOD:PUBLISH")
+	writeEvent(cw, outing, now)
+	cw.line("END:VCALENDAR")
+	return cw.flush()
+}
+
+// WriteFeed writes a subscribable calendar of the given outings. Calendar
+// apps re-fetch it periodically, so outings left out of a later version of
+// the feed disappear from the subscriber's calendar.
+func WriteFeed(w io.Writer, name string, outings []*domain.Outing, now time.Time) error {
+	cw := newContentWriter(w)
+	cw.line("BEGIN:VCALENDAR")
+	cw.line("VERSION:2.0")
+	cw.line("PRODID:" + prodID)
+	cw.line("CALSCALE:GREGORIAN")
+	cw.line("METHOD:PUBLISH")
+	cw.line("X-WR-CALNAME:" + escapeText(name))
+	cw.line("REFRESH-INTERVAL;VALUE=DURATION:" + feedRefreshInterval)
+	cw.line("X-PUBLISHED-TTL:" + feedRefreshInterval)
+	for _, outing := range outings {
+		writeEvent(cw, outing, now)
+	}
+	cw.line("END:VCALENDAR")
+	return cw.flush()
+}
+
+// EventWindow is when the outing takes place: its slot when it has one,
+// otherwise from booking until the booking expires.
+func EventWindow(outing *domain.Outing) (start, end time.Time) {
+	if slot := outing.Slot(); slot != nil {
+		return slot.StartsAt(), slot.EndsAt()
+	}
+	return outing.BookedAt(), outing.ExpiresAt()
+}
+
+func writeEvent(cw *contentWriter, outing *domain.Outing, now time.Time) {
+	offer := outing.Offer()
+	start, end := EventWindow(outing)
+
+	cw.line("BEGIN:VEVENT")
+	cw.line("UID:" + outing.ID() + "@booking.yousoon")
+	cw.line("DTSTAMP:" + formatTime(now))
+	cw.line("LAST-MODIFIED:" + formatTime(outing.UpdatedAt()))
+	cw.line("DTSTART:" + formatTime(start))
+	cw.line("DTEND:" + formatTime(end))
+	cw.line("SUMMARY:" + escapeText(offer.Title()))
+	cw.line("LOCATION:" + escapeText(location(offer)))
+	if offer.Latitude() != 0 || offer.Longitude() != 0 {
+		cw.line(fmt.Sprintf("GEO:%.6f;%.6f", offer.Latitude(), offer.Longitude()))
+	}
+	cw.line("DESCRIPTION:" + escapeText(description(outing)))
+	cw.line("STATUS:" + eventStatus(outing))
+	cw.line("TRANSP:OPAQUE")
+	cw.line("END:VEVENT")
+}
+
+func location(offer domain.OfferSnapshot) string {
+	if offer.EstablishmentAddress() == "" {
+		return offer.EstablishmentName()
+	}
+	return offer.EstablishmentName() + ", " + offer.EstablishmentAddress()
+}
+
+func description(outing *domain.Outing) string {
+	var b strings.Builder
+	if desc := outing.Offer().Description(); desc != "" {
+		b.WriteString(desc)
+		b.WriteString("\n\n")
+	}
+	fmt.Fprintf(&b, "Show your QR code at %s before %s.",
+		outing.Offer().EstablishmentName(),
+		outing.ExpiresAt().UTC().Format("2006-01-02 15:04 MST"))
+	return b.String()
+}
+
+func eventStatus(outing *domain.Outing) string {
+	switch outing.Status() {
+	case domain.OutingStatusCancelled, domain.OutingStatusExpired:
+		return "CANCELLED"
+	case domain.OutingStatusPending:
+		return "TENTATIVE"
+	default:
+		return "CONFIRMED"
+	}
+}
+
+func formatTime(t time.Time) string {
+	return t.UTC().Format("20060102T150405Z")
+}
+
+// escapeText escapes a TEXT property value
+func escapeText(value string) string {
+	return strings.NewReplacer(
+		`\`, `\\`,
+		";", `\;`,
+		",", `\,`,
+		"\r\n", `\n`,
+		"\n", `\n`,
+		"\r", "",
+	).Replace(value)
+}
+
+// =============================================================================
+// CONTENT LINES
+// =============================================================================
+
+// contentWriter writes CRLF-terminated content lines, folding those longer
+// than 75 octets without splitting UTF-8 sequences. The first write error is
+// kept and returned by flush.
+type contentWriter struct {
+	w   *bufio.Writer
+	err error
+}
+
+func newContentWriter(w io.Writer) *contentWriter {
+	return &contentWriter{w: bufio.NewWriter(w)}
+}
+
+func (c *contentWriter) line(content string) {
+	if c.err != nil {
+		return
+	}
+
+	limit := maxLineOctets
+	for len(content) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(content[cut]) {
+			cut--
+		}
+		c.write(content[:cut] + "\r\n ")
+		content = content[cut:]
+		// Continuation lines start with the folding space
+		limit = maxLineOctets - 1
+	}
+	c.write(content + "\r\n")
+}
+
+func (c *contentWriter) write(s string) {
+	if c.err == nil {
+		_, c.err = c.w.WriteString(s)
+	}
+}
+
+func (c *contentWriter) flush() error {
+	if c.err != nil {
+		return c.err
+	}
+	return c.w.Flush()
+}
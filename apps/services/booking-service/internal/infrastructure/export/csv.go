@@ -0,0 +1,42 @@
+package export
+
+import (
+	"encoding/csv"
+	"io"
+	"strings"
+)
+
+// CSVWriter streams rows as RFC 4180 CSV
+type CSVWriter struct {
+	w *csv.Writer
+}
+
+func NewCSVWriter(w io.Writer) *CSVWriter {
+	return &CSVWriter{w: csv.NewWriter(w)}
+}
+
+// WriteRow writes one record. Values that a spreadsheet would evaluate as a
+// formula are prefixed with a quote, as some of them come from users.
+func (c *CSVWriter) WriteRow(values []string) error {
+	record := make([]string, len(values))
+	for i, value := range values {
+		record[i] = neutralizeFormula(value)
+	}
+	if err := c.w.Write(record); err != nil {
+		return err
+	}
+	c.w.Flush()
+	return c.w.Error()
+}
+
+func (c *CSVWriter) Close() error {
+	c.w.Flush()
+	return c.w.Error()
+}
+
+func neutralizeFormula(value string) string {
+	if value != "" && strings.ContainsRune("=+-@\t\r", rune(value[0])) {
+		return "'" + value
+	}
+	return value
+}
@@ -0,0 +1,64 @@
+package export
+
+import (
+	"bytes"
+	"encoding/csv"
+	"testing"
+)
+
+// =============================================================================
+// CSV Writer Tests
+// =============================================================================
+
+func TestNeutralizeFormula(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+		want  string
+	}{
+		{"empty", "", ""},
+		{"plain text", "Le Comptoir", "Le Comptoir"},
+		{"number", "1250", "1250"},
+		{"formula", "=HYPERLINK(\"http://evil\")", "'=HYPERLINK(\"http://evil\")"},
+		{"plus", "+33612345678", "'+33612345678"},
+		{"minus", "-2+3", "'-2+3"},
+		{"at sign", "@SUM(A1:A2)", "'@SUM(A1:A2)"},
+		{"tab", "\t=1+1", "'\t=1+1"},
+		{"carriage return", "\r=1+1", "'\r=1+1"},
+		{"formula character later", "a=b", "a=b"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := neutralizeFormula(tt.value); got != tt.want {
+				t.Errorf("neutralizeFormula(%q) = %q, want %q", tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCSVWriter_WriteRow(t *testing.T) {
+	var buf bytes.Buffer
+	writer := NewCSVWriter(&buf)
+
+	if err := writer.WriteRow([]string{"outing-1", "=cmd|' /C calc'!A0", "Dupont, Jean"}); err != nil {
+		t.Fatalf("WriteRow() error = %v, want nil", err)
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatalf("Close() error = %v, want nil", err)
+	}
+
+	records, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatalf("output is not valid CSV: %v", err)
+	}
+	want := []string{"outing-1", "'=cmd|' /C calc'!A0", "Dupont, Jean"}
+	if len(records) != 1 || len(records[0]) != len(want) {
+		t.Fatalf("WriteRow() records = %q, want one row %q", records, want)
+	}
+	for i := range want {
+		if records[0][i] != want[i] {
+			t.Errorf("WriteRow() field %d = %q, want %q", i, records[0][i], want[i])
+		}
+	}
+}
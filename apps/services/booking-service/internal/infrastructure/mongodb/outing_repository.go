@@ -2,7 +2,9 @@ package mongodb
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	"go.mongodb.org/mongo-driver/bson"
@@ -11,6 +13,8 @@ import (
 	"go.mongodb.org/mongo-driver/mongo/options"
 
 	"github.com/yousoon/apps/services/booking-service/internal/domain"
+	shareddomain "github.com/yousoon/shared/domain"
+	"github.com/yousoon/shared/infrastructure/outbox"
 )
 
 // =============================================================================
@@ -27,9 +31,15 @@ type OutingDocument struct {
 	// User snapshot
 	User UserSnapshotDoc `bson:"user"`
 
+	// Booked time slot (optional)
+	Slot *SlotSnapshotDoc `bson:"slot,omitempty"`
+
 	// QR Code
 	QRCode QRCodeDoc `bson:"qr_code"`
 
+	// Short code, unique among the establishment's active outings
+	ShortCode string `bson:"short_code,omitempty"`
+
 	// Status
 	Status   string             `bson:"status"`
 	Timeline []TimelineEntryDoc `bson:"timeline"`
@@ -40,6 +50,12 @@ type OutingDocument struct {
 	// Cancellation (optional)
 	Cancellation *CancellationInfoDoc `bson:"cancellation,omitempty"`
 
+	// Partner confirmation deadline (pending outings only)
+	ConfirmBy *time.Time `bson:"confirm_by,omitempty"`
+
+	// Transfer awaiting the recipient's acceptance (optional)
+	Transfer *OutingTransferDoc `bson:"transfer,omitempty"`
+
 	// Timing
 	BookedAt  time.Time `bson:"booked_at"`
 	ExpiresAt time.Time `bson:"expires_at"`
@@ -73,9 +89,17 @@ type UserSnapshotDoc struct {
 	Email     string `bson:"email"`
 }
 
+type SlotSnapshotDoc struct {
+	Date      string    `bson:"date"`
+	StartTime string    `bson:"start_time"`
+	EndTime   string    `bson:"end_time"`
+	StartsAt  time.Time `bson:"starts_at"`
+	EndsAt    time.Time `bson:"ends_at"`
+	Capacity  *int      `bson:"capacity,omitempty"`
+}
+
 type QRCodeDoc struct {
 	Code      string    `bson:"code"`
-	Signature string    `bson:"signature"`
 	CreatedAt time.Time `bson:"created_at"`
 	ExpiresAt time.Time `bson:"expires_at"`
 }
@@ -93,23 +117,55 @@ type CheckInInfoDoc struct {
 	Method      string    `bson:"method"`
 	Latitude    *float64  `bson:"latitude,omitempty"`
 	Longitude   *float64  `bson:"longitude,omitempty"`
+
+	DistanceMeters   *float64 `bson:"distance_meters,omitempty"`
+	FlaggedForReview bool     `bson:"flagged_for_review,omitempty"`
+	ReviewReason     string   `bson:"review_reason,omitempty"`
+
+	Anomalies []CheckInAnomalyDoc `bson:"anomalies,omitempty"`
+	Bill      *BillDoc            `bson:"bill,omitempty"`
+}
+
+type CheckInAnomalyDoc struct {
+	Reason     string    `bson:"reason"`
+	Detail     string    `bson:"detail,omitempty"`
+	DetectedAt time.Time `bson:"detected_at"`
+}
+
+type BillDoc struct {
+	AmountCents  int64     `bson:"amount_cents"`
+	SavingsCents int64     `bson:"savings_cents"`
+	RecordedAt   time.Time `bson:"recorded_at"`
+	RecordedBy   string    `bson:"recorded_by"`
 }
 
 type CancellationInfoDoc struct {
 	CancelledAt time.Time `bson:"cancelled_at"`
 	CancelledBy string    `bson:"cancelled_by"`
 	Reason      string    `bson:"reason"`
+	Late        bool      `bson:"late,omitempty"`
+}
+
+type OutingTransferDoc struct {
+	FromUserID  string    `bson:"from_user_id"`
+	ToUserID    string    `bson:"to_user_id"`
+	InitiatedAt time.Time `bson:"initiated_at"`
 }
 
 // =============================================================================
 // REPOSITORY IMPLEMENTATION
 // =============================================================================
 
+// shortCodeIndexName names the index keeping short codes unique among the
+// active outings of an establishment
+const shortCodeIndexName = "establishment_short_code_active"
+
 type OutingRepository struct {
 	collection *mongo.Collection
+	outbox     *outbox.Store
 }
 
-func NewOutingRepository(db *mongo.Database) *OutingRepository {
+func NewOutingRepository(db *mongo.Database, outbox *outbox.Store) *OutingRepository {
 	collection := db.Collection("outings")
 
 	// Create indexes
@@ -118,20 +174,20 @@ func NewOutingRepository(db *mongo.Database) *OutingRepository {
 
 	indexes := []mongo.IndexModel{
 		{
-			Keys:    bson.D{{Key: "user_id", Value: 1}, {Key: "created_at", Value: -1}},
-			Options: options.Index().SetName("user_outings"),
+			Keys:    bson.D{{Key: "user_id", Value: 1}, {Key: "created_at", Value: -1}, {Key: "_id", Value: -1}},
+			Options: options.Index().SetName("user_outings_page"),
 		},
 		{
 			Keys:    bson.D{{Key: "offer.offer_id", Value: 1}},
 			Options: options.Index().SetName("offer_outings"),
 		},
 		{
-			Keys:    bson.D{{Key: "offer.partner_id", Value: 1}, {Key: "created_at", Value: -1}},
-			Options: options.Index().SetName("partner_outings"),
+			Keys:    bson.D{{Key: "offer.partner_id", Value: 1}, {Key: "created_at", Value: -1}, {Key: "_id", Value: -1}},
+			Options: options.Index().SetName("partner_outings_page"),
 		},
 		{
-			Keys:    bson.D{{Key: "offer.establishment_id", Value: 1}},
-			Options: options.Index().SetName("establishment_outings"),
+			Keys:    bson.D{{Key: "offer.establishment_id", Value: 1}, {Key: "created_at", Value: -1}, {Key: "_id", Value: -1}},
+			Options: options.Index().SetName("establishment_outings_page"),
 		},
 		{
 			Keys:    bson.D{{Key: "qr_code.code", Value: 1}},
@@ -149,37 +205,239 @@ func NewOutingRepository(db *mongo.Database) *OutingRepository {
 			Keys:    bson.D{{Key: "user_id", Value: 1}, {Key: "offer.offer_id", Value: 1}, {Key: "status", Value: 1}},
 			Options: options.Index().SetName("user_offer_active"),
 		},
+		{
+			Keys: bson.D{{Key: "offer.offer_id", Value: 1}, {Key: "slot.starts_at", Value: 1}, {Key: "status", Value: 1}},
+			Options: options.Index().
+				SetName("offer_slot_outings").
+				SetPartialFilterExpression(bson.D{{Key: "slot", Value: bson.D{{Key: "$exists", Value: true}}}}),
+		},
+		{
+			Keys: bson.D{{Key: "confirm_by", Value: 1}},
+			Options: options.Index().
+				SetName("pending_confirmation").
+				SetPartialFilterExpression(bson.D{{Key: "status", Value: string(domain.OutingStatusPending)}}),
+		},
+		{
+			Keys: bson.D{{Key: "offer.establishment_id", Value: 1}, {Key: "short_code", Value: 1}},
+			Options: options.Index().
+				SetUnique(true).
+				SetName(shortCodeIndexName).
+				// Outings booked before short codes existed have none
+				SetPartialFilterExpression(append(bson.D{
+					{Key: "short_code", Value: bson.D{{Key: "$exists", Value: true}}},
+				}, activeStatusFilter()...)),
+		},
+		{
+			Keys: bson.D{{Key: "transfer.to_user_id", Value: 1}},
+			Options: options.Index().
+				SetName("pending_transfers").
+				SetPartialFilterExpression(bson.D{{Key: "transfer", Value: bson.D{{Key: "$exists", Value: true}}}}),
+		},
+		{
+			Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "check_in.checked_in_at", Value: 1}},
+			Options: options.Index().
+				SetName("user_check_ins").
+				SetPartialFilterExpression(bson.D{{Key: "check_in", Value: bson.D{{Key: "$exists", Value: true}}}}),
+		},
+		{
+			Keys: bson.D{{Key: "check_in.checked_in_by", Value: 1}, {Key: "check_in.checked_in_at", Value: 1}},
+			Options: options.Index().
+				SetName("staff_check_ins").
+				SetPartialFilterExpression(bson.D{{Key: "check_in", Value: bson.D{{Key: "$exists", Value: true}}}}),
+		},
+		{
+			Keys: bson.D{{Key: "created_at", Value: -1}, {Key: "_id", Value: -1}},
+			Options: options.Index().
+				SetName("flagged_outings_page").
+				SetPartialFilterExpression(bson.D{{Key: "check_in.flagged_for_review", Value: true}}),
+		},
 	}
 
 	_, _ = collection.Indexes().CreateMany(ctx, indexes)
 
-	return &OutingRepository{collection: collection}
+	return &OutingRepository{collection: collection, outbox: outbox}
 }
 
+// errOutingConditionNotMet aborts a conditional update's transaction
+var errOutingConditionNotMet = errors.New("outing update condition not met")
+
+// Create inserts the outing and its pending domain events atomically.
 func (r *OutingRepository) Create(ctx context.Context, outing *domain.Outing) error {
 	doc := r.toDocument(outing)
-	doc.ID = primitive.NewObjectID()
+	if doc.ID.IsZero() {
+		doc.ID = primitive.NewObjectID()
+	}
+
+	return r.withOutbox(ctx, outing, func(sessCtx mongo.SessionContext) error {
+		if _, err := r.collection.InsertOne(sessCtx, doc); err != nil {
+			if mongo.IsDuplicateKeyError(err) && strings.Contains(err.Error(), shortCodeIndexName) {
+				return domain.ErrShortCodeTaken
+			}
+			return fmt.Errorf("failed to insert outing: %w", err)
+		}
+		return nil
+	})
+}
 
-	_, err := r.collection.InsertOne(ctx, doc)
+// Update saves the outing and its pending domain events atomically.
+func (r *OutingRepository) Update(ctx context.Context, outing *domain.Outing) error {
+	_, err := r.update(ctx, outing, nil)
+	return err
+}
+
+// UpdateIfStatus saves the outing like Update, provided its stored status is
+// still from, so two transitions out of the same status cannot both apply.
+func (r *OutingRepository) UpdateIfStatus(ctx context.Context, outing *domain.Outing, from domain.OutingStatus) error {
+	matched, err := r.update(ctx, outing, bson.D{{Key: "status", Value: string(from)}})
 	if err != nil {
-		return fmt.Errorf("failed to insert outing: %w", err)
+		return err
+	}
+	if !matched {
+		return domain.ErrOutingStatusChanged
 	}
-
 	return nil
 }
 
-func (r *OutingRepository) Update(ctx context.Context, outing *domain.Outing) error {
+// SaveAnomalies pushes each flagged anomaly with its own timeline entry, so a
+// check-in or bill saved concurrently is not overwritten. Each push is
+// conditional on the stored outing being checked in without that anomaly,
+// and only the flagged events of the pushed anomalies are published.
+func (r *OutingRepository) SaveAnomalies(ctx context.Context, outing *domain.Outing, reasons []domain.AnomalyReason) ([]domain.AnomalyReason, error) {
+	if len(reasons) == 0 || outing.CheckIn() == nil {
+		return nil, nil
+	}
+
 	doc := r.toDocument(outing)
+	var saved []domain.AnomalyReason
+
+	err := r.outbox.SaveWithEvents(ctx, eventsAppendedBy{outing}, func(sessCtx mongo.SessionContext) error {
+		saved = saved[:0]
+		pushed := make(map[string]bool, len(reasons))
+
+		for _, reason := range reasons {
+			anomaly, entry, ok := flaggedAnomalyDocs(doc, reason)
+			if !ok {
+				continue
+			}
+
+			filter := bson.D{
+				{Key: "_id", Value: doc.ID},
+				{Key: "status", Value: string(domain.OutingStatusCheckedIn)},
+				{Key: "check_in.anomalies.reason", Value: bson.D{{Key: "$ne", Value: string(reason)}}},
+			}
+			update := bson.D{
+				{Key: "$push", Value: bson.D{
+					{Key: "check_in.anomalies", Value: anomaly},
+					{Key: "timeline", Value: entry},
+				}},
+				{Key: "$set", Value: bson.D{
+					{Key: "check_in.flagged_for_review", Value: true},
+					{Key: "updated_at", Value: doc.UpdatedAt},
+				}},
+			}
+
+			result, err := r.collection.UpdateOne(sessCtx, filter, update)
+			if err != nil {
+				return fmt.Errorf("failed to save anomaly %s: %w", reason, err)
+			}
+			if result.MatchedCount > 0 {
+				saved = append(saved, reason)
+				pushed[string(reason)] = true
+			}
+		}
+		if len(saved) == 0 {
+			return domain.ErrOutingStatusChanged
+		}
 
-	filter := bson.D{{Key: "_id", Value: doc.ID}}
-	update := bson.D{{Key: "$set", Value: doc}}
+		// The first anomaly of an outing becomes its review reason
+		reviewFilter := bson.D{
+			{Key: "_id", Value: doc.ID},
+			{Key: "check_in.review_reason", Value: bson.D{{Key: "$in", Value: bson.A{nil, ""}}}},
+		}
+		reviewUpdate := bson.D{{Key: "$set", Value: bson.D{{Key: "check_in.review_reason", Value: string(saved[0])}}}}
+		if _, err := r.collection.UpdateOne(sessCtx, reviewFilter, reviewUpdate); err != nil {
+			return fmt.Errorf("failed to save review reason: %w", err)
+		}
 
-	_, err := r.collection.UpdateOne(ctx, filter, update)
+		var events []shareddomain.DomainEvent
+		for _, event := range outing.GetDomainEvents() {
+			if flagged, ok := event.(domain.OutingFlagged); ok && !pushed[flagged.Reason] {
+				continue
+			}
+			events = append(events, event)
+		}
+		return r.outbox.Append(sessCtx, events)
+	})
 	if err != nil {
-		return fmt.Errorf("failed to update outing: %w", err)
+		return nil, err
 	}
 
-	return nil
+	return saved, nil
+}
+
+// flaggedAnomalyDocs returns the anomaly flagged for reason and the timeline
+// entry recording it
+func flaggedAnomalyDocs(doc *OutingDocument, reason domain.AnomalyReason) (CheckInAnomalyDoc, TimelineEntryDoc, bool) {
+	var anomaly *CheckInAnomalyDoc
+	for i := range doc.CheckIn.Anomalies {
+		if doc.CheckIn.Anomalies[i].Reason == string(reason) {
+			anomaly = &doc.CheckIn.Anomalies[i]
+		}
+	}
+	if anomaly == nil {
+		return CheckInAnomalyDoc{}, TimelineEntryDoc{}, false
+	}
+
+	for i := len(doc.Timeline) - 1; i >= 0; i-- {
+		entry := doc.Timeline[i]
+		if entry.Metadata["action"] == "flagged" && entry.Metadata["reason"] == string(reason) {
+			return *anomaly, entry, true
+		}
+	}
+	return CheckInAnomalyDoc{}, TimelineEntryDoc{}, false
+}
+
+// eventsAppendedBy hands no events to SaveWithEvents for a write that appends
+// the outing's events itself, and clears them once the write has committed
+type eventsAppendedBy struct {
+	outing *domain.Outing
+}
+
+func (e eventsAppendedBy) GetDomainEvents() []shareddomain.DomainEvent { return nil }
+func (e eventsAppendedBy) ClearDomainEvents()                          { e.outing.ClearDomainEvents() }
+
+// update replaces the outing's fields, when the stored outing also matches
+// condition, and reports whether it did
+func (r *OutingRepository) update(ctx context.Context, outing *domain.Outing, condition bson.D) (bool, error) {
+	doc := r.toDocument(outing)
+
+	filter := append(bson.D{{Key: "_id", Value: doc.ID}}, condition...)
+	update := bson.D{{Key: "$set", Value: doc}}
+	if doc.Transfer == nil {
+		// $set leaves out the withdrawn or accepted transfer
+		update = append(update, bson.E{Key: "$unset", Value: bson.D{{Key: "transfer", Value: ""}}})
+	}
+
+	matched := false
+	err := r.withOutbox(ctx, outing, func(sessCtx mongo.SessionContext) error {
+		result, err := r.collection.UpdateOne(sessCtx, filter, update)
+		if err != nil {
+			if mongo.IsDuplicateKeyError(err) && strings.Contains(err.Error(), shortCodeIndexName) {
+				return domain.ErrShortCodeTaken
+			}
+			return fmt.Errorf("failed to update outing: %w", err)
+		}
+		if result.MatchedCount == 0 && len(condition) > 0 {
+			// Abort so the events of the lost transition are not published
+			return errOutingConditionNotMet
+		}
+		matched = true
+		return nil
+	})
+	if err == errOutingConditionNotMet {
+		return false, nil
+	}
+	return matched, err
 }
 
 func (r *OutingRepository) GetByID(ctx context.Context, id string) (*domain.Outing, error) {
@@ -219,24 +477,42 @@ func (r *OutingRepository) GetByQRCode(ctx context.Context, qrCode string) (*dom
 	return r.toDomain(&doc), nil
 }
 
-func (r *OutingRepository) GetByUserID(ctx context.Context, userID string, filter domain.OutingFilter) ([]*domain.Outing, int64, error) {
+func (r *OutingRepository) GetByShortCode(ctx context.Context, establishmentID, shortCode string) (*domain.Outing, error) {
+	query := append(bson.D{
+		{Key: "offer.establishment_id", Value: establishmentID},
+		{Key: "short_code", Value: shortCode},
+	}, activeStatusFilter()...)
+
+	var doc OutingDocument
+	err := r.collection.FindOne(ctx, query).Decode(&doc)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, domain.ErrOutingNotFound
+		}
+		return nil, fmt.Errorf("failed to get outing by short code: %w", err)
+	}
+
+	return r.toDomain(&doc), nil
+}
+
+func (r *OutingRepository) GetByUserID(ctx context.Context, userID string, filter domain.OutingFilter) (*domain.OutingPage, error) {
 	query := bson.D{{Key: "user_id", Value: userID}}
-	return r.findWithFilter(ctx, query, filter)
+	return r.findPage(ctx, query, filter)
 }
 
-func (r *OutingRepository) GetByOfferID(ctx context.Context, offerID string, filter domain.OutingFilter) ([]*domain.Outing, int64, error) {
+func (r *OutingRepository) GetByOfferID(ctx context.Context, offerID string, filter domain.OutingFilter) (*domain.OutingPage, error) {
 	query := bson.D{{Key: "offer.offer_id", Value: offerID}}
-	return r.findWithFilter(ctx, query, filter)
+	return r.findPage(ctx, query, filter)
 }
 
-func (r *OutingRepository) GetByPartnerID(ctx context.Context, partnerID string, filter domain.OutingFilter) ([]*domain.Outing, int64, error) {
+func (r *OutingRepository) GetByPartnerID(ctx context.Context, partnerID string, filter domain.OutingFilter) (*domain.OutingPage, error) {
 	query := bson.D{{Key: "offer.partner_id", Value: partnerID}}
-	return r.findWithFilter(ctx, query, filter)
+	return r.findPage(ctx, query, filter)
 }
 
-func (r *OutingRepository) GetByEstablishmentID(ctx context.Context, establishmentID string, filter domain.OutingFilter) ([]*domain.Outing, int64, error) {
+func (r *OutingRepository) GetByEstablishmentID(ctx context.Context, establishmentID string, filter domain.OutingFilter) (*domain.OutingPage, error) {
 	query := bson.D{{Key: "offer.establishment_id", Value: establishmentID}}
-	return r.findWithFilter(ctx, query, filter)
+	return r.findPage(ctx, query, filter)
 }
 
 func (r *OutingRepository) GetActiveByUserAndOffer(ctx context.Context, userID, offerID string) (*domain.Outing, error) {
@@ -268,6 +544,7 @@ func (r *OutingRepository) CountByUserAndOffer(ctx context.Context, userID, offe
 	query := bson.D{
 		{Key: "user_id", Value: userID},
 		{Key: "offer.offer_id", Value: offerID},
+		{Key: "status", Value: bson.D{{Key: "$in", Value: quotaConsumingStatuses()}}},
 	}
 
 	count, err := r.collection.CountDocuments(ctx, query)
@@ -281,6 +558,7 @@ func (r *OutingRepository) CountByUserAndOffer(ctx context.Context, userID, offe
 func (r *OutingRepository) CountByOfferAndPeriod(ctx context.Context, offerID string, start, end time.Time) (int64, error) {
 	query := bson.D{
 		{Key: "offer.offer_id", Value: offerID},
+		{Key: "status", Value: bson.D{{Key: "$in", Value: quotaConsumingStatuses()}}},
 		{Key: "created_at", Value: bson.D{
 			{Key: "$gte", Value: start},
 			{Key: "$lte", Value: end},
@@ -295,6 +573,250 @@ func (r *OutingRepository) CountByOfferAndPeriod(ctx context.Context, offerID st
 	return count, nil
 }
 
+func (r *OutingRepository) CountBySlot(ctx context.Context, offerID string, slotStartsAt time.Time) (int64, error) {
+	query := bson.D{
+		{Key: "offer.offer_id", Value: offerID},
+		{Key: "slot.starts_at", Value: slotStartsAt},
+		{Key: "status", Value: bson.D{{Key: "$in", Value: quotaConsumingStatuses()}}},
+	}
+
+	count, err := r.collection.CountDocuments(ctx, query)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count slot bookings: %w", err)
+	}
+
+	return count, nil
+}
+
+func (r *OutingRepository) GetStats(ctx context.Context, filter domain.StatsFilter) (*domain.BookingStats, error) {
+	match := bson.D{}
+	if filter.PartnerID != "" {
+		match = append(match, bson.E{Key: "offer.partner_id", Value: filter.PartnerID})
+	}
+	if filter.EstablishmentID != "" {
+		match = append(match, bson.E{Key: "offer.establishment_id", Value: filter.EstablishmentID})
+	}
+	if filter.OfferID != "" {
+		match = append(match, bson.E{Key: "offer.offer_id", Value: filter.OfferID})
+	}
+	if filter.StartDate != nil || filter.EndDate != nil {
+		period := bson.D{}
+		if filter.StartDate != nil {
+			period = append(period, bson.E{Key: "$gte", Value: *filter.StartDate})
+		}
+		if filter.EndDate != nil {
+			period = append(period, bson.E{Key: "$lte", Value: *filter.EndDate})
+		}
+		match = append(match, bson.E{Key: "booked_at", Value: period})
+	}
+
+	facets := bson.D{
+		{Key: "totals", Value: bson.A{
+			bson.D{{Key: "$group", Value: statsGroup(nil)}},
+		}},
+	}
+
+	if filter.Granularity.IsValid() {
+		dateTrunc := bson.D{
+			{Key: "date", Value: "$booked_at"},
+			{Key: "unit", Value: string(filter.Granularity)},
+		}
+		if filter.Granularity == domain.StatsGranularityWeek {
+			dateTrunc = append(dateTrunc, bson.E{Key: "startOfWeek", Value: "monday"})
+		}
+		if filter.Timezone != "" {
+			dateTrunc = append(dateTrunc, bson.E{Key: "timezone", Value: filter.Timezone})
+		}
+
+		facets = append(facets, bson.E{Key: "series", Value: bson.A{
+			bson.D{{Key: "$group", Value: statsGroup(bson.D{{Key: "$dateTrunc", Value: dateTrunc}})}},
+			bson.D{{Key: "$sort", Value: bson.D{{Key: "_id", Value: 1}}}},
+		}})
+	}
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: match}},
+		{{Key: "$facet", Value: facets}},
+	}
+
+	cursor, err := r.collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, fmt.Errorf("failed to aggregate booking stats: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var results []struct {
+		Totals []statsGroupDoc `bson:"totals"`
+		Series []statsGroupDoc `bson:"series"`
+	}
+	if err := cursor.All(ctx, &results); err != nil {
+		return nil, fmt.Errorf("failed to decode booking stats: %w", err)
+	}
+
+	stats := &domain.BookingStats{}
+	if len(results) == 0 {
+		return stats, nil
+	}
+
+	if len(results[0].Totals) > 0 {
+		stats.StatsCounts = results[0].Totals[0].toCounts()
+	}
+	for _, bucket := range results[0].Series {
+		periodStart, _ := bucket.ID.(primitive.DateTime)
+		stats.Series = append(stats.Series, domain.BookingStatsBucket{
+			PeriodStart: periodStart.Time(),
+			StatsCounts: bucket.toCounts(),
+		})
+	}
+
+	return stats, nil
+}
+
+func (r *OutingRepository) GetSavings(ctx context.Context, filter domain.SavingsFilter) (*domain.SavingsSummary, error) {
+	match := bson.D{
+		{Key: "user_id", Value: filter.UserID},
+		{Key: "status", Value: string(domain.OutingStatusCheckedIn)},
+		{Key: "check_in.bill", Value: bson.D{{Key: "$exists", Value: true}}},
+	}
+	if filter.StartDate != nil || filter.EndDate != nil {
+		period := bson.D{}
+		if filter.StartDate != nil {
+			period = append(period, bson.E{Key: "$gte", Value: *filter.StartDate})
+		}
+		if filter.EndDate != nil {
+			period = append(period, bson.E{Key: "$lte", Value: *filter.EndDate})
+		}
+		match = append(match, bson.E{Key: "check_in.checked_in_at", Value: period})
+	}
+
+	monthTrunc := bson.D{
+		{Key: "date", Value: "$check_in.checked_in_at"},
+		{Key: "unit", Value: "month"},
+	}
+	if filter.Timezone != "" {
+		monthTrunc = append(monthTrunc, bson.E{Key: "timezone", Value: filter.Timezone})
+	}
+
+	facets := bson.D{
+		{Key: "totals", Value: bson.A{
+			bson.D{{Key: "$group", Value: savingsGroup(nil)}},
+		}},
+		{Key: "by_month", Value: bson.A{
+			bson.D{{Key: "$group", Value: savingsGroup(bson.D{{Key: "$dateTrunc", Value: monthTrunc}})}},
+			bson.D{{Key: "$sort", Value: bson.D{{Key: "_id", Value: 1}}}},
+		}},
+		{Key: "by_category", Value: bson.A{
+			bson.D{{Key: "$group", Value: savingsGroup("$offer.category")}},
+			bson.D{{Key: "$sort", Value: bson.D{{Key: "savings_cents", Value: -1}, {Key: "_id", Value: 1}}}},
+		}},
+	}
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: match}},
+		{{Key: "$facet", Value: facets}},
+	}
+
+	cursor, err := r.collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, fmt.Errorf("failed to aggregate savings: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var results []struct {
+		Totals     []savingsGroupDoc `bson:"totals"`
+		ByMonth    []savingsGroupDoc `bson:"by_month"`
+		ByCategory []savingsGroupDoc `bson:"by_category"`
+	}
+	if err := cursor.All(ctx, &results); err != nil {
+		return nil, fmt.Errorf("failed to decode savings: %w", err)
+	}
+
+	summary := &domain.SavingsSummary{}
+	if len(results) == 0 {
+		return summary, nil
+	}
+
+	if len(results[0].Totals) > 0 {
+		summary.SavingsTotals = results[0].Totals[0].toTotals()
+	}
+	for _, bucket := range results[0].ByMonth {
+		monthStart, _ := bucket.ID.(primitive.DateTime)
+		summary.ByMonth = append(summary.ByMonth, domain.SavingsByMonth{
+			MonthStart:    monthStart.Time(),
+			SavingsTotals: bucket.toTotals(),
+		})
+	}
+	for _, bucket := range results[0].ByCategory {
+		category, _ := bucket.ID.(string)
+		summary.ByCategory = append(summary.ByCategory, domain.SavingsByCategory{
+			Category:      category,
+			SavingsTotals: bucket.toTotals(),
+		})
+	}
+
+	return summary, nil
+}
+
+func (r *OutingRepository) CountStaffCheckIns(ctx context.Context, staffUserID string, from, to time.Time) (int64, error) {
+	query := bson.D{
+		{Key: "check_in.checked_in_by", Value: staffUserID},
+		{Key: "check_in.checked_in_at", Value: bson.D{
+			{Key: "$gte", Value: from},
+			{Key: "$lte", Value: to},
+		}},
+	}
+
+	count, err := r.collection.CountDocuments(ctx, query)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count staff check-ins: %w", err)
+	}
+
+	return count, nil
+}
+
+func (r *OutingRepository) GetUserCheckIns(ctx context.Context, userID string, from, to time.Time) ([]*domain.Outing, error) {
+	query := bson.D{
+		{Key: "user_id", Value: userID},
+		{Key: "check_in.checked_in_at", Value: bson.D{
+			{Key: "$gte", Value: from},
+			{Key: "$lte", Value: to},
+		}},
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: "check_in.checked_in_at", Value: 1}})
+
+	cursor, err := r.collection.Find(ctx, query, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find user check-ins: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var outings []*domain.Outing
+	for cursor.Next(ctx) {
+		var doc OutingDocument
+		if err := cursor.Decode(&doc); err != nil {
+			continue
+		}
+		outings = append(outings, r.toDomain(&doc))
+	}
+
+	return outings, nil
+}
+
+func (r *OutingRepository) GetFlagged(ctx context.Context, scope domain.FlaggedOutingScope, filter domain.OutingFilter) (*domain.OutingPage, error) {
+	query := bson.D{{Key: "check_in.flagged_for_review", Value: true}}
+	if scope.Reason != "" {
+		query = append(query, bson.E{Key: "check_in.anomalies.reason", Value: string(scope.Reason)})
+	}
+	if scope.PartnerID != "" {
+		query = append(query, bson.E{Key: "offer.partner_id", Value: scope.PartnerID})
+	}
+	if scope.EstablishmentID != "" {
+		query = append(query, bson.E{Key: "offer.establishment_id", Value: scope.EstablishmentID})
+	}
+	return r.findPage(ctx, query, filter)
+}
+
 func (r *OutingRepository) GetExpiredOutings(ctx context.Context, before time.Time, limit int) ([]*domain.Outing, error) {
 	activeStatuses := []string{
 		string(domain.OutingStatusPending),
@@ -326,6 +848,88 @@ func (r *OutingRepository) GetExpiredOutings(ctx context.Context, before time.Ti
 	return outings, nil
 }
 
+func (r *OutingRepository) GetNoShowCandidates(ctx context.Context, before time.Time, limit int) ([]*domain.Outing, error) {
+	query := bson.D{
+		{Key: "status", Value: string(domain.OutingStatusExpired)},
+		{Key: "expires_at", Value: bson.D{{Key: "$lt", Value: before}}},
+	}
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: "expires_at", Value: 1}}).
+		SetLimit(int64(limit))
+
+	cursor, err := r.collection.Find(ctx, query, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find no-show candidates: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var outings []*domain.Outing
+	for cursor.Next(ctx) {
+		var doc OutingDocument
+		if err := cursor.Decode(&doc); err != nil {
+			continue
+		}
+		outings = append(outings, r.toDomain(&doc))
+	}
+
+	return outings, nil
+}
+
+func (r *OutingRepository) GetUnconfirmedOutings(ctx context.Context, before time.Time, limit int) ([]*domain.Outing, error) {
+	query := bson.D{
+		{Key: "status", Value: string(domain.OutingStatusPending)},
+		{Key: "confirm_by", Value: bson.D{{Key: "$lt", Value: before}}},
+	}
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: "confirm_by", Value: 1}}).
+		SetLimit(int64(limit))
+
+	cursor, err := r.collection.Find(ctx, query, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find unconfirmed outings: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var outings []*domain.Outing
+	for cursor.Next(ctx) {
+		var doc OutingDocument
+		if err := cursor.Decode(&doc); err != nil {
+			continue
+		}
+		outings = append(outings, r.toDomain(&doc))
+	}
+
+	return outings, nil
+}
+
+func (r *OutingRepository) GetPendingTransfersTo(ctx context.Context, userID string) ([]*domain.Outing, error) {
+	query := append(bson.D{
+		{Key: "transfer.to_user_id", Value: userID},
+		{Key: "expires_at", Value: bson.D{{Key: "$gt", Value: time.Now()}}},
+	}, activeStatusFilter()...)
+
+	opts := options.Find().SetSort(bson.D{{Key: "transfer.initiated_at", Value: -1}})
+
+	cursor, err := r.collection.Find(ctx, query, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find pending transfers: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var outings []*domain.Outing
+	for cursor.Next(ctx) {
+		var doc OutingDocument
+		if err := cursor.Decode(&doc); err != nil {
+			continue
+		}
+		outings = append(outings, r.toDomain(&doc))
+	}
+
+	return outings, nil
+}
+
 func (r *OutingRepository) Delete(ctx context.Context, id string) error {
 	oid, err := primitive.ObjectIDFromHex(id)
 	if err != nil {
@@ -344,7 +948,16 @@ func (r *OutingRepository) Delete(ctx context.Context, id string) error {
 // HELPER METHODS
 // =============================================================================
 
-func (r *OutingRepository) findWithFilter(ctx context.Context, baseQuery bson.D, filter domain.OutingFilter) ([]*domain.Outing, int64, error) {
+// withOutbox runs write in a transaction together with the insertion of the
+// outing's pending domain events into the outbox.
+func (r *OutingRepository) withOutbox(ctx context.Context, outing *domain.Outing, write func(mongo.SessionContext) error) error {
+	return r.outbox.SaveWithEvents(ctx, outing, write)
+}
+
+// findPage returns one page of the outings matching baseQuery and the filter,
+// using keyset pagination on (sort field, _id) so pages stay stable while
+// outings are added.
+func (r *OutingRepository) findPage(ctx context.Context, baseQuery bson.D, filter domain.OutingFilter) (*domain.OutingPage, error) {
 	query := baseQuery
 
 	// Add status filter
@@ -357,39 +970,74 @@ func (r *OutingRepository) findWithFilter(ctx context.Context, baseQuery bson.D,
 	}
 
 	// Add date filters
-	if filter.StartDate != nil {
-		query = append(query, bson.E{Key: "created_at", Value: bson.D{{Key: "$gte", Value: *filter.StartDate}}})
-	}
-	if filter.EndDate != nil {
-		query = append(query, bson.E{Key: "created_at", Value: bson.D{{Key: "$lte", Value: *filter.EndDate}}})
+	if filter.StartDate != nil || filter.EndDate != nil {
+		period := bson.D{}
+		if filter.StartDate != nil {
+			period = append(period, bson.E{Key: "$gte", Value: *filter.StartDate})
+		}
+		if filter.EndDate != nil {
+			period = append(period, bson.E{Key: "$lte", Value: *filter.EndDate})
+		}
+		query = append(query, bson.E{Key: "created_at", Value: period})
 	}
 
 	// Count total
 	total, err := r.collection.CountDocuments(ctx, query)
 	if err != nil {
-		return nil, 0, fmt.Errorf("failed to count outings: %w", err)
+		return nil, fmt.Errorf("failed to count outings: %w", err)
 	}
 
-	// Build options
-	opts := options.Find().
-		SetSkip(int64(filter.Offset)).
-		SetLimit(int64(filter.Limit))
-
 	// Sort
+	sortBy := filter.SortBy
+	if sortBy == "" {
+		sortBy = domain.OutingSortCreatedAt
+	}
+	if !sortBy.IsValid() {
+		return nil, fmt.Errorf("invalid sort field %q", sortBy)
+	}
 	sortOrder := -1
 	if filter.SortOrder == "asc" {
 		sortOrder = 1
 	}
-	sortField := filter.SortBy
-	if sortField == "" {
-		sortField = "created_at"
+
+	// Bound the page by its cursors
+	var bounds bson.A
+	for _, bound := range []struct {
+		cursor *domain.OutingCursor
+		after  bool
+	}{{filter.After, true}, {filter.Before, false}} {
+		if bound.cursor == nil {
+			continue
+		}
+		condition, err := cursorCondition(sortBy, sortOrder, bound.cursor, bound.after)
+		if err != nil {
+			return nil, err
+		}
+		bounds = append(bounds, condition)
+	}
+	pageQuery := query
+	if len(bounds) > 0 {
+		pageQuery = append(append(bson.D{}, query...), bson.E{Key: "$and", Value: bounds})
+	}
+
+	// Fetch one extra outing to know whether more follow. Backward pages are
+	// read in reverse order from Before, then put back in order.
+	limit := filter.Limit
+	if limit <= 0 {
+		limit = domain.DefaultOutingFilter().Limit
+	}
+	readOrder := sortOrder
+	if filter.Backward {
+		readOrder = -sortOrder
 	}
-	opts.SetSort(bson.D{{Key: sortField, Value: sortOrder}})
+	opts := options.Find().
+		SetSort(bson.D{{Key: string(sortBy), Value: readOrder}, {Key: "_id", Value: readOrder}}).
+		SetLimit(int64(limit + 1))
 
 	// Execute query
-	cursor, err := r.collection.Find(ctx, query, opts)
+	cursor, err := r.collection.Find(ctx, pageQuery, opts)
 	if err != nil {
-		return nil, 0, fmt.Errorf("failed to find outings: %w", err)
+		return nil, fmt.Errorf("failed to find outings: %w", err)
 	}
 	defer cursor.Close(ctx)
 
@@ -402,7 +1050,178 @@ func (r *OutingRepository) findWithFilter(ctx context.Context, baseQuery bson.D,
 		outings = append(outings, r.toDomain(&doc))
 	}
 
-	return outings, total, nil
+	hasMore := len(outings) > limit
+	if hasMore {
+		outings = outings[:limit]
+	}
+
+	page := &domain.OutingPage{TotalCount: total}
+	if filter.Backward {
+		for i, j := 0, len(outings)-1; i < j; i, j = i+1, j-1 {
+			outings[i], outings[j] = outings[j], outings[i]
+		}
+		page.HasPreviousPage = hasMore
+		page.HasNextPage, err = r.existsBeyond(ctx, query, sortBy, sortOrder, filter.Before, true)
+	} else {
+		page.HasNextPage = hasMore
+		page.HasPreviousPage, err = r.existsBeyond(ctx, query, sortBy, sortOrder, filter.After, false)
+	}
+	if err != nil {
+		return nil, err
+	}
+	page.Outings = outings
+
+	return page, nil
+}
+
+// existsBeyond reports whether an outing matching query lies strictly after
+// (or before) the cursor; false without a cursor
+func (r *OutingRepository) existsBeyond(ctx context.Context, query bson.D, sortBy domain.OutingSortField, sortOrder int, cursor *domain.OutingCursor, after bool) (bool, error) {
+	if cursor == nil {
+		return false, nil
+	}
+
+	condition, err := cursorCondition(sortBy, sortOrder, cursor, after)
+	if err != nil {
+		return false, err
+	}
+	beyond := append(append(bson.D{}, query...), bson.E{Key: "$and", Value: bson.A{condition}})
+
+	count, err := r.collection.CountDocuments(ctx, beyond, options.Count().SetLimit(1))
+	if err != nil {
+		return false, fmt.Errorf("failed to check adjacent outings: %w", err)
+	}
+
+	return count > 0, nil
+}
+
+// cursorCondition matches outings strictly after (or before) the cursor in
+// the given sort order, breaking ties on _id
+func cursorCondition(sortBy domain.OutingSortField, sortOrder int, cursor *domain.OutingCursor, after bool) (bson.D, error) {
+	if cursor.SortBy != sortBy {
+		return nil, domain.ErrInvalidCursor
+	}
+	oid, err := primitive.ObjectIDFromHex(cursor.ID)
+	if err != nil {
+		return nil, domain.ErrInvalidCursor
+	}
+
+	op := "$gt"
+	if (sortOrder < 0) == after {
+		op = "$lt"
+	}
+
+	return bson.D{{Key: "$or", Value: bson.A{
+		bson.D{{Key: string(sortBy), Value: bson.D{{Key: op, Value: cursor.SortValue}}}},
+		bson.D{
+			{Key: string(sortBy), Value: cursor.SortValue},
+			{Key: "_id", Value: bson.D{{Key: op, Value: oid}}},
+		},
+	}}}, nil
+}
+
+// quotaConsumingStatuses lists the statuses of outings that hold offer capacity
+func quotaConsumingStatuses() []string {
+	return []string{
+		string(domain.OutingStatusPending),
+		string(domain.OutingStatusConfirmed),
+		string(domain.OutingStatusCheckedIn),
+	}
+}
+
+// activeStatusFilter matches pending and confirmed outings, the ones that can
+// still be checked in
+func activeStatusFilter() bson.D {
+	return bson.D{{Key: "status", Value: bson.D{{Key: "$in", Value: bson.A{
+		string(domain.OutingStatusPending),
+		string(domain.OutingStatusConfirmed),
+	}}}}}
+}
+
+// statsGroupDoc is the output of the $group stage built by statsGroup
+type statsGroupDoc struct {
+	ID                 interface{} `bson:"_id"`
+	TotalBookings      int64       `bson:"total_bookings"`
+	TotalCheckIns      int64       `bson:"total_check_ins"`
+	TotalCancelled     int64       `bson:"total_cancelled"`
+	TotalExpired       int64       `bson:"total_expired"`
+	TotalNoShow        int64       `bson:"total_no_show"`
+	AverageCheckInTime *float64    `bson:"avg_check_in_ms"`
+	TotalBilled        int64       `bson:"total_billed"`
+	TotalBillCents     int64       `bson:"total_bill_cents"`
+	TotalSavingsCents  int64       `bson:"total_savings_cents"`
+}
+
+func (d statsGroupDoc) toCounts() domain.StatsCounts {
+	counts := domain.StatsCounts{
+		TotalBookings:  d.TotalBookings,
+		TotalCheckIns:  d.TotalCheckIns,
+		TotalCancelled: d.TotalCancelled,
+		TotalExpired:   d.TotalExpired,
+		TotalNoShow:    d.TotalNoShow,
+
+		TotalBilled:       d.TotalBilled,
+		TotalBillCents:    d.TotalBillCents,
+		TotalSavingsCents: d.TotalSavingsCents,
+	}
+	if d.AverageCheckInTime != nil {
+		counts.AverageCheckInTime = *d.AverageCheckInTime / float64(time.Minute/time.Millisecond)
+	}
+	return counts
+}
+
+// statsGroup builds a $group stage counting outings per status under the given key
+func statsGroup(id interface{}) bson.D {
+	countStatus := func(status domain.OutingStatus) bson.D {
+		return bson.D{{Key: "$sum", Value: bson.D{{Key: "$cond", Value: bson.A{
+			bson.D{{Key: "$eq", Value: bson.A{"$status", string(status)}}}, 1, 0,
+		}}}}}
+	}
+
+	return bson.D{
+		{Key: "_id", Value: id},
+		{Key: "total_bookings", Value: bson.D{{Key: "$sum", Value: 1}}},
+		{Key: "total_check_ins", Value: countStatus(domain.OutingStatusCheckedIn)},
+		{Key: "total_cancelled", Value: countStatus(domain.OutingStatusCancelled)},
+		{Key: "total_expired", Value: countStatus(domain.OutingStatusExpired)},
+		{Key: "total_no_show", Value: countStatus(domain.OutingStatusNoShow)},
+		// $avg ignores the nulls produced for outings without a check-in
+		{Key: "avg_check_in_ms", Value: bson.D{{Key: "$avg", Value: bson.D{{Key: "$subtract", Value: bson.A{
+			"$check_in.checked_in_at", "$booked_at",
+		}}}}}},
+		// $sum ignores the missing amounts of outings without a bill
+		{Key: "total_billed", Value: bson.D{{Key: "$sum", Value: bson.D{{Key: "$cond", Value: bson.A{
+			bson.D{{Key: "$gt", Value: bson.A{"$check_in.bill", nil}}}, 1, 0,
+		}}}}}},
+		{Key: "total_bill_cents", Value: bson.D{{Key: "$sum", Value: "$check_in.bill.amount_cents"}}},
+		{Key: "total_savings_cents", Value: bson.D{{Key: "$sum", Value: "$check_in.bill.savings_cents"}}},
+	}
+}
+
+// savingsGroupDoc is the output of the $group stage built by savingsGroup
+type savingsGroupDoc struct {
+	ID           interface{} `bson:"_id"`
+	Outings      int64       `bson:"outings"`
+	BillCents    int64       `bson:"bill_cents"`
+	SavingsCents int64       `bson:"savings_cents"`
+}
+
+func (d savingsGroupDoc) toTotals() domain.SavingsTotals {
+	return domain.SavingsTotals{
+		Outings:      d.Outings,
+		BillCents:    d.BillCents,
+		SavingsCents: d.SavingsCents,
+	}
+}
+
+// savingsGroup builds a $group stage summing bills and savings under the given key
+func savingsGroup(id interface{}) bson.D {
+	return bson.D{
+		{Key: "_id", Value: id},
+		{Key: "outings", Value: bson.D{{Key: "$sum", Value: 1}}},
+		{Key: "bill_cents", Value: bson.D{{Key: "$sum", Value: "$check_in.bill.amount_cents"}}},
+		{Key: "savings_cents", Value: bson.D{{Key: "$sum", Value: "$check_in.bill.savings_cents"}}},
+	}
 }
 
 // =============================================================================
@@ -436,11 +1255,12 @@ func (r *OutingRepository) toDocument(outing *domain.Outing) *OutingDocument {
 		},
 		QRCode: QRCodeDoc{
 			Code:      outing.QRCode().Code(),
-			Signature: outing.QRCode().Signature(),
 			CreatedAt: outing.QRCode().CreatedAt(),
 			ExpiresAt: outing.QRCode().ExpiresAt(),
 		},
+		ShortCode: outing.ShortCode(),
 		Status:    string(outing.Status()),
+		ConfirmBy: outing.ConfirmBy(),
 		BookedAt:  outing.BookedAt(),
 		ExpiresAt: outing.ExpiresAt(),
 		CreatedAt: outing.CreatedAt(),
@@ -462,6 +1282,9 @@ func (r *OutingRepository) toDocument(outing *domain.Outing) *OutingDocument {
 		})
 	}
 
+	// Map slot
+	doc.Slot = toSlotDoc(outing.Slot())
+
 	// Map check-in
 	if outing.CheckIn() != nil {
 		doc.CheckIn = &CheckInInfoDoc{
@@ -470,6 +1293,25 @@ func (r *OutingRepository) toDocument(outing *domain.Outing) *OutingDocument {
 			Method:      string(outing.CheckIn().Method()),
 			Latitude:    outing.CheckIn().Latitude(),
 			Longitude:   outing.CheckIn().Longitude(),
+
+			DistanceMeters:   outing.CheckIn().DistanceMeters(),
+			FlaggedForReview: outing.CheckIn().FlaggedForReview(),
+			ReviewReason:     outing.CheckIn().ReviewReason(),
+		}
+		for _, anomaly := range outing.CheckIn().Anomalies() {
+			doc.CheckIn.Anomalies = append(doc.CheckIn.Anomalies, CheckInAnomalyDoc{
+				Reason:     string(anomaly.Reason()),
+				Detail:     anomaly.Detail(),
+				DetectedAt: anomaly.DetectedAt(),
+			})
+		}
+		if bill := outing.CheckIn().Bill(); bill != nil {
+			doc.CheckIn.Bill = &BillDoc{
+				AmountCents:  bill.AmountCents(),
+				SavingsCents: bill.SavingsCents(),
+				RecordedAt:   bill.RecordedAt(),
+				RecordedBy:   bill.RecordedBy(),
+			}
 		}
 	}
 
@@ -479,6 +1321,16 @@ func (r *OutingRepository) toDocument(outing *domain.Outing) *OutingDocument {
 			CancelledAt: outing.Cancellation().CancelledAt(),
 			CancelledBy: string(outing.Cancellation().CancelledBy()),
 			Reason:      outing.Cancellation().Reason(),
+			Late:        outing.Cancellation().IsLate(),
+		}
+	}
+
+	// Map pending transfer
+	if transfer := outing.Transfer(); transfer != nil {
+		doc.Transfer = &OutingTransferDoc{
+			FromUserID:  transfer.FromUserID(),
+			ToUserID:    transfer.ToUserID(),
+			InitiatedAt: transfer.InitiatedAt(),
 		}
 	}
 
@@ -512,10 +1364,12 @@ func (r *OutingRepository) toDomain(doc *OutingDocument) *domain.Outing {
 		doc.User.Email,
 	)
 
+	// Reconstruct slot
+	slot := toSlotSnapshot(doc.Slot)
+
 	// Reconstruct QR code
 	qrCode := domain.ReconstructQRCode(
 		doc.QRCode.Code,
-		doc.QRCode.Signature,
 		doc.QRCode.CreatedAt,
 		doc.QRCode.ExpiresAt,
 	)
@@ -534,12 +1388,35 @@ func (r *OutingRepository) toDomain(doc *OutingDocument) *domain.Outing {
 	// Reconstruct check-in
 	var checkIn *domain.CheckInInfo
 	if doc.CheckIn != nil {
+		var bill *domain.Bill
+		if doc.CheckIn.Bill != nil {
+			b := domain.ReconstructBill(
+				doc.CheckIn.Bill.AmountCents,
+				doc.CheckIn.Bill.SavingsCents,
+				doc.CheckIn.Bill.RecordedAt,
+				doc.CheckIn.Bill.RecordedBy,
+			)
+			bill = &b
+		}
+		anomalies := make([]domain.CheckInAnomaly, 0, len(doc.CheckIn.Anomalies))
+		for _, a := range doc.CheckIn.Anomalies {
+			anomalies = append(anomalies, domain.ReconstructCheckInAnomaly(domain.AnomalyReason(a.Reason), a.Detail, a.DetectedAt))
+		}
+		// Check-ins flagged by the geofence before anomalies were recorded
+		if len(anomalies) == 0 && doc.CheckIn.FlaggedForReview && doc.CheckIn.ReviewReason != "" {
+			anomalies = append(anomalies, domain.ReconstructCheckInAnomaly(domain.AnomalyReason(doc.CheckIn.ReviewReason), "", doc.CheckIn.CheckedInAt))
+		}
 		ci := domain.ReconstructCheckInInfo(
 			doc.CheckIn.CheckedInAt,
 			doc.CheckIn.CheckedInBy,
 			domain.CheckInMethod(doc.CheckIn.Method),
 			doc.CheckIn.Latitude,
 			doc.CheckIn.Longitude,
+			doc.CheckIn.DistanceMeters,
+			doc.CheckIn.FlaggedForReview,
+			doc.CheckIn.ReviewReason,
+			anomalies,
+			bill,
 		)
 		checkIn = &ci
 	}
@@ -551,23 +1428,68 @@ func (r *OutingRepository) toDomain(doc *OutingDocument) *domain.Outing {
 			doc.Cancellation.CancelledAt,
 			domain.CancellationActor(doc.Cancellation.CancelledBy),
 			doc.Cancellation.Reason,
+			doc.Cancellation.Late,
 		)
 		cancellation = &c
 	}
 
+	// Reconstruct pending transfer
+	var transfer *domain.OutingTransfer
+	if doc.Transfer != nil {
+		t := domain.ReconstructOutingTransfer(
+			doc.Transfer.FromUserID,
+			doc.Transfer.ToUserID,
+			doc.Transfer.InitiatedAt,
+		)
+		transfer = &t
+	}
+
 	return domain.ReconstructOuting(
 		doc.ID.Hex(),
 		doc.UserID,
 		offer,
 		user,
+		slot,
 		qrCode,
+		doc.ShortCode,
 		domain.OutingStatus(doc.Status),
 		timeline,
 		checkIn,
 		cancellation,
+		doc.ConfirmBy,
+		transfer,
 		doc.BookedAt,
 		doc.ExpiresAt,
 		doc.CreatedAt,
 		doc.UpdatedAt,
 	)
 }
+
+func toSlotDoc(slot *domain.SlotSnapshot) *SlotSnapshotDoc {
+	if slot == nil {
+		return nil
+	}
+	return &SlotSnapshotDoc{
+		Date:      slot.Date(),
+		StartTime: slot.StartTime(),
+		EndTime:   slot.EndTime(),
+		StartsAt:  slot.StartsAt(),
+		EndsAt:    slot.EndsAt(),
+		Capacity:  slot.Capacity(),
+	}
+}
+
+func toSlotSnapshot(doc *SlotSnapshotDoc) *domain.SlotSnapshot {
+	if doc == nil {
+		return nil
+	}
+	slot := domain.ReconstructSlotSnapshot(
+		doc.Date,
+		doc.StartTime,
+		doc.EndTime,
+		doc.StartsAt,
+		doc.EndsAt,
+		doc.Capacity,
+	)
+	return &slot
+}
@@ -0,0 +1,119 @@
+package mongodb
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/yousoon/apps/services/booking-service/internal/domain"
+	"github.com/yousoon/shared/infrastructure/outbox"
+)
+
+// =============================================================================
+// MONGODB DOCUMENT
+// =============================================================================
+
+// UserStandingDocument is keyed by user ID, one per user
+type UserStandingDocument struct {
+	UserID      string      `bson:"_id"`
+	Strikes     []StrikeDoc `bson:"strikes"`
+	BannedUntil *time.Time  `bson:"banned_until,omitempty"`
+	UpdatedAt   time.Time   `bson:"updated_at"`
+	Version     int64       `bson:"version"`
+}
+
+type StrikeDoc struct {
+	Kind       string    `bson:"kind"`
+	OutingID   string    `bson:"outing_id"`
+	RecordedAt time.Time `bson:"recorded_at"`
+}
+
+// =============================================================================
+// REPOSITORY IMPLEMENTATION
+// =============================================================================
+
+type UserStandingRepository struct {
+	collection *mongo.Collection
+	outbox     *outbox.Store
+}
+
+func NewUserStandingRepository(db *mongo.Database, outbox *outbox.Store) *UserStandingRepository {
+	return &UserStandingRepository{
+		collection: db.Collection("user_standings"),
+		outbox:     outbox,
+	}
+}
+
+func (r *UserStandingRepository) Get(ctx context.Context, userID string) (*domain.UserStanding, error) {
+	var doc UserStandingDocument
+	err := r.collection.FindOne(ctx, bson.D{{Key: "_id", Value: userID}}).Decode(&doc)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return domain.NewUserStanding(userID), nil
+		}
+		return nil, fmt.Errorf("failed to get user standing: %w", err)
+	}
+
+	return r.toDomain(&doc), nil
+}
+
+// Save upserts the standing and its pending domain events atomically, as long
+// as the stored standing is still at the version it was loaded at. When it is
+// not, the upsert collides with the stored _id and ErrUserStandingChanged is
+// returned.
+func (r *UserStandingRepository) Save(ctx context.Context, standing *domain.UserStanding) error {
+	doc := r.toDocument(standing)
+	doc.Version = standing.Version() + 1
+
+	filter := bson.D{{Key: "_id", Value: doc.UserID}, {Key: "version", Value: standing.Version()}}
+	if standing.Version() == 0 {
+		// Never saved, or saved before standings were versioned
+		filter = bson.D{{Key: "_id", Value: doc.UserID}, {Key: "version", Value: bson.D{{Key: "$exists", Value: false}}}}
+	}
+	opts := options.Replace().SetUpsert(true)
+
+	return r.outbox.SaveWithEvents(ctx, standing, func(sessCtx mongo.SessionContext) error {
+		if _, err := r.collection.ReplaceOne(sessCtx, filter, doc, opts); err != nil {
+			if mongo.IsDuplicateKeyError(err) {
+				return domain.ErrUserStandingChanged
+			}
+			return fmt.Errorf("failed to save user standing: %w", err)
+		}
+		return nil
+	})
+}
+
+// =============================================================================
+// HELPER METHODS
+// =============================================================================
+
+func (r *UserStandingRepository) toDocument(standing *domain.UserStanding) *UserStandingDocument {
+	doc := &UserStandingDocument{
+		UserID:      standing.UserID(),
+		Strikes:     make([]StrikeDoc, 0, len(standing.Strikes())),
+		BannedUntil: standing.BannedUntil(),
+		UpdatedAt:   standing.UpdatedAt(),
+	}
+	for _, strike := range standing.Strikes() {
+		doc.Strikes = append(doc.Strikes, StrikeDoc{
+			Kind:       string(strike.Kind()),
+			OutingID:   strike.OutingID(),
+			RecordedAt: strike.RecordedAt(),
+		})
+	}
+
+	return doc
+}
+
+func (r *UserStandingRepository) toDomain(doc *UserStandingDocument) *domain.UserStanding {
+	strikes := make([]domain.Strike, 0, len(doc.Strikes))
+	for _, strike := range doc.Strikes {
+		strikes = append(strikes, domain.ReconstructStrike(domain.StrikeKind(strike.Kind), strike.OutingID, strike.RecordedAt))
+	}
+
+	return domain.ReconstructUserStanding(doc.UserID, strikes, doc.BannedUntil, doc.UpdatedAt, doc.Version)
+}
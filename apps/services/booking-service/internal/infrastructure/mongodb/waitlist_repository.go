@@ -0,0 +1,300 @@
+package mongodb
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/yousoon/apps/services/booking-service/internal/domain"
+	"github.com/yousoon/shared/infrastructure/outbox"
+)
+
+// =============================================================================
+// MONGODB DOCUMENT
+// =============================================================================
+
+type WaitlistEntryDocument struct {
+	ID      primitive.ObjectID `bson:"_id,omitempty"`
+	UserID  string             `bson:"user_id"`
+	OfferID string             `bson:"offer_id"`
+
+	// Slot waited for (optional); SlotStartsAt is duplicated at the top level
+	// so "the offer itself" (null) and slot instances share one index
+	Slot         *SlotSnapshotDoc `bson:"slot,omitempty"`
+	SlotStartsAt *time.Time       `bson:"slot_starts_at"`
+
+	// Status
+	Status        string     `bson:"status"`
+	HeldAt        *time.Time `bson:"held_at,omitempty"`
+	HoldExpiresAt *time.Time `bson:"hold_expires_at,omitempty"`
+	OutingID      string     `bson:"outing_id,omitempty"`
+	CloseReason   string     `bson:"close_reason,omitempty"`
+
+	// Metadata
+	JoinedAt  time.Time `bson:"joined_at"`
+	UpdatedAt time.Time `bson:"updated_at"`
+}
+
+// =============================================================================
+// REPOSITORY IMPLEMENTATION
+// =============================================================================
+
+const activeEntryIndexName = "user_active_entry"
+
+type WaitlistRepository struct {
+	collection *mongo.Collection
+	outbox     *outbox.Store
+}
+
+func NewWaitlistRepository(db *mongo.Database, outbox *outbox.Store) *WaitlistRepository {
+	collection := db.Collection("waitlist")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	indexes := []mongo.IndexModel{
+		{
+			Keys: bson.D{
+				{Key: "offer_id", Value: 1},
+				{Key: "slot_starts_at", Value: 1},
+				{Key: "status", Value: 1},
+				{Key: "joined_at", Value: 1},
+			},
+			Options: options.Index().SetName("offer_waitlist_line"),
+		},
+		{
+			Keys:    bson.D{{Key: "user_id", Value: 1}, {Key: "status", Value: 1}},
+			Options: options.Index().SetName("user_waitlist"),
+		},
+		{
+			Keys: bson.D{{Key: "hold_expires_at", Value: 1}},
+			Options: options.Index().
+				SetName("held_entries").
+				SetPartialFilterExpression(bson.D{{Key: "status", Value: string(domain.WaitlistStatusHeld)}}),
+		},
+		{
+			// One place in line per user, even when two joins race
+			Keys: bson.D{
+				{Key: "user_id", Value: 1},
+				{Key: "offer_id", Value: 1},
+				{Key: "slot_starts_at", Value: 1},
+			},
+			Options: options.Index().
+				SetName(activeEntryIndexName).
+				SetUnique(true).
+				SetPartialFilterExpression(bson.D{
+					{Key: "status", Value: bson.D{{Key: "$in", Value: activeWaitlistStatuses()}}},
+				}),
+		},
+	}
+
+	_, _ = collection.Indexes().CreateMany(ctx, indexes)
+
+	return &WaitlistRepository{collection: collection, outbox: outbox}
+}
+
+// Create inserts the entry and its pending domain events atomically.
+func (r *WaitlistRepository) Create(ctx context.Context, entry *domain.WaitlistEntry) error {
+	doc := r.toDocument(entry)
+
+	return r.outbox.SaveWithEvents(ctx, entry, func(sessCtx mongo.SessionContext) error {
+		if _, err := r.collection.InsertOne(sessCtx, doc); err != nil {
+			if mongo.IsDuplicateKeyError(err) && strings.Contains(err.Error(), activeEntryIndexName) {
+				return domain.ErrAlreadyOnWaitlist
+			}
+			return fmt.Errorf("failed to insert waitlist entry: %w", err)
+		}
+		return nil
+	})
+}
+
+// Update saves the entry and its pending domain events atomically, provided
+// the stored entry is still in status from. Two holders of the same entry,
+// such as a user accepting a hold while the expiry job expires it, cannot
+// both win.
+func (r *WaitlistRepository) Update(ctx context.Context, entry *domain.WaitlistEntry, from domain.WaitlistStatus) error {
+	doc := r.toDocument(entry)
+
+	filter := bson.D{{Key: "_id", Value: doc.ID}, {Key: "status", Value: string(from)}}
+	update := bson.D{{Key: "$set", Value: doc}}
+
+	return r.outbox.SaveWithEvents(ctx, entry, func(sessCtx mongo.SessionContext) error {
+		result, err := r.collection.UpdateOne(sessCtx, filter, update)
+		if err != nil {
+			if mongo.IsDuplicateKeyError(err) && strings.Contains(err.Error(), activeEntryIndexName) {
+				return domain.ErrAlreadyOnWaitlist
+			}
+			return fmt.Errorf("failed to update waitlist entry: %w", err)
+		}
+		if result.MatchedCount == 0 {
+			return domain.ErrWaitlistEntryConflict
+		}
+		return nil
+	})
+}
+
+func (r *WaitlistRepository) GetByID(ctx context.Context, id string) (*domain.WaitlistEntry, error) {
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, domain.ErrWaitlistEntryNotFound
+	}
+
+	return r.findOne(ctx, bson.D{{Key: "_id", Value: oid}}, nil)
+}
+
+func (r *WaitlistRepository) GetActiveByUserAndOffer(ctx context.Context, userID, offerID string, slotStartsAt *time.Time) (*domain.WaitlistEntry, error) {
+	query := bson.D{
+		{Key: "user_id", Value: userID},
+		{Key: "offer_id", Value: offerID},
+		{Key: "slot_starts_at", Value: slotStartsAt},
+		{Key: "status", Value: bson.D{{Key: "$in", Value: activeWaitlistStatuses()}}},
+	}
+
+	return r.findOne(ctx, query, nil)
+}
+
+func (r *WaitlistRepository) GetActiveByUserID(ctx context.Context, userID string) ([]*domain.WaitlistEntry, error) {
+	query := bson.D{
+		{Key: "user_id", Value: userID},
+		{Key: "status", Value: bson.D{{Key: "$in", Value: activeWaitlistStatuses()}}},
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "joined_at", Value: 1}})
+
+	return r.find(ctx, query, opts)
+}
+
+func (r *WaitlistRepository) GetNextWaiting(ctx context.Context, offerID string, slotStartsAt *time.Time) (*domain.WaitlistEntry, error) {
+	query := bson.D{
+		{Key: "offer_id", Value: offerID},
+		{Key: "slot_starts_at", Value: slotStartsAt},
+		{Key: "status", Value: string(domain.WaitlistStatusWaiting)},
+	}
+	opts := options.FindOne().SetSort(bson.D{{Key: "joined_at", Value: 1}, {Key: "_id", Value: 1}})
+
+	return r.findOne(ctx, query, opts)
+}
+
+func (r *WaitlistRepository) CountWaitingAhead(ctx context.Context, entry *domain.WaitlistEntry) (int64, error) {
+	var slotStartsAt *time.Time
+	if entry.Slot() != nil {
+		startsAt := entry.Slot().StartsAt()
+		slotStartsAt = &startsAt
+	}
+
+	query := bson.D{
+		{Key: "offer_id", Value: entry.OfferID()},
+		{Key: "slot_starts_at", Value: slotStartsAt},
+		{Key: "status", Value: string(domain.WaitlistStatusWaiting)},
+		{Key: "joined_at", Value: bson.D{{Key: "$lt", Value: entry.JoinedAt()}}},
+	}
+
+	count, err := r.collection.CountDocuments(ctx, query)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count waitlist position: %w", err)
+	}
+
+	return count, nil
+}
+
+func (r *WaitlistRepository) GetExpiredHolds(ctx context.Context, before time.Time, limit int) ([]*domain.WaitlistEntry, error) {
+	query := bson.D{
+		{Key: "status", Value: string(domain.WaitlistStatusHeld)},
+		{Key: "hold_expires_at", Value: bson.D{{Key: "$lt", Value: before}}},
+	}
+	opts := options.Find().
+		SetSort(bson.D{{Key: "hold_expires_at", Value: 1}}).
+		SetLimit(int64(limit))
+
+	return r.find(ctx, query, opts)
+}
+
+// =============================================================================
+// HELPER METHODS
+// =============================================================================
+
+func activeWaitlistStatuses() []string {
+	return []string{
+		string(domain.WaitlistStatusWaiting),
+		string(domain.WaitlistStatusHeld),
+	}
+}
+
+func (r *WaitlistRepository) findOne(ctx context.Context, query bson.D, opts *options.FindOneOptions) (*domain.WaitlistEntry, error) {
+	var doc WaitlistEntryDocument
+	err := r.collection.FindOne(ctx, query, opts).Decode(&doc)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, domain.ErrWaitlistEntryNotFound
+		}
+		return nil, fmt.Errorf("failed to get waitlist entry: %w", err)
+	}
+
+	return r.toDomain(&doc), nil
+}
+
+func (r *WaitlistRepository) find(ctx context.Context, query bson.D, opts *options.FindOptions) ([]*domain.WaitlistEntry, error) {
+	cursor, err := r.collection.Find(ctx, query, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find waitlist entries: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var entries []*domain.WaitlistEntry
+	for cursor.Next(ctx) {
+		var doc WaitlistEntryDocument
+		if err := cursor.Decode(&doc); err != nil {
+			continue
+		}
+		entries = append(entries, r.toDomain(&doc))
+	}
+
+	return entries, nil
+}
+
+func (r *WaitlistRepository) toDocument(entry *domain.WaitlistEntry) *WaitlistEntryDocument {
+	doc := &WaitlistEntryDocument{
+		UserID:        entry.UserID(),
+		OfferID:       entry.OfferID(),
+		Slot:          toSlotDoc(entry.Slot()),
+		Status:        string(entry.Status()),
+		HeldAt:        entry.HeldAt(),
+		HoldExpiresAt: entry.HoldExpiresAt(),
+		OutingID:      entry.OutingID(),
+		CloseReason:   entry.CloseReason(),
+		JoinedAt:      entry.JoinedAt(),
+		UpdatedAt:     entry.UpdatedAt(),
+	}
+	if entry.Slot() != nil {
+		startsAt := entry.Slot().StartsAt()
+		doc.SlotStartsAt = &startsAt
+	}
+
+	// Parse ID if it's a valid ObjectID
+	if oid, err := primitive.ObjectIDFromHex(entry.ID()); err == nil {
+		doc.ID = oid
+	}
+
+	return doc
+}
+
+func (r *WaitlistRepository) toDomain(doc *WaitlistEntryDocument) *domain.WaitlistEntry {
+	return domain.ReconstructWaitlistEntry(
+		doc.ID.Hex(),
+		doc.UserID,
+		doc.OfferID,
+		toSlotSnapshot(doc.Slot),
+		domain.WaitlistStatus(doc.Status),
+		doc.HeldAt,
+		doc.HoldExpiresAt,
+		doc.OutingID,
+		doc.CloseReason,
+		doc.JoinedAt,
+		doc.UpdatedAt,
+	)
+}
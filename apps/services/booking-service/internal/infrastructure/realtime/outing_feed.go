@@ -0,0 +1,130 @@
+package realtime
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/nats-io/nats.go"
+
+	"github.com/yousoon/apps/services/booking-service/internal/domain"
+)
+
+const (
+	// outingEventsSubject matches the outing events relayed from the outbox
+	outingEventsSubject = "yousoon.events.outing.>"
+
+	// subscriberBuffer is how many updates a slow subscriber may lag behind
+	// before further updates are dropped for it
+	subscriberBuffer = 32
+)
+
+// =============================================================================
+// OUTING FEED
+// =============================================================================
+
+// OutingFeed fans outing domain events out to live subscribers of this
+// instance. It listens on a plain NATS subscription rather than a durable
+// consumer so that every instance sees every event; subscribers only get
+// events published while they are connected.
+type OutingFeed struct {
+	outingRepo domain.OutingRepository
+
+	mu          sync.RWMutex
+	subscribers map[string]map[chan domain.OutingUpdate]struct{}
+}
+
+func NewOutingFeed(outingRepo domain.OutingRepository) *OutingFeed {
+	return &OutingFeed{
+		outingRepo:  outingRepo,
+		subscribers: make(map[string]map[chan domain.OutingUpdate]struct{}),
+	}
+}
+
+func (f *OutingFeed) SubscribeEstablishment(ctx context.Context, establishmentID string) (<-chan domain.OutingUpdate, error) {
+	ch := make(chan domain.OutingUpdate, subscriberBuffer)
+
+	f.mu.Lock()
+	if f.subscribers[establishmentID] == nil {
+		f.subscribers[establishmentID] = make(map[chan domain.OutingUpdate]struct{})
+	}
+	f.subscribers[establishmentID][ch] = struct{}{}
+	f.mu.Unlock()
+
+	go func() {
+		<-ctx.Done()
+
+		f.mu.Lock()
+		delete(f.subscribers[establishmentID], ch)
+		if len(f.subscribers[establishmentID]) == 0 {
+			delete(f.subscribers, establishmentID)
+		}
+		f.mu.Unlock()
+
+		close(ch)
+	}()
+
+	return ch, nil
+}
+
+// Run feeds subscribers from the outing events on NATS until ctx is done
+func (f *OutingFeed) Run(ctx context.Context, conn *nats.Conn) error {
+	sub, err := conn.Subscribe(outingEventsSubject, func(msg *nats.Msg) {
+		f.handle(ctx, msg.Data)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to subscribe to outing events: %w", err)
+	}
+
+	<-ctx.Done()
+	return sub.Unsubscribe()
+}
+
+// outingEvent is the part of the published event envelope the feed needs
+type outingEvent struct {
+	EventType   string    `json:"event_type"`
+	AggregateID string    `json:"aggregate_id"`
+	OccurredAt  time.Time `json:"occurred_at"`
+}
+
+func (f *OutingFeed) handle(ctx context.Context, data []byte) {
+	f.mu.RLock()
+	idle := len(f.subscribers) == 0
+	f.mu.RUnlock()
+	if idle {
+		return
+	}
+
+	var event outingEvent
+	if err := json.Unmarshal(data, &event); err != nil || !strings.HasPrefix(event.EventType, "outing.") {
+		return
+	}
+
+	// Events do not all carry the establishment; the outing has it and is
+	// what subscribers receive
+	outing, err := f.outingRepo.GetByID(ctx, event.AggregateID)
+	if err != nil {
+		log.Printf("warning: failed to load outing %s for live update: %v", event.AggregateID, err)
+		return
+	}
+
+	update := domain.OutingUpdate{
+		EventType:  event.EventType,
+		Outing:     outing,
+		OccurredAt: event.OccurredAt,
+	}
+
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	for ch := range f.subscribers[outing.Offer().EstablishmentID()] {
+		select {
+		case ch <- update:
+		default:
+			log.Printf("warning: dropping live update of outing %s for a slow subscriber", outing.ID())
+		}
+	}
+}
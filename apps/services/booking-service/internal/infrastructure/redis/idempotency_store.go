@@ -0,0 +1,132 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	goredis "github.com/redis/go-redis/v9"
+
+	"github.com/yousoon/apps/services/booking-service/internal/domain"
+	sharedredis "github.com/yousoon/shared/infrastructure/redis"
+)
+
+// releaseClaimScript deletes a key only while it still holds the pending
+// claim in ARGV[1], so a completed outcome is never dropped.
+var releaseClaimScript = goredis.NewScript(`
+	if redis.call("GET", KEYS[1]) == ARGV[1] then
+		return redis.call("DEL", KEYS[1])
+	end
+	return 0
+`)
+
+// idempotencyClaimTTL bounds how long a crashed attempt keeps its key locked
+const idempotencyClaimTTL = time.Minute
+
+// =============================================================================
+// IDEMPOTENCY STORE
+// =============================================================================
+
+// idempotencyRecord is stored as JSON under the key, pending while the first
+// attempt runs and with its outcome once it completed
+type idempotencyRecord struct {
+	Fingerprint string `json:"fingerprint"`
+	Completed   bool   `json:"completed"`
+	OutingID    string `json:"outingId,omitempty"`
+}
+
+// IdempotencyStore keeps idempotency records in Redis; completed outcomes are
+// kept for ttl.
+type IdempotencyStore struct {
+	client *sharedredis.Client
+	ttl    time.Duration
+}
+
+func NewIdempotencyStore(client *sharedredis.Client, ttl time.Duration) *IdempotencyStore {
+	return &IdempotencyStore{
+		client: client,
+		ttl:    ttl,
+	}
+}
+
+func (s *IdempotencyStore) Claim(ctx context.Context, key, fingerprint string) (*domain.IdempotentOutcome, error) {
+	pending, err := json.Marshal(idempotencyRecord{Fingerprint: fingerprint})
+	if err != nil {
+		return nil, err
+	}
+
+	// The key may expire between SETNX and GET; claim again then
+	for attempt := 0; attempt < 2; attempt++ {
+		claimed, err := s.client.SetNX(ctx, s.key(key), pending, idempotencyClaimTTL)
+		if err != nil {
+			return nil, fmt.Errorf("failed to claim idempotency key: %w", err)
+		}
+		if claimed {
+			return nil, nil
+		}
+
+		stored, err := s.client.Get(ctx, s.key(key))
+		if errors.Is(err, sharedredis.ErrKeyNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read idempotency key: %w", err)
+		}
+
+		var record idempotencyRecord
+		if err := json.Unmarshal([]byte(stored), &record); err != nil {
+			return nil, fmt.Errorf("failed to decode idempotency record: %w", err)
+		}
+		if record.Fingerprint != fingerprint {
+			return nil, domain.ErrIdempotencyKeyMismatch
+		}
+		if !record.Completed {
+			return nil, domain.ErrIdempotencyKeyInUse
+		}
+		return &domain.IdempotentOutcome{OutingID: record.OutingID}, nil
+	}
+
+	return nil, domain.ErrIdempotencyKeyInUse
+}
+
+func (s *IdempotencyStore) Complete(ctx context.Context, key, fingerprint string, outcome domain.IdempotentOutcome) error {
+	completed, err := json.Marshal(idempotencyRecord{
+		Fingerprint: fingerprint,
+		Completed:   true,
+		OutingID:    outcome.OutingID,
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := s.client.Set(ctx, s.key(key), completed, s.ttl); err != nil {
+		return fmt.Errorf("failed to store idempotency outcome: %w", err)
+	}
+	return nil
+}
+
+func (s *IdempotencyStore) Release(ctx context.Context, key string) error {
+	stored, err := s.client.Get(ctx, s.key(key))
+	if errors.Is(err, sharedredis.ErrKeyNotFound) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read idempotency key: %w", err)
+	}
+
+	var record idempotencyRecord
+	if err := json.Unmarshal([]byte(stored), &record); err != nil || record.Completed {
+		return nil
+	}
+
+	if err := releaseClaimScript.Run(ctx, s.client.Client(), []string{s.key(key)}, stored).Err(); err != nil {
+		return fmt.Errorf("failed to release idempotency key: %w", err)
+	}
+	return nil
+}
+
+func (s *IdempotencyStore) key(key string) string {
+	return "booking:idempotency:" + key
+}
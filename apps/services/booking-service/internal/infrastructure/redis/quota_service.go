@@ -0,0 +1,284 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	goredis "github.com/redis/go-redis/v9"
+
+	"github.com/yousoon/apps/services/booking-service/internal/domain"
+	sharedredis "github.com/yousoon/shared/infrastructure/redis"
+)
+
+// =============================================================================
+// LUA SCRIPTS
+// =============================================================================
+
+// reserveScript checks every limited counter, then increments them all, in one
+// atomic step. KEYS are the total, per-user and per-day counters; ARGV holds
+// the matching limits (-1 = unlimited).
+// Returns 0 on success, the index of the exhausted counter (1..3), or -1 when
+// a limited counter is missing and must be seeded from MongoDB first.
+var reserveScript = goredis.NewScript(`
+	for i = 1, 3 do
+		local limit = tonumber(ARGV[i])
+		if limit >= 0 then
+			local used = redis.call("GET", KEYS[i])
+			if not used then
+				return -1
+			end
+			if tonumber(used) >= limit then
+				return i
+			end
+		end
+	end
+	for i = 1, 3 do
+		if tonumber(ARGV[i]) >= 0 then
+			redis.call("INCR", KEYS[i])
+		end
+	end
+	return 0
+`)
+
+// releaseScript decrements the existing counters without going below zero.
+// Missing counters are left alone: they are re-seeded from MongoDB, which
+// already reflects the released outing.
+var releaseScript = goredis.NewScript(`
+	for i = 1, #KEYS do
+		local used = redis.call("GET", KEYS[i])
+		if used and tonumber(used) > 0 then
+			redis.call("DECR", KEYS[i])
+		end
+	end
+	return 0
+`)
+
+// releaseOutingScript runs releaseScript at most once per outing: KEYS[1] is
+// the set of outing IDs already released, the other KEYS are the counters.
+// ARGV[1] is the outing ID and ARGV[2] the set's TTL in seconds.
+// Returns 0 on release, or 1 when the outing was already released.
+var releaseOutingScript = goredis.NewScript(`
+	if redis.call("SADD", KEYS[1], ARGV[1]) == 0 then
+		return 1
+	end
+	redis.call("EXPIRE", KEYS[1], ARGV[2])
+	for i = 2, #KEYS do
+		local used = redis.call("GET", KEYS[i])
+		if used and tonumber(used) > 0 then
+			redis.call("DECR", KEYS[i])
+		end
+	end
+	return 0
+`)
+
+// transferScript moves one unit from the owner's per-user counter (KEYS[1])
+// to the recipient's (KEYS[2]) if the recipient is below the limit in ARGV[1].
+// Returns 0 on success, 1 when the recipient's quota is exhausted, or -1 when
+// the recipient's counter is missing and must be seeded from MongoDB first.
+var transferScript = goredis.NewScript(`
+	local used = redis.call("GET", KEYS[2])
+	if not used then
+		return -1
+	end
+	if tonumber(used) >= tonumber(ARGV[1]) then
+		return 1
+	end
+	redis.call("INCR", KEYS[2])
+	local owned = redis.call("GET", KEYS[1])
+	if owned and tonumber(owned) > 0 then
+		redis.call("DECR", KEYS[1])
+	end
+	return 0
+`)
+
+const maxSeedAttempts = 3
+
+// =============================================================================
+// QUOTA SERVICE
+// =============================================================================
+
+// QuotaService enforces offer quotas with Redis counters. Counters are seeded
+// from MongoDB (the source of truth) when missing and expire after counterTTL,
+// so any drift is reconciled on the next seed.
+type QuotaService struct {
+	client     *sharedredis.Client
+	outingRepo domain.OutingRepository
+	counterTTL time.Duration
+	location   *time.Location
+}
+
+func NewQuotaService(client *sharedredis.Client, outingRepo domain.OutingRepository, counterTTL time.Duration, location *time.Location) *QuotaService {
+	return &QuotaService{
+		client:     client,
+		outingRepo: outingRepo,
+		counterTTL: counterTTL,
+		location:   location,
+	}
+}
+
+func (s *QuotaService) Reserve(ctx context.Context, offerID, userID string, quota domain.OfferQuota, at time.Time) error {
+	if quota.IsUnlimited() {
+		return nil
+	}
+
+	keys := s.keys(offerID, userID, at)
+	limits := []interface{}{limitArg(quota.Total()), limitArg(quota.PerUser()), limitArg(quota.PerDay())}
+
+	for attempt := 0; attempt < maxSeedAttempts; attempt++ {
+		code, err := reserveScript.Run(ctx, s.client.Client(), keys, limits...).Int()
+		if err != nil {
+			return fmt.Errorf("failed to reserve quota: %w", err)
+		}
+
+		switch code {
+		case 0:
+			return nil
+		case 1:
+			return domain.ErrOfferQuotaExceeded
+		case 2:
+			return domain.ErrUserQuotaExceeded
+		case 3:
+			return domain.ErrDailyQuotaExceeded
+		}
+
+		if err := s.seed(ctx, offerID, userID, at, quota); err != nil {
+			return err
+		}
+	}
+
+	return fmt.Errorf("failed to reserve quota: counters could not be seeded")
+}
+
+// Release gives back the outing's capacity once, however many times it is
+// called for the same outing
+func (s *QuotaService) Release(ctx context.Context, outing *domain.Outing) error {
+	offerID := outing.Offer().OfferID()
+	keys := append([]string{s.releasedKey(offerID, outing.BookedAt())}, s.keys(offerID, outing.UserID(), outing.BookedAt())...)
+	ttl := int(s.counterTTL.Seconds())
+
+	if err := releaseOutingScript.Run(ctx, s.client.Client(), keys, outing.ID(), ttl).Err(); err != nil {
+		return fmt.Errorf("failed to release quota: %w", err)
+	}
+
+	return nil
+}
+
+func (s *QuotaService) ReleaseReservation(ctx context.Context, offerID, userID string, at time.Time) error {
+	keys := s.keys(offerID, userID, at)
+
+	if err := releaseScript.Run(ctx, s.client.Client(), keys).Err(); err != nil {
+		return fmt.Errorf("failed to release quota: %w", err)
+	}
+
+	return nil
+}
+
+func (s *QuotaService) Transfer(ctx context.Context, offerID, fromUserID, toUserID string, at time.Time, quota domain.OfferQuota) error {
+	if quota.PerUser() == nil {
+		return nil
+	}
+
+	keys := []string{
+		s.keys(offerID, fromUserID, at)[1],
+		s.keys(offerID, toUserID, at)[1],
+	}
+
+	for attempt := 0; attempt < maxSeedAttempts; attempt++ {
+		code, err := transferScript.Run(ctx, s.client.Client(), keys, *quota.PerUser()).Int()
+		if err != nil {
+			return fmt.Errorf("failed to transfer quota: %w", err)
+		}
+
+		switch code {
+		case 0:
+			return nil
+		case 1:
+			return domain.ErrUserQuotaExceeded
+		}
+
+		// Only the recipient's per-user counter is needed
+		perUser := domain.NewOfferQuota(nil, quota.PerUser(), nil)
+		if err := s.seed(ctx, offerID, toUserID, at, perUser); err != nil {
+			return err
+		}
+	}
+
+	return fmt.Errorf("failed to transfer quota: counter could not be seeded")
+}
+
+// seed initializes the missing limited counters from MongoDB counts.
+// SETNX keeps a counter another request seeded concurrently.
+func (s *QuotaService) seed(ctx context.Context, offerID, userID string, at time.Time, quota domain.OfferQuota) error {
+	keys := s.keys(offerID, userID, at)
+
+	if quota.Total() != nil {
+		count, err := s.outingRepo.CountByOfferAndPeriod(ctx, offerID, time.Time{}, farFuture)
+		if err != nil {
+			return fmt.Errorf("failed to seed offer quota: %w", err)
+		}
+		if _, err := s.client.SetNX(ctx, keys[0], count, s.counterTTL); err != nil {
+			return fmt.Errorf("failed to seed offer quota: %w", err)
+		}
+	}
+
+	if quota.PerUser() != nil {
+		count, err := s.outingRepo.CountByUserAndOffer(ctx, userID, offerID)
+		if err != nil {
+			return fmt.Errorf("failed to seed user quota: %w", err)
+		}
+		if _, err := s.client.SetNX(ctx, keys[1], count, s.counterTTL); err != nil {
+			return fmt.Errorf("failed to seed user quota: %w", err)
+		}
+	}
+
+	if quota.PerDay() != nil {
+		dayStart, dayEnd := s.dayBounds(at)
+		count, err := s.outingRepo.CountByOfferAndPeriod(ctx, offerID, dayStart, dayEnd)
+		if err != nil {
+			return fmt.Errorf("failed to seed daily quota: %w", err)
+		}
+		if _, err := s.client.SetNX(ctx, keys[2], count, s.counterTTL); err != nil {
+			return fmt.Errorf("failed to seed daily quota: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// =============================================================================
+// HELPERS
+// =============================================================================
+
+var farFuture = time.Date(9999, 12, 31, 0, 0, 0, 0, time.UTC)
+
+func (s *QuotaService) keys(offerID, userID string, at time.Time) []string {
+	day := at.In(s.location).Format("2006-01-02")
+	return []string{
+		// Hash tag keeps an offer's counters in one cluster slot for the scripts
+		fmt.Sprintf("booking:quota:{%s}:total", offerID),
+		fmt.Sprintf("booking:quota:{%s}:user:%s", offerID, userID),
+		fmt.Sprintf("booking:quota:{%s}:day:%s", offerID, day),
+	}
+}
+
+// releasedKey is the set of outings booked on the day of at whose capacity
+// was already released
+func (s *QuotaService) releasedKey(offerID string, at time.Time) string {
+	day := at.In(s.location).Format("2006-01-02")
+	return fmt.Sprintf("booking:quota:{%s}:released:%s", offerID, day)
+}
+
+func (s *QuotaService) dayBounds(at time.Time) (time.Time, time.Time) {
+	local := at.In(s.location)
+	start := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, s.location)
+	end := start.AddDate(0, 0, 1).Add(-time.Nanosecond)
+	return start, end
+}
+
+func limitArg(limit *int) int {
+	if limit == nil {
+		return -1
+	}
+	return *limit
+}
@@ -0,0 +1,81 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	goredis "github.com/redis/go-redis/v9"
+
+	"github.com/yousoon/apps/services/booking-service/internal/domain"
+	sharedredis "github.com/yousoon/shared/infrastructure/redis"
+)
+
+// attemptScript counts one attempt unless ARGV[1] attempts are already
+// counted, starting the window on the first one so the counter resets ARGV[2]
+// milliseconds after it. Returns 0 when the attempt is allowed, 1 otherwise.
+var attemptScript = goredis.NewScript(`
+	local attempts = tonumber(redis.call("GET", KEYS[1]) or "0")
+	if attempts >= tonumber(ARGV[1]) then
+		return 1
+	end
+	if redis.call("INCR", KEYS[1]) == 1 then
+		redis.call("PEXPIRE", KEYS[1], ARGV[2])
+	end
+	return 0
+`)
+
+// refundScript gives back one counted attempt without going below zero
+var refundScript = goredis.NewScript(`
+	local attempts = redis.call("GET", KEYS[1])
+	if attempts and tonumber(attempts) > 0 then
+		redis.call("DECR", KEYS[1])
+	end
+	return 0
+`)
+
+// =============================================================================
+// SHORT CODE ATTEMPT LIMITER
+// =============================================================================
+
+// ShortCodeAttemptLimiter counts short code attempts per establishment in a
+// fixed window. Every attempt is counted before the lookup, so concurrent
+// guesses cannot all slip under the limit, and attempts that matched an outing
+// are refunded: only invalid codes use up the window started by the first one.
+type ShortCodeAttemptLimiter struct {
+	client      *sharedredis.Client
+	maxFailures int64
+	window      time.Duration
+}
+
+func NewShortCodeAttemptLimiter(client *sharedredis.Client, maxFailures int, window time.Duration) *ShortCodeAttemptLimiter {
+	return &ShortCodeAttemptLimiter{
+		client:      client,
+		maxFailures: int64(maxFailures),
+		window:      window,
+	}
+}
+
+func (l *ShortCodeAttemptLimiter) Attempt(ctx context.Context, establishmentID string) error {
+	keys := []string{l.key(establishmentID)}
+	refused, err := attemptScript.Run(ctx, l.client.Client(), keys, l.maxFailures, l.window.Milliseconds()).Int()
+	if err != nil {
+		return fmt.Errorf("failed to count short code attempt: %w", err)
+	}
+	if refused == 1 {
+		return domain.ErrTooManyShortCodeAttempts
+	}
+	return nil
+}
+
+func (l *ShortCodeAttemptLimiter) Refund(ctx context.Context, establishmentID string) error {
+	keys := []string{l.key(establishmentID)}
+	if err := refundScript.Run(ctx, l.client.Client(), keys).Err(); err != nil {
+		return fmt.Errorf("failed to refund short code attempt: %w", err)
+	}
+	return nil
+}
+
+func (l *ShortCodeAttemptLimiter) key(establishmentID string) string {
+	return "booking:short_code_failures:" + establishmentID
+}
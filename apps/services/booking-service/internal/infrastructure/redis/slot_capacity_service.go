@@ -0,0 +1,153 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	goredis "github.com/redis/go-redis/v9"
+
+	"github.com/yousoon/apps/services/booking-service/internal/domain"
+	sharedredis "github.com/yousoon/shared/infrastructure/redis"
+)
+
+// reserveSeatScript takes one seat of a slot counter. ARGV[1] is the capacity.
+// Returns 0 on success, 1 when the slot is full, or -1 when the counter is
+// missing and must be seeded from MongoDB first.
+var reserveSeatScript = goredis.NewScript(`
+	local used = redis.call("GET", KEYS[1])
+	if not used then
+		return -1
+	end
+	if tonumber(used) >= tonumber(ARGV[1]) then
+		return 1
+	end
+	redis.call("INCR", KEYS[1])
+	return 0
+`)
+
+// slotCounterRetention keeps a slot counter around after the slot ended so
+// late cancellations and expiries still find it.
+const slotCounterRetention = 24 * time.Hour
+
+// =============================================================================
+// SLOT CAPACITY SERVICE
+// =============================================================================
+
+// SlotCapacityService tracks the seats taken in each slot instance with one
+// Redis counter per offer and slot start. Like QuotaService, counters are
+// seeded from MongoDB when missing.
+type SlotCapacityService struct {
+	client     *sharedredis.Client
+	outingRepo domain.OutingRepository
+}
+
+func NewSlotCapacityService(client *sharedredis.Client, outingRepo domain.OutingRepository) *SlotCapacityService {
+	return &SlotCapacityService{
+		client:     client,
+		outingRepo: outingRepo,
+	}
+}
+
+func (s *SlotCapacityService) Reserve(ctx context.Context, offerID string, slot domain.SlotSnapshot) error {
+	if slot.Capacity() == nil {
+		return nil
+	}
+
+	keys := []string{s.key(offerID, slot)}
+
+	for attempt := 0; attempt < maxSeedAttempts; attempt++ {
+		code, err := reserveSeatScript.Run(ctx, s.client.Client(), keys, *slot.Capacity()).Int()
+		if err != nil {
+			return fmt.Errorf("failed to reserve slot: %w", err)
+		}
+
+		switch code {
+		case 0:
+			return nil
+		case 1:
+			return domain.ErrSlotFull
+		}
+
+		if _, err := s.seed(ctx, offerID, slot); err != nil {
+			return err
+		}
+	}
+
+	return fmt.Errorf("failed to reserve slot: counter could not be seeded")
+}
+
+// Release gives back the outing's seat once, however many times it is called
+// for the same outing
+func (s *SlotCapacityService) Release(ctx context.Context, outing *domain.Outing) error {
+	slot := outing.Slot()
+	if slot == nil || slot.Capacity() == nil {
+		return nil
+	}
+
+	offerID := outing.Offer().OfferID()
+	keys := []string{s.key(offerID, *slot) + ":released", s.key(offerID, *slot)}
+	ttl := time.Until(slot.EndsAt()) + slotCounterRetention
+	if ttl < slotCounterRetention {
+		ttl = slotCounterRetention
+	}
+
+	if err := releaseOutingScript.Run(ctx, s.client.Client(), keys, outing.ID(), int(ttl.Seconds())).Err(); err != nil {
+		return fmt.Errorf("failed to release slot: %w", err)
+	}
+
+	return nil
+}
+
+func (s *SlotCapacityService) ReleaseSeat(ctx context.Context, offerID string, slot domain.SlotSnapshot) error {
+	if slot.Capacity() == nil {
+		return nil
+	}
+
+	keys := []string{s.key(offerID, slot)}
+	if err := releaseScript.Run(ctx, s.client.Client(), keys).Err(); err != nil {
+		return fmt.Errorf("failed to release slot: %w", err)
+	}
+
+	return nil
+}
+
+func (s *SlotCapacityService) Remaining(ctx context.Context, offerID string, slot domain.SlotSnapshot) (*int, error) {
+	if slot.Capacity() == nil {
+		return nil, nil
+	}
+
+	used, err := s.client.Client().Get(ctx, s.key(offerID, slot)).Int64()
+	if err == goredis.Nil {
+		used, err = s.seed(ctx, offerID, slot)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get slot usage: %w", err)
+	}
+
+	remaining := *slot.Capacity() - int(used)
+	if remaining < 0 {
+		remaining = 0
+	}
+	return &remaining, nil
+}
+
+// seed initializes the slot counter from MongoDB and returns the seeded count.
+// SETNX keeps a counter another request seeded concurrently.
+func (s *SlotCapacityService) seed(ctx context.Context, offerID string, slot domain.SlotSnapshot) (int64, error) {
+	count, err := s.outingRepo.CountBySlot(ctx, offerID, slot.StartsAt())
+	if err != nil {
+		return 0, fmt.Errorf("failed to seed slot capacity: %w", err)
+	}
+
+	ttl := time.Until(slot.EndsAt()) + slotCounterRetention
+	if _, err := s.client.SetNX(ctx, s.key(offerID, slot), count, ttl); err != nil {
+		return 0, fmt.Errorf("failed to seed slot capacity: %w", err)
+	}
+
+	return count, nil
+}
+
+func (s *SlotCapacityService) key(offerID string, slot domain.SlotSnapshot) string {
+	return fmt.Sprintf("booking:slot:{%s}:%d", offerID, slot.StartsAt().Unix())
+}
@@ -0,0 +1,109 @@
+package signedurl
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+// =============================================================================
+// Signed Link Tests
+// =============================================================================
+
+func parseLink(t *testing.T, link string) *url.URL {
+	t.Helper()
+	u, err := url.Parse(link)
+	if err != nil {
+		t.Fatalf("url.Parse(%q) error = %v", link, err)
+	}
+	return u
+}
+
+func TestSigner_RoundTrip(t *testing.T) {
+	signer := NewSigner("https://booking.example.com/", "secret")
+	now := time.Now()
+	expiresAt := now.Add(time.Hour)
+
+	link := signer.URL("/calendar/outing.ics", url.Values{"outingId": {"o-1"}, "userId": {"u-1"}}, &expiresAt)
+	if !strings.HasPrefix(link, "https://booking.example.com/calendar/outing.ics?") {
+		t.Errorf("URL() = %v, want an absolute link under the base URL", link)
+	}
+
+	params, err := signer.Verify(parseLink(t, link), now)
+	if err != nil {
+		t.Fatalf("Verify() error = %v, want nil", err)
+	}
+	if params.Get("outingId") != "o-1" || params.Get("userId") != "u-1" {
+		t.Errorf("Verify() params = %v, want the signed parameters", params)
+	}
+}
+
+func TestSigner_Verify_Rejects(t *testing.T) {
+	signer := NewSigner("https://booking.example.com", "secret")
+	now := time.Now()
+	expiresAt := now.Add(time.Hour)
+	link := signer.URL("/calendar/feed.ics", url.Values{"userId": {"u-1"}}, &expiresAt)
+
+	tests := []struct {
+		name   string
+		mutate func(u *url.URL)
+		at     time.Time
+		want   error
+	}{
+		{
+			name:   "other user",
+			mutate: func(u *url.URL) { setParam(u, "userId", "u-2") },
+			at:     now,
+			want:   ErrInvalidSignature,
+		},
+		{
+			name:   "extended expiry",
+			mutate: func(u *url.URL) { setParam(u, expiresParam, "99999999999") },
+			at:     now,
+			want:   ErrInvalidSignature,
+		},
+		{
+			name:   "other path",
+			mutate: func(u *url.URL) { u.Path = "/calendar/outing.ics" },
+			at:     now,
+			want:   ErrInvalidSignature,
+		},
+		{
+			name:   "missing signature",
+			mutate: func(u *url.URL) { setParam(u, signatureParam, "") },
+			at:     now,
+			want:   ErrInvalidSignature,
+		},
+		{
+			name:   "expired",
+			mutate: func(u *url.URL) {},
+			at:     expiresAt.Add(time.Second),
+			want:   ErrLinkExpired,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u := parseLink(t, link)
+			tt.mutate(u)
+			if _, err := signer.Verify(u, tt.at); err != tt.want {
+				t.Errorf("Verify() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestSigner_Verify_OtherSecret(t *testing.T) {
+	link := NewSigner("https://booking.example.com", "secret").URL("/calendar/feed.ics", url.Values{"userId": {"u-1"}}, nil)
+
+	if _, err := NewSigner("https://booking.example.com", "other").Verify(parseLink(t, link), time.Now()); err != ErrInvalidSignature {
+		t.Errorf("Verify() error = %v, want %v", err, ErrInvalidSignature)
+	}
+}
+
+func setParam(u *url.URL, key, value string) {
+	params := u.Query()
+	params.Set(key, value)
+	u.RawQuery = params.Encode()
+}
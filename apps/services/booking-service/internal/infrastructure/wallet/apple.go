@@ -0,0 +1,307 @@
+package wallet
+
+import (
+	"archive/zip"
+	"bytes"
+	"crypto"
+	"crypto/sha1"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"os"
+	"time"
+
+	"github.com/yousoon/apps/services/booking-service/internal/domain"
+	"github.com/yousoon/apps/services/booking-service/internal/infrastructure/calendar"
+)
+
+// =============================================================================
+// APPLE WALLET (.pkpass)
+// =============================================================================
+
+// ApplePassConfig holds the pass type identity and the PEM files of its
+// signing certificate, private key and the Apple WWDR intermediate. A
+// self-signed certificate works for tests; Wallet only accepts passes signed
+// with a certificate issued by Apple.
+type ApplePassConfig struct {
+	PassTypeID       string
+	TeamID           string
+	OrganizationName string
+	CertFile         string
+	KeyFile          string
+	WWDRCertFile     string
+}
+
+// ApplePassBuilder builds signed .pkpass archives for outings
+type ApplePassBuilder struct {
+	config ApplePassConfig
+	cert   *x509.Certificate
+	key    crypto.Signer
+	chain  []*x509.Certificate
+	icons  map[string][]byte
+	keys   *domain.CheckInKeys
+}
+
+func NewApplePassBuilder(config ApplePassConfig, keys *domain.CheckInKeys) (*ApplePassBuilder, error) {
+	if config.PassTypeID == "" || config.TeamID == "" {
+		return nil, errors.New("apple pass type ID and team ID are required")
+	}
+
+	cert, err := loadCertificate(config.CertFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load pass certificate: %w", err)
+	}
+	key, err := loadPrivateKey(config.KeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load pass key: %w", err)
+	}
+
+	var chain []*x509.Certificate
+	if config.WWDRCertFile != "" {
+		wwdr, err := loadCertificate(config.WWDRCertFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load WWDR certificate: %w", err)
+		}
+		chain = append(chain, wwdr)
+	}
+
+	icons := make(map[string][]byte, 2)
+	for name, size := range map[string]int{"icon.png": 29, "icon@2x.png": 58} {
+		if icons[name], err = renderIcon(size); err != nil {
+			return nil, err
+		}
+	}
+
+	return &ApplePassBuilder{
+		config: config,
+		cert:   cert,
+		key:    key,
+		chain:  chain,
+		icons:  icons,
+		keys:   keys,
+	}, nil
+}
+
+// pass is the pass.json document
+type pass struct {
+	FormatVersion      int             `json:"formatVersion"`
+	PassTypeIdentifier string          `json:"passTypeIdentifier"`
+	SerialNumber       string          `json:"serialNumber"`
+	TeamIdentifier     string          `json:"teamIdentifier"`
+	OrganizationName   string          `json:"organizationName"`
+	Description        string          `json:"description"`
+	LogoText           string          `json:"logoText,omitempty"`
+	ExpirationDate     string          `json:"expirationDate"`
+	RelevantDate       string          `json:"relevantDate,omitempty"`
+	Voided             bool            `json:"voided,omitempty"`
+	Locations          []passLocation  `json:"locations,omitempty"`
+	Barcodes           []passBarcode   `json:"barcodes"`
+	EventTicket        passFieldGroups `json:"eventTicket"`
+}
+
+type passLocation struct {
+	Latitude     float64 `json:"latitude"`
+	Longitude    float64 `json:"longitude"`
+	RelevantText string  `json:"relevantText,omitempty"`
+}
+
+type passBarcode struct {
+	Format          string `json:"format"`
+	Message         string `json:"message"`
+	MessageEncoding string `json:"messageEncoding"`
+	AltText         string `json:"altText,omitempty"`
+}
+
+type passFieldGroups struct {
+	PrimaryFields   []passField `json:"primaryFields"`
+	SecondaryFields []passField `json:"secondaryFields,omitempty"`
+	AuxiliaryFields []passField `json:"auxiliaryFields,omitempty"`
+	BackFields      []passField `json:"backFields,omitempty"`
+}
+
+type passField struct {
+	Key       string `json:"key"`
+	Label     string `json:"label,omitempty"`
+	Value     string `json:"value"`
+	DateStyle string `json:"dateStyle,omitempty"`
+	TimeStyle string `json:"timeStyle,omitempty"`
+}
+
+// Write streams the signed .pkpass archive of the outing. A pass cannot
+// rotate its barcode, so it holds the outing's static pass credential.
+func (b *ApplePassBuilder) Write(w io.Writer, outing *domain.Outing, now time.Time) error {
+	passPayload, err := outing.PassPayload(b.keys)
+	if err != nil {
+		return fmt.Errorf("failed to build pass payload: %w", err)
+	}
+
+	passJSON, err := json.Marshal(b.pass(outing, passPayload))
+	if err != nil {
+		return fmt.Errorf("failed to encode pass: %w", err)
+	}
+
+	files := map[string][]byte{"pass.json": passJSON}
+	for name, icon := range b.icons {
+		files[name] = icon
+	}
+
+	manifest := make(map[string]string, len(files))
+	for name, content := range files {
+		sum := sha1.Sum(content)
+		manifest[name] = hex.EncodeToString(sum[:])
+	}
+	manifestJSON, err := json.Marshal(manifest)
+	if err != nil {
+		return fmt.Errorf("failed to encode manifest: %w", err)
+	}
+	files["manifest.json"] = manifestJSON
+
+	signature, err := signDetached(manifestJSON, b.cert, b.key, b.chain, now)
+	if err != nil {
+		return fmt.Errorf("failed to sign manifest: %w", err)
+	}
+	files["signature"] = signature
+
+	zw := zip.NewWriter(w)
+	for _, name := range []string{"pass.json", "icon.png", "icon@2x.png", "manifest.json", "signature"} {
+		fw, err := zw.Create(name)
+		if err != nil {
+			return fmt.Errorf("failed to write %s: %w", name, err)
+		}
+		if _, err := fw.Write(files[name]); err != nil {
+			return fmt.Errorf("failed to write %s: %w", name, err)
+		}
+	}
+	return zw.Close()
+}
+
+func (b *ApplePassBuilder) pass(outing *domain.Outing, passPayload string) pass {
+	offer := outing.Offer()
+	start, _ := calendar.EventWindow(outing)
+
+	p := pass{
+		FormatVersion:      1,
+		PassTypeIdentifier: b.config.PassTypeID,
+		SerialNumber:       outing.ID(),
+		TeamIdentifier:     b.config.TeamID,
+		OrganizationName:   b.config.OrganizationName,
+		Description:        offer.Title(),
+		LogoText:           b.config.OrganizationName,
+		ExpirationDate:     outing.ExpiresAt().UTC().Format(time.RFC3339),
+		RelevantDate:       start.UTC().Format(time.RFC3339),
+		Voided:             !outing.IsActive(),
+		Barcodes: []passBarcode{{
+			Format:          "PKBarcodeFormatQR",
+			Message:         passPayload,
+			MessageEncoding: "iso-8859-1",
+		}},
+		EventTicket: passFieldGroups{
+			PrimaryFields: []passField{
+				{Key: "offer", Label: "Offer", Value: offer.Title()},
+			},
+			SecondaryFields: []passField{
+				{Key: "establishment", Label: "Where", Value: offer.EstablishmentName()},
+			},
+			AuxiliaryFields: []passField{
+				{Key: "expiresAt", Label: "Valid until", Value: outing.ExpiresAt().UTC().Format(time.RFC3339), DateStyle: "PKDateStyleShort", TimeStyle: "PKDateStyleShort"},
+			},
+			BackFields: []passField{
+				{Key: "address", Label: "Address", Value: offer.EstablishmentAddress()},
+				{Key: "discount", Label: "Discount", Value: discountLabel(offer)},
+				{Key: "outingId", Label: "Booking", Value: outing.ID()},
+			},
+		},
+	}
+
+	if offer.Latitude() != 0 || offer.Longitude() != 0 {
+		p.Locations = []passLocation{{
+			Latitude:     offer.Latitude(),
+			Longitude:    offer.Longitude(),
+			RelevantText: "Your outing at " + offer.EstablishmentName(),
+		}}
+	}
+
+	return p
+}
+
+// =============================================================================
+// HELPERS
+// =============================================================================
+
+func discountLabel(offer domain.OfferSnapshot) string {
+	switch offer.DiscountType() {
+	case "percentage":
+		return fmt.Sprintf("%d%% off", offer.DiscountValue())
+	case "fixed":
+		return fmt.Sprintf("%.2f off", float64(offer.DiscountValue())/100)
+	default:
+		return offer.Title()
+	}
+}
+
+// renderIcon draws the square icon Wallet requires in every pass
+func renderIcon(size int) ([]byte, error) {
+	img := image.NewRGBA(image.Rect(0, 0, size, size))
+	brand := color.RGBA{R: 0xE8, G: 0x5D, B: 0x3F, A: 0xFF}
+	for y := 0; y < size; y++ {
+		for x := 0; x < size; x++ {
+			img.Set(x, y, brand)
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, fmt.Errorf("failed to render icon: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+func loadCertificate(path string) (*x509.Certificate, error) {
+	block, err := readPEM(path)
+	if err != nil {
+		return nil, err
+	}
+	return x509.ParseCertificate(block.Bytes)
+}
+
+// loadPrivateKey reads a PKCS #8, PKCS #1 (RSA) or SEC 1 (EC) PEM private key
+func loadPrivateKey(path string) (crypto.Signer, error) {
+	block, err := readPEM(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if key, err := x509.ParsePKCS8PrivateKey(block.Bytes); err == nil {
+		signer, ok := key.(crypto.Signer)
+		if !ok {
+			return nil, fmt.Errorf("unsupported private key type %T", key)
+		}
+		return signer, nil
+	}
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	if key, err := x509.ParseECPrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	return nil, errors.New("unsupported private key encoding")
+}
+
+func readPEM(path string) (*pem.Block, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("no PEM block in %s", path)
+	}
+	return block, nil
+}
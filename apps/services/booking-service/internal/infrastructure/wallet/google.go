@@ -0,0 +1,204 @@
+package wallet
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/yousoon/apps/services/booking-service/internal/domain"
+	"github.com/yousoon/apps/services/booking-service/internal/infrastructure/calendar"
+)
+
+// =============================================================================
+// GOOGLE WALLET
+// =============================================================================
+
+const googleSaveURL = "https://pay.google.com/gp/v/save/"
+
+// GoogleWalletConfig identifies the issuer account and the service account
+// whose RSA key signs "Save to Google Wallet" links.
+type GoogleWalletConfig struct {
+	IssuerID            string
+	ClassSuffix         string
+	ServiceAccountEmail string
+	KeyFile             string
+	Origins             []string
+}
+
+// GoogleWalletIssuer builds Google Wallet generic pass objects for outings
+// and the signed JWTs that save them.
+type GoogleWalletIssuer struct {
+	config GoogleWalletConfig
+	key    *rsa.PrivateKey
+	keys   *domain.CheckInKeys
+}
+
+func NewGoogleWalletIssuer(config GoogleWalletConfig, keys *domain.CheckInKeys) (*GoogleWalletIssuer, error) {
+	if config.IssuerID == "" || config.ClassSuffix == "" || config.ServiceAccountEmail == "" {
+		return nil, errors.New("google wallet issuer ID, class suffix and service account are required")
+	}
+
+	key, err := loadPrivateKey(config.KeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load google wallet key: %w", err)
+	}
+	rsaKey, ok := key.(*rsa.PrivateKey)
+	if !ok {
+		return nil, errors.New("google wallet key must be an RSA key")
+	}
+
+	return &GoogleWalletIssuer{config: config, key: rsaKey, keys: keys}, nil
+}
+
+// GenericObject is the Google Wallet genericObject resource of an outing
+type GenericObject struct {
+	ID                string              `json:"id"`
+	ClassID           string              `json:"classId"`
+	State             string              `json:"state"`
+	CardTitle         localizedString     `json:"cardTitle"`
+	Header            localizedString     `json:"header"`
+	Subheader         localizedString     `json:"subheader"`
+	Barcode           googleBarcode       `json:"barcode"`
+	ValidTimeInterval googleTimeInterval  `json:"validTimeInterval"`
+	TextModulesData   []googleTextModule  `json:"textModulesData,omitempty"`
+	Locations         []googleLatLongPair `json:"locations,omitempty"`
+}
+
+type localizedString struct {
+	DefaultValue translatedString `json:"defaultValue"`
+}
+
+type translatedString struct {
+	Language string `json:"language"`
+	Value    string `json:"value"`
+}
+
+type googleBarcode struct {
+	Type  string `json:"type"`
+	Value string `json:"value"`
+}
+
+type googleTimeInterval struct {
+	Start googleDateTime `json:"start"`
+	End   googleDateTime `json:"end"`
+}
+
+type googleDateTime struct {
+	Date string `json:"date"`
+}
+
+type googleTextModule struct {
+	ID     string `json:"id"`
+	Header string `json:"header"`
+	Body   string `json:"body"`
+}
+
+type googleLatLongPair struct {
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+}
+
+// Object returns the pass object of the outing, carrying its static pass
+// credential.
+func (g *GoogleWalletIssuer) Object(outing *domain.Outing) (*GenericObject, error) {
+	passPayload, err := outing.PassPayload(g.keys)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build pass payload: %w", err)
+	}
+
+	offer := outing.Offer()
+	start, _ := calendar.EventWindow(outing)
+
+	object := &GenericObject{
+		ID:        g.config.IssuerID + "." + outing.ID(),
+		ClassID:   g.config.IssuerID + "." + g.config.ClassSuffix,
+		State:     googleObjectState(outing),
+		CardTitle: localized(offer.EstablishmentName()),
+		Header:    localized(offer.Title()),
+		Subheader: localized(offer.EstablishmentAddress()),
+		Barcode:   googleBarcode{Type: "QR_CODE", Value: passPayload},
+		ValidTimeInterval: googleTimeInterval{
+			Start: googleDateTime{Date: start.UTC().Format(time.RFC3339)},
+			End:   googleDateTime{Date: outing.ExpiresAt().UTC().Format(time.RFC3339)},
+		},
+		TextModulesData: []googleTextModule{
+			{ID: "discount", Header: "Discount", Body: discountLabel(offer)},
+			{ID: "booking", Header: "Booking", Body: outing.ID()},
+		},
+	}
+	if offer.Latitude() != 0 || offer.Longitude() != 0 {
+		object.Locations = []googleLatLongPair{{Latitude: offer.Latitude(), Longitude: offer.Longitude()}}
+	}
+
+	return object, nil
+}
+
+// SaveURL returns the "Save to Google Wallet" link of the outing: a JWT
+// signed by the service account embedding the pass object.
+func (g *GoogleWalletIssuer) SaveURL(outing *domain.Outing, now time.Time) (string, error) {
+	object, err := g.Object(outing)
+	if err != nil {
+		return "", err
+	}
+
+	claims := map[string]interface{}{
+		"iss":     g.config.ServiceAccountEmail,
+		"aud":     "google",
+		"typ":     "savetowallet",
+		"iat":     now.Unix(),
+		"payload": map[string]interface{}{"genericObjects": []*GenericObject{object}},
+	}
+	if len(g.config.Origins) > 0 {
+		claims["origins"] = g.config.Origins
+	}
+
+	token, err := g.signJWT(claims)
+	if err != nil {
+		return "", err
+	}
+	return googleSaveURL + token, nil
+}
+
+// signJWT encodes the claims as an RS256 JWT
+func (g *GoogleWalletIssuer) signJWT(claims map[string]interface{}) (string, error) {
+	header, err := json.Marshal(map[string]string{"alg": "RS256", "typ": "JWT"})
+	if err != nil {
+		return "", err
+	}
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode wallet claims: %w", err)
+	}
+
+	signingInput := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(payload)
+	digest := sha256.Sum256([]byte(signingInput))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, g.key, crypto.SHA256, digest[:])
+	if err != nil {
+		return "", fmt.Errorf("failed to sign wallet JWT: %w", err)
+	}
+
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(signature), nil
+}
+
+func googleObjectState(outing *domain.Outing) string {
+	switch outing.Status() {
+	case domain.OutingStatusPending, domain.OutingStatusConfirmed:
+		return "ACTIVE"
+	case domain.OutingStatusCheckedIn:
+		return "COMPLETED"
+	case domain.OutingStatusExpired, domain.OutingStatusNoShow:
+		return "EXPIRED"
+	default:
+		return "INACTIVE"
+	}
+}
+
+func localized(value string) localizedString {
+	return localizedString{DefaultValue: translatedString{Language: "en", Value: value}}
+}
@@ -0,0 +1,172 @@
+package wallet
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"fmt"
+	"math/big"
+	"sort"
+	"time"
+)
+
+// =============================================================================
+// PKCS #7 DETACHED SIGNATURE (RFC 2315)
+// =============================================================================
+
+var (
+	oidData                   = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 7, 1}
+	oidSignedData             = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 7, 2}
+	oidAttributeContentType   = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 9, 3}
+	oidAttributeMessageDigest = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 9, 4}
+	oidAttributeSigningTime   = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 9, 5}
+	oidSHA256                 = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 2, 1}
+	oidRSAEncryption          = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 1, 1}
+	oidECDSAWithSHA256        = asn1.ObjectIdentifier{1, 2, 840, 10045, 4, 3, 2}
+)
+
+type contentInfo struct {
+	ContentType asn1.ObjectIdentifier
+	Content     asn1.RawValue `asn1:"optional"`
+}
+
+type signedData struct {
+	Version          int
+	DigestAlgorithms []pkix.AlgorithmIdentifier `asn1:"set"`
+	ContentInfo      contentInfo
+	Certificates     asn1.RawValue
+	SignerInfos      []signerInfo `asn1:"set"`
+}
+
+type issuerAndSerial struct {
+	Issuer       asn1.RawValue
+	SerialNumber *big.Int
+}
+
+type signerInfo struct {
+	Version                   int
+	IssuerAndSerialNumber     issuerAndSerial
+	DigestAlgorithm           pkix.AlgorithmIdentifier
+	AuthenticatedAttributes   asn1.RawValue
+	DigestEncryptionAlgorithm pkix.AlgorithmIdentifier
+	EncryptedDigest           []byte
+}
+
+type attribute struct {
+	Type  asn1.ObjectIdentifier
+	Value asn1.RawValue
+}
+
+// signDetached returns a DER PKCS #7 signedData over content without
+// embedding it, signed with SHA-256 by cert's key. The chain certificates
+// (the intermediate CA) are included after the signer certificate.
+func signDetached(content []byte, cert *x509.Certificate, key crypto.Signer, chain []*x509.Certificate, signedAt time.Time) ([]byte, error) {
+	digest := sha256.Sum256(content)
+
+	attributes, err := marshalAttributes(
+		attributeOf(oidAttributeContentType, oidData),
+		attributeOf(oidAttributeSigningTime, signedAt.UTC()),
+		attributeOf(oidAttributeMessageDigest, digest[:]),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	// The signature covers the attributes encoded as a SET; they are then
+	// stored under the implicit [0] tag.
+	attributesDigest := sha256.Sum256(attributes)
+	var encryptionAlgorithm asn1.ObjectIdentifier
+	switch key.Public().(type) {
+	case *rsa.PublicKey:
+		encryptionAlgorithm = oidRSAEncryption
+	case *ecdsa.PublicKey:
+		encryptionAlgorithm = oidECDSAWithSHA256
+	default:
+		return nil, fmt.Errorf("unsupported signing key type %T", key.Public())
+	}
+	signature, err := key.Sign(rand.Reader, attributesDigest[:], crypto.SHA256)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign: %w", err)
+	}
+
+	var certificates []byte
+	for _, c := range append([]*x509.Certificate{cert}, chain...) {
+		certificates = append(certificates, c.Raw...)
+	}
+
+	sha256Algorithm := pkix.AlgorithmIdentifier{Algorithm: oidSHA256, Parameters: asn1.NullRawValue}
+	sd := signedData{
+		Version:          1,
+		DigestAlgorithms: []pkix.AlgorithmIdentifier{sha256Algorithm},
+		ContentInfo:      contentInfo{ContentType: oidData},
+		Certificates:     asn1.RawValue{Class: asn1.ClassContextSpecific, Tag: 0, IsCompound: true, Bytes: certificates},
+		SignerInfos: []signerInfo{{
+			Version: 1,
+			IssuerAndSerialNumber: issuerAndSerial{
+				Issuer:       asn1.RawValue{FullBytes: cert.RawIssuer},
+				SerialNumber: cert.SerialNumber,
+			},
+			DigestAlgorithm:           sha256Algorithm,
+			AuthenticatedAttributes:   asn1.RawValue{Class: asn1.ClassContextSpecific, Tag: 0, IsCompound: true, Bytes: attributes[setHeaderLength(attributes):]},
+			DigestEncryptionAlgorithm: pkix.AlgorithmIdentifier{Algorithm: encryptionAlgorithm, Parameters: asn1.NullRawValue},
+			EncryptedDigest:           signature,
+		}},
+	}
+
+	inner, err := asn1.Marshal(sd)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode signed data: %w", err)
+	}
+
+	return asn1.Marshal(contentInfo{
+		ContentType: oidSignedData,
+		Content:     asn1.RawValue{Class: asn1.ClassContextSpecific, Tag: 0, IsCompound: true, Bytes: inner},
+	})
+}
+
+// pendingAttribute is a single-valued attribute not encoded yet
+type pendingAttribute struct {
+	oid   asn1.ObjectIdentifier
+	value interface{}
+}
+
+func attributeOf(oid asn1.ObjectIdentifier, value interface{}) pendingAttribute {
+	return pendingAttribute{oid: oid, value: value}
+}
+
+// marshalAttributes encodes the attributes as a DER SET OF, which must be
+// sorted by encoding.
+func marshalAttributes(pending ...pendingAttribute) ([]byte, error) {
+	encoded := make([][]byte, 0, len(pending))
+	for _, p := range pending {
+		value, err := asn1.Marshal(p.value)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode attribute %s: %w", p.oid, err)
+		}
+		attr, err := asn1.Marshal(attribute{
+			Type:  p.oid,
+			Value: asn1.RawValue{Class: asn1.ClassUniversal, Tag: asn1.TagSet, IsCompound: true, Bytes: value},
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode attribute %s: %w", p.oid, err)
+		}
+		encoded = append(encoded, attr)
+	}
+	sort.Slice(encoded, func(i, j int) bool { return bytes.Compare(encoded[i], encoded[j]) < 0 })
+
+	return asn1.Marshal(asn1.RawValue{Class: asn1.ClassUniversal, Tag: asn1.TagSet, IsCompound: true, Bytes: bytes.Join(encoded, nil)})
+}
+
+// setHeaderLength returns the length of the tag and length octets of a DER value
+func setHeaderLength(der []byte) int {
+	if der[1] < 0x80 {
+		return 2
+	}
+	return 2 + int(der[1]&0x7f)
+}
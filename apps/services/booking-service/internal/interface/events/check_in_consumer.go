@@ -0,0 +1,72 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+
+	natsgo "github.com/nats-io/nats.go"
+
+	"github.com/yousoon/apps/services/booking-service/internal/application/commands"
+	"github.com/yousoon/shared/infrastructure/nats"
+)
+
+const (
+	checkInSubject          = "yousoon.events.outing.checked_in"
+	anomalyDetectorConsumer = "booking-anomaly-detector"
+)
+
+// =============================================================================
+// CHECK-IN CONSUMER
+// =============================================================================
+
+// CheckInConsumer screens every check-in relayed from the outbox for
+// anomalies. It reads a durable consumer so check-ins made while no instance
+// runs are screened once one is back, each by a single instance.
+type CheckInConsumer struct {
+	subscriber *nats.Subscriber
+	stream     string
+	detect     *commands.DetectCheckInAnomaliesHandler
+}
+
+func NewCheckInConsumer(subscriber *nats.Subscriber, stream string, detect *commands.DetectCheckInAnomaliesHandler) *CheckInConsumer {
+	return &CheckInConsumer{
+		subscriber: subscriber,
+		stream:     stream,
+		detect:     detect,
+	}
+}
+
+// Start subscribes to check-in events until ctx is done
+func (c *CheckInConsumer) Start(ctx context.Context) error {
+	cfg := nats.DefaultSubscribeConfig(c.stream, anomalyDetectorConsumer, checkInSubject, c.handle)
+	if err := c.subscriber.Subscribe(ctx, cfg); err != nil {
+		return fmt.Errorf("failed to subscribe to check-in events: %w", err)
+	}
+	return nil
+}
+
+// checkInEvent is the part of the published event envelope the consumer needs
+type checkInEvent struct {
+	EventType   string `json:"event_type"`
+	AggregateID string `json:"aggregate_id"`
+}
+
+func (c *CheckInConsumer) handle(ctx context.Context, msg *natsgo.Msg) error {
+	var event checkInEvent
+	if err := json.Unmarshal(msg.Data, &event); err != nil {
+		// Redelivering a malformed event cannot succeed
+		log.Printf("warning: dropping malformed check-in event: %v", err)
+		return nil
+	}
+
+	result, err := c.detect.Handle(ctx, commands.DetectCheckInAnomaliesCommand{OutingID: event.AggregateID})
+	if err != nil {
+		return fmt.Errorf("failed to screen check-in of outing %s: %w", event.AggregateID, err)
+	}
+	if len(result.Flagged) > 0 {
+		log.Printf("Outing %s flagged for review: %v", event.AggregateID, result.Flagged)
+	}
+	return nil
+}
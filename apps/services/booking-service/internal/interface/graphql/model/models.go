@@ -33,10 +33,35 @@ func (s OutingStatus) String() string {
 type CheckInMethod string
 
 const (
-	CheckInMethodQRScan CheckInMethod = "QR_SCAN"
-	CheckInMethodManual CheckInMethod = "MANUAL"
+	CheckInMethodQRScan      CheckInMethod = "QR_SCAN"
+	CheckInMethodManual      CheckInMethod = "MANUAL"
+	CheckInMethodOfflineScan CheckInMethod = "OFFLINE_SCAN"
+	CheckInMethodShortCode   CheckInMethod = "SHORT_CODE"
 )
 
+type AnomalyReason string
+
+const (
+	AnomalyReasonOutsideGeofence     AnomalyReason = "OUTSIDE_GEOFENCE"
+	AnomalyReasonLocationMissing     AnomalyReason = "LOCATION_MISSING"
+	AnomalyReasonStaffCheckInBurst   AnomalyReason = "STAFF_CHECK_IN_BURST"
+	AnomalyReasonOutsideOpeningHours AnomalyReason = "OUTSIDE_OPENING_HOURS"
+	AnomalyReasonImpossibleTravel    AnomalyReason = "IMPOSSIBLE_TRAVEL"
+)
+
+func (r AnomalyReason) IsValid() bool {
+	switch r {
+	case AnomalyReasonOutsideGeofence, AnomalyReasonLocationMissing, AnomalyReasonStaffCheckInBurst,
+		AnomalyReasonOutsideOpeningHours, AnomalyReasonImpossibleTravel:
+		return true
+	}
+	return false
+}
+
+func (r AnomalyReason) String() string {
+	return string(r)
+}
+
 type CancellationActor string
 
 const (
@@ -45,28 +70,66 @@ const (
 	CancellationActorSystem  CancellationActor = "SYSTEM"
 )
 
+type StatsGranularity string
+
+const (
+	StatsGranularityDay   StatsGranularity = "DAY"
+	StatsGranularityWeek  StatsGranularity = "WEEK"
+	StatsGranularityMonth StatsGranularity = "MONTH"
+)
+
+func (g StatsGranularity) IsValid() bool {
+	switch g {
+	case StatsGranularityDay, StatsGranularityWeek, StatsGranularityMonth:
+		return true
+	}
+	return false
+}
+
+func (g StatsGranularity) String() string {
+	return string(g)
+}
+
 type BookingErrorCode string
 
 const (
-	BookingErrorCodeOfferNotFound        BookingErrorCode = "OFFER_NOT_FOUND"
-	BookingErrorCodeOfferNotAvailable    BookingErrorCode = "OFFER_NOT_AVAILABLE"
-	BookingErrorCodeOfferQuotaExceeded   BookingErrorCode = "OFFER_QUOTA_EXCEEDED"
-	BookingErrorCodeUserNotVerified      BookingErrorCode = "USER_NOT_VERIFIED"
-	BookingErrorCodeUserQuotaExceeded    BookingErrorCode = "USER_QUOTA_EXCEEDED"
-	BookingErrorCodeAlreadyBooked        BookingErrorCode = "ALREADY_BOOKED"
-	BookingErrorCodeSubscriptionRequired BookingErrorCode = "SUBSCRIPTION_REQUIRED"
-	BookingErrorCodeInternalError        BookingErrorCode = "INTERNAL_ERROR"
+	BookingErrorCodeOfferNotFound         BookingErrorCode = "OFFER_NOT_FOUND"
+	BookingErrorCodeOfferNotAvailable     BookingErrorCode = "OFFER_NOT_AVAILABLE"
+	BookingErrorCodeOfferQuotaExceeded    BookingErrorCode = "OFFER_QUOTA_EXCEEDED"
+	BookingErrorCodeUserNotVerified       BookingErrorCode = "USER_NOT_VERIFIED"
+	BookingErrorCodeUserQuotaExceeded     BookingErrorCode = "USER_QUOTA_EXCEEDED"
+	BookingErrorCodeAlreadyBooked         BookingErrorCode = "ALREADY_BOOKED"
+	BookingErrorCodeSubscriptionRequired  BookingErrorCode = "SUBSCRIPTION_REQUIRED"
+	BookingErrorCodeSlotNotAvailable      BookingErrorCode = "SLOT_NOT_AVAILABLE"
+	BookingErrorCodeSlotFull              BookingErrorCode = "SLOT_FULL"
+	BookingErrorCodeUserBanned            BookingErrorCode = "USER_BANNED"
+	BookingErrorCodeInvalidIdempotencyKey BookingErrorCode = "INVALID_IDEMPOTENCY_KEY"
+	BookingErrorCodeIdempotencyKeyReused  BookingErrorCode = "IDEMPOTENCY_KEY_REUSED"
+	BookingErrorCodeRequestInProgress     BookingErrorCode = "REQUEST_IN_PROGRESS"
+	BookingErrorCodeInternalError         BookingErrorCode = "INTERNAL_ERROR"
 )
 
 type CheckInErrorCode string
 
 const (
-	CheckInErrorCodeOutingNotFound   CheckInErrorCode = "OUTING_NOT_FOUND"
-	CheckInErrorCodeInvalidQRCode    CheckInErrorCode = "INVALID_QR_CODE"
-	CheckInErrorCodeOutingExpired    CheckInErrorCode = "OUTING_EXPIRED"
-	CheckInErrorCodeAlreadyCheckedIn CheckInErrorCode = "ALREADY_CHECKED_IN"
-	CheckInErrorCodeOutingCancelled  CheckInErrorCode = "OUTING_CANCELLED"
-	CheckInErrorCodeInternalError    CheckInErrorCode = "INTERNAL_ERROR"
+	CheckInErrorCodeOutingNotFound        CheckInErrorCode = "OUTING_NOT_FOUND"
+	CheckInErrorCodeInvalidQRCode         CheckInErrorCode = "INVALID_QR_CODE"
+	CheckInErrorCodeQRCodeStale           CheckInErrorCode = "QR_CODE_STALE"
+	CheckInErrorCodeOutingExpired         CheckInErrorCode = "OUTING_EXPIRED"
+	CheckInErrorCodeAlreadyCheckedIn      CheckInErrorCode = "ALREADY_CHECKED_IN"
+	CheckInErrorCodeOutingCancelled       CheckInErrorCode = "OUTING_CANCELLED"
+	CheckInErrorCodeCheckInNotOpen        CheckInErrorCode = "CHECK_IN_NOT_OPEN"
+	CheckInErrorCodeOutsideGeofence       CheckInErrorCode = "OUTSIDE_GEOFENCE"
+	CheckInErrorCodeAwaitingConfirmation  CheckInErrorCode = "AWAITING_CONFIRMATION"
+	CheckInErrorCodeInvalidShortCode      CheckInErrorCode = "INVALID_SHORT_CODE"
+	CheckInErrorCodeTooManyAttempts       CheckInErrorCode = "TOO_MANY_ATTEMPTS"
+	CheckInErrorCodeNotTeamMember         CheckInErrorCode = "NOT_TEAM_MEMBER"
+	CheckInErrorCodeInvalidBillAmount     CheckInErrorCode = "INVALID_BILL_AMOUNT"
+	CheckInErrorCodeNotCheckedIn          CheckInErrorCode = "NOT_CHECKED_IN"
+	CheckInErrorCodeInvalidIdempotencyKey CheckInErrorCode = "INVALID_IDEMPOTENCY_KEY"
+	CheckInErrorCodeIdempotencyKeyReused  CheckInErrorCode = "IDEMPOTENCY_KEY_REUSED"
+	CheckInErrorCodeRequestInProgress     CheckInErrorCode = "REQUEST_IN_PROGRESS"
+	CheckInErrorCodeInternalError         CheckInErrorCode = "INTERNAL_ERROR"
 )
 
 type CancellationErrorCode string
@@ -78,6 +141,89 @@ const (
 	CancellationErrorCodeInternalError    CancellationErrorCode = "INTERNAL_ERROR"
 )
 
+type BookingDecisionErrorCode string
+
+const (
+	BookingDecisionErrorCodeOutingNotFound       BookingDecisionErrorCode = "OUTING_NOT_FOUND"
+	BookingDecisionErrorCodeNotPending           BookingDecisionErrorCode = "NOT_PENDING"
+	BookingDecisionErrorCodeConfirmationTimedOut BookingDecisionErrorCode = "CONFIRMATION_TIMED_OUT"
+	BookingDecisionErrorCodeNotTeamMember        BookingDecisionErrorCode = "NOT_TEAM_MEMBER"
+	BookingDecisionErrorCodeInternalError        BookingDecisionErrorCode = "INTERNAL_ERROR"
+)
+
+type TransferErrorCode string
+
+const (
+	TransferErrorCodeOutingNotFound        TransferErrorCode = "OUTING_NOT_FOUND"
+	TransferErrorCodeRecipientNotFound     TransferErrorCode = "RECIPIENT_NOT_FOUND"
+	TransferErrorCodeNotOwner              TransferErrorCode = "NOT_OWNER"
+	TransferErrorCodeTransferToSelf        TransferErrorCode = "TRANSFER_TO_SELF"
+	TransferErrorCodeNoPendingTransfer     TransferErrorCode = "NO_PENDING_TRANSFER"
+	TransferErrorCodeNotRecipient          TransferErrorCode = "NOT_RECIPIENT"
+	TransferErrorCodeOutingNotTransferable TransferErrorCode = "OUTING_NOT_TRANSFERABLE"
+	TransferErrorCodeRecipientNotEligible  TransferErrorCode = "RECIPIENT_NOT_ELIGIBLE"
+	TransferErrorCodeAlreadyBooked         TransferErrorCode = "ALREADY_BOOKED"
+	TransferErrorCodeUserQuotaExceeded     TransferErrorCode = "USER_QUOTA_EXCEEDED"
+	TransferErrorCodeInternalError         TransferErrorCode = "INTERNAL_ERROR"
+)
+
+type OfflineCheckInStatus string
+
+const (
+	OfflineCheckInStatusApplied  OfflineCheckInStatus = "APPLIED"
+	OfflineCheckInStatusConflict OfflineCheckInStatus = "CONFLICT"
+)
+
+type OfflineCheckInConflict string
+
+const (
+	OfflineCheckInConflictAlreadyUsed          OfflineCheckInConflict = "ALREADY_USED"
+	OfflineCheckInConflictCancelled            OfflineCheckInConflict = "CANCELLED"
+	OfflineCheckInConflictExpired              OfflineCheckInConflict = "EXPIRED"
+	OfflineCheckInConflictOutsideGeofence      OfflineCheckInConflict = "OUTSIDE_GEOFENCE"
+	OfflineCheckInConflictAwaitingConfirmation OfflineCheckInConflict = "AWAITING_CONFIRMATION"
+	OfflineCheckInConflictNotTeamMember        OfflineCheckInConflict = "NOT_TEAM_MEMBER"
+	OfflineCheckInConflictInvalid              OfflineCheckInConflict = "INVALID"
+)
+
+type OutingUpdateType string
+
+const (
+	OutingUpdateTypeBooked    OutingUpdateType = "BOOKED"
+	OutingUpdateTypeConfirmed OutingUpdateType = "CONFIRMED"
+	OutingUpdateTypeRejected  OutingUpdateType = "REJECTED"
+	OutingUpdateTypeCheckedIn OutingUpdateType = "CHECKED_IN"
+	OutingUpdateTypeCancelled OutingUpdateType = "CANCELLED"
+	OutingUpdateTypeExpired   OutingUpdateType = "EXPIRED"
+	OutingUpdateTypeNoShow    OutingUpdateType = "NO_SHOW"
+	OutingUpdateTypeUpdated   OutingUpdateType = "UPDATED"
+)
+
+type WaitlistStatus string
+
+const (
+	WaitlistStatusWaiting   WaitlistStatus = "WAITING"
+	WaitlistStatusHeld      WaitlistStatus = "HELD"
+	WaitlistStatusConverted WaitlistStatus = "CONVERTED"
+	WaitlistStatusExpired   WaitlistStatus = "EXPIRED"
+	WaitlistStatusLeft      WaitlistStatus = "LEFT"
+	WaitlistStatusRemoved   WaitlistStatus = "REMOVED"
+)
+
+type WaitlistErrorCode string
+
+const (
+	WaitlistErrorCodeAlreadyOnWaitlist WaitlistErrorCode = "ALREADY_ON_WAITLIST"
+	WaitlistErrorCodeAlreadyBooked     WaitlistErrorCode = "ALREADY_BOOKED"
+	WaitlistErrorCodeEntryNotFound     WaitlistErrorCode = "ENTRY_NOT_FOUND"
+	WaitlistErrorCodeHoldNotActive     WaitlistErrorCode = "HOLD_NOT_ACTIVE"
+	WaitlistErrorCodeHoldExpired       WaitlistErrorCode = "HOLD_EXPIRED"
+	WaitlistErrorCodeEntryChanged      WaitlistErrorCode = "ENTRY_CHANGED"
+	WaitlistErrorCodeSlotNotAvailable  WaitlistErrorCode = "SLOT_NOT_AVAILABLE"
+	WaitlistErrorCodeUserBanned        WaitlistErrorCode = "USER_BANNED"
+	WaitlistErrorCodeInternalError     WaitlistErrorCode = "INTERNAL_ERROR"
+)
+
 // =============================================================================
 // TYPES
 // =============================================================================
@@ -86,11 +232,15 @@ type Outing struct {
 	ID            string            `json:"id"`
 	UserID        string            `json:"userId"`
 	OfferSnapshot *OfferSnapshot    `json:"offerSnapshot"`
-	QRCode        *QRCodeInfo       `json:"qrCode"`
+	Slot          *BookedSlot       `json:"slot,omitempty"`
+	QRCode        *QRCodeInfo       `json:"qrCode,omitempty"`
+	ShortCode     *string           `json:"shortCode,omitempty"`
 	Status        OutingStatus      `json:"status"`
 	Timeline      []*TimelineEntry  `json:"timeline"`
 	CheckIn       *CheckInInfo      `json:"checkIn,omitempty"`
 	Cancellation  *CancellationInfo `json:"cancellation,omitempty"`
+	ConfirmBy     *time.Time        `json:"confirmBy,omitempty"`
+	Transfer      *OutingTransfer   `json:"transfer,omitempty"`
 	BookedAt      time.Time         `json:"bookedAt"`
 	ExpiresAt     time.Time         `json:"expiresAt"`
 	CreatedAt     time.Time         `json:"createdAt"`
@@ -114,9 +264,28 @@ type OfferSnapshot struct {
 	CapturedAt           time.Time `json:"capturedAt"`
 }
 
+type BookedSlot struct {
+	Date      string    `json:"date"`
+	StartTime string    `json:"startTime"`
+	EndTime   string    `json:"endTime"`
+	StartsAt  time.Time `json:"startsAt"`
+	EndsAt    time.Time `json:"endsAt"`
+}
+
+type SlotAvailability struct {
+	Date      string    `json:"date"`
+	StartTime string    `json:"startTime"`
+	EndTime   string    `json:"endTime"`
+	StartsAt  time.Time `json:"startsAt"`
+	EndsAt    time.Time `json:"endsAt"`
+	Capacity  *int      `json:"capacity,omitempty"`
+	Remaining *int      `json:"remaining,omitempty"`
+}
+
 type QRCodeInfo struct {
 	Code      string    `json:"code"`
 	FullCode  string    `json:"fullCode"`
+	RefreshAt time.Time `json:"refreshAt"`
 	ExpiresAt time.Time `json:"expiresAt"`
 	IsExpired bool      `json:"isExpired"`
 }
@@ -134,21 +303,115 @@ type CheckInInfo struct {
 	Method      CheckInMethod `json:"method"`
 	Latitude    *float64      `json:"latitude,omitempty"`
 	Longitude   *float64      `json:"longitude,omitempty"`
+
+	DistanceMeters   *float64 `json:"distanceMeters,omitempty"`
+	FlaggedForReview bool     `json:"flaggedForReview"`
+	ReviewReason     *string  `json:"reviewReason,omitempty"`
+	Bill             *Bill    `json:"bill,omitempty"`
+
+	Anomalies []*CheckInAnomaly `json:"anomalies"`
+}
+
+type CheckInAnomaly struct {
+	Reason     AnomalyReason `json:"reason"`
+	Detail     *string       `json:"detail,omitempty"`
+	DetectedAt time.Time     `json:"detectedAt"`
+}
+
+type Bill struct {
+	AmountCents      int       `json:"amountCents"`
+	SavingsCents     int       `json:"savingsCents"`
+	FinalAmountCents int       `json:"finalAmountCents"`
+	RecordedAt       time.Time `json:"recordedAt"`
+	RecordedBy       string    `json:"recordedBy"`
 }
 
 type CancellationInfo struct {
 	CancelledAt time.Time         `json:"cancelledAt"`
 	CancelledBy CancellationActor `json:"cancelledBy"`
 	Reason      *string           `json:"reason,omitempty"`
+	Late        bool              `json:"late"`
+}
+
+type OutingTransfer struct {
+	FromUserID  string    `json:"fromUserId"`
+	ToUserID    string    `json:"toUserId"`
+	InitiatedAt time.Time `json:"initiatedAt"`
 }
 
 type BookingStats struct {
-	TotalBookings      int     `json:"totalBookings"`
-	TotalCheckIns      int     `json:"totalCheckIns"`
-	TotalCancelled     int     `json:"totalCancelled"`
-	TotalExpired       int     `json:"totalExpired"`
-	ConversionRate     float64 `json:"conversionRate"`
-	AverageCheckInTime float64 `json:"averageCheckInTime"`
+	TotalBookings      int                   `json:"totalBookings"`
+	TotalCheckIns      int                   `json:"totalCheckIns"`
+	TotalCancelled     int                   `json:"totalCancelled"`
+	TotalExpired       int                   `json:"totalExpired"`
+	TotalNoShow        int                   `json:"totalNoShow"`
+	ConversionRate     float64               `json:"conversionRate"`
+	AverageCheckInTime float64               `json:"averageCheckInTime"`
+	TotalBilled        int                   `json:"totalBilled"`
+	TotalBillCents     int                   `json:"totalBillCents"`
+	TotalSavingsCents  int                   `json:"totalSavingsCents"`
+	Series             []*BookingStatsBucket `json:"series"`
+}
+
+type BookingStatsBucket struct {
+	PeriodStart        time.Time `json:"periodStart"`
+	TotalBookings      int       `json:"totalBookings"`
+	TotalCheckIns      int       `json:"totalCheckIns"`
+	TotalCancelled     int       `json:"totalCancelled"`
+	TotalExpired       int       `json:"totalExpired"`
+	TotalNoShow        int       `json:"totalNoShow"`
+	ConversionRate     float64   `json:"conversionRate"`
+	AverageCheckInTime float64   `json:"averageCheckInTime"`
+	TotalBilled        int       `json:"totalBilled"`
+	TotalBillCents     int       `json:"totalBillCents"`
+	TotalSavingsCents  int       `json:"totalSavingsCents"`
+}
+
+type Savings struct {
+	TotalOutings      int                `json:"totalOutings"`
+	TotalBillCents    int                `json:"totalBillCents"`
+	TotalSavingsCents int                `json:"totalSavingsCents"`
+	ByMonth           []*MonthlySavings  `json:"byMonth"`
+	ByCategory        []*CategorySavings `json:"byCategory"`
+}
+
+type MonthlySavings struct {
+	MonthStart   time.Time `json:"monthStart"`
+	Outings      int       `json:"outings"`
+	BillCents    int       `json:"billCents"`
+	SavingsCents int       `json:"savingsCents"`
+}
+
+type CategorySavings struct {
+	Category     string `json:"category"`
+	Outings      int    `json:"outings"`
+	BillCents    int    `json:"billCents"`
+	SavingsCents int    `json:"savingsCents"`
+}
+
+type WaitlistEntry struct {
+	ID            string         `json:"id"`
+	OfferID       string         `json:"offerId"`
+	UserID        string         `json:"userId"`
+	Slot          *BookedSlot    `json:"slot,omitempty"`
+	Status        WaitlistStatus `json:"status"`
+	Position      *int           `json:"position,omitempty"`
+	HoldExpiresAt *time.Time     `json:"holdExpiresAt,omitempty"`
+	OutingID      *string        `json:"outingId,omitempty"`
+	JoinedAt      time.Time      `json:"joinedAt"`
+}
+
+type OutingUpdate struct {
+	Type       OutingUpdateType `json:"type"`
+	Outing     *Outing          `json:"outing"`
+	OccurredAt time.Time        `json:"occurredAt"`
+}
+
+type OutingPassLinks struct {
+	CalendarURL     string    `json:"calendarUrl"`
+	AppleWalletURL  *string   `json:"appleWalletUrl,omitempty"`
+	GoogleWalletURL *string   `json:"googleWalletUrl,omitempty"`
+	ExpiresAt       time.Time `json:"expiresAt"`
 }
 
 // =============================================================================
@@ -178,19 +441,59 @@ type PageInfo struct {
 // =============================================================================
 
 type BookOfferInput struct {
-	OfferID string `json:"offerId"`
+	OfferID        string     `json:"offerId"`
+	Slot           *SlotInput `json:"slot,omitempty"`
+	IdempotencyKey *string    `json:"idempotencyKey,omitempty"`
+}
+
+type SlotInput struct {
+	Date      string `json:"date"`
+	StartTime string `json:"startTime"`
 }
 
 type CheckInInput struct {
-	QRCode    string   `json:"qrCode"`
-	Latitude  *float64 `json:"latitude,omitempty"`
-	Longitude *float64 `json:"longitude,omitempty"`
+	QRCode          string   `json:"qrCode"`
+	Latitude        *float64 `json:"latitude,omitempty"`
+	Longitude       *float64 `json:"longitude,omitempty"`
+	BillAmountCents *int     `json:"billAmountCents,omitempty"`
+	IdempotencyKey  *string  `json:"idempotencyKey,omitempty"`
+}
+
+type ShortCodeCheckInInput struct {
+	EstablishmentID string   `json:"establishmentId"`
+	ShortCode       string   `json:"shortCode"`
+	Latitude        *float64 `json:"latitude,omitempty"`
+	Longitude       *float64 `json:"longitude,omitempty"`
+	BillAmountCents *int     `json:"billAmountCents,omitempty"`
+	IdempotencyKey  *string  `json:"idempotencyKey,omitempty"`
 }
 
 type ManualCheckInInput struct {
-	OutingID  string   `json:"outingId"`
-	Latitude  *float64 `json:"latitude,omitempty"`
-	Longitude *float64 `json:"longitude,omitempty"`
+	OutingID        string   `json:"outingId"`
+	Latitude        *float64 `json:"latitude,omitempty"`
+	Longitude       *float64 `json:"longitude,omitempty"`
+	BillAmountCents *int     `json:"billAmountCents,omitempty"`
+}
+
+type RecordOutingBillInput struct {
+	OutingID        string `json:"outingId"`
+	BillAmountCents int    `json:"billAmountCents"`
+}
+
+type OfflineScanInput struct {
+	QRCode    string    `json:"qrCode"`
+	ScannedAt time.Time `json:"scannedAt"`
+	Latitude  *float64  `json:"latitude,omitempty"`
+	Longitude *float64  `json:"longitude,omitempty"`
+}
+
+type SyncOfflineCheckInsInput struct {
+	Scans []*OfflineScanInput `json:"scans"`
+}
+
+type JoinWaitlistInput struct {
+	OfferID string     `json:"offerId"`
+	Slot    *SlotInput `json:"slot,omitempty"`
 }
 
 type CancelOutingInput struct {
@@ -198,6 +501,17 @@ type CancelOutingInput struct {
 	Reason   *string `json:"reason,omitempty"`
 }
 
+type RejectOutingInput struct {
+	OutingID string  `json:"outingId"`
+	Reason   *string `json:"reason,omitempty"`
+}
+
+type TransferOutingInput struct {
+	OutingID        string  `json:"outingId"`
+	RecipientUserID *string `json:"recipientUserId,omitempty"`
+	RecipientEmail  *string `json:"recipientEmail,omitempty"`
+}
+
 type OutingFilterInput struct {
 	Status    []OutingStatus `json:"status,omitempty"`
 	StartDate *time.Time     `json:"startDate,omitempty"`
@@ -227,12 +541,44 @@ type CheckInPayload struct {
 	Error   *CheckInError `json:"error,omitempty"`
 }
 
+type OfflineCheckInResult struct {
+	QRCode   string                  `json:"qrCode"`
+	OutingID *string                 `json:"outingId,omitempty"`
+	Status   OfflineCheckInStatus    `json:"status"`
+	Conflict *OfflineCheckInConflict `json:"conflict,omitempty"`
+	Outing   *Outing                 `json:"outing,omitempty"`
+}
+
+type SyncOfflineCheckInsPayload struct {
+	Results       []*OfflineCheckInResult `json:"results"`
+	AppliedCount  int                     `json:"appliedCount"`
+	ConflictCount int                     `json:"conflictCount"`
+}
+
 type CancelOutingPayload struct {
 	Success bool               `json:"success"`
 	Outing  *Outing            `json:"outing,omitempty"`
 	Error   *CancellationError `json:"error,omitempty"`
 }
 
+type BookingDecisionPayload struct {
+	Success bool                  `json:"success"`
+	Outing  *Outing               `json:"outing,omitempty"`
+	Error   *BookingDecisionError `json:"error,omitempty"`
+}
+
+type TransferPayload struct {
+	Success bool           `json:"success"`
+	Outing  *Outing        `json:"outing,omitempty"`
+	Error   *TransferError `json:"error,omitempty"`
+}
+
+type WaitlistPayload struct {
+	Success bool           `json:"success"`
+	Entry   *WaitlistEntry `json:"entry,omitempty"`
+	Error   *WaitlistError `json:"error,omitempty"`
+}
+
 type BookingError struct {
 	Code    BookingErrorCode `json:"code"`
 	Message string           `json:"message"`
@@ -248,6 +594,21 @@ type CancellationError struct {
 	Message string                `json:"message"`
 }
 
+type BookingDecisionError struct {
+	Code    BookingDecisionErrorCode `json:"code"`
+	Message string                   `json:"message"`
+}
+
+type TransferError struct {
+	Code    TransferErrorCode `json:"code"`
+	Message string            `json:"message"`
+}
+
+type WaitlistError struct {
+	Code    WaitlistErrorCode `json:"code"`
+	Message string            `json:"message"`
+}
+
 // =============================================================================
 // FEDERATION TYPES
 // =============================================================================
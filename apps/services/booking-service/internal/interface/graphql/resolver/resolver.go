@@ -2,14 +2,18 @@ package resolver
 
 import (
 	"context"
-	"encoding/base64"
+	"errors"
 	"fmt"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/yousoon/apps/services/booking-service/internal/application/commands"
 	"github.com/yousoon/apps/services/booking-service/internal/application/queries"
 	"github.com/yousoon/apps/services/booking-service/internal/domain"
+	"github.com/yousoon/apps/services/booking-service/internal/infrastructure/wallet"
 	"github.com/yousoon/apps/services/booking-service/internal/interface/graphql/model"
+	"github.com/yousoon/apps/services/booking-service/internal/interface/rest"
 )
 
 // =============================================================================
@@ -18,40 +22,97 @@ import (
 
 type Resolver struct {
 	// Command handlers
-	bookOutingHandler   *commands.BookOutingHandler
-	checkInHandler      *commands.CheckInOutingHandler
-	cancelOutingHandler *commands.CancelOutingHandler
+	bookOutingHandler           *commands.BookOutingHandler
+	checkInHandler              *commands.CheckInOutingHandler
+	syncOfflineCheckInsHandler  *commands.SyncOfflineCheckInsHandler
+	recordOutingBillHandler     *commands.RecordOutingBillHandler
+	cancelOutingHandler         *commands.CancelOutingHandler
+	confirmOutingHandler        *commands.ConfirmOutingHandler
+	rejectOutingHandler         *commands.RejectOutingHandler
+	transferOutingHandler       *commands.TransferOutingHandler
+	acceptOutingTransferHandler *commands.AcceptOutingTransferHandler
+	cancelOutingTransferHandler *commands.CancelOutingTransferHandler
+	joinWaitlistHandler         *commands.JoinWaitlistHandler
+	leaveWaitlistHandler        *commands.LeaveWaitlistHandler
+	acceptWaitlistHoldHandler   *commands.AcceptWaitlistHoldHandler
 
 	// Query handlers
-	getOutingHandler                *queries.GetOutingHandler
-	getOutingByQRHandler            *queries.GetOutingByQRHandler
-	listUserOutingsHandler          *queries.ListUserOutingsHandler
-	listPartnerOutingsHandler       *queries.ListPartnerOutingsHandler
-	listEstablishmentOutingsHandler *queries.ListEstablishmentOutingsHandler
-	getBookingStatsHandler          *queries.GetBookingStatsHandler
+	getOutingHandler                 *queries.GetOutingHandler
+	getOutingByQRHandler             *queries.GetOutingByQRHandler
+	listUserOutingsHandler           *queries.ListUserOutingsHandler
+	listPartnerOutingsHandler        *queries.ListPartnerOutingsHandler
+	listEstablishmentOutingsHandler  *queries.ListEstablishmentOutingsHandler
+	listFlaggedOutingsHandler        *queries.ListFlaggedOutingsHandler
+	getBookingStatsHandler           *queries.GetBookingStatsHandler
+	getUserSavingsHandler            *queries.GetUserSavingsHandler
+	getSlotAvailabilityHandler       *queries.GetSlotAvailabilityHandler
+	listUserWaitlistHandler          *queries.ListUserWaitlistHandler
+	listIncomingTransfersHandler     *queries.ListIncomingTransfersHandler
+	watchEstablishmentOutingsHandler *queries.WatchEstablishmentOutingsHandler
+	passLinks                        *rest.PassLinks
+	googleWallet                     *wallet.GoogleWalletIssuer
+	checkInKeys                      *domain.CheckInKeys
 }
 
 func NewResolver(
 	bookOutingHandler *commands.BookOutingHandler,
 	checkInHandler *commands.CheckInOutingHandler,
+	syncOfflineCheckInsHandler *commands.SyncOfflineCheckInsHandler,
+	recordOutingBillHandler *commands.RecordOutingBillHandler,
 	cancelOutingHandler *commands.CancelOutingHandler,
+	confirmOutingHandler *commands.ConfirmOutingHandler,
+	rejectOutingHandler *commands.RejectOutingHandler,
+	transferOutingHandler *commands.TransferOutingHandler,
+	acceptOutingTransferHandler *commands.AcceptOutingTransferHandler,
+	cancelOutingTransferHandler *commands.CancelOutingTransferHandler,
+	joinWaitlistHandler *commands.JoinWaitlistHandler,
+	leaveWaitlistHandler *commands.LeaveWaitlistHandler,
+	acceptWaitlistHoldHandler *commands.AcceptWaitlistHoldHandler,
 	getOutingHandler *queries.GetOutingHandler,
 	getOutingByQRHandler *queries.GetOutingByQRHandler,
 	listUserOutingsHandler *queries.ListUserOutingsHandler,
 	listPartnerOutingsHandler *queries.ListPartnerOutingsHandler,
 	listEstablishmentOutingsHandler *queries.ListEstablishmentOutingsHandler,
+	listFlaggedOutingsHandler *queries.ListFlaggedOutingsHandler,
 	getBookingStatsHandler *queries.GetBookingStatsHandler,
+	getUserSavingsHandler *queries.GetUserSavingsHandler,
+	getSlotAvailabilityHandler *queries.GetSlotAvailabilityHandler,
+	listUserWaitlistHandler *queries.ListUserWaitlistHandler,
+	listIncomingTransfersHandler *queries.ListIncomingTransfersHandler,
+	watchEstablishmentOutingsHandler *queries.WatchEstablishmentOutingsHandler,
+	passLinks *rest.PassLinks,
+	googleWallet *wallet.GoogleWalletIssuer,
+	checkInKeys *domain.CheckInKeys,
 ) *Resolver {
 	return &Resolver{
-		bookOutingHandler:               bookOutingHandler,
-		checkInHandler:                  checkInHandler,
-		cancelOutingHandler:             cancelOutingHandler,
-		getOutingHandler:                getOutingHandler,
-		getOutingByQRHandler:            getOutingByQRHandler,
-		listUserOutingsHandler:          listUserOutingsHandler,
-		listPartnerOutingsHandler:       listPartnerOutingsHandler,
-		listEstablishmentOutingsHandler: listEstablishmentOutingsHandler,
-		getBookingStatsHandler:          getBookingStatsHandler,
+		bookOutingHandler:                bookOutingHandler,
+		checkInHandler:                   checkInHandler,
+		syncOfflineCheckInsHandler:       syncOfflineCheckInsHandler,
+		recordOutingBillHandler:          recordOutingBillHandler,
+		cancelOutingHandler:              cancelOutingHandler,
+		confirmOutingHandler:             confirmOutingHandler,
+		rejectOutingHandler:              rejectOutingHandler,
+		transferOutingHandler:            transferOutingHandler,
+		acceptOutingTransferHandler:      acceptOutingTransferHandler,
+		cancelOutingTransferHandler:      cancelOutingTransferHandler,
+		joinWaitlistHandler:              joinWaitlistHandler,
+		leaveWaitlistHandler:             leaveWaitlistHandler,
+		acceptWaitlistHoldHandler:        acceptWaitlistHoldHandler,
+		getOutingHandler:                 getOutingHandler,
+		getOutingByQRHandler:             getOutingByQRHandler,
+		listUserOutingsHandler:           listUserOutingsHandler,
+		listPartnerOutingsHandler:        listPartnerOutingsHandler,
+		listEstablishmentOutingsHandler:  listEstablishmentOutingsHandler,
+		listFlaggedOutingsHandler:        listFlaggedOutingsHandler,
+		getBookingStatsHandler:           getBookingStatsHandler,
+		getUserSavingsHandler:            getUserSavingsHandler,
+		getSlotAvailabilityHandler:       getSlotAvailabilityHandler,
+		listUserWaitlistHandler:          listUserWaitlistHandler,
+		listIncomingTransfersHandler:     listIncomingTransfersHandler,
+		watchEstablishmentOutingsHandler: watchEstablishmentOutingsHandler,
+		passLinks:                        passLinks,
+		googleWallet:                     googleWallet,
+		checkInKeys:                      checkInKeys,
 	}
 }
 
@@ -64,7 +125,7 @@ func (r *Resolver) Outing(ctx context.Context, id string) (*model.Outing, error)
 	if err != nil {
 		return nil, err
 	}
-	return mapOutingToModel(result.Outing), nil
+	return r.mapOutingToModel(ctx, result.Outing)
 }
 
 func (r *Resolver) OutingByQRCode(ctx context.Context, qrCode string) (*model.Outing, error) {
@@ -72,7 +133,7 @@ func (r *Resolver) OutingByQRCode(ctx context.Context, qrCode string) (*model.Ou
 	if err != nil {
 		return nil, err
 	}
-	return mapOutingToModel(result.Outing), nil
+	return r.mapOutingToModel(ctx, result.Outing)
 }
 
 func (r *Resolver) MyOutings(ctx context.Context, filter *model.OutingFilterInput, pagination *model.PaginationInput) (*model.OutingConnection, error) {
@@ -82,7 +143,10 @@ func (r *Resolver) MyOutings(ctx context.Context, filter *model.OutingFilterInpu
 		return nil, fmt.Errorf("unauthorized")
 	}
 
-	domainFilter := mapFilterToDomain(filter, pagination)
+	domainFilter, err := mapFilterToDomain(filter, pagination)
+	if err != nil {
+		return nil, err
+	}
 
 	result, err := r.listUserOutingsHandler.Handle(ctx, queries.ListUserOutingsQuery{
 		UserID: userID,
@@ -92,11 +156,14 @@ func (r *Resolver) MyOutings(ctx context.Context, filter *model.OutingFilterInpu
 		return nil, err
 	}
 
-	return buildOutingConnection(result.Outings, result.TotalCount, domainFilter), nil
+	return r.buildOutingConnection(ctx, result.Page, domainFilter.SortBy)
 }
 
 func (r *Resolver) PartnerOutings(ctx context.Context, partnerID string, filter *model.OutingFilterInput, pagination *model.PaginationInput) (*model.OutingConnection, error) {
-	domainFilter := mapFilterToDomain(filter, pagination)
+	domainFilter, err := mapFilterToDomain(filter, pagination)
+	if err != nil {
+		return nil, err
+	}
 
 	result, err := r.listPartnerOutingsHandler.Handle(ctx, queries.ListPartnerOutingsQuery{
 		PartnerID: partnerID,
@@ -106,11 +173,14 @@ func (r *Resolver) PartnerOutings(ctx context.Context, partnerID string, filter
 		return nil, err
 	}
 
-	return buildOutingConnection(result.Outings, result.TotalCount, domainFilter), nil
+	return r.buildOutingConnection(ctx, result.Page, domainFilter.SortBy)
 }
 
 func (r *Resolver) EstablishmentOutings(ctx context.Context, establishmentID string, filter *model.OutingFilterInput, pagination *model.PaginationInput) (*model.OutingConnection, error) {
-	domainFilter := mapFilterToDomain(filter, pagination)
+	domainFilter, err := mapFilterToDomain(filter, pagination)
+	if err != nil {
+		return nil, err
+	}
 
 	result, err := r.listEstablishmentOutingsHandler.Handle(ctx, queries.ListEstablishmentOutingsQuery{
 		EstablishmentID: establishmentID,
@@ -120,11 +190,46 @@ func (r *Resolver) EstablishmentOutings(ctx context.Context, establishmentID str
 		return nil, err
 	}
 
-	return buildOutingConnection(result.Outings, result.TotalCount, domainFilter), nil
+	return r.buildOutingConnection(ctx, result.Page, domainFilter.SortBy)
+}
+
+func (r *Resolver) FlaggedOutings(ctx context.Context, reason *model.AnomalyReason, partnerID, establishmentID *string, filter *model.OutingFilterInput, pagination *model.PaginationInput) (*model.OutingConnection, error) {
+	if !hasRole(ctx, "admin") {
+		return nil, fmt.Errorf("forbidden")
+	}
+
+	domainFilter, err := mapFilterToDomain(filter, pagination)
+	if err != nil {
+		return nil, err
+	}
+
+	var scope domain.FlaggedOutingScope
+	if reason != nil {
+		scope.Reason = domain.AnomalyReason(strings.ToLower(reason.String()))
+	}
+	if partnerID != nil {
+		scope.PartnerID = *partnerID
+	}
+	if establishmentID != nil {
+		scope.EstablishmentID = *establishmentID
+	}
+
+	result, err := r.listFlaggedOutingsHandler.Handle(ctx, queries.ListFlaggedOutingsQuery{
+		Scope:  scope,
+		Filter: domainFilter,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return r.buildOutingConnection(ctx, result.Page, domainFilter.SortBy)
 }
 
-func (r *Resolver) BookingStats(ctx context.Context, partnerID, establishmentID, offerID *string) (*model.BookingStats, error) {
-	query := queries.GetBookingStatsQuery{}
+func (r *Resolver) BookingStats(ctx context.Context, partnerID, establishmentID, offerID *string, startDate, endDate *time.Time, granularity *model.StatsGranularity, timezone *string) (*model.BookingStats, error) {
+	query := queries.GetBookingStatsQuery{
+		StartDate: startDate,
+		EndDate:   endDate,
+	}
 	if partnerID != nil {
 		query.PartnerID = *partnerID
 	}
@@ -134,20 +239,235 @@ func (r *Resolver) BookingStats(ctx context.Context, partnerID, establishmentID,
 	if offerID != nil {
 		query.OfferID = *offerID
 	}
+	if granularity != nil {
+		query.Granularity = domain.StatsGranularity(strings.ToLower(granularity.String()))
+	}
+	if timezone != nil {
+		query.Timezone = *timezone
+	}
 
 	result, err := r.getBookingStatsHandler.Handle(ctx, query)
 	if err != nil {
 		return nil, err
 	}
 
-	return &model.BookingStats{
+	stats := &model.BookingStats{
 		TotalBookings:      int(result.Stats.TotalBookings),
 		TotalCheckIns:      int(result.Stats.TotalCheckIns),
 		TotalCancelled:     int(result.Stats.TotalCancelled),
 		TotalExpired:       int(result.Stats.TotalExpired),
-		ConversionRate:     result.Stats.ConversionRate,
+		TotalNoShow:        int(result.Stats.TotalNoShow),
+		ConversionRate:     result.Stats.ConversionRate(),
 		AverageCheckInTime: result.Stats.AverageCheckInTime,
-	}, nil
+		TotalBilled:        int(result.Stats.TotalBilled),
+		TotalBillCents:     int(result.Stats.TotalBillCents),
+		TotalSavingsCents:  int(result.Stats.TotalSavingsCents),
+		Series:             make([]*model.BookingStatsBucket, 0, len(result.Stats.Series)),
+	}
+	for _, bucket := range result.Stats.Series {
+		stats.Series = append(stats.Series, &model.BookingStatsBucket{
+			PeriodStart:        bucket.PeriodStart,
+			TotalBookings:      int(bucket.TotalBookings),
+			TotalCheckIns:      int(bucket.TotalCheckIns),
+			TotalCancelled:     int(bucket.TotalCancelled),
+			TotalExpired:       int(bucket.TotalExpired),
+			TotalNoShow:        int(bucket.TotalNoShow),
+			ConversionRate:     bucket.ConversionRate(),
+			AverageCheckInTime: bucket.AverageCheckInTime,
+			TotalBilled:        int(bucket.TotalBilled),
+			TotalBillCents:     int(bucket.TotalBillCents),
+			TotalSavingsCents:  int(bucket.TotalSavingsCents),
+		})
+	}
+
+	return stats, nil
+}
+
+func (r *Resolver) MySavings(ctx context.Context, startDate, endDate *time.Time, timezone *string) (*model.Savings, error) {
+	userID := getUserIDFromContext(ctx)
+	if userID == "" {
+		return nil, fmt.Errorf("unauthorized")
+	}
+
+	query := queries.GetUserSavingsQuery{
+		UserID:    userID,
+		StartDate: startDate,
+		EndDate:   endDate,
+	}
+	if timezone != nil {
+		query.Timezone = *timezone
+	}
+
+	result, err := r.getUserSavingsHandler.Handle(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	savings := &model.Savings{
+		TotalOutings:      int(result.Savings.Outings),
+		TotalBillCents:    int(result.Savings.BillCents),
+		TotalSavingsCents: int(result.Savings.SavingsCents),
+		ByMonth:           make([]*model.MonthlySavings, 0, len(result.Savings.ByMonth)),
+		ByCategory:        make([]*model.CategorySavings, 0, len(result.Savings.ByCategory)),
+	}
+	for _, month := range result.Savings.ByMonth {
+		savings.ByMonth = append(savings.ByMonth, &model.MonthlySavings{
+			MonthStart:   month.MonthStart,
+			Outings:      int(month.Outings),
+			BillCents:    int(month.BillCents),
+			SavingsCents: int(month.SavingsCents),
+		})
+	}
+	for _, category := range result.Savings.ByCategory {
+		savings.ByCategory = append(savings.ByCategory, &model.CategorySavings{
+			Category:     category.Category,
+			Outings:      int(category.Outings),
+			BillCents:    int(category.BillCents),
+			SavingsCents: int(category.SavingsCents),
+		})
+	}
+
+	return savings, nil
+}
+
+func (r *Resolver) OfferSlotAvailability(ctx context.Context, offerID string, date string) ([]*model.SlotAvailability, error) {
+	result, err := r.getSlotAvailabilityHandler.Handle(ctx, queries.GetSlotAvailabilityQuery{
+		OfferID: offerID,
+		Date:    date,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	slots := make([]*model.SlotAvailability, 0, len(result.Slots))
+	for _, availability := range result.Slots {
+		slot := availability.Slot
+		slots = append(slots, &model.SlotAvailability{
+			Date:      slot.Date(),
+			StartTime: slot.StartTime(),
+			EndTime:   slot.EndTime(),
+			StartsAt:  slot.StartsAt(),
+			EndsAt:    slot.EndsAt(),
+			Capacity:  slot.Capacity(),
+			Remaining: availability.Remaining,
+		})
+	}
+
+	return slots, nil
+}
+
+func (r *Resolver) MyWaitlist(ctx context.Context) ([]*model.WaitlistEntry, error) {
+	userID := getUserIDFromContext(ctx)
+	if userID == "" {
+		return nil, fmt.Errorf("unauthorized")
+	}
+
+	result, err := r.listUserWaitlistHandler.Handle(ctx, queries.ListUserWaitlistQuery{UserID: userID})
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]*model.WaitlistEntry, 0, len(result.Entries))
+	for _, item := range result.Entries {
+		entries = append(entries, mapWaitlistEntryToModel(item.Entry, item.Position))
+	}
+
+	return entries, nil
+}
+
+func (r *Resolver) MyIncomingTransfers(ctx context.Context) ([]*model.Outing, error) {
+	userID := getUserIDFromContext(ctx)
+	if userID == "" {
+		return nil, fmt.Errorf("unauthorized")
+	}
+
+	result, err := r.listIncomingTransfersHandler.Handle(ctx, queries.ListIncomingTransfersQuery{UserID: userID})
+	if err != nil {
+		return nil, err
+	}
+
+	outings := make([]*model.Outing, 0, len(result.Outings))
+	for _, o := range result.Outings {
+		outing, err := r.mapOutingToModel(ctx, o)
+		if err != nil {
+			return nil, err
+		}
+		outings = append(outings, outing)
+	}
+
+	return outings, nil
+}
+
+func (r *Resolver) OutingPassLinks(ctx context.Context, outingID string) (*model.OutingPassLinks, error) {
+	userID := getUserIDFromContext(ctx)
+	if userID == "" {
+		return nil, fmt.Errorf("unauthorized")
+	}
+
+	result, err := r.getOutingHandler.Handle(ctx, queries.GetOutingQuery{OutingID: outingID})
+	if err != nil {
+		return nil, err
+	}
+	outing := result.Outing
+	if outing.UserID() != userID {
+		return nil, domain.ErrOutingNotFound
+	}
+
+	links := &model.OutingPassLinks{
+		CalendarURL:    r.passLinks.OutingCalendarURL(outing),
+		AppleWalletURL: r.passLinks.ApplePassURL(outing),
+		ExpiresAt:      outing.ExpiresAt(),
+	}
+	if r.googleWallet != nil {
+		saveURL, err := r.googleWallet.SaveURL(outing, time.Now())
+		if err != nil {
+			return nil, err
+		}
+		links.GoogleWalletURL = &saveURL
+	}
+
+	return links, nil
+}
+
+func (r *Resolver) MyCalendarFeedURL(ctx context.Context) (string, error) {
+	userID := getUserIDFromContext(ctx)
+	if userID == "" {
+		return "", fmt.Errorf("unauthorized")
+	}
+	return r.passLinks.CalendarFeedURL(userID, time.Now()), nil
+}
+
+// =============================================================================
+// SUBSCRIPTION RESOLVERS
+// =============================================================================
+
+func (r *Resolver) EstablishmentOutingUpdates(ctx context.Context, establishmentID string) (<-chan *model.OutingUpdate, error) {
+	userID := getUserIDFromContext(ctx)
+	if userID == "" {
+		return nil, fmt.Errorf("unauthorized")
+	}
+
+	updates, err := r.watchEstablishmentOutingsHandler.Handle(ctx, queries.WatchEstablishmentOutingsQuery{
+		UserID:          userID,
+		EstablishmentID: establishmentID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	out := make(chan *model.OutingUpdate, 1)
+	go func() {
+		defer close(out)
+		for update := range updates {
+			select {
+			case out <- mapOutingUpdateToModel(update):
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return out, nil
 }
 
 // =============================================================================
@@ -166,10 +486,19 @@ func (r *Resolver) BookOffer(ctx context.Context, input model.BookOfferInput) (*
 		}, nil
 	}
 
-	result, err := r.bookOutingHandler.Handle(ctx, commands.BookOutingCommand{
+	cmd := commands.BookOutingCommand{
 		UserID:  userID,
 		OfferID: input.OfferID,
-	})
+	}
+	if input.Slot != nil {
+		cmd.SlotDate = input.Slot.Date
+		cmd.SlotStartTime = input.Slot.StartTime
+	}
+	if input.IdempotencyKey != nil {
+		cmd.IdempotencyKey = *input.IdempotencyKey
+	}
+
+	result, err := r.bookOutingHandler.Handle(ctx, cmd)
 	if err != nil {
 		return &model.BookOfferPayload{
 			Success: false,
@@ -177,77 +506,465 @@ func (r *Resolver) BookOffer(ctx context.Context, input model.BookOfferInput) (*
 		}, nil
 	}
 
+	outingModel, err := r.mapOutingToModel(ctx, result.Outing)
+	if err != nil {
+		return nil, err
+	}
+
 	return &model.BookOfferPayload{
 		Success: true,
-		Outing:  mapOutingToModel(result.Outing),
+		Outing:  outingModel,
+	}, nil
+}
+
+func (r *Resolver) CheckInOuting(ctx context.Context, input model.CheckInInput) (*model.CheckInPayload, error) {
+	staffUserID := getUserIDFromContext(ctx)
+
+	cmd := commands.CheckInOutingCommand{
+		QRCode:          input.QRCode,
+		StaffUserID:     staffUserID,
+		Latitude:        input.Latitude,
+		Longitude:       input.Longitude,
+		BillAmountCents: billAmountCents(input.BillAmountCents),
+	}
+	if input.IdempotencyKey != nil {
+		cmd.IdempotencyKey = *input.IdempotencyKey
+	}
+
+	result, err := r.checkInHandler.Handle(ctx, cmd)
+	if err != nil {
+		return &model.CheckInPayload{
+			Success: false,
+			Error:   mapCheckInError(err),
+		}, nil
+	}
+
+	outingModel, err := r.mapOutingToModel(ctx, result.Outing)
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.CheckInPayload{
+		Success: true,
+		Outing:  outingModel,
+	}, nil
+}
+
+func (r *Resolver) CheckInWithShortCode(ctx context.Context, input model.ShortCodeCheckInInput) (*model.CheckInPayload, error) {
+	staffUserID := getUserIDFromContext(ctx)
+
+	cmd := commands.CheckInOutingCommand{
+		ShortCode:       input.ShortCode,
+		EstablishmentID: input.EstablishmentID,
+		StaffUserID:     staffUserID,
+		Latitude:        input.Latitude,
+		Longitude:       input.Longitude,
+		BillAmountCents: billAmountCents(input.BillAmountCents),
+	}
+	if input.IdempotencyKey != nil {
+		cmd.IdempotencyKey = *input.IdempotencyKey
+	}
+
+	result, err := r.checkInHandler.Handle(ctx, cmd)
+	if err != nil {
+		return &model.CheckInPayload{
+			Success: false,
+			Error:   mapCheckInError(err),
+		}, nil
+	}
+
+	outingModel, err := r.mapOutingToModel(ctx, result.Outing)
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.CheckInPayload{
+		Success: true,
+		Outing:  outingModel,
+	}, nil
+}
+
+func (r *Resolver) ManualCheckIn(ctx context.Context, input model.ManualCheckInInput) (*model.CheckInPayload, error) {
+	staffUserID := getUserIDFromContext(ctx)
+
+	result, err := r.checkInHandler.Handle(ctx, commands.CheckInOutingCommand{
+		OutingID:        input.OutingID,
+		StaffUserID:     staffUserID,
+		Latitude:        input.Latitude,
+		Longitude:       input.Longitude,
+		BillAmountCents: billAmountCents(input.BillAmountCents),
+	})
+	if err != nil {
+		return &model.CheckInPayload{
+			Success: false,
+			Error:   mapCheckInError(err),
+		}, nil
+	}
+
+	outingModel, err := r.mapOutingToModel(ctx, result.Outing)
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.CheckInPayload{
+		Success: true,
+		Outing:  outingModel,
+	}, nil
+}
+
+func (r *Resolver) RecordOutingBill(ctx context.Context, input model.RecordOutingBillInput) (*model.CheckInPayload, error) {
+	staffUserID := getUserIDFromContext(ctx)
+	if staffUserID == "" {
+		return nil, fmt.Errorf("unauthorized")
+	}
+
+	result, err := r.recordOutingBillHandler.Handle(ctx, commands.RecordOutingBillCommand{
+		OutingID:        input.OutingID,
+		StaffUserID:     staffUserID,
+		BillAmountCents: int64(input.BillAmountCents),
+	})
+	if err != nil {
+		return &model.CheckInPayload{
+			Success: false,
+			Error:   mapCheckInError(err),
+		}, nil
+	}
+
+	outingModel, err := r.mapOutingToModel(ctx, result.Outing)
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.CheckInPayload{
+		Success: true,
+		Outing:  outingModel,
+	}, nil
+}
+
+func (r *Resolver) SyncOfflineCheckIns(ctx context.Context, input model.SyncOfflineCheckInsInput) (*model.SyncOfflineCheckInsPayload, error) {
+	staffUserID := getUserIDFromContext(ctx)
+	if staffUserID == "" {
+		return nil, fmt.Errorf("unauthorized")
+	}
+
+	cmd := commands.SyncOfflineCheckInsCommand{StaffUserID: staffUserID}
+	for _, scan := range input.Scans {
+		cmd.Scans = append(cmd.Scans, commands.OfflineScan{
+			QRCode:    scan.QRCode,
+			ScannedAt: scan.ScannedAt,
+			Latitude:  scan.Latitude,
+			Longitude: scan.Longitude,
+		})
+	}
+
+	result, err := r.syncOfflineCheckInsHandler.Handle(ctx, cmd)
+	if err != nil {
+		return nil, err
+	}
+
+	payload := &model.SyncOfflineCheckInsPayload{
+		Results: make([]*model.OfflineCheckInResult, 0, len(result.Results)),
+	}
+	for _, res := range result.Results {
+		outing, err := r.mapOutingToModel(ctx, res.Outing)
+		if err != nil {
+			return nil, err
+		}
+		item := &model.OfflineCheckInResult{
+			QRCode: res.QRCode,
+			Status: model.OfflineCheckInStatusApplied,
+			Outing: outing,
+		}
+		if res.Outing != nil {
+			outingID := res.Outing.ID()
+			item.OutingID = &outingID
+		}
+		if res.Status == commands.OfflineCheckInStatusConflict {
+			item.Status = model.OfflineCheckInStatusConflict
+			conflict := mapOfflineCheckInConflict(res.Conflict)
+			item.Conflict = &conflict
+			payload.ConflictCount++
+		} else {
+			payload.AppliedCount++
+		}
+		payload.Results = append(payload.Results, item)
+	}
+
+	return payload, nil
+}
+
+func (r *Resolver) CancelOuting(ctx context.Context, input model.CancelOutingInput) (*model.CancelOutingPayload, error) {
+	userID := getUserIDFromContext(ctx)
+	if userID == "" {
+		return nil, fmt.Errorf("unauthorized")
+	}
+
+	reason := ""
+	if input.Reason != nil {
+		reason = *input.Reason
+	}
+
+	result, err := r.cancelOutingHandler.Handle(ctx, commands.CancelOutingCommand{
+		OutingID: input.OutingID,
+		UserID:   userID,
+		IsAdmin:  hasRole(ctx, "admin"),
+		Reason:   reason,
+	})
+	if err != nil {
+		return &model.CancelOutingPayload{
+			Success: false,
+			Error:   mapCancellationError(err),
+		}, nil
+	}
+
+	outingModel, err := r.mapOutingToModel(ctx, result.Outing)
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.CancelOutingPayload{
+		Success: true,
+		Outing:  outingModel,
+	}, nil
+}
+
+func (r *Resolver) ConfirmOuting(ctx context.Context, outingID string) (*model.BookingDecisionPayload, error) {
+	staffUserID := getUserIDFromContext(ctx)
+	if staffUserID == "" {
+		return nil, fmt.Errorf("unauthorized")
+	}
+
+	result, err := r.confirmOutingHandler.Handle(ctx, commands.ConfirmOutingCommand{
+		OutingID:    outingID,
+		StaffUserID: staffUserID,
+	})
+	if err != nil {
+		return &model.BookingDecisionPayload{
+			Success: false,
+			Error:   mapBookingDecisionError(err),
+		}, nil
+	}
+
+	outingModel, err := r.mapOutingToModel(ctx, result.Outing)
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.BookingDecisionPayload{
+		Success: true,
+		Outing:  outingModel,
+	}, nil
+}
+
+func (r *Resolver) RejectOuting(ctx context.Context, input model.RejectOutingInput) (*model.BookingDecisionPayload, error) {
+	staffUserID := getUserIDFromContext(ctx)
+	if staffUserID == "" {
+		return nil, fmt.Errorf("unauthorized")
+	}
+
+	reason := ""
+	if input.Reason != nil {
+		reason = *input.Reason
+	}
+
+	result, err := r.rejectOutingHandler.Handle(ctx, commands.RejectOutingCommand{
+		OutingID:    input.OutingID,
+		StaffUserID: staffUserID,
+		Reason:      reason,
+	})
+	if err != nil {
+		return &model.BookingDecisionPayload{
+			Success: false,
+			Error:   mapBookingDecisionError(err),
+		}, nil
+	}
+
+	outingModel, err := r.mapOutingToModel(ctx, result.Outing)
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.BookingDecisionPayload{
+		Success: true,
+		Outing:  outingModel,
+	}, nil
+}
+
+func (r *Resolver) TransferOuting(ctx context.Context, input model.TransferOutingInput) (*model.TransferPayload, error) {
+	userID := getUserIDFromContext(ctx)
+	if userID == "" {
+		return nil, fmt.Errorf("unauthorized")
+	}
+
+	cmd := commands.TransferOutingCommand{
+		OutingID: input.OutingID,
+		UserID:   userID,
+	}
+	if input.RecipientUserID != nil {
+		cmd.RecipientUserID = *input.RecipientUserID
+	}
+	if input.RecipientEmail != nil {
+		cmd.RecipientEmail = *input.RecipientEmail
+	}
+
+	result, err := r.transferOutingHandler.Handle(ctx, cmd)
+	if err != nil {
+		return &model.TransferPayload{
+			Success: false,
+			Error:   mapTransferError(err),
+		}, nil
+	}
+
+	outingModel, err := r.mapOutingToModel(ctx, result.Outing)
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.TransferPayload{
+		Success: true,
+		Outing:  outingModel,
+	}, nil
+}
+
+func (r *Resolver) AcceptOutingTransfer(ctx context.Context, outingID string) (*model.TransferPayload, error) {
+	userID := getUserIDFromContext(ctx)
+	if userID == "" {
+		return nil, fmt.Errorf("unauthorized")
+	}
+
+	result, err := r.acceptOutingTransferHandler.Handle(ctx, commands.AcceptOutingTransferCommand{
+		OutingID: outingID,
+		UserID:   userID,
+	})
+	if err != nil {
+		return &model.TransferPayload{
+			Success: false,
+			Error:   mapTransferError(err),
+		}, nil
+	}
+
+	outingModel, err := r.mapOutingToModel(ctx, result.Outing)
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.TransferPayload{
+		Success: true,
+		Outing:  outingModel,
+	}, nil
+}
+
+func (r *Resolver) CancelOutingTransfer(ctx context.Context, outingID string) (*model.TransferPayload, error) {
+	userID := getUserIDFromContext(ctx)
+	if userID == "" {
+		return nil, fmt.Errorf("unauthorized")
+	}
+
+	result, err := r.cancelOutingTransferHandler.Handle(ctx, commands.CancelOutingTransferCommand{
+		OutingID: outingID,
+		UserID:   userID,
+	})
+	if err != nil {
+		return &model.TransferPayload{
+			Success: false,
+			Error:   mapTransferError(err),
+		}, nil
+	}
+
+	outingModel, err := r.mapOutingToModel(ctx, result.Outing)
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.TransferPayload{
+		Success: true,
+		Outing:  outingModel,
 	}, nil
 }
 
-func (r *Resolver) CheckInOuting(ctx context.Context, input model.CheckInInput) (*model.CheckInPayload, error) {
-	staffUserID := getUserIDFromContext(ctx)
+func (r *Resolver) JoinWaitlist(ctx context.Context, input model.JoinWaitlistInput) (*model.WaitlistPayload, error) {
+	userID := getUserIDFromContext(ctx)
+	if userID == "" {
+		return nil, fmt.Errorf("unauthorized")
+	}
 
-	result, err := r.checkInHandler.Handle(ctx, commands.CheckInOutingCommand{
-		QRCode:      input.QRCode,
-		StaffUserID: staffUserID,
-		Latitude:    input.Latitude,
-		Longitude:   input.Longitude,
-	})
+	cmd := commands.JoinWaitlistCommand{
+		UserID:  userID,
+		OfferID: input.OfferID,
+	}
+	if input.Slot != nil {
+		cmd.SlotDate = input.Slot.Date
+		cmd.SlotStartTime = input.Slot.StartTime
+	}
+
+	result, err := r.joinWaitlistHandler.Handle(ctx, cmd)
 	if err != nil {
-		return &model.CheckInPayload{
+		return &model.WaitlistPayload{
 			Success: false,
-			Error:   mapCheckInError(err),
+			Error:   mapWaitlistError(err),
 		}, nil
 	}
 
-	return &model.CheckInPayload{
+	return &model.WaitlistPayload{
 		Success: true,
-		Outing:  mapOutingToModel(result.Outing),
+		Entry:   mapWaitlistEntryToModel(result.Entry, result.Position),
 	}, nil
 }
 
-func (r *Resolver) ManualCheckIn(ctx context.Context, input model.ManualCheckInInput) (*model.CheckInPayload, error) {
-	staffUserID := getUserIDFromContext(ctx)
+func (r *Resolver) LeaveWaitlist(ctx context.Context, entryID string) (*model.WaitlistPayload, error) {
+	userID := getUserIDFromContext(ctx)
+	if userID == "" {
+		return nil, fmt.Errorf("unauthorized")
+	}
 
-	result, err := r.checkInHandler.Handle(ctx, commands.CheckInOutingCommand{
-		OutingID:    input.OutingID,
-		StaffUserID: staffUserID,
-		Latitude:    input.Latitude,
-		Longitude:   input.Longitude,
+	result, err := r.leaveWaitlistHandler.Handle(ctx, commands.LeaveWaitlistCommand{
+		EntryID: entryID,
+		UserID:  userID,
 	})
 	if err != nil {
-		return &model.CheckInPayload{
+		return &model.WaitlistPayload{
 			Success: false,
-			Error:   mapCheckInError(err),
+			Error:   mapWaitlistError(err),
 		}, nil
 	}
 
-	return &model.CheckInPayload{
+	return &model.WaitlistPayload{
 		Success: true,
-		Outing:  mapOutingToModel(result.Outing),
+		Entry:   mapWaitlistEntryToModel(result.Entry, 0),
 	}, nil
 }
 
-func (r *Resolver) CancelOuting(ctx context.Context, input model.CancelOutingInput) (*model.CancelOutingPayload, error) {
-	reason := ""
-	if input.Reason != nil {
-		reason = *input.Reason
+func (r *Resolver) AcceptWaitlistHold(ctx context.Context, entryID string) (*model.BookOfferPayload, error) {
+	userID := getUserIDFromContext(ctx)
+	if userID == "" {
+		return &model.BookOfferPayload{
+			Success: false,
+			Error: &model.BookingError{
+				Code:    model.BookingErrorCodeUserNotVerified,
+				Message: "User not authenticated",
+			},
+		}, nil
 	}
 
-	result, err := r.cancelOutingHandler.Handle(ctx, commands.CancelOutingCommand{
-		OutingID:    input.OutingID,
-		CancelledBy: domain.CancellationActorUser,
-		Reason:      reason,
+	result, err := r.acceptWaitlistHoldHandler.Handle(ctx, commands.AcceptWaitlistHoldCommand{
+		EntryID: entryID,
+		UserID:  userID,
 	})
 	if err != nil {
-		return &model.CancelOutingPayload{
+		return &model.BookOfferPayload{
 			Success: false,
-			Error:   mapCancellationError(err),
+			Error:   mapBookingError(err),
 		}, nil
 	}
 
-	return &model.CancelOutingPayload{
+	outingModel, err := r.mapOutingToModel(ctx, result.Outing)
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.BookOfferPayload{
 		Success: true,
-		Outing:  mapOutingToModel(result.Outing),
+		Outing:  outingModel,
 	}, nil
 }
 
@@ -260,7 +977,10 @@ func (r *Resolver) FindOutingByID(ctx context.Context, id string) (*model.Outing
 }
 
 func (r *Resolver) UserOutings(ctx context.Context, obj *model.User, filter *model.OutingFilterInput, pagination *model.PaginationInput) (*model.OutingConnection, error) {
-	domainFilter := mapFilterToDomain(filter, pagination)
+	domainFilter, err := mapFilterToDomain(filter, pagination)
+	if err != nil {
+		return nil, err
+	}
 
 	result, err := r.listUserOutingsHandler.Handle(ctx, queries.ListUserOutingsQuery{
 		UserID: obj.ID,
@@ -270,11 +990,14 @@ func (r *Resolver) UserOutings(ctx context.Context, obj *model.User, filter *mod
 		return nil, err
 	}
 
-	return buildOutingConnection(result.Outings, result.TotalCount, domainFilter), nil
+	return r.buildOutingConnection(ctx, result.Page, domainFilter.SortBy)
 }
 
 func (r *Resolver) OfferBookings(ctx context.Context, obj *model.Offer, filter *model.OutingFilterInput, pagination *model.PaginationInput) (*model.OutingConnection, error) {
-	domainFilter := mapFilterToDomain(filter, pagination)
+	domainFilter, err := mapFilterToDomain(filter, pagination)
+	if err != nil {
+		return nil, err
+	}
 
 	result, err := r.listPartnerOutingsHandler.Handle(ctx, queries.ListPartnerOutingsQuery{
 		PartnerID: obj.ID, // This should be offer ID, need separate handler
@@ -284,7 +1007,7 @@ func (r *Resolver) OfferBookings(ctx context.Context, obj *model.Offer, filter *
 		return nil, err
 	}
 
-	return buildOutingConnection(result.Outings, result.TotalCount, domainFilter), nil
+	return r.buildOutingConnection(ctx, result.Page, domainFilter.SortBy)
 }
 
 func (r *Resolver) OfferActiveBookingsCount(ctx context.Context, obj *model.Offer) (int, error) {
@@ -305,7 +1028,21 @@ func getUserIDFromContext(ctx context.Context) string {
 	return ""
 }
 
-func mapFilterToDomain(filter *model.OutingFilterInput, pagination *model.PaginationInput) domain.OutingFilter {
+// hasRole reports whether the router forwarded the role for the user
+func hasRole(ctx context.Context, role string) bool {
+	roles, _ := ctx.Value("user_roles").([]string)
+	for _, granted := range roles {
+		if granted == role {
+			return true
+		}
+	}
+	return false
+}
+
+// maxPageSize caps first/last so a client cannot fetch a whole history at once
+const maxPageSize = 100
+
+func mapFilterToDomain(filter *model.OutingFilterInput, pagination *model.PaginationInput) (domain.OutingFilter, error) {
 	df := domain.DefaultOutingFilter()
 
 	if filter != nil {
@@ -323,32 +1060,52 @@ func mapFilterToDomain(filter *model.OutingFilterInput, pagination *model.Pagina
 	}
 
 	if pagination != nil {
+		if pagination.First != nil && pagination.Last != nil {
+			return df, fmt.Errorf("first and last cannot be combined")
+		}
 		if pagination.First != nil {
 			df.Limit = *pagination.First
 		}
+		if pagination.Last != nil {
+			df.Limit = *pagination.Last
+			df.Backward = true
+		}
+		if df.Limit <= 0 || df.Limit > maxPageSize {
+			return df, fmt.Errorf("page size must be between 1 and %d", maxPageSize)
+		}
+
 		if pagination.After != nil {
-			// Decode cursor to offset
-			if offset, err := decodeCursor(*pagination.After); err == nil {
-				df.Offset = offset
+			cursor, err := domain.DecodeOutingCursor(*pagination.After)
+			if err != nil {
+				return df, err
 			}
+			df.After = cursor
+		}
+		if pagination.Before != nil {
+			cursor, err := domain.DecodeOutingCursor(*pagination.Before)
+			if err != nil {
+				return df, err
+			}
+			df.Before = cursor
 		}
 	}
 
-	return df
+	return df, nil
 }
 
-func buildOutingConnection(outings []*domain.Outing, total int64, filter domain.OutingFilter) *model.OutingConnection {
-	edges := make([]*model.OutingEdge, len(outings))
-	for i, o := range outings {
+func (r *Resolver) buildOutingConnection(ctx context.Context, page *domain.OutingPage, sortBy domain.OutingSortField) (*model.OutingConnection, error) {
+	edges := make([]*model.OutingEdge, len(page.Outings))
+	for i, o := range page.Outings {
+		node, err := r.mapOutingToModel(ctx, o)
+		if err != nil {
+			return nil, err
+		}
 		edges[i] = &model.OutingEdge{
-			Node:   mapOutingToModel(o),
-			Cursor: encodeCursor(filter.Offset + i),
+			Node:   node,
+			Cursor: domain.NewOutingCursor(o, sortBy).Encode(),
 		}
 	}
 
-	hasNextPage := int64(filter.Offset+len(outings)) < total
-	hasPrevPage := filter.Offset > 0
-
 	var startCursor, endCursor *string
 	if len(edges) > 0 {
 		startCursor = &edges[0].Cursor
@@ -358,34 +1115,49 @@ func buildOutingConnection(outings []*domain.Outing, total int64, filter domain.
 	return &model.OutingConnection{
 		Edges: edges,
 		PageInfo: &model.PageInfo{
-			HasNextPage:     hasNextPage,
-			HasPreviousPage: hasPrevPage,
+			HasNextPage:     page.HasNextPage,
+			HasPreviousPage: page.HasPreviousPage,
 			StartCursor:     startCursor,
 			EndCursor:       endCursor,
 		},
-		TotalCount: int(total),
-	}
+		TotalCount: int(page.TotalCount),
+	}, nil
 }
 
-func encodeCursor(offset int) string {
-	return base64.StdEncoding.EncodeToString([]byte(fmt.Sprintf("cursor:%d", offset)))
-}
+// mapOutingToModel maps an outing, with its check-in credentials only when
+// the viewer owns it: staff, and the recipient of a pending transfer, get the
+// details alone
+func (r *Resolver) mapOutingToModel(ctx context.Context, o *domain.Outing) (*model.Outing, error) {
+	if o == nil {
+		return nil, nil
+	}
+	if getUserIDFromContext(ctx) != o.UserID() {
+		return mapOutingDetailsToModel(o), nil
+	}
 
-func decodeCursor(cursor string) (int, error) {
-	data, err := base64.StdEncoding.DecodeString(cursor)
+	// The QR payload rotates; clients refetch it at refreshAt
+	qrPayload, qrRefreshAt, err := o.QRPayload(r.checkInKeys, time.Now())
 	if err != nil {
-		return 0, err
+		return nil, fmt.Errorf("failed to build QR payload: %w", err)
 	}
-	var offset int
-	_, err = fmt.Sscanf(string(data), "cursor:%d", &offset)
-	return offset, err
-}
 
-func mapOutingToModel(o *domain.Outing) *model.Outing {
-	if o == nil {
-		return nil
+	outing := mapOutingDetailsToModel(o)
+	outing.QRCode = &model.QRCodeInfo{
+		Code:      o.QRCode().Code(),
+		FullCode:  qrPayload,
+		RefreshAt: qrRefreshAt,
+		ExpiresAt: o.QRCode().ExpiresAt(),
+		IsExpired: o.QRCode().IsExpired(),
 	}
+	shortCode := o.ShortCode()
+	outing.ShortCode = &shortCode
+
+	return outing, nil
+}
 
+// mapOutingDetailsToModel maps an outing without the QR and short codes, for
+// viewers who may see the outing but must not be able to check it in
+func mapOutingDetailsToModel(o *domain.Outing) *model.Outing {
 	desc := o.Offer().Description()
 	category := o.Offer().Category()
 	imageURL := o.Offer().ImageURL()
@@ -409,19 +1181,25 @@ func mapOutingToModel(o *domain.Outing) *model.Outing {
 			ImageURL:             &imageURL,
 			CapturedAt:           o.Offer().CapturedAt(),
 		},
-		QRCode: &model.QRCodeInfo{
-			Code:      o.QRCode().Code(),
-			FullCode:  o.QRCode().FullCode(),
-			ExpiresAt: o.QRCode().ExpiresAt(),
-			IsExpired: o.QRCode().IsExpired(),
-		},
 		Status:    model.OutingStatus(o.Status()),
+		ConfirmBy: o.ConfirmBy(),
 		BookedAt:  o.BookedAt(),
 		ExpiresAt: o.ExpiresAt(),
 		CreatedAt: o.CreatedAt(),
 		UpdatedAt: o.UpdatedAt(),
 	}
 
+	// Map slot
+	if slot := o.Slot(); slot != nil {
+		outing.Slot = &model.BookedSlot{
+			Date:      slot.Date(),
+			StartTime: slot.StartTime(),
+			EndTime:   slot.EndTime(),
+			StartsAt:  slot.StartsAt(),
+			EndsAt:    slot.EndsAt(),
+		}
+	}
+
 	// Map timeline
 	for _, entry := range o.Timeline() {
 		outing.Timeline = append(outing.Timeline, &model.TimelineEntry{
@@ -440,6 +1218,41 @@ func mapOutingToModel(o *domain.Outing) *model.Outing {
 			Method:      model.CheckInMethod(o.CheckIn().Method()),
 			Latitude:    o.CheckIn().Latitude(),
 			Longitude:   o.CheckIn().Longitude(),
+
+			DistanceMeters:   o.CheckIn().DistanceMeters(),
+			FlaggedForReview: o.CheckIn().FlaggedForReview(),
+		}
+		if reason := o.CheckIn().ReviewReason(); reason != "" {
+			outing.CheckIn.ReviewReason = &reason
+		}
+		outing.CheckIn.Anomalies = make([]*model.CheckInAnomaly, 0, len(o.CheckIn().Anomalies()))
+		for _, anomaly := range o.CheckIn().Anomalies() {
+			a := &model.CheckInAnomaly{
+				Reason:     model.AnomalyReason(strings.ToUpper(string(anomaly.Reason()))),
+				DetectedAt: anomaly.DetectedAt(),
+			}
+			if detail := anomaly.Detail(); detail != "" {
+				a.Detail = &detail
+			}
+			outing.CheckIn.Anomalies = append(outing.CheckIn.Anomalies, a)
+		}
+		if bill := o.CheckIn().Bill(); bill != nil {
+			outing.CheckIn.Bill = &model.Bill{
+				AmountCents:      int(bill.AmountCents()),
+				SavingsCents:     int(bill.SavingsCents()),
+				FinalAmountCents: int(bill.FinalCents()),
+				RecordedAt:       bill.RecordedAt(),
+				RecordedBy:       bill.RecordedBy(),
+			}
+		}
+	}
+
+	// Map pending transfer
+	if t := o.Transfer(); t != nil {
+		outing.Transfer = &model.OutingTransfer{
+			FromUserID:  t.FromUserID(),
+			ToUserID:    t.ToUserID(),
+			InitiatedAt: t.InitiatedAt(),
 		}
 	}
 
@@ -450,13 +1263,79 @@ func mapOutingToModel(o *domain.Outing) *model.Outing {
 			CancelledAt: o.Cancellation().CancelledAt(),
 			CancelledBy: model.CancellationActor(o.Cancellation().CancelledBy()),
 			Reason:      &reason,
+			Late:        o.Cancellation().IsLate(),
 		}
 	}
 
 	return outing
 }
 
+// mapOutingUpdateToModel maps a live feed update. Staff watching the feed do
+// not get the outing's check-in credentials.
+func mapOutingUpdateToModel(u domain.OutingUpdate) *model.OutingUpdate {
+	updateType := model.OutingUpdateTypeUpdated
+	switch u.EventType {
+	case "outing.booked":
+		updateType = model.OutingUpdateTypeBooked
+	case "outing.confirmed":
+		updateType = model.OutingUpdateTypeConfirmed
+	case "outing.rejected":
+		updateType = model.OutingUpdateTypeRejected
+	case "outing.checked_in":
+		updateType = model.OutingUpdateTypeCheckedIn
+	case "outing.cancelled":
+		updateType = model.OutingUpdateTypeCancelled
+	case "outing.expired":
+		updateType = model.OutingUpdateTypeExpired
+	case "outing.no_show":
+		updateType = model.OutingUpdateTypeNoShow
+	}
+
+	return &model.OutingUpdate{
+		Type:       updateType,
+		Outing:     mapOutingDetailsToModel(u.Outing),
+		OccurredAt: u.OccurredAt,
+	}
+}
+
+// mapWaitlistEntryToModel maps an entry; position 0 means not waiting
+func mapWaitlistEntryToModel(e *domain.WaitlistEntry, position int) *model.WaitlistEntry {
+	entry := &model.WaitlistEntry{
+		ID:            e.ID(),
+		OfferID:       e.OfferID(),
+		UserID:        e.UserID(),
+		Status:        model.WaitlistStatus(strings.ToUpper(string(e.Status()))),
+		HoldExpiresAt: e.HoldExpiresAt(),
+		JoinedAt:      e.JoinedAt(),
+	}
+	if position > 0 {
+		entry.Position = &position
+	}
+	if outingID := e.OutingID(); outingID != "" {
+		entry.OutingID = &outingID
+	}
+	if slot := e.Slot(); slot != nil {
+		entry.Slot = &model.BookedSlot{
+			Date:      slot.Date(),
+			StartTime: slot.StartTime(),
+			EndTime:   slot.EndTime(),
+			StartsAt:  slot.StartsAt(),
+			EndsAt:    slot.EndsAt(),
+		}
+	}
+
+	return entry
+}
+
 func mapBookingError(err error) *model.BookingError {
+	// CanBook errors arrive wrapped by the handlers
+	if errors.Is(err, domain.ErrUserBookingBanned) {
+		return &model.BookingError{
+			Code:    model.BookingErrorCodeUserBanned,
+			Message: err.Error(),
+		}
+	}
+
 	switch err {
 	case domain.ErrOfferNotBookable:
 		return &model.BookingError{
@@ -468,7 +1347,7 @@ func mapBookingError(err error) *model.BookingError {
 			Code:    model.BookingErrorCodeUserQuotaExceeded,
 			Message: err.Error(),
 		}
-	case domain.ErrOfferQuotaExceeded:
+	case domain.ErrOfferQuotaExceeded, domain.ErrDailyQuotaExceeded:
 		return &model.BookingError{
 			Code:    model.BookingErrorCodeOfferQuotaExceeded,
 			Message: err.Error(),
@@ -478,6 +1357,36 @@ func mapBookingError(err error) *model.BookingError {
 			Code:    model.BookingErrorCodeAlreadyBooked,
 			Message: err.Error(),
 		}
+	case domain.ErrSlotNotAvailable, domain.ErrSlotEnded:
+		return &model.BookingError{
+			Code:    model.BookingErrorCodeSlotNotAvailable,
+			Message: err.Error(),
+		}
+	case domain.ErrSlotFull:
+		return &model.BookingError{
+			Code:    model.BookingErrorCodeSlotFull,
+			Message: err.Error(),
+		}
+	case domain.ErrInvalidIdempotencyKey:
+		return &model.BookingError{
+			Code:    model.BookingErrorCodeInvalidIdempotencyKey,
+			Message: err.Error(),
+		}
+	case domain.ErrIdempotencyKeyMismatch:
+		return &model.BookingError{
+			Code:    model.BookingErrorCodeIdempotencyKeyReused,
+			Message: err.Error(),
+		}
+	case domain.ErrIdempotencyKeyInUse:
+		return &model.BookingError{
+			Code:    model.BookingErrorCodeRequestInProgress,
+			Message: err.Error(),
+		}
+	case domain.ErrWaitlistEntryNotFound, domain.ErrWaitlistHoldNotActive, domain.ErrWaitlistHoldExpired:
+		return &model.BookingError{
+			Code:    model.BookingErrorCodeOfferNotAvailable,
+			Message: err.Error(),
+		}
 	default:
 		return &model.BookingError{
 			Code:    model.BookingErrorCodeInternalError,
@@ -498,6 +1407,11 @@ func mapCheckInError(err error) *model.CheckInError {
 			Code:    model.CheckInErrorCodeInvalidQRCode,
 			Message: err.Error(),
 		}
+	case domain.ErrQRCodeStale:
+		return &model.CheckInError{
+			Code:    model.CheckInErrorCodeQRCodeStale,
+			Message: err.Error(),
+		}
 	case domain.ErrOutingExpired:
 		return &model.CheckInError{
 			Code:    model.CheckInErrorCodeOutingExpired,
@@ -513,6 +1427,61 @@ func mapCheckInError(err error) *model.CheckInError {
 			Code:    model.CheckInErrorCodeOutingCancelled,
 			Message: err.Error(),
 		}
+	case domain.ErrInvalidCheckInWindow:
+		return &model.CheckInError{
+			Code:    model.CheckInErrorCodeCheckInNotOpen,
+			Message: err.Error(),
+		}
+	case domain.ErrOutsideGeofence:
+		return &model.CheckInError{
+			Code:    model.CheckInErrorCodeOutsideGeofence,
+			Message: err.Error(),
+		}
+	case domain.ErrAwaitingConfirmation:
+		return &model.CheckInError{
+			Code:    model.CheckInErrorCodeAwaitingConfirmation,
+			Message: err.Error(),
+		}
+	case domain.ErrInvalidShortCode:
+		return &model.CheckInError{
+			Code:    model.CheckInErrorCodeInvalidShortCode,
+			Message: err.Error(),
+		}
+	case domain.ErrTooManyShortCodeAttempts:
+		return &model.CheckInError{
+			Code:    model.CheckInErrorCodeTooManyAttempts,
+			Message: err.Error(),
+		}
+	case domain.ErrNotPartnerTeamMember:
+		return &model.CheckInError{
+			Code:    model.CheckInErrorCodeNotTeamMember,
+			Message: err.Error(),
+		}
+	case domain.ErrInvalidBillAmount:
+		return &model.CheckInError{
+			Code:    model.CheckInErrorCodeInvalidBillAmount,
+			Message: err.Error(),
+		}
+	case domain.ErrOutingNotCheckedIn:
+		return &model.CheckInError{
+			Code:    model.CheckInErrorCodeNotCheckedIn,
+			Message: err.Error(),
+		}
+	case domain.ErrInvalidIdempotencyKey:
+		return &model.CheckInError{
+			Code:    model.CheckInErrorCodeInvalidIdempotencyKey,
+			Message: err.Error(),
+		}
+	case domain.ErrIdempotencyKeyMismatch:
+		return &model.CheckInError{
+			Code:    model.CheckInErrorCodeIdempotencyKeyReused,
+			Message: err.Error(),
+		}
+	case domain.ErrIdempotencyKeyInUse:
+		return &model.CheckInError{
+			Code:    model.CheckInErrorCodeRequestInProgress,
+			Message: err.Error(),
+		}
 	default:
 		return &model.CheckInError{
 			Code:    model.CheckInErrorCodeInternalError,
@@ -521,6 +1490,86 @@ func mapCheckInError(err error) *model.CheckInError {
 	}
 }
 
+// billAmountCents widens an optional GraphQL bill amount
+func billAmountCents(amount *int) *int64 {
+	if amount == nil {
+		return nil
+	}
+	cents := int64(*amount)
+	return &cents
+}
+
+func mapOfflineCheckInConflict(conflict commands.OfflineCheckInConflict) model.OfflineCheckInConflict {
+	switch conflict {
+	case commands.OfflineConflictAlreadyUsed:
+		return model.OfflineCheckInConflictAlreadyUsed
+	case commands.OfflineConflictCancelled:
+		return model.OfflineCheckInConflictCancelled
+	case commands.OfflineConflictExpired:
+		return model.OfflineCheckInConflictExpired
+	case commands.OfflineConflictOutsideGeofence:
+		return model.OfflineCheckInConflictOutsideGeofence
+	case commands.OfflineConflictPending:
+		return model.OfflineCheckInConflictAwaitingConfirmation
+	case commands.OfflineConflictNotTeamMember:
+		return model.OfflineCheckInConflictNotTeamMember
+	default:
+		return model.OfflineCheckInConflictInvalid
+	}
+}
+
+func mapWaitlistError(err error) *model.WaitlistError {
+	if errors.Is(err, domain.ErrUserBookingBanned) {
+		return &model.WaitlistError{
+			Code:    model.WaitlistErrorCodeUserBanned,
+			Message: err.Error(),
+		}
+	}
+
+	switch err {
+	case domain.ErrAlreadyOnWaitlist:
+		return &model.WaitlistError{
+			Code:    model.WaitlistErrorCodeAlreadyOnWaitlist,
+			Message: err.Error(),
+		}
+	case domain.ErrOutingAlreadyExists:
+		return &model.WaitlistError{
+			Code:    model.WaitlistErrorCodeAlreadyBooked,
+			Message: err.Error(),
+		}
+	case domain.ErrWaitlistEntryNotFound:
+		return &model.WaitlistError{
+			Code:    model.WaitlistErrorCodeEntryNotFound,
+			Message: err.Error(),
+		}
+	case domain.ErrWaitlistHoldNotActive, domain.ErrWaitlistEntryClosed:
+		return &model.WaitlistError{
+			Code:    model.WaitlistErrorCodeHoldNotActive,
+			Message: err.Error(),
+		}
+	case domain.ErrWaitlistHoldExpired:
+		return &model.WaitlistError{
+			Code:    model.WaitlistErrorCodeHoldExpired,
+			Message: err.Error(),
+		}
+	case domain.ErrWaitlistEntryConflict:
+		return &model.WaitlistError{
+			Code:    model.WaitlistErrorCodeEntryChanged,
+			Message: err.Error(),
+		}
+	case domain.ErrSlotNotAvailable, domain.ErrSlotEnded:
+		return &model.WaitlistError{
+			Code:    model.WaitlistErrorCodeSlotNotAvailable,
+			Message: err.Error(),
+		}
+	default:
+		return &model.WaitlistError{
+			Code:    model.WaitlistErrorCodeInternalError,
+			Message: err.Error(),
+		}
+	}
+}
+
 func mapCancellationError(err error) *model.CancellationError {
 	switch err {
 	case domain.ErrOutingNotFound:
@@ -546,5 +1595,98 @@ func mapCancellationError(err error) *model.CancellationError {
 	}
 }
 
+func mapBookingDecisionError(err error) *model.BookingDecisionError {
+	switch err {
+	case domain.ErrOutingNotFound:
+		return &model.BookingDecisionError{
+			Code:    model.BookingDecisionErrorCodeOutingNotFound,
+			Message: err.Error(),
+		}
+	case domain.ErrOutingNotPending:
+		return &model.BookingDecisionError{
+			Code:    model.BookingDecisionErrorCodeNotPending,
+			Message: err.Error(),
+		}
+	case domain.ErrConfirmationTimedOut:
+		return &model.BookingDecisionError{
+			Code:    model.BookingDecisionErrorCodeConfirmationTimedOut,
+			Message: err.Error(),
+		}
+	case domain.ErrNotPartnerTeamMember:
+		return &model.BookingDecisionError{
+			Code:    model.BookingDecisionErrorCodeNotTeamMember,
+			Message: err.Error(),
+		}
+	default:
+		return &model.BookingDecisionError{
+			Code:    model.BookingDecisionErrorCodeInternalError,
+			Message: err.Error(),
+		}
+	}
+}
+
+func mapTransferError(err error) *model.TransferError {
+	// CanBook errors of the recipient arrive wrapped
+	if errors.Is(err, domain.ErrRecipientNotEligible) {
+		return &model.TransferError{
+			Code:    model.TransferErrorCodeRecipientNotEligible,
+			Message: err.Error(),
+		}
+	}
+
+	switch err {
+	case domain.ErrOutingNotFound:
+		return &model.TransferError{
+			Code:    model.TransferErrorCodeOutingNotFound,
+			Message: err.Error(),
+		}
+	case domain.ErrUserNotFound:
+		return &model.TransferError{
+			Code:    model.TransferErrorCodeRecipientNotFound,
+			Message: err.Error(),
+		}
+	case domain.ErrNotOutingOwner:
+		return &model.TransferError{
+			Code:    model.TransferErrorCodeNotOwner,
+			Message: err.Error(),
+		}
+	case domain.ErrTransferToSelf:
+		return &model.TransferError{
+			Code:    model.TransferErrorCodeTransferToSelf,
+			Message: err.Error(),
+		}
+	case domain.ErrNoPendingTransfer:
+		return &model.TransferError{
+			Code:    model.TransferErrorCodeNoPendingTransfer,
+			Message: err.Error(),
+		}
+	case domain.ErrNotTransferRecipient:
+		return &model.TransferError{
+			Code:    model.TransferErrorCodeNotRecipient,
+			Message: err.Error(),
+		}
+	case domain.ErrOutingCancelled, domain.ErrOutingAlreadyUsed, domain.ErrOutingExpired:
+		return &model.TransferError{
+			Code:    model.TransferErrorCodeOutingNotTransferable,
+			Message: err.Error(),
+		}
+	case domain.ErrOutingAlreadyExists:
+		return &model.TransferError{
+			Code:    model.TransferErrorCodeAlreadyBooked,
+			Message: err.Error(),
+		}
+	case domain.ErrUserQuotaExceeded:
+		return &model.TransferError{
+			Code:    model.TransferErrorCodeUserQuotaExceeded,
+			Message: err.Error(),
+		}
+	default:
+		return &model.TransferError{
+			Code:    model.TransferErrorCodeInternalError,
+			Message: err.Error(),
+		}
+	}
+}
+
 // Unused import fix
 var _ = strconv.Itoa
@@ -0,0 +1,153 @@
+package rest
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/yousoon/apps/services/booking-service/internal/application/queries"
+	"github.com/yousoon/apps/services/booking-service/internal/domain"
+	"github.com/yousoon/apps/services/booking-service/internal/infrastructure/export"
+)
+
+// =============================================================================
+// OUTING EXPORTS
+// =============================================================================
+
+const (
+	exportFormatCSV  = "csv"
+	exportFormatXLSX = "xlsx"
+)
+
+// ExportHandler streams the outings of a partner or an establishment as a CSV
+// or XLSX download.
+//
+//	GET /exports/outings?partnerId=...&establishmentId=...&from=...&to=...&format=csv|xlsx
+//
+// from and to accept RFC 3339 timestamps or YYYY-MM-DD dates; a date given as
+// "to" includes the whole day. Only team members of the establishment or
+// partner may export; the handler expects ForwardedUser in front of it.
+type ExportHandler struct {
+	exportOutings *queries.ExportOutingsHandler
+}
+
+func NewExportHandler(exportOutings *queries.ExportOutingsHandler) *ExportHandler {
+	return &ExportHandler{
+		exportOutings: exportOutings,
+	}
+}
+
+// exportRowWriter is implemented by the export writers
+type exportRowWriter interface {
+	WriteRow(values []string) error
+	Close() error
+}
+
+func (h *ExportHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.Header().Set("Allow", http.MethodGet)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	userID, _ := r.Context().Value("user_id").(string)
+	if userID == "" {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	params := r.URL.Query()
+	query := queries.ExportOutingsQuery{
+		UserID:          userID,
+		PartnerID:       params.Get("partnerId"),
+		EstablishmentID: params.Get("establishmentId"),
+	}
+	switch err := h.exportOutings.Authorize(r.Context(), query); err {
+	case nil:
+	case queries.ErrExportScopeRequired:
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	case domain.ErrNotPartnerTeamMember:
+		http.Error(w, "forbidden", http.StatusForbidden)
+		return
+	default:
+		log.Printf("outing export authorization failed: %v", err)
+		http.Error(w, "failed to start export", http.StatusInternalServerError)
+		return
+	}
+
+	var err error
+	if query.StartDate, err = parseExportTime(params.Get("from"), false); err != nil {
+		http.Error(w, "invalid from: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	if query.EndDate, err = parseExportTime(params.Get("to"), true); err != nil {
+		http.Error(w, "invalid to: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	if query.StartDate != nil && query.EndDate != nil && query.EndDate.Before(*query.StartDate) {
+		http.Error(w, "to must not be before from", http.StatusBadRequest)
+		return
+	}
+
+	format := params.Get("format")
+	if format == "" {
+		format = exportFormatCSV
+	}
+
+	filename := fmt.Sprintf("outings-%s.%s", time.Now().UTC().Format("20060102-150405"), format)
+
+	var writer exportRowWriter
+	switch format {
+	case exportFormatCSV:
+		w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+		w.Header().Set("Content-Disposition", `attachment; filename="`+filename+`"`)
+		writer = export.NewCSVWriter(w)
+	case exportFormatXLSX:
+		w.Header().Set("Content-Type", "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet")
+		w.Header().Set("Content-Disposition", `attachment; filename="`+filename+`"`)
+		writer, err = export.NewXLSXWriter(w, "Outings")
+		if err != nil {
+			http.Error(w, "failed to start export", http.StatusInternalServerError)
+			return
+		}
+	default:
+		http.Error(w, "format must be csv or xlsx", http.StatusBadRequest)
+		return
+	}
+
+	// Large exports outlive the server write timeout
+	if err := http.NewResponseController(w).SetWriteDeadline(time.Time{}); err != nil && !errors.Is(err, http.ErrNotSupported) {
+		log.Printf("warning: failed to lift export write deadline: %v", err)
+	}
+
+	// The status line is already sent once rows are streamed, so errors past
+	// this point can only cut the download short.
+	written, err := h.exportOutings.Handle(r.Context(), query, writer)
+	if err != nil {
+		log.Printf("outing export failed after %d rows: %v", written, err)
+		return
+	}
+	if err := writer.Close(); err != nil {
+		log.Printf("outing export failed to finish: %v", err)
+	}
+}
+
+func parseExportTime(value string, endOfDay bool) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return &t, nil
+	}
+	t, err := time.Parse(time.DateOnly, value)
+	if err != nil {
+		return nil, fmt.Errorf("expected an RFC 3339 timestamp or a YYYY-MM-DD date")
+	}
+	if endOfDay {
+		t = t.AddDate(0, 0, 1).Add(-time.Nanosecond)
+	}
+	return &t, nil
+}
@@ -0,0 +1,211 @@
+package rest
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/yousoon/apps/services/booking-service/internal/application/queries"
+	"github.com/yousoon/apps/services/booking-service/internal/domain"
+	"github.com/yousoon/apps/services/booking-service/internal/infrastructure/calendar"
+	"github.com/yousoon/apps/services/booking-service/internal/infrastructure/signedurl"
+	"github.com/yousoon/apps/services/booking-service/internal/infrastructure/wallet"
+)
+
+// =============================================================================
+// CALENDAR AND WALLET PASSES
+// =============================================================================
+
+const (
+	OutingCalendarPath = "/calendar/outing.ics"
+	CalendarFeedPath   = "/calendar/feed.ics"
+	ApplePassPath      = "/wallet/outing.pkpass"
+
+	// calendarFeedSize bounds the outings listed in a calendar feed
+	calendarFeedSize = 100
+)
+
+// PassLinks issues the signed links served by PassHandler. Outing links
+// expire with the outing; feed links expire after feedTTL, long enough for
+// calendar apps to keep polling them, so a leaked link stops working and the
+// app fetches a fresh one.
+type PassLinks struct {
+	signer             *signedurl.Signer
+	applePassesEnabled bool
+	feedTTL            time.Duration
+}
+
+func NewPassLinks(signer *signedurl.Signer, applePassesEnabled bool, feedTTL time.Duration) *PassLinks {
+	return &PassLinks{
+		signer:             signer,
+		applePassesEnabled: applePassesEnabled,
+		feedTTL:            feedTTL,
+	}
+}
+
+func (l *PassLinks) OutingCalendarURL(outing *domain.Outing) string {
+	return l.outingURL(OutingCalendarPath, outing)
+}
+
+// ApplePassURL returns nil when Apple Wallet passes are not enabled
+func (l *PassLinks) ApplePassURL(outing *domain.Outing) *string {
+	if !l.applePassesEnabled {
+		return nil
+	}
+	link := l.outingURL(ApplePassPath, outing)
+	return &link
+}
+
+func (l *PassLinks) CalendarFeedURL(userID string, now time.Time) string {
+	expiresAt := now.Add(l.feedTTL)
+	return l.signer.URL(CalendarFeedPath, url.Values{"userId": {userID}}, &expiresAt)
+}
+
+func (l *PassLinks) outingURL(path string, outing *domain.Outing) string {
+	expiresAt := outing.ExpiresAt()
+	return l.signer.URL(path, url.Values{
+		"outingId": {outing.ID()},
+		"userId":   {outing.UserID()},
+	}, &expiresAt)
+}
+
+// PassHandler serves outing calendar events, the per-user calendar feed and
+// Apple Wallet passes behind signed links. applePasses is nil when Apple
+// Wallet signing is not configured.
+type PassHandler struct {
+	signer          *signedurl.Signer
+	getOuting       *queries.GetOutingHandler
+	listUserOutings *queries.ListUserOutingsHandler
+	applePasses     *wallet.ApplePassBuilder
+}
+
+func NewPassHandler(
+	signer *signedurl.Signer,
+	getOuting *queries.GetOutingHandler,
+	listUserOutings *queries.ListUserOutingsHandler,
+	applePasses *wallet.ApplePassBuilder,
+) *PassHandler {
+	return &PassHandler{
+		signer:          signer,
+		getOuting:       getOuting,
+		listUserOutings: listUserOutings,
+		applePasses:     applePasses,
+	}
+}
+
+// ServeOutingCalendar serves the .ics event of an outing
+func (h *PassHandler) ServeOutingCalendar(w http.ResponseWriter, r *http.Request) {
+	outing, ok := h.outingFromLink(w, r)
+	if !ok {
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+	w.Header().Set("Content-Disposition", `attachment; filename="outing-`+outing.ID()+`.ics"`)
+	if err := calendar.WriteEvent(w, outing, time.Now()); err != nil {
+		log.Printf("failed to write outing calendar event: %v", err)
+	}
+}
+
+// ServeCalendarFeed serves the subscribable feed of a user's upcoming outings
+func (h *PassHandler) ServeCalendarFeed(w http.ResponseWriter, r *http.Request) {
+	params, ok := h.verify(w, r)
+	if !ok {
+		return
+	}
+
+	filter := domain.DefaultOutingFilter()
+	filter.Status = []domain.OutingStatus{domain.OutingStatusPending, domain.OutingStatusConfirmed}
+	filter.Limit = calendarFeedSize
+
+	result, err := h.listUserOutings.Handle(r.Context(), queries.ListUserOutingsQuery{
+		UserID: params.Get("userId"),
+		Filter: filter,
+	})
+	if err != nil {
+		log.Printf("failed to list outings for calendar feed: %v", err)
+		http.Error(w, "failed to load outings", http.StatusInternalServerError)
+		return
+	}
+
+	now := time.Now()
+	upcoming := make([]*domain.Outing, 0, len(result.Page.Outings))
+	for _, outing := range result.Page.Outings {
+		if _, end := calendar.EventWindow(outing); end.After(now) {
+			upcoming = append(upcoming, outing)
+		}
+	}
+
+	w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+	w.Header().Set("Cache-Control", "private, max-age=300")
+	if err := calendar.WriteFeed(w, "Yousoon outings", upcoming, now); err != nil {
+		log.Printf("failed to write calendar feed: %v", err)
+	}
+}
+
+// ServeApplePass serves the signed .pkpass of an outing
+func (h *PassHandler) ServeApplePass(w http.ResponseWriter, r *http.Request) {
+	if h.applePasses == nil {
+		http.Error(w, "apple wallet passes are not enabled", http.StatusNotFound)
+		return
+	}
+
+	outing, ok := h.outingFromLink(w, r)
+	if !ok {
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/vnd.apple.pkpass")
+	w.Header().Set("Content-Disposition", `attachment; filename="outing-`+outing.ID()+`.pkpass"`)
+	if err := h.applePasses.Write(w, outing, time.Now()); err != nil {
+		log.Printf("failed to write apple wallet pass: %v", err)
+	}
+}
+
+// outingFromLink loads the outing of a verified outing link, writing the
+// error response when it cannot.
+func (h *PassHandler) outingFromLink(w http.ResponseWriter, r *http.Request) (*domain.Outing, bool) {
+	params, ok := h.verify(w, r)
+	if !ok {
+		return nil, false
+	}
+
+	result, err := h.getOuting.Handle(r.Context(), queries.GetOutingQuery{OutingID: params.Get("outingId")})
+	if err != nil {
+		if errors.Is(err, domain.ErrOutingNotFound) {
+			http.Error(w, "outing not found", http.StatusNotFound)
+			return nil, false
+		}
+		log.Printf("failed to load outing for pass: %v", err)
+		http.Error(w, "failed to load outing", http.StatusInternalServerError)
+		return nil, false
+	}
+	if result.Outing.UserID() != params.Get("userId") {
+		http.Error(w, "outing not found", http.StatusNotFound)
+		return nil, false
+	}
+
+	return result.Outing, true
+}
+
+func (h *PassHandler) verify(w http.ResponseWriter, r *http.Request) (url.Values, bool) {
+	if r.Method != http.MethodGet {
+		w.Header().Set("Allow", http.MethodGet)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return nil, false
+	}
+
+	params, err := h.signer.Verify(r.URL, time.Now())
+	switch {
+	case errors.Is(err, signedurl.ErrLinkExpired):
+		http.Error(w, err.Error(), http.StatusGone)
+		return nil, false
+	case err != nil:
+		http.Error(w, err.Error(), http.StatusForbidden)
+		return nil, false
+	}
+
+	return params, true
+}
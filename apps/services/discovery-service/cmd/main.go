@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"fmt"
 	"log/slog"
 	"net/http"
 	"os"
@@ -9,11 +10,26 @@ import (
 	"syscall"
 	"time"
 
+	"github.com/99designs/gqlgen/graphql/handler"
+	"github.com/99designs/gqlgen/graphql/handler/extension"
+	"github.com/99designs/gqlgen/graphql/handler/lru"
+	"github.com/99designs/gqlgen/graphql/handler/transport"
+
+	"github.com/yousoon/discovery-service/events"
+	"github.com/yousoon/discovery-service/internal/application/commands"
+	"github.com/yousoon/discovery-service/internal/domain"
 	mongodb "github.com/yousoon/discovery-service/internal/infrastructure/mongodb"
+	"github.com/yousoon/discovery-service/internal/infrastructure/realtime"
+	eventconsumers "github.com/yousoon/discovery-service/internal/interface/events"
+	"github.com/yousoon/discovery-service/internal/interface/graphql/generated"
 	"github.com/yousoon/discovery-service/internal/interface/graphql/resolver"
+	"github.com/yousoon/discovery-service/internal/interface/rest"
 	"github.com/yousoon/shared/config"
 	sharedmongo "github.com/yousoon/shared/infrastructure/mongodb"
 	"github.com/yousoon/shared/infrastructure/nats"
+	"github.com/yousoon/shared/infrastructure/outbox"
+	"github.com/yousoon/shared/infrastructure/redis"
+	"github.com/yousoon/shared/infrastructure/scheduler"
 )
 
 const (
@@ -30,6 +46,14 @@ func main() {
 	logger := slog.New(slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{Level: logLevel}))
 	slog.SetDefault(logger)
 
+	if len(os.Args) > 1 && os.Args[1] == "reindex" {
+		if err := runReindex(); err != nil {
+			slog.Error("Reindex failed", "error", err)
+			os.Exit(1)
+		}
+		return
+	}
+
 	slog.Info("Starting discovery service...")
 
 	// Get configuration from environment
@@ -66,9 +90,24 @@ func main() {
 
 	slog.Info("Connected to NATS")
 
+	// Initialize Redis client (scheduler locks)
+	redisConfig := redis.DefaultConfig()
+	redisConfig.Address = config.GetEnv("REDIS_ADDR", redisConfig.Address)
+	redisConfig.Password = config.GetEnv("REDIS_PASSWORD", "")
+	redisConfig.DB = config.GetEnvInt("REDIS_DB", 0)
+	redisClient, err := redis.NewClient(context.Background(), redisConfig)
+	if err != nil {
+		slog.Error("Failed to connect to Redis", "error", err)
+		os.Exit(1)
+	}
+	defer redisClient.Close()
+
+	slog.Info("Connected to Redis")
+
 	// Initialize repositories
-	offerRepo := mongodb.NewOfferRepository(mongoClient.Database())
-	categoryRepo := mongodb.NewCategoryRepository(mongoClient.Database())
+	outboxStore := outbox.NewStore(mongoClient.Database())
+	offerRepo := mongodb.NewOfferRepository(mongoClient.Database(), outboxStore)
+	categoryRepo := mongodb.NewCategoryRepository(mongoClient.Database(), outboxStore)
 
 	// Ensure indexes
 	if err := offerRepo.EnsureIndexes(context.Background()); err != nil {
@@ -77,11 +116,85 @@ func main() {
 	if err := categoryRepo.EnsureIndexes(context.Background()); err != nil {
 		slog.Warn("Failed to ensure category indexes", "error", err)
 	}
+	if err := outboxStore.EnsureIndexes(context.Background()); err != nil {
+		slog.Warn("Failed to ensure outbox indexes", "error", err)
+	}
+
+	// Relay offer and category events saved to the outbox to NATS
+	events.Register(nats.GlobalRegistry())
+	relayConfig := outbox.DefaultConfig()
+	relayConfig.PollInterval = config.GetEnvDuration("OUTBOX_POLL_INTERVAL", relayConfig.PollInterval)
+	relayConfig.BatchSize = config.GetEnvInt("OUTBOX_BATCH_SIZE", relayConfig.BatchSize)
+	outboxRelay := outbox.NewRelay(outboxStore, nats.NewEventPublisher(natsClient), relayConfig)
+
+	workerCtx, stopWorkers := context.WithCancel(context.Background())
+	defer stopWorkers()
+	go outboxRelay.Run(workerCtx)
+
+	// Scheduled publication and expiry of offers, once across replicas
+	jobInterval := config.GetEnvDuration("OFFER_JOB_INTERVAL", time.Minute)
+	jobBatchSize := config.GetEnvInt("OFFER_JOB_BATCH_SIZE", 100)
+	publishScheduledOffersHandler := commands.NewPublishScheduledOffersHandler(offerRepo)
+	expireOffersHandler := commands.NewExpireOffersHandler(offerRepo)
+
+	jobScheduler := scheduler.NewScheduler(redisClient, "discovery:jobs:", "discovery_jobs")
+	jobScheduler.Register(scheduler.Job{
+		Name:     "publish-scheduled-offers",
+		Interval: jobInterval,
+		Run: func(ctx context.Context) (scheduler.JobResult, error) {
+			result, err := publishScheduledOffersHandler.Handle(ctx, commands.PublishScheduledOffersCommand{BatchSize: jobBatchSize})
+			if err != nil {
+				return scheduler.JobResult{}, err
+			}
+			return scheduler.JobResult{Processed: result.Processed, Failed: result.Failed}, nil
+		},
+	})
+	jobScheduler.Register(scheduler.Job{
+		Name:     "expire-offers",
+		Interval: jobInterval,
+		Run: func(ctx context.Context) (scheduler.JobResult, error) {
+			result, err := expireOffersHandler.Handle(ctx, commands.ExpireOffersCommand{BatchSize: jobBatchSize})
+			if err != nil {
+				return scheduler.JobResult{}, err
+			}
+			return scheduler.JobResult{Processed: result.Processed, Failed: result.Failed}, nil
+		},
+	})
+	jobScheduler.Start(workerCtx)
+
+	// Keep the offers search index in sync from the offer events relayed to NATS
+	searchRepo, err := newOfferSearchRepository()
+	if err != nil {
+		slog.Error("Failed to initialize Elasticsearch", "error", err)
+		os.Exit(1)
+	}
+	if err := searchRepo.EnsureIndex(context.Background()); err != nil {
+		slog.Warn("Failed to ensure offers index", "error", err)
+	}
+
+	eventSubscriber := nats.NewSubscriber(natsClient)
+	defer eventSubscriber.Close()
+	searchProjector := eventconsumers.NewSearchProjector(eventSubscriber, config.GetEnv("NATS_STREAM_NAME", "YOUSOON_EVENTS"), offerRepo, searchRepo)
+	if err := searchProjector.Start(workerCtx); err != nil {
+		slog.Warn("Search index sync disabled", "error", err)
+	}
+
+	// Live offer updates for the subscription resolvers, fed by the offer
+	// events relayed to NATS
+	feedConfig := realtime.DefaultConfig()
+	feedConfig.ExpiringSoonWindow = config.GetEnvDuration("EXPIRING_SOON_WINDOW", feedConfig.ExpiringSoonWindow)
+	feedConfig.ExpiringSoonInterval = config.GetEnvDuration("EXPIRING_SOON_CHECK_INTERVAL", feedConfig.ExpiringSoonInterval)
+	offerFeed := realtime.NewOfferFeed(offerRepo, &stubFavoriteService{}, feedConfig)
+	go func() {
+		if err := offerFeed.Run(workerCtx, natsClient.Conn()); err != nil {
+			slog.Error("Live offer feed failed", "error", err)
+		}
+	}()
 
 	// Initialize GraphQL resolver
 	// Note: For now, we pass offerRepo as both OfferRepository and OfferReadRepository
 	// In the future, OfferReadRepository could be an Elasticsearch implementation
-	graphqlResolver := resolver.NewResolver(offerRepo, categoryRepo, offerRepo)
+	graphqlResolver := resolver.NewResolver(offerRepo, categoryRepo, offerRepo, searchRepo, offerFeed)
 
 	// Create HTTP server
 	mux := http.NewServeMux()
@@ -106,12 +219,28 @@ func main() {
 		w.Write([]byte(`{"status":"ready"}`))
 	})
 
-	// GraphQL endpoint placeholder
-	// After running gqlgen generate, this will be replaced with the actual handler
-	mux.HandleFunc("/graphql", func(w http.ResponseWriter, r *http.Request) {
-		w.Header().Set("Content-Type", "application/json")
-		w.Write([]byte(`{"message":"GraphQL endpoint ready. Run 'go generate ./...' to generate schema."}`))
+	// GraphQL endpoint
+	srv := handler.New(generated.NewExecutableSchema(generated.Config{Resolvers: graphqlResolver}))
+
+	// Subscriptions over websocket; the connection must carry the user
+	// forwarded by the router
+	srv.AddTransport(transport.Websocket{
+		KeepAlivePingInterval: 10 * time.Second,
+		InitFunc: func(ctx context.Context, initPayload transport.InitPayload) (context.Context, *transport.InitPayload, error) {
+			if _, ok := ctx.Value("user_id").(string); !ok {
+				return nil, nil, fmt.Errorf("unauthorized")
+			}
+			return ctx, nil, nil
+		},
 	})
+	srv.AddTransport(transport.Options{})
+	srv.AddTransport(transport.GET{})
+	srv.AddTransport(transport.POST{})
+	srv.SetQueryCache(lru.New(1000))
+	srv.Use(extension.Introspection{})
+	srv.Use(extension.AutomaticPersistedQuery{Cache: lru.New(100)})
+
+	mux.Handle("/graphql", rest.ForwardedUser(srv))
 
 	// GraphQL Playground (development only)
 	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
@@ -162,6 +291,8 @@ func main() {
 	<-quit
 
 	slog.Info("Shutting down server...")
+	stopWorkers()
+	jobScheduler.Wait()
 
 	// Graceful shutdown with timeout
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
@@ -173,8 +304,14 @@ func main() {
 	}
 
 	slog.Info("Server stopped")
+}
+
+// stubFavoriteService stands in for the Engagement favorites client, which
+// does not exist yet: Engagement neither serves favorites to other services
+// nor publishes its favorite events. Until it does, offerExpiringSoon accepts
+// subscriptions but never announces an offer.
+type stubFavoriteService struct{}
 
-	// Suppress unused variable warning
-	_ = graphqlResolver
-	_ = natsClient
+func (s *stubFavoriteService) GetFavoriteOfferIDs(ctx context.Context, userID domain.UserID) ([]domain.OfferID, error) {
+	return nil, nil
 }
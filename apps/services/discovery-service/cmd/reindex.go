@@ -0,0 +1,72 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	es "github.com/elastic/go-elasticsearch/v8"
+
+	"github.com/yousoon/discovery-service/internal/infrastructure/elasticsearch"
+	mongodb "github.com/yousoon/discovery-service/internal/infrastructure/mongodb"
+	"github.com/yousoon/shared/config"
+	sharedmongo "github.com/yousoon/shared/infrastructure/mongodb"
+)
+
+// newOfferSearchRepository connects to the Elasticsearch cluster holding the
+// offers index.
+func newOfferSearchRepository() (*elasticsearch.OfferSearchRepository, error) {
+	client, err := es.NewClient(es.Config{
+		Addresses: strings.Split(config.GetEnv("ELASTICSEARCH_URLS", "http://localhost:9200"), ","),
+		Username:  config.GetEnv("ELASTICSEARCH_USERNAME", ""),
+		Password:  config.GetEnv("ELASTICSEARCH_PASSWORD", ""),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Elasticsearch client: %w", err)
+	}
+
+	return elasticsearch.NewOfferSearchRepository(client), nil
+}
+
+// runReindex rebuilds the offers index from MongoDB and swaps it in without
+// downtime. Run as `discovery-service reindex`, e.g. from a Kubernetes Job
+// after a mapping change.
+func runReindex() error {
+	ctx := context.Background()
+
+	mongoClient, err := sharedmongo.NewClient(ctx, sharedmongo.Config{
+		URI:            config.GetEnv("MONGODB_URI", "mongodb://localhost:27017"),
+		Database:       config.GetEnv("MONGODB_DATABASE", "discovery_db"),
+		ConnectTimeout: 10 * time.Second,
+		MaxPoolSize:    10,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to connect to MongoDB: %w", err)
+	}
+	defer mongoClient.Close(ctx)
+
+	searchRepo, err := newOfferSearchRepository()
+	if err != nil {
+		return err
+	}
+
+	// Reads only, the outbox is never written to
+	offerRepo := mongodb.NewOfferRepository(mongoClient.Database(), nil)
+	reindexer := elasticsearch.NewReindexer(searchRepo, offerRepo, config.GetEnvInt("REINDEX_BATCH_SIZE", 500))
+
+	slog.Info("Reindexing offers...")
+	result, err := reindexer.Run(ctx)
+	if err != nil {
+		return err
+	}
+
+	slog.Info("Offers reindexed",
+		"index", result.Index,
+		"indexed", result.Indexed,
+		"caught_up", result.CaughtUp,
+		"removed", result.Removed,
+		"duration", result.Duration)
+	return nil
+}
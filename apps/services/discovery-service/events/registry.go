@@ -0,0 +1,34 @@
+// Package events registers the discovery domain events for deserialization.
+// It sits outside internal so that other services consuming discovery events
+// can register them: github.com/yousoon/discovery-service/events.Register.
+package events
+
+import (
+	"github.com/yousoon/discovery-service/internal/domain"
+	"github.com/yousoon/shared/infrastructure/nats"
+)
+
+// Register registers the discovery event types with the registry, so that
+// consumers holding it can deserialize the events published by this service.
+func Register(registry *nats.EventRegistry) {
+	// Offer events
+	registry.Register(&domain.OfferCreatedEvent{})
+	registry.Register(&domain.OfferSubmittedForReviewEvent{})
+	registry.Register(&domain.OfferApprovedEvent{})
+	registry.Register(&domain.OfferRejectedEvent{})
+	registry.Register(&domain.OfferPublishedEvent{})
+	registry.Register(&domain.OfferPausedEvent{})
+	registry.Register(&domain.OfferResumedEvent{})
+	registry.Register(&domain.OfferExpiredEvent{})
+	registry.Register(&domain.OfferArchivedEvent{})
+	registry.Register(&domain.OfferUpdatedEvent{})
+	registry.Register(&domain.OfferDeletedEvent{})
+	registry.Register(&domain.OfferBookedEvent{})
+	registry.Register(&domain.OfferFavoritedEvent{})
+	registry.Register(&domain.OfferUnfavoritedEvent{})
+	registry.Register(&domain.OfferViewedEvent{})
+
+	// Category events
+	registry.Register(&domain.CategoryCreatedEvent{})
+	registry.Register(&domain.CategoryUpdatedEvent{})
+}
@@ -3,6 +3,7 @@ package commands
 
 import (
 	"context"
+	"time"
 
 	"github.com/yousoon/discovery-service/internal/domain"
 )
@@ -178,6 +179,87 @@ func (h *PublishOfferHandler) Handle(ctx context.Context, cmd PublishOfferComman
 	return offer, nil
 }
 
+// =============================================================================
+// Schedule Offer Publication Command
+// =============================================================================
+
+// ScheduleOfferPublicationCommand has an offer published automatically once
+// approved. Without PublishAt it is published when its validity starts.
+type ScheduleOfferPublicationCommand struct {
+	OfferID   string
+	PublishAt *time.Time
+}
+
+// ScheduleOfferPublicationHandler handles the schedule publication command.
+type ScheduleOfferPublicationHandler struct {
+	offerRepo domain.OfferRepository
+}
+
+// NewScheduleOfferPublicationHandler creates a new handler.
+func NewScheduleOfferPublicationHandler(offerRepo domain.OfferRepository) *ScheduleOfferPublicationHandler {
+	return &ScheduleOfferPublicationHandler{
+		offerRepo: offerRepo,
+	}
+}
+
+// Handle executes the schedule publication command.
+func (h *ScheduleOfferPublicationHandler) Handle(ctx context.Context, cmd ScheduleOfferPublicationCommand) (*domain.Offer, error) {
+	offer, err := h.offerRepo.FindByID(ctx, domain.OfferID(cmd.OfferID))
+	if err != nil {
+		return nil, err
+	}
+	if offer == nil {
+		return nil, domain.ErrOfferNotFound
+	}
+
+	if err := offer.SchedulePublication(cmd.PublishAt); err != nil {
+		return nil, err
+	}
+
+	if err := h.offerRepo.Save(ctx, offer); err != nil {
+		return nil, err
+	}
+
+	return offer, nil
+}
+
+// CancelScheduledOfferPublicationCommand drops the chosen publication time, so
+// the offer is published when its validity starts.
+type CancelScheduledOfferPublicationCommand struct {
+	OfferID string
+}
+
+// CancelScheduledOfferPublicationHandler handles the cancel scheduled publication command.
+type CancelScheduledOfferPublicationHandler struct {
+	offerRepo domain.OfferRepository
+}
+
+// NewCancelScheduledOfferPublicationHandler creates a new handler.
+func NewCancelScheduledOfferPublicationHandler(offerRepo domain.OfferRepository) *CancelScheduledOfferPublicationHandler {
+	return &CancelScheduledOfferPublicationHandler{
+		offerRepo: offerRepo,
+	}
+}
+
+// Handle executes the cancel scheduled publication command.
+func (h *CancelScheduledOfferPublicationHandler) Handle(ctx context.Context, cmd CancelScheduledOfferPublicationCommand) (*domain.Offer, error) {
+	offer, err := h.offerRepo.FindByID(ctx, domain.OfferID(cmd.OfferID))
+	if err != nil {
+		return nil, err
+	}
+	if offer == nil {
+		return nil, domain.ErrOfferNotFound
+	}
+
+	offer.CancelScheduledPublication()
+
+	if err := h.offerRepo.Save(ctx, offer); err != nil {
+		return nil, err
+	}
+
+	return offer, nil
+}
+
 // =============================================================================
 // Pause Offer Command
 // =============================================================================
@@ -465,3 +547,107 @@ func (h *IncrementOfferViewsHandler) Handle(ctx context.Context, cmd IncrementOf
 
 	return h.offerRepo.Save(ctx, offer)
 }
+
+// =============================================================================
+// Publish Scheduled Offers Command (System)
+// =============================================================================
+
+// PublishScheduledOffersCommand publishes the approved offers whose scheduled
+// publication time has come.
+type PublishScheduledOffersCommand struct {
+	BatchSize int
+}
+
+// ScheduledJobResult reports how many offers a system command changed and
+// how many it failed to.
+type ScheduledJobResult struct {
+	Processed int
+	Failed    int
+}
+
+// PublishScheduledOffersHandler handles the publish scheduled offers command.
+type PublishScheduledOffersHandler struct {
+	offerRepo domain.OfferRepository
+}
+
+// NewPublishScheduledOffersHandler creates a new handler.
+func NewPublishScheduledOffersHandler(offerRepo domain.OfferRepository) *PublishScheduledOffersHandler {
+	return &PublishScheduledOffersHandler{
+		offerRepo: offerRepo,
+	}
+}
+
+// Handle executes the publish scheduled offers command.
+func (h *PublishScheduledOffersHandler) Handle(ctx context.Context, cmd PublishScheduledOffersCommand) (*ScheduledJobResult, error) {
+	now := time.Now()
+	offers, err := h.offerRepo.FindDueForPublication(ctx, now, cmd.BatchSize)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &ScheduledJobResult{}
+	for _, offer := range offers {
+		if !offer.IsDueForPublication(now) {
+			continue
+		}
+		if err := offer.Publish(); err != nil {
+			result.Failed++
+			continue
+		}
+		if err := h.offerRepo.Save(ctx, offer); err != nil {
+			result.Failed++
+			continue
+		}
+		result.Processed++
+	}
+
+	return result, nil
+}
+
+// =============================================================================
+// Expire Offers Command (System)
+// =============================================================================
+
+// ExpireOffersCommand expires the offers whose validity ended.
+type ExpireOffersCommand struct {
+	BatchSize int
+}
+
+// ExpireOffersHandler handles the expire offers command.
+type ExpireOffersHandler struct {
+	offerRepo domain.OfferRepository
+}
+
+// NewExpireOffersHandler creates a new handler.
+func NewExpireOffersHandler(offerRepo domain.OfferRepository) *ExpireOffersHandler {
+	return &ExpireOffersHandler{
+		offerRepo: offerRepo,
+	}
+}
+
+// Handle executes the expire offers command.
+func (h *ExpireOffersHandler) Handle(ctx context.Context, cmd ExpireOffersCommand) (*ScheduledJobResult, error) {
+	now := time.Now()
+	offers, err := h.offerRepo.FindPastEndDate(ctx, now, cmd.BatchSize)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &ScheduledJobResult{}
+	for _, offer := range offers {
+		if !offer.IsPastEndDate(now) {
+			continue
+		}
+		if err := offer.Expire(); err != nil {
+			result.Failed++
+			continue
+		}
+		if err := h.offerRepo.Save(ctx, offer); err != nil {
+			result.Failed++
+			continue
+		}
+		result.Processed++
+	}
+
+	return result, nil
+}
@@ -6,6 +6,8 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+
+	shareddomain "github.com/yousoon/shared/domain"
 )
 
 // Category represents a category of offers.
@@ -24,7 +26,7 @@ type Category struct {
 	updatedAt   time.Time
 
 	// Domain events
-	events []interface{}
+	events []shareddomain.DomainEvent
 }
 
 // LocalizedString represents a string with translations.
@@ -59,7 +61,7 @@ func NewCategory(
 	}
 
 	now := time.Now()
-	return &Category{
+	category := &Category{
 		id:   CategoryID(uuid.New().String()),
 		slug: slug,
 		name: LocalizedString{
@@ -71,8 +73,18 @@ func NewCategory(
 		order:     0,
 		createdAt: now,
 		updatedAt: now,
-		events:    make([]interface{}, 0),
-	}, nil
+		events:    make([]shareddomain.DomainEvent, 0),
+	}
+
+	category.events = append(category.events, CategoryCreatedEvent{
+		ID:         newEventID(),
+		CategoryID: category.id,
+		Slug:       category.slug,
+		Name:       category.name.FR,
+		Timestamp:  now,
+	})
+
+	return category, nil
 }
 
 // Getters
@@ -89,8 +101,11 @@ func (c *Category) Order() int                   { return c.order }
 func (c *Category) IsActive() bool               { return c.isActive }
 func (c *Category) CreatedAt() time.Time         { return c.createdAt }
 func (c *Category) UpdatedAt() time.Time         { return c.updatedAt }
-func (c *Category) Events() []interface{}        { return c.events }
-func (c *Category) ClearEvents()                 { c.events = make([]interface{}, 0) }
+
+// Domain events raised since the category was loaded
+
+func (c *Category) Events() []shareddomain.DomainEvent { return c.events }
+func (c *Category) ClearEvents()                       { c.events = make([]shareddomain.DomainEvent, 0) }
 
 // IsRoot checks if the category is a root category (no parent).
 func (c *Category) IsRoot() bool {
@@ -107,14 +122,14 @@ func (c *Category) UpdateName(fr, en string) error {
 		return errors.New("name (FR) is required")
 	}
 	c.name = LocalizedString{FR: fr, EN: en}
-	c.updatedAt = time.Now()
+	c.touch()
 	return nil
 }
 
 // UpdateDescription updates the category description.
 func (c *Category) UpdateDescription(fr, en string) {
 	c.description = LocalizedString{FR: fr, EN: en}
-	c.updatedAt = time.Now()
+	c.touch()
 }
 
 // UpdateSlug updates the category slug.
@@ -123,50 +138,64 @@ func (c *Category) UpdateSlug(slug string) error {
 		return errors.New("slug is required")
 	}
 	c.slug = slug
-	c.updatedAt = time.Now()
+	c.touch()
 	return nil
 }
 
 // UpdateIcon updates the category icon.
 func (c *Category) UpdateIcon(icon string) {
 	c.icon = icon
-	c.updatedAt = time.Now()
+	c.touch()
 }
 
 // UpdateColor updates the category color.
 func (c *Category) UpdateColor(color string) {
 	c.color = color
-	c.updatedAt = time.Now()
+	c.touch()
 }
 
 // UpdateImage updates the category image.
 func (c *Category) UpdateImage(image string) {
 	c.image = image
-	c.updatedAt = time.Now()
+	c.touch()
 }
 
 // SetParent sets the parent category.
 func (c *Category) SetParent(parentID *CategoryID) {
 	c.parentID = parentID
-	c.updatedAt = time.Now()
+	c.touch()
 }
 
 // SetOrder sets the display order.
 func (c *Category) SetOrder(order int) {
 	c.order = order
-	c.updatedAt = time.Now()
+	c.touch()
 }
 
 // Activate activates the category.
 func (c *Category) Activate() {
 	c.isActive = true
-	c.updatedAt = time.Now()
+	c.touch()
 }
 
 // Deactivate deactivates the category.
 func (c *Category) Deactivate() {
 	c.isActive = false
+	c.touch()
+}
+
+// touch records a change. Changes saved together raise a single update event,
+// and none alongside the creation event.
+func (c *Category) touch() {
 	c.updatedAt = time.Now()
+	if len(c.events) > 0 {
+		return
+	}
+	c.events = append(c.events, CategoryUpdatedEvent{
+		ID:         newEventID(),
+		CategoryID: c.id,
+		Timestamp:  c.updatedAt,
+	})
 }
 
 // =============================================================================
@@ -201,7 +230,7 @@ func ReconstructCategory(
 		isActive:    isActive,
 		createdAt:   createdAt,
 		updatedAt:   updatedAt,
-		events:      make([]interface{}, 0),
+		events:      make([]shareddomain.DomainEvent, 0),
 	}
 }
 
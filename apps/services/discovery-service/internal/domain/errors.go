@@ -15,6 +15,7 @@ var (
 	ErrUserQuotaExceeded       = errors.New("user has exceeded their quota for this offer")
 	ErrOfferAlreadyPublished   = errors.New("offer is already published")
 	ErrOfferAlreadyArchived    = errors.New("offer is already archived")
+	ErrInvalidPublishAt        = errors.New("publication date must be before the offer ends")
 
 	// Category errors
 	ErrCategoryNotFound    = errors.New("category not found")
@@ -1,7 +1,20 @@
 // Package domain contains domain events for the Discovery service.
 package domain
 
-import "time"
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// newEventID identifies an event instance, so that consumers can deduplicate
+// redeliveries. Events implement shared/domain.DomainEvent; they are stored in
+// the outbox with the aggregate that raised them and relayed to NATS on
+// yousoon.events.<event name>.
+func newEventID() string {
+	return uuid.New().String()
+}
 
 // =============================================================================
 // Offer Events
@@ -9,6 +22,7 @@ import "time"
 
 // OfferCreatedEvent is raised when an offer is created.
 type OfferCreatedEvent struct {
+	ID              string          `json:"eventId"`
 	OfferID         OfferID         `json:"offerId"`
 	PartnerID       PartnerID       `json:"partnerId"`
 	EstablishmentID EstablishmentID `json:"establishmentId"`
@@ -17,12 +31,17 @@ type OfferCreatedEvent struct {
 	Timestamp       time.Time       `json:"timestamp"`
 }
 
-func (e OfferCreatedEvent) EventName() string     { return "discovery.offer.created" }
-func (e OfferCreatedEvent) OccurredAt() time.Time { return e.Timestamp }
-func (e OfferCreatedEvent) AggregateID() string   { return e.OfferID.String() }
+func (e OfferCreatedEvent) EventName() string        { return "discovery.offer.created" }
+func (e OfferCreatedEvent) OccurredAt() time.Time    { return e.Timestamp }
+func (e OfferCreatedEvent) AggregateID() string      { return e.OfferID.String() }
+func (e OfferCreatedEvent) EventID() string          { return e.ID }
+func (e OfferCreatedEvent) AggregateType() string    { return "Offer" }
+func (e OfferCreatedEvent) Version() int             { return 1 }
+func (e OfferCreatedEvent) Payload() ([]byte, error) { return json.Marshal(e) }
 
 // OfferSubmittedForReviewEvent is raised when an offer is submitted for moderation.
 type OfferSubmittedForReviewEvent struct {
+	ID        string    `json:"eventId"`
 	OfferID   OfferID   `json:"offerId"`
 	PartnerID PartnerID `json:"partnerId"`
 	Timestamp time.Time `json:"timestamp"`
@@ -31,35 +50,50 @@ type OfferSubmittedForReviewEvent struct {
 func (e OfferSubmittedForReviewEvent) EventName() string {
 	return "discovery.offer.submitted_for_review"
 }
-func (e OfferSubmittedForReviewEvent) OccurredAt() time.Time { return e.Timestamp }
-func (e OfferSubmittedForReviewEvent) AggregateID() string   { return e.OfferID.String() }
+func (e OfferSubmittedForReviewEvent) OccurredAt() time.Time    { return e.Timestamp }
+func (e OfferSubmittedForReviewEvent) AggregateID() string      { return e.OfferID.String() }
+func (e OfferSubmittedForReviewEvent) EventID() string          { return e.ID }
+func (e OfferSubmittedForReviewEvent) AggregateType() string    { return "Offer" }
+func (e OfferSubmittedForReviewEvent) Version() int             { return 1 }
+func (e OfferSubmittedForReviewEvent) Payload() ([]byte, error) { return json.Marshal(e) }
 
 // OfferApprovedEvent is raised when an offer is approved by moderation.
 type OfferApprovedEvent struct {
+	ID         string    `json:"eventId"`
 	OfferID    OfferID   `json:"offerId"`
 	PartnerID  PartnerID `json:"partnerId"`
 	ReviewerID string    `json:"reviewerId"`
 	Timestamp  time.Time `json:"timestamp"`
 }
 
-func (e OfferApprovedEvent) EventName() string     { return "discovery.offer.approved" }
-func (e OfferApprovedEvent) OccurredAt() time.Time { return e.Timestamp }
-func (e OfferApprovedEvent) AggregateID() string   { return e.OfferID.String() }
+func (e OfferApprovedEvent) EventName() string        { return "discovery.offer.approved" }
+func (e OfferApprovedEvent) OccurredAt() time.Time    { return e.Timestamp }
+func (e OfferApprovedEvent) AggregateID() string      { return e.OfferID.String() }
+func (e OfferApprovedEvent) EventID() string          { return e.ID }
+func (e OfferApprovedEvent) AggregateType() string    { return "Offer" }
+func (e OfferApprovedEvent) Version() int             { return 1 }
+func (e OfferApprovedEvent) Payload() ([]byte, error) { return json.Marshal(e) }
 
 // OfferRejectedEvent is raised when an offer is rejected by moderation.
 type OfferRejectedEvent struct {
+	ID        string    `json:"eventId"`
 	OfferID   OfferID   `json:"offerId"`
 	PartnerID PartnerID `json:"partnerId"`
 	Reason    string    `json:"reason"`
 	Timestamp time.Time `json:"timestamp"`
 }
 
-func (e OfferRejectedEvent) EventName() string     { return "discovery.offer.rejected" }
-func (e OfferRejectedEvent) OccurredAt() time.Time { return e.Timestamp }
-func (e OfferRejectedEvent) AggregateID() string   { return e.OfferID.String() }
+func (e OfferRejectedEvent) EventName() string        { return "discovery.offer.rejected" }
+func (e OfferRejectedEvent) OccurredAt() time.Time    { return e.Timestamp }
+func (e OfferRejectedEvent) AggregateID() string      { return e.OfferID.String() }
+func (e OfferRejectedEvent) EventID() string          { return e.ID }
+func (e OfferRejectedEvent) AggregateType() string    { return "Offer" }
+func (e OfferRejectedEvent) Version() int             { return 1 }
+func (e OfferRejectedEvent) Payload() ([]byte, error) { return json.Marshal(e) }
 
 // OfferPublishedEvent is raised when an offer is published (becomes active).
 type OfferPublishedEvent struct {
+	ID              string          `json:"eventId"`
 	OfferID         OfferID         `json:"offerId"`
 	PartnerID       PartnerID       `json:"partnerId"`
 	EstablishmentID EstablishmentID `json:"establishmentId"`
@@ -68,98 +102,174 @@ type OfferPublishedEvent struct {
 	Timestamp       time.Time       `json:"timestamp"`
 }
 
-func (e OfferPublishedEvent) EventName() string     { return "discovery.offer.published" }
-func (e OfferPublishedEvent) OccurredAt() time.Time { return e.Timestamp }
-func (e OfferPublishedEvent) AggregateID() string   { return e.OfferID.String() }
+func (e OfferPublishedEvent) EventName() string        { return "discovery.offer.published" }
+func (e OfferPublishedEvent) OccurredAt() time.Time    { return e.Timestamp }
+func (e OfferPublishedEvent) AggregateID() string      { return e.OfferID.String() }
+func (e OfferPublishedEvent) EventID() string          { return e.ID }
+func (e OfferPublishedEvent) AggregateType() string    { return "Offer" }
+func (e OfferPublishedEvent) Version() int             { return 1 }
+func (e OfferPublishedEvent) Payload() ([]byte, error) { return json.Marshal(e) }
 
 // OfferPausedEvent is raised when an offer is paused.
 type OfferPausedEvent struct {
+	ID        string    `json:"eventId"`
 	OfferID   OfferID   `json:"offerId"`
 	PartnerID PartnerID `json:"partnerId"`
 	Timestamp time.Time `json:"timestamp"`
 }
 
-func (e OfferPausedEvent) EventName() string     { return "discovery.offer.paused" }
-func (e OfferPausedEvent) OccurredAt() time.Time { return e.Timestamp }
-func (e OfferPausedEvent) AggregateID() string   { return e.OfferID.String() }
+func (e OfferPausedEvent) EventName() string        { return "discovery.offer.paused" }
+func (e OfferPausedEvent) OccurredAt() time.Time    { return e.Timestamp }
+func (e OfferPausedEvent) AggregateID() string      { return e.OfferID.String() }
+func (e OfferPausedEvent) EventID() string          { return e.ID }
+func (e OfferPausedEvent) AggregateType() string    { return "Offer" }
+func (e OfferPausedEvent) Version() int             { return 1 }
+func (e OfferPausedEvent) Payload() ([]byte, error) { return json.Marshal(e) }
 
 // OfferResumedEvent is raised when an offer is resumed.
 type OfferResumedEvent struct {
+	ID        string    `json:"eventId"`
 	OfferID   OfferID   `json:"offerId"`
 	PartnerID PartnerID `json:"partnerId"`
 	Timestamp time.Time `json:"timestamp"`
 }
 
-func (e OfferResumedEvent) EventName() string     { return "discovery.offer.resumed" }
-func (e OfferResumedEvent) OccurredAt() time.Time { return e.Timestamp }
-func (e OfferResumedEvent) AggregateID() string   { return e.OfferID.String() }
+func (e OfferResumedEvent) EventName() string        { return "discovery.offer.resumed" }
+func (e OfferResumedEvent) OccurredAt() time.Time    { return e.Timestamp }
+func (e OfferResumedEvent) AggregateID() string      { return e.OfferID.String() }
+func (e OfferResumedEvent) EventID() string          { return e.ID }
+func (e OfferResumedEvent) AggregateType() string    { return "Offer" }
+func (e OfferResumedEvent) Version() int             { return 1 }
+func (e OfferResumedEvent) Payload() ([]byte, error) { return json.Marshal(e) }
 
 // OfferExpiredEvent is raised when an offer expires.
 type OfferExpiredEvent struct {
+	ID        string    `json:"eventId"`
 	OfferID   OfferID   `json:"offerId"`
 	PartnerID PartnerID `json:"partnerId"`
 	Timestamp time.Time `json:"timestamp"`
 }
 
-func (e OfferExpiredEvent) EventName() string     { return "discovery.offer.expired" }
-func (e OfferExpiredEvent) OccurredAt() time.Time { return e.Timestamp }
-func (e OfferExpiredEvent) AggregateID() string   { return e.OfferID.String() }
+func (e OfferExpiredEvent) EventName() string        { return "discovery.offer.expired" }
+func (e OfferExpiredEvent) OccurredAt() time.Time    { return e.Timestamp }
+func (e OfferExpiredEvent) AggregateID() string      { return e.OfferID.String() }
+func (e OfferExpiredEvent) EventID() string          { return e.ID }
+func (e OfferExpiredEvent) AggregateType() string    { return "Offer" }
+func (e OfferExpiredEvent) Version() int             { return 1 }
+func (e OfferExpiredEvent) Payload() ([]byte, error) { return json.Marshal(e) }
 
 // OfferArchivedEvent is raised when an offer is archived.
 type OfferArchivedEvent struct {
+	ID        string    `json:"eventId"`
+	OfferID   OfferID   `json:"offerId"`
+	PartnerID PartnerID `json:"partnerId"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+func (e OfferArchivedEvent) EventName() string        { return "discovery.offer.archived" }
+func (e OfferArchivedEvent) OccurredAt() time.Time    { return e.Timestamp }
+func (e OfferArchivedEvent) AggregateID() string      { return e.OfferID.String() }
+func (e OfferArchivedEvent) EventID() string          { return e.ID }
+func (e OfferArchivedEvent) AggregateType() string    { return "Offer" }
+func (e OfferArchivedEvent) Version() int             { return 1 }
+func (e OfferArchivedEvent) Payload() ([]byte, error) { return json.Marshal(e) }
+
+// OfferUpdatedEvent is raised when the content of an offer is edited.
+type OfferUpdatedEvent struct {
+	ID        string    `json:"eventId"`
+	OfferID   OfferID   `json:"offerId"`
+	PartnerID PartnerID `json:"partnerId"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+func (e OfferUpdatedEvent) EventName() string        { return "discovery.offer.updated" }
+func (e OfferUpdatedEvent) OccurredAt() time.Time    { return e.Timestamp }
+func (e OfferUpdatedEvent) AggregateID() string      { return e.OfferID.String() }
+func (e OfferUpdatedEvent) EventID() string          { return e.ID }
+func (e OfferUpdatedEvent) AggregateType() string    { return "Offer" }
+func (e OfferUpdatedEvent) Version() int             { return 1 }
+func (e OfferUpdatedEvent) Payload() ([]byte, error) { return json.Marshal(e) }
+
+// OfferDeletedEvent is raised when an offer is deleted.
+type OfferDeletedEvent struct {
+	ID        string    `json:"eventId"`
 	OfferID   OfferID   `json:"offerId"`
 	PartnerID PartnerID `json:"partnerId"`
 	Timestamp time.Time `json:"timestamp"`
 }
 
-func (e OfferArchivedEvent) EventName() string     { return "discovery.offer.archived" }
-func (e OfferArchivedEvent) OccurredAt() time.Time { return e.Timestamp }
-func (e OfferArchivedEvent) AggregateID() string   { return e.OfferID.String() }
+func (e OfferDeletedEvent) EventName() string        { return "discovery.offer.deleted" }
+func (e OfferDeletedEvent) OccurredAt() time.Time    { return e.Timestamp }
+func (e OfferDeletedEvent) AggregateID() string      { return e.OfferID.String() }
+func (e OfferDeletedEvent) EventID() string          { return e.ID }
+func (e OfferDeletedEvent) AggregateType() string    { return "Offer" }
+func (e OfferDeletedEvent) Version() int             { return 1 }
+func (e OfferDeletedEvent) Payload() ([]byte, error) { return json.Marshal(e) }
 
 // OfferBookedEvent is raised when an offer is booked (from Booking context).
 type OfferBookedEvent struct {
+	ID        string    `json:"eventId"`
 	OfferID   OfferID   `json:"offerId"`
 	UserID    UserID    `json:"userId"`
 	OutingID  string    `json:"outingId"`
 	Timestamp time.Time `json:"timestamp"`
 }
 
-func (e OfferBookedEvent) EventName() string     { return "discovery.offer.booked" }
-func (e OfferBookedEvent) OccurredAt() time.Time { return e.Timestamp }
-func (e OfferBookedEvent) AggregateID() string   { return e.OfferID.String() }
+func (e OfferBookedEvent) EventName() string        { return "discovery.offer.booked" }
+func (e OfferBookedEvent) OccurredAt() time.Time    { return e.Timestamp }
+func (e OfferBookedEvent) AggregateID() string      { return e.OfferID.String() }
+func (e OfferBookedEvent) EventID() string          { return e.ID }
+func (e OfferBookedEvent) AggregateType() string    { return "Offer" }
+func (e OfferBookedEvent) Version() int             { return 1 }
+func (e OfferBookedEvent) Payload() ([]byte, error) { return json.Marshal(e) }
 
 // OfferFavoritedEvent is raised when an offer is added to favorites.
 type OfferFavoritedEvent struct {
+	ID        string    `json:"eventId"`
 	OfferID   OfferID   `json:"offerId"`
 	UserID    UserID    `json:"userId"`
 	Timestamp time.Time `json:"timestamp"`
 }
 
-func (e OfferFavoritedEvent) EventName() string     { return "discovery.offer.favorited" }
-func (e OfferFavoritedEvent) OccurredAt() time.Time { return e.Timestamp }
-func (e OfferFavoritedEvent) AggregateID() string   { return e.OfferID.String() }
+func (e OfferFavoritedEvent) EventName() string        { return "discovery.offer.favorited" }
+func (e OfferFavoritedEvent) OccurredAt() time.Time    { return e.Timestamp }
+func (e OfferFavoritedEvent) AggregateID() string      { return e.OfferID.String() }
+func (e OfferFavoritedEvent) EventID() string          { return e.ID }
+func (e OfferFavoritedEvent) AggregateType() string    { return "Offer" }
+func (e OfferFavoritedEvent) Version() int             { return 1 }
+func (e OfferFavoritedEvent) Payload() ([]byte, error) { return json.Marshal(e) }
 
 // OfferUnfavoritedEvent is raised when an offer is removed from favorites.
 type OfferUnfavoritedEvent struct {
+	ID        string    `json:"eventId"`
 	OfferID   OfferID   `json:"offerId"`
 	UserID    UserID    `json:"userId"`
 	Timestamp time.Time `json:"timestamp"`
 }
 
-func (e OfferUnfavoritedEvent) EventName() string     { return "discovery.offer.unfavorited" }
-func (e OfferUnfavoritedEvent) OccurredAt() time.Time { return e.Timestamp }
-func (e OfferUnfavoritedEvent) AggregateID() string   { return e.OfferID.String() }
+func (e OfferUnfavoritedEvent) EventName() string        { return "discovery.offer.unfavorited" }
+func (e OfferUnfavoritedEvent) OccurredAt() time.Time    { return e.Timestamp }
+func (e OfferUnfavoritedEvent) AggregateID() string      { return e.OfferID.String() }
+func (e OfferUnfavoritedEvent) EventID() string          { return e.ID }
+func (e OfferUnfavoritedEvent) AggregateType() string    { return "Offer" }
+func (e OfferUnfavoritedEvent) Version() int             { return 1 }
+func (e OfferUnfavoritedEvent) Payload() ([]byte, error) { return json.Marshal(e) }
 
 // OfferViewedEvent is raised when an offer is viewed.
 type OfferViewedEvent struct {
+	ID        string    `json:"eventId"`
 	OfferID   OfferID   `json:"offerId"`
 	UserID    *UserID   `json:"userId,omitempty"` // nil if anonymous
 	Timestamp time.Time `json:"timestamp"`
 }
 
-func (e OfferViewedEvent) EventName() string     { return "discovery.offer.viewed" }
-func (e OfferViewedEvent) OccurredAt() time.Time { return e.Timestamp }
-func (e OfferViewedEvent) AggregateID() string   { return e.OfferID.String() }
+func (e OfferViewedEvent) EventName() string        { return "discovery.offer.viewed" }
+func (e OfferViewedEvent) OccurredAt() time.Time    { return e.Timestamp }
+func (e OfferViewedEvent) AggregateID() string      { return e.OfferID.String() }
+func (e OfferViewedEvent) EventID() string          { return e.ID }
+func (e OfferViewedEvent) AggregateType() string    { return "Offer" }
+func (e OfferViewedEvent) Version() int             { return 1 }
+func (e OfferViewedEvent) Payload() ([]byte, error) { return json.Marshal(e) }
 
 // =============================================================================
 // Category Events
@@ -167,22 +277,32 @@ func (e OfferViewedEvent) AggregateID() string   { return e.OfferID.String() }
 
 // CategoryCreatedEvent is raised when a category is created.
 type CategoryCreatedEvent struct {
+	ID         string     `json:"eventId"`
 	CategoryID CategoryID `json:"categoryId"`
 	Slug       string     `json:"slug"`
 	Name       string     `json:"name"`
 	Timestamp  time.Time  `json:"timestamp"`
 }
 
-func (e CategoryCreatedEvent) EventName() string     { return "discovery.category.created" }
-func (e CategoryCreatedEvent) OccurredAt() time.Time { return e.Timestamp }
-func (e CategoryCreatedEvent) AggregateID() string   { return e.CategoryID.String() }
+func (e CategoryCreatedEvent) EventName() string        { return "discovery.category.created" }
+func (e CategoryCreatedEvent) OccurredAt() time.Time    { return e.Timestamp }
+func (e CategoryCreatedEvent) AggregateID() string      { return e.CategoryID.String() }
+func (e CategoryCreatedEvent) EventID() string          { return e.ID }
+func (e CategoryCreatedEvent) AggregateType() string    { return "Category" }
+func (e CategoryCreatedEvent) Version() int             { return 1 }
+func (e CategoryCreatedEvent) Payload() ([]byte, error) { return json.Marshal(e) }
 
 // CategoryUpdatedEvent is raised when a category is updated.
 type CategoryUpdatedEvent struct {
+	ID         string     `json:"eventId"`
 	CategoryID CategoryID `json:"categoryId"`
 	Timestamp  time.Time  `json:"timestamp"`
 }
 
-func (e CategoryUpdatedEvent) EventName() string     { return "discovery.category.updated" }
-func (e CategoryUpdatedEvent) OccurredAt() time.Time { return e.Timestamp }
-func (e CategoryUpdatedEvent) AggregateID() string   { return e.CategoryID.String() }
+func (e CategoryUpdatedEvent) EventName() string        { return "discovery.category.updated" }
+func (e CategoryUpdatedEvent) OccurredAt() time.Time    { return e.Timestamp }
+func (e CategoryUpdatedEvent) AggregateID() string      { return e.CategoryID.String() }
+func (e CategoryUpdatedEvent) EventID() string          { return e.ID }
+func (e CategoryUpdatedEvent) AggregateType() string    { return "Category" }
+func (e CategoryUpdatedEvent) Version() int             { return 1 }
+func (e CategoryUpdatedEvent) Payload() ([]byte, error) { return json.Marshal(e) }
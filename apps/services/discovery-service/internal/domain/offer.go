@@ -7,6 +7,8 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+
+	shareddomain "github.com/yousoon/shared/domain"
 )
 
 // OfferStatus represents the status of an offer.
@@ -79,8 +81,11 @@ type Offer struct {
 	publishedAt *time.Time
 	deletedAt   *time.Time
 
+	// Scheduled publication, nil when published manually
+	publishAt *time.Time
+
 	// Domain events
-	events []interface{}
+	events []shareddomain.DomainEvent
 }
 
 // NewOffer creates a new Offer.
@@ -125,10 +130,11 @@ func NewOffer(
 		stats:     OfferStats{},
 		createdAt: now,
 		updatedAt: now,
-		events:    make([]interface{}, 0),
+		events:    make([]shareddomain.DomainEvent, 0),
 	}
 
 	offer.events = append(offer.events, OfferCreatedEvent{
+		ID:              newEventID(),
 		OfferID:         offer.id,
 		PartnerID:       offer.partnerID,
 		EstablishmentID: offer.establishmentID,
@@ -165,9 +171,10 @@ func (o *Offer) Moderation() Moderation                       { return o.moderat
 func (o *Offer) CreatedAt() time.Time                         { return o.createdAt }
 func (o *Offer) UpdatedAt() time.Time                         { return o.updatedAt }
 func (o *Offer) PublishedAt() *time.Time                      { return o.publishedAt }
+func (o *Offer) PublishAt() *time.Time                        { return o.publishAt }
 func (o *Offer) DeletedAt() *time.Time                        { return o.deletedAt }
-func (o *Offer) Events() []interface{}                        { return o.events }
-func (o *Offer) ClearEvents()                                 { o.events = make([]interface{}, 0) }
+func (o *Offer) Events() []shareddomain.DomainEvent           { return o.events }
+func (o *Offer) ClearEvents()                                 { o.events = make([]shareddomain.DomainEvent, 0) }
 
 // IsActive checks if the offer is currently active and bookable.
 func (o *Offer) IsActive() bool {
@@ -183,6 +190,26 @@ func (o *Offer) IsActive() bool {
 	return true
 }
 
+// Location returns the timezone the offer's schedule is expressed in.
+func (o *Offer) Location() *time.Location {
+	return o.validity.Location()
+}
+
+// IsAvailableAt checks if the offer is active and its schedule open at t,
+// evaluated in the offer's timezone. Booking and search use it to ask whether
+// an offer is available at a given time, e.g. at 23:30 tonight.
+func (o *Offer) IsAvailableAt(t time.Time) bool {
+	if o.status != OfferStatusActive {
+		return false
+	}
+	return o.validity.IsActiveAt(t) && o.schedule.IsAvailableAt(t, o.Location())
+}
+
+// IsExpiringSoon reports whether the offer is active and ends within the window.
+func (o *Offer) IsExpiringSoon(window time.Duration) bool {
+	return o.IsActive() && time.Until(o.validity.EndDate) <= window
+}
+
 // CanBeBooked checks if the offer can be booked right now.
 func (o *Offer) CanBeBooked() error {
 	if !o.IsActive() {
@@ -191,7 +218,7 @@ func (o *Offer) CanBeBooked() error {
 	if o.quota.IsExhausted() {
 		return ErrOfferFullyBooked
 	}
-	if !o.schedule.IsAvailableNow() {
+	if !o.schedule.IsAvailableNow(o.Location()) {
 		return ErrOfferNotAvailableNow
 	}
 	return nil
@@ -220,7 +247,7 @@ func (o *Offer) UpdateBasicInfo(title, description, shortDescription string) err
 	o.title = title
 	o.description = description
 	o.shortDescription = shortDescription
-	o.updatedAt = time.Now()
+	o.touch()
 	return nil
 }
 
@@ -230,14 +257,14 @@ func (o *Offer) UpdateCategory(categoryID CategoryID) error {
 		return errors.New("category ID is required")
 	}
 	o.categoryID = categoryID
-	o.updatedAt = time.Now()
+	o.touch()
 	return nil
 }
 
 // UpdateTags updates the tags of the offer.
 func (o *Offer) UpdateTags(tags []string) {
 	o.tags = tags
-	o.updatedAt = time.Now()
+	o.touch()
 }
 
 // UpdateDiscount updates the discount of the offer.
@@ -246,7 +273,7 @@ func (o *Offer) UpdateDiscount(discount Discount) error {
 		return err
 	}
 	o.discount = discount
-	o.updatedAt = time.Now()
+	o.touch()
 	return nil
 }
 
@@ -254,7 +281,7 @@ func (o *Offer) UpdateDiscount(discount Discount) error {
 func (o *Offer) UpdateConditions(conditions []Condition, terms string) {
 	o.conditions = conditions
 	o.termsAndConditions = terms
-	o.updatedAt = time.Now()
+	o.touch()
 }
 
 // UpdateValidity updates the validity period of the offer.
@@ -263,20 +290,20 @@ func (o *Offer) UpdateValidity(validity Validity) error {
 		return err
 	}
 	o.validity = validity
-	o.updatedAt = time.Now()
+	o.touch()
 	return nil
 }
 
 // UpdateSchedule updates the schedule of the offer.
 func (o *Offer) UpdateSchedule(schedule Schedule) {
 	o.schedule = schedule
-	o.updatedAt = time.Now()
+	o.touch()
 }
 
 // UpdateQuota updates the quota of the offer.
 func (o *Offer) UpdateQuota(quota Quota) {
 	o.quota = quota
-	o.updatedAt = time.Now()
+	o.touch()
 }
 
 // AddImage adds an image to the offer.
@@ -291,7 +318,7 @@ func (o *Offer) AddImage(image OfferImage) {
 	}
 	image.Order = len(o.images)
 	o.images = append(o.images, image)
-	o.updatedAt = time.Now()
+	o.touch()
 }
 
 // RemoveImage removes an image from the offer.
@@ -307,19 +334,35 @@ func (o *Offer) RemoveImage(url string) {
 	for i := range o.images {
 		o.images[i].Order = i
 	}
-	o.updatedAt = time.Now()
+	o.touch()
 }
 
 // SetPartnerSnapshot sets the denormalized partner data.
 func (o *Offer) SetPartnerSnapshot(snapshot PartnerSnapshot) {
 	o.partnerSnapshot = snapshot
-	o.updatedAt = time.Now()
+	o.touch()
 }
 
 // SetEstablishmentSnapshot sets the denormalized establishment data.
 func (o *Offer) SetEstablishmentSnapshot(snapshot EstablishmentSnapshot) {
 	o.establishmentSnapshot = snapshot
+	o.touch()
+}
+
+// touch records an edit. Edits saved together raise a single
+// OfferUpdatedEvent, none when the offer was created or changed status in the
+// same save, whose event already tells the offer changed.
+func (o *Offer) touch() {
 	o.updatedAt = time.Now()
+	if len(o.events) > 0 {
+		return
+	}
+	o.events = append(o.events, OfferUpdatedEvent{
+		ID:        newEventID(),
+		OfferID:   o.id,
+		PartnerID: o.partnerID,
+		Timestamp: o.updatedAt,
+	})
 }
 
 // =============================================================================
@@ -339,6 +382,7 @@ func (o *Offer) SubmitForReview() error {
 	o.updatedAt = time.Now()
 
 	o.events = append(o.events, OfferSubmittedForReviewEvent{
+		ID:        newEventID(),
 		OfferID:   o.id,
 		PartnerID: o.partnerID,
 		Timestamp: time.Now(),
@@ -361,6 +405,14 @@ func (o *Offer) Approve(reviewerID string) error {
 	}
 	o.updatedAt = now
 
+	o.events = append(o.events, OfferApprovedEvent{
+		ID:         newEventID(),
+		OfferID:    o.id,
+		PartnerID:  o.partnerID,
+		ReviewerID: reviewerID,
+		Timestamp:  now,
+	})
+
 	return nil
 }
 
@@ -381,6 +433,7 @@ func (o *Offer) Reject(reviewerID, reason string) error {
 	o.updatedAt = now
 
 	o.events = append(o.events, OfferRejectedEvent{
+		ID:        newEventID(),
 		OfferID:   o.id,
 		PartnerID: o.partnerID,
 		Reason:    reason,
@@ -402,9 +455,11 @@ func (o *Offer) Publish() error {
 	now := time.Now()
 	o.status = OfferStatusActive
 	o.publishedAt = &now
+	o.publishAt = nil
 	o.updatedAt = now
 
 	o.events = append(o.events, OfferPublishedEvent{
+		ID:              newEventID(),
 		OfferID:         o.id,
 		PartnerID:       o.partnerID,
 		EstablishmentID: o.establishmentID,
@@ -416,6 +471,61 @@ func (o *Offer) Publish() error {
 	return nil
 }
 
+// SchedulePublication has the offer published automatically at the given
+// time once approved, or when its validity starts without one.
+func (o *Offer) SchedulePublication(at *time.Time) error {
+	if o.status != OfferStatusDraft && o.status != OfferStatusPending {
+		return ErrInvalidStatusTransition
+	}
+
+	publishAt := o.validity.StartDate
+	if at != nil {
+		publishAt = *at
+	}
+	if !publishAt.Before(o.validity.EndDate) {
+		return ErrInvalidPublishAt
+	}
+
+	o.publishAt = &publishAt
+	o.touch()
+	return nil
+}
+
+// CancelScheduledPublication drops the chosen publication time, so the offer
+// is published when its validity starts, unless the partner publishes it first.
+func (o *Offer) CancelScheduledPublication() {
+	if o.publishAt == nil {
+		return
+	}
+	o.publishAt = nil
+	o.touch()
+}
+
+// IsDueForPublication reports whether the offer is approved and its
+// publication time has come, before it ended: the scheduled time, or the
+// start of its validity when none was chosen.
+func (o *Offer) IsDueForPublication(now time.Time) bool {
+	publishAt := o.validity.StartDate
+	if o.publishAt != nil {
+		publishAt = *o.publishAt
+	}
+
+	return o.status == OfferStatusPending &&
+		o.moderation.Status == ModerationStatusApproved &&
+		!now.Before(publishAt) &&
+		now.Before(o.validity.EndDate)
+}
+
+// IsPastEndDate reports whether the offer is live or about to be and its
+// validity has ended, so it must be expired.
+func (o *Offer) IsPastEndDate(now time.Time) bool {
+	switch o.status {
+	case OfferStatusPending, OfferStatusActive, OfferStatusPaused:
+		return !now.Before(o.validity.EndDate)
+	}
+	return false
+}
+
 // Pause pauses the offer temporarily.
 func (o *Offer) Pause() error {
 	if o.status != OfferStatusActive {
@@ -426,6 +536,7 @@ func (o *Offer) Pause() error {
 	o.updatedAt = time.Now()
 
 	o.events = append(o.events, OfferPausedEvent{
+		ID:        newEventID(),
 		OfferID:   o.id,
 		PartnerID: o.partnerID,
 		Timestamp: time.Now(),
@@ -443,6 +554,13 @@ func (o *Offer) Resume() error {
 	o.status = OfferStatusActive
 	o.updatedAt = time.Now()
 
+	o.events = append(o.events, OfferResumedEvent{
+		ID:        newEventID(),
+		OfferID:   o.id,
+		PartnerID: o.partnerID,
+		Timestamp: o.updatedAt,
+	})
+
 	return nil
 }
 
@@ -456,6 +574,7 @@ func (o *Offer) Expire() error {
 	o.updatedAt = time.Now()
 
 	o.events = append(o.events, OfferExpiredEvent{
+		ID:        newEventID(),
 		OfferID:   o.id,
 		PartnerID: o.partnerID,
 		Timestamp: time.Now(),
@@ -473,6 +592,13 @@ func (o *Offer) Archive() error {
 	o.status = OfferStatusArchived
 	o.updatedAt = time.Now()
 
+	o.events = append(o.events, OfferArchivedEvent{
+		ID:        newEventID(),
+		OfferID:   o.id,
+		PartnerID: o.partnerID,
+		Timestamp: o.updatedAt,
+	})
+
 	return nil
 }
 
@@ -481,6 +607,14 @@ func (o *Offer) Delete() error {
 	now := time.Now()
 	o.deletedAt = &now
 	o.updatedAt = now
+
+	o.events = append(o.events, OfferDeletedEvent{
+		ID:        newEventID(),
+		OfferID:   o.id,
+		PartnerID: o.partnerID,
+		Timestamp: now,
+	})
+
 	return nil
 }
 
@@ -587,6 +721,7 @@ func ReconstructOffer(
 	updatedAt time.Time,
 	publishedAt *time.Time,
 	deletedAt *time.Time,
+	publishAt *time.Time,
 ) *Offer {
 	return &Offer{
 		id:                    id,
@@ -613,6 +748,7 @@ func ReconstructOffer(
 		updatedAt:             updatedAt,
 		publishedAt:           publishedAt,
 		deletedAt:             deletedAt,
-		events:                make([]interface{}, 0),
+		publishAt:             publishAt,
+		events:                make([]shareddomain.DomainEvent, 0),
 	}
 }
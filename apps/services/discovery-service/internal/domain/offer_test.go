@@ -164,3 +164,364 @@ func TestValidity_IsActive(t *testing.T) {
 		t.Error("Validity.IsActive() should return true for current period")
 	}
 }
+
+// =============================================================================
+// Schedule Tests
+// =============================================================================
+
+func TestSchedule_IsAvailableAt(t *testing.T) {
+	paris, err := time.LoadLocation("Europe/Paris")
+	if err != nil {
+		t.Skip("timezone database not available")
+	}
+
+	// Friday evening until 2am
+	schedule := NewScheduleWithSlots([]TimeSlot{
+		{DayOfWeek: int(time.Friday), StartTime: "22:00", EndTime: "02:00"},
+	})
+
+	tests := []struct {
+		name string
+		at   time.Time
+		want bool
+	}{
+		{"friday 23:30", time.Date(2026, 10, 16, 23, 30, 0, 0, paris), true},
+		{"saturday 01:30", time.Date(2026, 10, 17, 1, 30, 0, 0, paris), true},
+		{"saturday 02:00", time.Date(2026, 10, 17, 2, 0, 0, 0, paris), false},
+		{"saturday 03:00", time.Date(2026, 10, 17, 3, 0, 0, 0, paris), false},
+		{"friday 21:00", time.Date(2026, 10, 16, 21, 0, 0, 0, paris), false},
+		{"thursday 01:30", time.Date(2026, 10, 15, 1, 30, 0, 0, paris), false},
+		// 21:30 UTC is 23:30 in Paris
+		{"same instant in UTC", time.Date(2026, 10, 16, 21, 30, 0, 0, time.UTC), true},
+	}
+	for _, tt := range tests {
+		if got := schedule.IsAvailableAt(tt.at, paris); got != tt.want {
+			t.Errorf("%s: IsAvailableAt() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestSchedule_IsAvailableAt_DST(t *testing.T) {
+	paris, err := time.LoadLocation("Europe/Paris")
+	if err != nil {
+		t.Skip("timezone database not available")
+	}
+
+	schedule := NewScheduleWithSlots([]TimeSlot{
+		{DayOfWeek: int(time.Sunday), StartTime: "09:00", EndTime: "12:00"},
+	})
+
+	// Clocks go forward on 29 March 2026: 07:30 UTC is 09:30 local, not 08:30
+	if !schedule.IsAvailableAt(time.Date(2026, 3, 29, 7, 30, 0, 0, time.UTC), paris) {
+		t.Error("Slot should be open at 09:30 local time on the DST day")
+	}
+	// The week before, 07:30 UTC is 08:30 local
+	if schedule.IsAvailableAt(time.Date(2026, 3, 22, 7, 30, 0, 0, time.UTC), paris) {
+		t.Error("Slot should not be open at 08:30 local time")
+	}
+}
+
+func TestSchedule_IsAvailableOn_Bounds(t *testing.T) {
+	schedule := NewScheduleWithSlots([]TimeSlot{
+		{DayOfWeek: int(time.Monday), StartTime: "17:00", EndTime: "19:00"},
+		{DayOfWeek: int(time.Tuesday), StartTime: "20:00", EndTime: "24:00"},
+	})
+
+	tests := []struct {
+		day  time.Weekday
+		time string
+		want bool
+	}{
+		{time.Monday, "17:00", true},
+		{time.Monday, "18:59", true},
+		{time.Monday, "19:00", false},
+		{time.Tuesday, "23:59", true},
+		{time.Wednesday, "00:00", false},
+	}
+	for _, tt := range tests {
+		if got := schedule.IsAvailableOn(int(tt.day), tt.time); got != tt.want {
+			t.Errorf("IsAvailableOn(%v, %s) = %v, want %v", tt.day, tt.time, got, tt.want)
+		}
+	}
+
+	allDay := NewAllDaySchedule().GetSlotsForDay(int(time.Monday))
+	if len(allDay) != 1 || allDay[0].StartTime != "00:00" || allDay[0].EndTime != "24:00" {
+		t.Errorf("GetSlotsForDay() on an all-day schedule = %v, want 00:00 - 24:00", allDay)
+	}
+}
+
+func TestOffer_IsAvailableAt(t *testing.T) {
+	paris, err := time.LoadLocation("Europe/Paris")
+	if err != nil {
+		t.Skip("timezone database not available")
+	}
+
+	validity, _ := NewValidity(time.Now().Add(-24*time.Hour), time.Now().Add(30*24*time.Hour), "Europe/Paris")
+	offer, _ := NewOffer("partner-123", "establishment-123", "Late Night", "", "category-123", NewPercentageDiscount(20), validity)
+
+	tonight := time.Now().In(paris)
+	at := time.Date(tonight.Year(), tonight.Month(), tonight.Day(), 23, 30, 0, 0, paris)
+	offer.UpdateSchedule(NewScheduleWithSlots([]TimeSlot{
+		{DayOfWeek: int(at.Weekday()), StartTime: "22:00", EndTime: "01:00"},
+	}))
+
+	if offer.IsAvailableAt(at) {
+		t.Error("Draft offer should not be available")
+	}
+
+	_ = offer.SubmitForReview()
+	_ = offer.Approve("admin-123")
+	_ = offer.Publish()
+
+	if !offer.IsAvailableAt(at) {
+		t.Error("Offer should be available at 23:30 tonight")
+	}
+	if offer.IsAvailableAt(at.Add(-2 * time.Hour)) {
+		t.Error("Offer should not be available at 21:30 tonight")
+	}
+	if offer.IsAvailableAt(validity.EndDate.Add(time.Hour)) {
+		t.Error("Offer should not be available after its end date")
+	}
+}
+
+// =============================================================================
+// Domain Event Tests
+// =============================================================================
+
+func TestOffer_LifecycleEvents(t *testing.T) {
+	validity, _ := NewValidity(time.Now(), time.Now().Add(30*24*time.Hour), "Europe/Paris")
+	offer, _ := NewOffer("partner-123", "establishment-123", "Happy Hour", "", "category-123", NewPercentageDiscount(20), validity)
+
+	_ = offer.SubmitForReview()
+	_ = offer.Approve("admin-123")
+	_ = offer.Publish()
+	_ = offer.Pause()
+	_ = offer.Resume()
+	_ = offer.Archive()
+
+	want := []string{
+		"discovery.offer.created",
+		"discovery.offer.submitted_for_review",
+		"discovery.offer.approved",
+		"discovery.offer.published",
+		"discovery.offer.paused",
+		"discovery.offer.resumed",
+		"discovery.offer.archived",
+	}
+	events := offer.Events()
+	if len(events) != len(want) {
+		t.Fatalf("Offer events = %d, want %d", len(events), len(want))
+	}
+
+	seen := make(map[string]bool)
+	for i, event := range events {
+		if event.EventName() != want[i] {
+			t.Errorf("Offer event %d = %s, want %s", i, event.EventName(), want[i])
+		}
+		if event.EventID() == "" || seen[event.EventID()] {
+			t.Errorf("Offer event %s ID = %q, want a unique ID", event.EventName(), event.EventID())
+		}
+		seen[event.EventID()] = true
+		if event.AggregateID() != offer.ID().String() || event.AggregateType() != "Offer" {
+			t.Errorf("Offer event %s aggregate = %s %s", event.EventName(), event.AggregateType(), event.AggregateID())
+		}
+	}
+
+	offer.ClearEvents()
+	if len(offer.Events()) != 0 {
+		t.Error("ClearEvents() should drop pending events")
+	}
+}
+
+func TestOffer_EditAndDeleteEvents(t *testing.T) {
+	validity, _ := NewValidity(time.Now(), time.Now().Add(30*24*time.Hour), "Europe/Paris")
+	offer, _ := NewOffer("partner-123", "establishment-123", "Happy Hour", "", "category-123", NewPercentageDiscount(20), validity)
+	offer.UpdateTags([]string{"drinks"})
+
+	if events := offer.Events(); len(events) != 1 || events[0].EventName() != "discovery.offer.created" {
+		t.Fatalf("NewOffer() events = %v, want only the creation event", events)
+	}
+
+	offer.ClearEvents()
+	_ = offer.UpdateBasicInfo("Happy Hour XXL", "", "")
+	offer.UpdateTags([]string{"drinks", "cocktails"})
+
+	events := offer.Events()
+	if len(events) != 1 || events[0].EventName() != "discovery.offer.updated" {
+		t.Fatalf("Offer edits events = %v, want a single update event", events)
+	}
+
+	offer.ClearEvents()
+	_ = offer.Delete()
+
+	events = offer.Events()
+	if len(events) != 1 || events[0].EventName() != "discovery.offer.deleted" {
+		t.Fatalf("Delete() events = %v, want the deletion event", events)
+	}
+}
+
+func TestCategory_Events(t *testing.T) {
+	category, _ := NewCategory("bars", "Bars", "Bars", "glass")
+	category.UpdateColor("#ff0000")
+
+	if events := category.Events(); len(events) != 1 || events[0].EventName() != "discovery.category.created" {
+		t.Fatalf("NewCategory() events = %v, want only the creation event", events)
+	}
+
+	category.ClearEvents()
+	category.UpdateColor("#00ff00")
+	category.UpdateIcon("cocktail")
+	category.Deactivate()
+
+	events := category.Events()
+	if len(events) != 1 || events[0].EventName() != "discovery.category.updated" {
+		t.Fatalf("Category updates events = %v, want a single update event", events)
+	}
+	if _, err := events[0].Payload(); err != nil {
+		t.Errorf("Payload() error = %v", err)
+	}
+}
+
+// =============================================================================
+// Live Feed Filter Tests
+// =============================================================================
+
+func TestGeoLocation_DistanceKm(t *testing.T) {
+	paris, _ := NewGeoLocation(2.3522, 48.8566)
+	lyon, _ := NewGeoLocation(4.8357, 45.7640)
+
+	if d := paris.DistanceKm(lyon); d < 385 || d > 400 {
+		t.Errorf("Paris-Lyon distance = %.1f km, want about 392 km", d)
+	}
+	if d := paris.DistanceKm(paris); d != 0 {
+		t.Errorf("Distance to itself = %f, want 0", d)
+	}
+}
+
+func TestPublishedOfferFilter_Matches(t *testing.T) {
+	validity, _ := NewValidity(time.Now(), time.Now().Add(30*24*time.Hour), "Europe/Paris")
+	offer, _ := NewOffer("partner-123", "establishment-123", "Happy Hour", "", "bars", NewPercentageDiscount(20), validity)
+	location, _ := NewGeoLocation(2.3522, 48.8566)
+	offer.SetEstablishmentSnapshot(EstablishmentSnapshot{Name: "Le Bar", City: "Paris", Location: location})
+
+	nearby, _ := NewGeoLocation(2.2945, 48.8584) // about 4 km away
+	lyon, _ := NewGeoLocation(4.8357, 45.7640)
+
+	tests := []struct {
+		name   string
+		filter PublishedOfferFilter
+		want   bool
+	}{
+		{"no criteria", PublishedOfferFilter{}, true},
+		{"matching category", PublishedOfferFilter{CategoryIDs: []CategoryID{"restaurants", "bars"}}, true},
+		{"other category", PublishedOfferFilter{CategoryIDs: []CategoryID{"restaurants"}}, false},
+		{"within radius", PublishedOfferFilter{Near: &nearby, RadiusKm: 5}, true},
+		{"outside radius", PublishedOfferFilter{Near: &lyon, RadiusKm: 50}, false},
+		{"category and radius", PublishedOfferFilter{CategoryIDs: []CategoryID{"bars"}, Near: &nearby, RadiusKm: 2}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.filter.Matches(offer); got != tt.want {
+				t.Errorf("Matches() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// =============================================================================
+// Scheduled Publication Tests
+// =============================================================================
+
+func TestOffer_ScheduledPublication(t *testing.T) {
+	start := time.Now().Add(24 * time.Hour)
+	validity, _ := NewValidity(start, start.Add(7*24*time.Hour), "Europe/Paris")
+	offer, _ := NewOffer("partner-123", "establishment-123", "Happy Hour", "", "category-123", NewPercentageDiscount(20), validity)
+
+	if err := offer.SchedulePublication(nil); err != nil {
+		t.Fatalf("SchedulePublication() error = %v", err)
+	}
+	if offer.PublishAt() == nil || !offer.PublishAt().Equal(start) {
+		t.Fatalf("PublishAt() = %v, want the validity start %v", offer.PublishAt(), start)
+	}
+
+	late := validity.EndDate.Add(time.Hour)
+	if err := offer.SchedulePublication(&late); err != ErrInvalidPublishAt {
+		t.Errorf("SchedulePublication() after the end error = %v, want %v", err, ErrInvalidPublishAt)
+	}
+
+	// Not approved yet
+	if offer.IsDueForPublication(start) {
+		t.Error("Draft offer should not be due for publication")
+	}
+
+	_ = offer.SubmitForReview()
+	_ = offer.Approve("admin-123")
+
+	if offer.IsDueForPublication(start.Add(-time.Minute)) {
+		t.Error("Offer should not be due before its publication time")
+	}
+	if !offer.IsDueForPublication(start) {
+		t.Error("Approved offer should be due at its publication time")
+	}
+	if offer.IsDueForPublication(validity.EndDate) {
+		t.Error("Offer should not be due once ended")
+	}
+
+	_ = offer.Publish()
+	if offer.PublishAt() != nil {
+		t.Error("Publish() should clear the scheduled publication")
+	}
+}
+
+func TestOffer_IsDueForPublication_Unscheduled(t *testing.T) {
+	start := time.Now().Add(24 * time.Hour)
+	validity, _ := NewValidity(start, start.Add(7*24*time.Hour), "Europe/Paris")
+	offer, _ := NewOffer("partner-123", "establishment-123", "Happy Hour", "", "category-123", NewPercentageDiscount(20), validity)
+
+	_ = offer.SubmitForReview()
+	_ = offer.Approve("admin-123")
+
+	if offer.IsDueForPublication(start.Add(-time.Minute)) {
+		t.Error("Unscheduled offer should not be due before its validity starts")
+	}
+	if !offer.IsDueForPublication(start) {
+		t.Error("Unscheduled approved offer should be due when its validity starts")
+	}
+
+	// A cancelled schedule falls back to the validity start
+	publishAt := start.Add(48 * time.Hour)
+	_ = offer.SchedulePublication(&publishAt)
+	if offer.IsDueForPublication(start) {
+		t.Error("Scheduled offer should not be due before its publication time")
+	}
+	offer.CancelScheduledPublication()
+	if !offer.IsDueForPublication(start) {
+		t.Error("Offer should be due at its validity start once its schedule is cancelled")
+	}
+}
+
+func TestOffer_IsPastEndDate(t *testing.T) {
+	validity, _ := NewValidity(time.Now().Add(-48*time.Hour), time.Now().Add(-time.Hour), "Europe/Paris")
+	offer, _ := NewOffer("partner-123", "establishment-123", "Happy Hour", "", "category-123", NewPercentageDiscount(20), validity)
+
+	if offer.IsPastEndDate(time.Now()) {
+		t.Error("Draft offer should not be expired")
+	}
+
+	_ = offer.SubmitForReview()
+	_ = offer.Approve("admin-123")
+	_ = offer.Publish()
+
+	if offer.IsPastEndDate(validity.EndDate.Add(-time.Minute)) {
+		t.Error("Offer should not be past its end date before it")
+	}
+	if !offer.IsPastEndDate(time.Now()) {
+		t.Error("Active offer should be past its end date")
+	}
+
+	_ = offer.Expire()
+	if offer.IsPastEndDate(time.Now()) {
+		t.Error("Expired offer should not be expired again")
+	}
+}
@@ -1,7 +1,10 @@
 // Package domain contains repository interfaces for the Discovery service.
 package domain
 
-import "context"
+import (
+	"context"
+	"time"
+)
 
 // =============================================================================
 // Offer Repository
@@ -35,6 +38,14 @@ type OfferRepository interface {
 
 	// ExistsActiveForPartner checks if a partner has any active offers.
 	ExistsActiveForPartner(ctx context.Context, partnerID PartnerID) (bool, error)
+
+	// FindDueForPublication retrieves approved offers whose publication time
+	// has come: the scheduled time, or the validity start without one.
+	FindDueForPublication(ctx context.Context, now time.Time, limit int) ([]*Offer, error)
+
+	// FindPastEndDate retrieves pending, active or paused offers whose
+	// validity ended.
+	FindPastEndDate(ctx context.Context, now time.Time, limit int) ([]*Offer, error)
 }
 
 // OfferFilter defines filters for listing offers.
@@ -169,6 +180,64 @@ type CategoryRepository interface {
 	GetCategorySummaries(ctx context.Context) ([]CategorySummary, error)
 }
 
+// =============================================================================
+// Live Updates
+// =============================================================================
+
+// OfferFeed delivers offer changes to live subscribers. Channels are closed
+// once ctx is done.
+type OfferFeed interface {
+	// SubscribePublished streams offers published from now on that match the filter.
+	SubscribePublished(ctx context.Context, filter PublishedOfferFilter) (<-chan *Offer, error)
+
+	// SubscribeOffer streams the offer each time it changes.
+	SubscribeOffer(ctx context.Context, id OfferID) (<-chan *Offer, error)
+
+	// SubscribeExpiringSoon streams the user's favorite offers once each as
+	// they come close to their end date.
+	SubscribeExpiringSoon(ctx context.Context, userID UserID) (<-chan *Offer, error)
+}
+
+// PublishedOfferFilter selects the published offers a feed subscriber gets.
+// Empty criteria match every offer.
+type PublishedOfferFilter struct {
+	CategoryIDs []CategoryID
+	Near        *GeoLocation
+	RadiusKm    float64
+}
+
+// Matches reports whether the offer is in one of the categories and its
+// establishment within the radius.
+func (f PublishedOfferFilter) Matches(offer *Offer) bool {
+	if len(f.CategoryIDs) > 0 {
+		inCategory := false
+		for _, id := range f.CategoryIDs {
+			if id == offer.CategoryID() {
+				inCategory = true
+				break
+			}
+		}
+		if !inCategory {
+			return false
+		}
+	}
+
+	if f.Near != nil {
+		location := offer.EstablishmentSnapshot().Location
+		if len(location.Coordinates) < 2 || f.Near.DistanceKm(location) > f.RadiusKm {
+			return false
+		}
+	}
+
+	return true
+}
+
+// FavoriteService reads user favorites, owned by the Engagement context.
+type FavoriteService interface {
+	// GetFavoriteOfferIDs returns the IDs of the offers the user favorited.
+	GetFavoriteOfferIDs(ctx context.Context, userID UserID) ([]OfferID, error)
+}
+
 // =============================================================================
 // Unit of Work (for transactions)
 // =============================================================================
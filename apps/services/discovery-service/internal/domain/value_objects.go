@@ -3,6 +3,10 @@ package domain
 
 import (
 	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"sync"
 	"time"
 )
 
@@ -222,13 +226,19 @@ type Validity struct {
 	Timezone  string    `json:"timezone" bson:"timezone"`
 }
 
+// defaultTimezone is the timezone of offers that do not set one.
+const defaultTimezone = "Europe/Paris"
+
 // NewValidity creates a new validity period.
 func NewValidity(startDate, endDate time.Time, timezone string) (Validity, error) {
 	if endDate.Before(startDate) {
 		return Validity{}, errors.New("end date must be after start date")
 	}
 	if timezone == "" {
-		timezone = "Europe/Paris"
+		timezone = defaultTimezone
+	}
+	if _, err := time.LoadLocation(timezone); err != nil {
+		return Validity{}, fmt.Errorf("%w: unknown timezone %q", ErrInvalidValidity, timezone)
 	}
 	return Validity{
 		StartDate: startDate,
@@ -245,6 +255,12 @@ func (v Validity) Validate() error {
 	return nil
 }
 
+// Location returns the timezone the offer's schedule is expressed in. An
+// unknown or missing timezone falls back to Europe/Paris.
+func (v Validity) Location() *time.Location {
+	return loadLocation(v.Timezone)
+}
+
 // IsExpired checks if the validity period has expired.
 func (v Validity) IsExpired() bool {
 	return time.Now().After(v.EndDate)
@@ -252,13 +268,19 @@ func (v Validity) IsExpired() bool {
 
 // HasStarted checks if the validity period has started.
 func (v Validity) HasStarted() bool {
-	return time.Now().After(v.StartDate)
+	return !time.Now().Before(v.StartDate)
 }
 
 // IsActive checks if the current time is within the validity period.
 func (v Validity) IsActive() bool {
-	now := time.Now()
-	return now.After(v.StartDate) && now.Before(v.EndDate)
+	return v.IsActiveAt(time.Now())
+}
+
+// IsActiveAt checks if t is within the validity period, start included and
+// end excluded. Both bounds are instants, so the result does not depend on
+// the timezone t is expressed in.
+func (v Validity) IsActiveAt(t time.Time) bool {
+	return !t.Before(v.StartDate) && t.Before(v.EndDate)
 }
 
 // DaysRemaining returns the number of days remaining.
@@ -273,13 +295,15 @@ func (v Validity) DaysRemaining() int {
 // Schedule Value Object
 // =============================================================================
 
-// Schedule represents when the offer is available.
+// Schedule represents when the offer is available. Slot times are wall-clock
+// times in the offer's timezone.
 type Schedule struct {
 	AllDay bool       `json:"allDay" bson:"all_day"`
 	Slots  []TimeSlot `json:"slots" bson:"slots"`
 }
 
-// TimeSlot represents a time slot when the offer is available.
+// TimeSlot represents a time slot when the offer is available. A slot whose
+// end time is before its start time crosses midnight and ends the next day.
 type TimeSlot struct {
 	DayOfWeek int    `json:"dayOfWeek" bson:"day_of_week"` // 0 = Sunday
 	StartTime string `json:"startTime" bson:"start_time"`  // "17:00"
@@ -302,25 +326,25 @@ func NewScheduleWithSlots(slots []TimeSlot) Schedule {
 	}
 }
 
-// IsAvailableNow checks if the offer is available at the current time.
-func (s Schedule) IsAvailableNow() bool {
+// IsAvailableNow checks if the offer is available at the current time in the
+// given timezone.
+func (s Schedule) IsAvailableNow(loc *time.Location) bool {
+	return s.IsAvailableAt(time.Now(), loc)
+}
+
+// IsAvailableAt checks if the offer is available at t, read on the wall
+// clock of loc. Comparing wall-clock times keeps slots right across DST
+// transitions: a 17:00 slot starts at 17:00 local time in summer and winter.
+func (s Schedule) IsAvailableAt(t time.Time, loc *time.Location) bool {
 	if s.AllDay {
 		return true
 	}
-
-	now := time.Now()
-	currentDayOfWeek := int(now.Weekday())
-	currentTime := now.Format("15:04")
-
-	for _, slot := range s.Slots {
-		if slot.DayOfWeek == currentDayOfWeek {
-			if currentTime >= slot.StartTime && currentTime <= slot.EndTime {
-				return true
-			}
-		}
+	if loc == nil {
+		loc = loadLocation("")
 	}
 
-	return false
+	local := t.In(loc)
+	return s.isAvailableOn(int(local.Weekday()), local.Hour()*60+local.Minute())
 }
 
 // IsAvailableOn checks if the offer is available on a specific day and time.
@@ -329,11 +353,39 @@ func (s Schedule) IsAvailableOn(dayOfWeek int, timeStr string) bool {
 		return true
 	}
 
+	minute, ok := parseClock(timeStr)
+	if !ok {
+		return false
+	}
+	return s.isAvailableOn(dayOfWeek, minute)
+}
+
+// isAvailableOn checks the slots of the day, and those of the day before that
+// cross midnight, against a minute of the day. Slots end exclusively, as
+// establishment opening hours do: a 17:00 - 19:00 slot is over at 19:00.
+func (s Schedule) isAvailableOn(dayOfWeek int, minute int) bool {
+	previousDay := (dayOfWeek + 6) % 7
+
 	for _, slot := range s.Slots {
-		if slot.DayOfWeek == dayOfWeek {
-			if timeStr >= slot.StartTime && timeStr <= slot.EndTime {
+		start, okStart := parseClock(slot.StartTime)
+		end, okEnd := parseClock(slot.EndTime)
+		if !okStart || !okEnd {
+			continue
+		}
+
+		if end >= start {
+			if slot.DayOfWeek == dayOfWeek && minute >= start && minute < end {
 				return true
 			}
+			continue
+		}
+
+		// Crosses midnight: the evening of its day and the early hours of the next
+		if slot.DayOfWeek == dayOfWeek && minute >= start {
+			return true
+		}
+		if slot.DayOfWeek == previousDay && minute < end {
+			return true
 		}
 	}
 
@@ -343,7 +395,7 @@ func (s Schedule) IsAvailableOn(dayOfWeek int, timeStr string) bool {
 // GetSlotsForDay returns all slots for a specific day.
 func (s Schedule) GetSlotsForDay(dayOfWeek int) []TimeSlot {
 	if s.AllDay {
-		return []TimeSlot{{DayOfWeek: dayOfWeek, StartTime: "00:00", EndTime: "23:59"}}
+		return []TimeSlot{{DayOfWeek: dayOfWeek, StartTime: "00:00", EndTime: "24:00"}}
 	}
 
 	result := make([]TimeSlot, 0)
@@ -355,6 +407,48 @@ func (s Schedule) GetSlotsForDay(dayOfWeek int) []TimeSlot {
 	return result
 }
 
+// parseClock parses an "HH:MM" time into minutes since midnight. "24:00" is
+// accepted as the end of the day.
+func parseClock(value string) (int, bool) {
+	if len(value) != 5 || value[2] != ':' {
+		return 0, false
+	}
+	hour, errHour := strconv.Atoi(value[:2])
+	minute, errMinute := strconv.Atoi(value[3:])
+	if errHour != nil || errMinute != nil || hour < 0 || minute < 0 || minute > 59 {
+		return 0, false
+	}
+	if hour > 23 && !(hour == 24 && minute == 0) {
+		return 0, false
+	}
+	return hour*60 + minute, true
+}
+
+// locations caches the timezones loaded by loadLocation.
+var locations sync.Map
+
+// loadLocation loads a timezone by IANA name, falling back to Europe/Paris
+// when it is empty or unknown, and to UTC when no timezone database is
+// available.
+func loadLocation(name string) *time.Location {
+	if name == "" {
+		name = defaultTimezone
+	}
+	if loc, ok := locations.Load(name); ok {
+		return loc.(*time.Location)
+	}
+
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		if name != defaultTimezone {
+			return loadLocation(defaultTimezone)
+		}
+		loc = time.UTC
+	}
+	locations.Store(name, loc)
+	return loc
+}
+
 // =============================================================================
 // Quota Value Object
 // =============================================================================
@@ -514,6 +608,21 @@ func (g GeoLocation) Latitude() float64 {
 	return g.Coordinates[1]
 }
 
+// earthRadiusKm is the mean radius of the Earth.
+const earthRadiusKm = 6371.0
+
+// DistanceKm returns the great-circle distance to another location.
+func (g GeoLocation) DistanceKm(other GeoLocation) float64 {
+	lat1 := g.Latitude() * math.Pi / 180
+	lat2 := other.Latitude() * math.Pi / 180
+	dLat := lat2 - lat1
+	dLng := (other.Longitude() - g.Longitude()) * math.Pi / 180
+
+	a := math.Sin(dLat/2)*math.Sin(dLat/2) +
+		math.Cos(lat1)*math.Cos(lat2)*math.Sin(dLng/2)*math.Sin(dLng/2)
+	return 2 * earthRadiusKm * math.Asin(math.Sqrt(a))
+}
+
 // OfferSnapshot represents an immutable snapshot of an offer for bookings.
 type OfferSnapshot struct {
 	ID              OfferID         `json:"id" bson:"id"`
@@ -6,7 +6,6 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"strings"
 	"time"
 
 	"github.com/elastic/go-elasticsearch/v8"
@@ -15,6 +14,8 @@ import (
 )
 
 const (
+	// offersIndex is the alias searches and writes go through. It points to a
+	// single versioned index, which a full reindex replaces as a whole.
 	offersIndex = "offers"
 )
 
@@ -85,10 +86,111 @@ type searchResult struct {
 	Aggregations map[string]json.RawMessage `json:"aggregations,omitempty"`
 }
 
-// EnsureIndex creates the offers index with proper mappings if it doesn't exist.
+// offersMapping holds the settings and mappings of the versioned offer indices.
+const offersMapping = `{
+	"settings": {
+		"number_of_shards": 2,
+		"number_of_replicas": 1,
+		"analysis": {
+			"analyzer": {
+				"french_analyzer": {
+					"type": "custom",
+					"tokenizer": "standard",
+					"filter": ["lowercase", "french_elision", "french_stop", "french_stemmer"]
+				}
+			},
+			"filter": {
+				"french_elision": {
+					"type": "elision",
+					"articles_case": true,
+					"articles": ["l", "m", "t", "qu", "n", "s", "j", "d", "c", "jusqu", "quoiqu", "lorsqu", "puisqu"]
+				},
+				"french_stop": {
+					"type": "stop",
+					"stopwords": "_french_"
+				},
+				"french_stemmer": {
+					"type": "stemmer",
+					"language": "light_french"
+				}
+			}
+		}
+	},
+	"mappings": {
+		"properties": {
+			"id": { "type": "keyword" },
+			"partner_id": { "type": "keyword" },
+			"establishment_id": { "type": "keyword" },
+			"title": {
+				"type": "text",
+				"analyzer": "french_analyzer",
+				"fields": {
+					"keyword": { "type": "keyword" },
+					"autocomplete": {
+						"type": "text",
+						"analyzer": "simple"
+					}
+				}
+			},
+			"description": {
+				"type": "text",
+				"analyzer": "french_analyzer"
+			},
+			"short_description": {
+				"type": "text",
+				"analyzer": "french_analyzer"
+			},
+			"category_id": { "type": "keyword" },
+			"tags": { "type": "keyword" },
+			"discount_type": { "type": "keyword" },
+			"discount_value": { "type": "integer" },
+			"original_price": { "type": "long" },
+			"original_price_currency": { "type": "keyword" },
+			"discounted_price": { "type": "long" },
+			"formula": { "type": "text" },
+			"status": { "type": "keyword" },
+			"validity_start_date": { "type": "date" },
+			"validity_end_date": { "type": "date" },
+			"location": { "type": "geo_point" },
+			"partner_name": {
+				"type": "text",
+				"analyzer": "french_analyzer",
+				"fields": {
+					"keyword": { "type": "keyword" }
+				}
+			},
+			"establishment_name": {
+				"type": "text",
+				"analyzer": "french_analyzer",
+				"fields": {
+					"keyword": { "type": "keyword" }
+				}
+			},
+			"establishment_city": {
+				"type": "text",
+				"analyzer": "french_analyzer",
+				"fields": {
+					"keyword": { "type": "keyword" }
+				}
+			},
+			"views": { "type": "long" },
+			"clicks": { "type": "long" },
+			"bookings": { "type": "long" },
+			"favorites": { "type": "long" },
+			"avg_rating": { "type": "float" },
+			"review_count": { "type": "integer" },
+			"created_at": { "type": "date" },
+			"updated_at": { "type": "date" },
+			"published_at": { "type": "date" }
+		}
+	}
+}`
+
+// EnsureIndex creates a versioned offers index behind the offers alias if
+// neither exists.
 func (r *OfferSearchRepository) EnsureIndex(ctx context.Context) error {
-	// Check if index exists
-	res, err := r.client.Indices.Exists([]string{offersIndex})
+	// Check if the alias (or a legacy index of the same name) exists
+	res, err := r.client.Indices.Exists([]string{offersIndex}, r.client.Indices.Exists.WithContext(ctx))
 	if err != nil {
 		return fmt.Errorf("failed to check index existence: %w", err)
 	}
@@ -98,109 +200,27 @@ func (r *OfferSearchRepository) EnsureIndex(ctx context.Context) error {
 		return nil // Index already exists
 	}
 
-	// Create index with mappings
-	mapping := `{
-		"settings": {
-			"number_of_shards": 2,
-			"number_of_replicas": 1,
-			"analysis": {
-				"analyzer": {
-					"french_analyzer": {
-						"type": "custom",
-						"tokenizer": "standard",
-						"filter": ["lowercase", "french_elision", "french_stop", "french_stemmer"]
-					}
-				},
-				"filter": {
-					"french_elision": {
-						"type": "elision",
-						"articles_case": true,
-						"articles": ["l", "m", "t", "qu", "n", "s", "j", "d", "c", "jusqu", "quoiqu", "lorsqu", "puisqu"]
-					},
-					"french_stop": {
-						"type": "stop",
-						"stopwords": "_french_"
-					},
-					"french_stemmer": {
-						"type": "stemmer",
-						"language": "light_french"
-					}
-				}
-			}
-		},
-		"mappings": {
-			"properties": {
-				"id": { "type": "keyword" },
-				"partner_id": { "type": "keyword" },
-				"establishment_id": { "type": "keyword" },
-				"title": {
-					"type": "text",
-					"analyzer": "french_analyzer",
-					"fields": {
-						"keyword": { "type": "keyword" },
-						"autocomplete": {
-							"type": "text",
-							"analyzer": "simple"
-						}
-					}
-				},
-				"description": {
-					"type": "text",
-					"analyzer": "french_analyzer"
-				},
-				"short_description": {
-					"type": "text",
-					"analyzer": "french_analyzer"
-				},
-				"category_id": { "type": "keyword" },
-				"tags": { "type": "keyword" },
-				"discount_type": { "type": "keyword" },
-				"discount_value": { "type": "integer" },
-				"original_price": { "type": "long" },
-				"original_price_currency": { "type": "keyword" },
-				"discounted_price": { "type": "long" },
-				"formula": { "type": "text" },
-				"status": { "type": "keyword" },
-				"validity_start_date": { "type": "date" },
-				"validity_end_date": { "type": "date" },
-				"location": { "type": "geo_point" },
-				"partner_name": {
-					"type": "text",
-					"analyzer": "french_analyzer",
-					"fields": {
-						"keyword": { "type": "keyword" }
-					}
-				},
-				"establishment_name": {
-					"type": "text",
-					"analyzer": "french_analyzer",
-					"fields": {
-						"keyword": { "type": "keyword" }
-					}
-				},
-				"establishment_city": {
-					"type": "text",
-					"analyzer": "french_analyzer",
-					"fields": {
-						"keyword": { "type": "keyword" }
-					}
-				},
-				"views": { "type": "long" },
-				"clicks": { "type": "long" },
-				"bookings": { "type": "long" },
-				"favorites": { "type": "long" },
-				"avg_rating": { "type": "float" },
-				"review_count": { "type": "integer" },
-				"created_at": { "type": "date" },
-				"updated_at": { "type": "date" },
-				"published_at": { "type": "date" }
-			}
-		}
-	}`
+	return r.createIndex(ctx, newVersionedIndexName(), true)
+}
+
+// createIndex creates an offers index, behind the offers alias when aliased.
+func (r *OfferSearchRepository) createIndex(ctx context.Context, name string, aliased bool) error {
+	var body map[string]interface{}
+	if err := json.Unmarshal([]byte(offersMapping), &body); err != nil {
+		return fmt.Errorf("invalid offers mapping: %w", err)
+	}
+	if aliased {
+		body["aliases"] = map[string]interface{}{offersIndex: map[string]interface{}{}}
+	}
+
+	data, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("failed to marshal index settings: %w", err)
+	}
 
-	res, err = r.client.Indices.Create(
-		offersIndex,
-		r.client.Indices.Create.WithBody(strings.NewReader(mapping)),
+	res, err := r.client.Indices.Create(
+		name,
+		r.client.Indices.Create.WithBody(bytes.NewReader(data)),
 		r.client.Indices.Create.WithContext(ctx),
 	)
 	if err != nil {
@@ -710,34 +730,53 @@ func (r *OfferSearchRepository) documentToSummary(doc *offerDocument) *domain.Of
 
 // BulkIndex indexes multiple offers in a single bulk request.
 func (r *OfferSearchRepository) BulkIndex(ctx context.Context, offers []*domain.Offer) error {
-	if len(offers) == 0 {
+	return r.bulk(ctx, offersIndex, offers, nil, "true")
+}
+
+// bulk indexes offers and deletes the removed IDs from index in a single
+// bulk request, failing if any of the operations failed.
+func (r *OfferSearchRepository) bulk(ctx context.Context, index string, offers []*domain.Offer, removed []domain.OfferID, refresh string) error {
+	if len(offers) == 0 && len(removed) == 0 {
 		return nil
 	}
 
 	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
 	for _, offer := range offers {
 		meta := map[string]interface{}{
 			"index": map[string]interface{}{
-				"_index": offersIndex,
+				"_index": index,
 				"_id":    offer.ID().String(),
 			},
 		}
 
-		if err := json.NewEncoder(&buf).Encode(meta); err != nil {
+		if err := enc.Encode(meta); err != nil {
 			return fmt.Errorf("failed to encode meta: %w", err)
 		}
 
 		doc := r.offerToDocument(offer)
-		if err := json.NewEncoder(&buf).Encode(doc); err != nil {
+		if err := enc.Encode(doc); err != nil {
 			return fmt.Errorf("failed to encode document: %w", err)
 		}
 	}
+	for _, id := range removed {
+		meta := map[string]interface{}{
+			"delete": map[string]interface{}{
+				"_index": index,
+				"_id":    id.String(),
+			},
+		}
+
+		if err := enc.Encode(meta); err != nil {
+			return fmt.Errorf("failed to encode meta: %w", err)
+		}
+	}
 
 	res, err := r.client.Bulk(
 		bytes.NewReader(buf.Bytes()),
 		r.client.Bulk.WithContext(ctx),
-		r.client.Bulk.WithIndex(offersIndex),
-		r.client.Bulk.WithRefresh("true"),
+		r.client.Bulk.WithIndex(index),
+		r.client.Bulk.WithRefresh(refresh),
 	)
 	if err != nil {
 		return fmt.Errorf("failed to bulk index: %w", err)
@@ -748,7 +787,51 @@ func (r *OfferSearchRepository) BulkIndex(ctx context.Context, offers []*domain.
 		return fmt.Errorf("bulk index error: %s", res.String())
 	}
 
-	return nil
+	var result bulkResult
+	if err := json.NewDecoder(res.Body).Decode(&result); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return result.err()
+}
+
+// bulkResult is the response of a bulk request.
+type bulkResult struct {
+	Errors bool                        `json:"errors"`
+	Items  []map[string]bulkItemResult `json:"items"`
+}
+
+type bulkItemResult struct {
+	ID     string          `json:"_id"`
+	Status int             `json:"status"`
+	Error  json.RawMessage `json:"error,omitempty"`
+}
+
+// err reports the first failed operation and how many failed. Deleting a
+// document that is not indexed is not a failure.
+func (b bulkResult) err() error {
+	if !b.Errors {
+		return nil
+	}
+
+	var first error
+	failed := 0
+	for _, item := range b.Items {
+		for action, res := range item {
+			if res.Status < 300 || (action == "delete" && res.Status == 404) {
+				continue
+			}
+			if first == nil {
+				first = fmt.Errorf("%s of offer %s failed with status %d: %s", action, res.ID, res.Status, res.Error)
+			}
+			failed++
+		}
+	}
+	if first == nil {
+		return nil
+	}
+
+	return fmt.Errorf("bulk index error: %d operations failed, first: %w", failed, first)
 }
 
 // GetOffersByIDs retrieves offers by their IDs from Elasticsearch.
@@ -806,18 +889,5 @@ func (r *OfferSearchRepository) GetOffersByIDs(ctx context.Context, ids []domain
 
 // Refresh forces a refresh of the offers index.
 func (r *OfferSearchRepository) Refresh(ctx context.Context) error {
-	res, err := r.client.Indices.Refresh(
-		r.client.Indices.Refresh.WithContext(ctx),
-		r.client.Indices.Refresh.WithIndex(offersIndex),
-	)
-	if err != nil {
-		return fmt.Errorf("failed to refresh index: %w", err)
-	}
-	defer res.Body.Close()
-
-	if res.IsError() {
-		return fmt.Errorf("refresh error: %s", res.String())
-	}
-
-	return nil
+	return r.refreshIndex(ctx, offersIndex)
 }
@@ -0,0 +1,251 @@
+package elasticsearch
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/yousoon/discovery-service/internal/domain"
+)
+
+// versionedIndexLayout suffixes the versioned offer indices, e.g. offers_20240131120000.
+const versionedIndexLayout = "20060102150405"
+
+// newVersionedIndexName names a new offers index after the current time.
+func newVersionedIndexName() string {
+	return offersIndex + "_" + time.Now().UTC().Format(versionedIndexLayout)
+}
+
+// OfferSource streams offers from the store of record.
+// Implemented by mongodb.OfferRepository.
+type OfferSource interface {
+	ScanOffers(ctx context.Context, updatedSince *time.Time, batchSize int, fn func([]*domain.Offer) error) error
+}
+
+// ReindexResult describes a completed reindex.
+type ReindexResult struct {
+	Index     string
+	Indexed   int
+	CaughtUp  int
+	Removed   int
+	StartedAt time.Time
+	Duration  time.Duration
+}
+
+// Reindexer rebuilds the offers index from the store of record without
+// downtime: offers are indexed into a new versioned index while searches keep
+// using the current one, then the alias is swapped atomically.
+type Reindexer struct {
+	search    *OfferSearchRepository
+	source    OfferSource
+	batchSize int
+}
+
+// NewReindexer creates a new reindexer.
+func NewReindexer(search *OfferSearchRepository, source OfferSource, batchSize int) *Reindexer {
+	if batchSize <= 0 {
+		batchSize = 500
+	}
+	return &Reindexer{
+		search:    search,
+		source:    source,
+		batchSize: batchSize,
+	}
+}
+
+// Run builds a new index, swaps the alias to it and deletes the old indices.
+// Offers changed while the index was built are indexed again afterwards.
+func (r *Reindexer) Run(ctx context.Context) (*ReindexResult, error) {
+	result := &ReindexResult{
+		Index:     newVersionedIndexName(),
+		StartedAt: time.Now(),
+	}
+
+	if err := r.search.createIndex(ctx, result.Index, false); err != nil {
+		return nil, err
+	}
+
+	err := r.source.ScanOffers(ctx, nil, r.batchSize, func(offers []*domain.Offer) error {
+		if err := r.search.bulk(ctx, result.Index, offers, nil, "false"); err != nil {
+			return err
+		}
+		result.Indexed += len(offers)
+		slog.Info("Indexed offers", "index", result.Index, "count", result.Indexed)
+		return nil
+	})
+	if err == nil {
+		err = r.search.refreshIndex(ctx, result.Index)
+	}
+	if err == nil {
+		err = r.search.SwapAlias(ctx, result.Index)
+	}
+	if err != nil {
+		// Searches still use the current index, drop the partial one
+		if delErr := r.search.deleteIndices(context.Background(), []string{result.Index}); delErr != nil {
+			slog.Error("Failed to delete partial index", "index", result.Index, "error", delErr)
+		}
+		return nil, fmt.Errorf("reindex into %s failed: %w", result.Index, err)
+	}
+
+	// Changes written to the old index while the new one was built were lost
+	// with it: index every offer updated since the reindex started again.
+	err = r.source.ScanOffers(ctx, &result.StartedAt, r.batchSize, func(offers []*domain.Offer) error {
+		live := make([]*domain.Offer, 0, len(offers))
+		var removed []domain.OfferID
+		for _, offer := range offers {
+			if offer.DeletedAt() != nil {
+				removed = append(removed, offer.ID())
+				continue
+			}
+			live = append(live, offer)
+		}
+
+		if err := r.search.bulk(ctx, offersIndex, live, removed, "true"); err != nil {
+			return err
+		}
+		result.CaughtUp += len(live)
+		result.Removed += len(removed)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("catch-up after swapping to %s failed: %w", result.Index, err)
+	}
+
+	result.Duration = time.Since(result.StartedAt)
+	return result, nil
+}
+
+// SwapAlias points the offers alias to index in a single atomic request, then
+// deletes the indices it pointed to before. A legacy concrete offers index is
+// replaced by the alias.
+func (r *OfferSearchRepository) SwapAlias(ctx context.Context, index string) error {
+	current, legacy, err := r.aliasedIndices(ctx)
+	if err != nil {
+		return err
+	}
+
+	actions := []map[string]interface{}{
+		{"add": map[string]interface{}{"index": index, "alias": offersIndex}},
+	}
+	if legacy {
+		actions = append(actions, map[string]interface{}{
+			"remove_index": map[string]interface{}{"index": offersIndex},
+		})
+	}
+	var old []string
+	for _, name := range current {
+		if name == index {
+			continue
+		}
+		actions = append(actions, map[string]interface{}{
+			"remove": map[string]interface{}{"index": name, "alias": offersIndex},
+		})
+		old = append(old, name)
+	}
+
+	data, err := json.Marshal(map[string]interface{}{"actions": actions})
+	if err != nil {
+		return fmt.Errorf("failed to marshal alias actions: %w", err)
+	}
+
+	res, err := r.client.Indices.UpdateAliases(
+		bytes.NewReader(data),
+		r.client.Indices.UpdateAliases.WithContext(ctx),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to swap alias: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.IsError() {
+		return fmt.Errorf("failed to swap alias: %s", res.String())
+	}
+
+	// The alias no longer points to them, failing to delete them only wastes space
+	if err := r.deleteIndices(ctx, old); err != nil {
+		slog.Warn("Failed to delete old offer indices", "indices", old, "error", err)
+	}
+
+	return nil
+}
+
+// aliasedIndices returns the indices behind the offers alias, and whether
+// offers is a concrete index instead of an alias.
+func (r *OfferSearchRepository) aliasedIndices(ctx context.Context) ([]string, bool, error) {
+	res, err := r.client.Indices.Get(
+		[]string{offersIndex},
+		r.client.Indices.Get.WithContext(ctx),
+		r.client.Indices.Get.WithIgnoreUnavailable(true),
+	)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to get offer indices: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode == 404 {
+		return nil, false, nil
+	}
+	if res.IsError() {
+		return nil, false, fmt.Errorf("failed to get offer indices: %s", res.String())
+	}
+
+	// Keyed by concrete index name, whether offers names it or an alias does
+	var indices map[string]json.RawMessage
+	if err := json.NewDecoder(res.Body).Decode(&indices); err != nil {
+		return nil, false, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	if _, ok := indices[offersIndex]; ok {
+		return nil, true, nil
+	}
+
+	names := make([]string, 0, len(indices))
+	for name := range indices {
+		names = append(names, name)
+	}
+	return names, false, nil
+}
+
+// refreshIndex makes everything indexed into index searchable.
+func (r *OfferSearchRepository) refreshIndex(ctx context.Context, index string) error {
+	res, err := r.client.Indices.Refresh(
+		r.client.Indices.Refresh.WithContext(ctx),
+		r.client.Indices.Refresh.WithIndex(index),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to refresh index: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.IsError() {
+		return fmt.Errorf("refresh error: %s", res.String())
+	}
+
+	return nil
+}
+
+// deleteIndices deletes the given offer indices.
+func (r *OfferSearchRepository) deleteIndices(ctx context.Context, indices []string) error {
+	if len(indices) == 0 {
+		return nil
+	}
+
+	res, err := r.client.Indices.Delete(
+		indices,
+		r.client.Indices.Delete.WithContext(ctx),
+		r.client.Indices.Delete.WithIgnoreUnavailable(true),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to delete indices: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.IsError() {
+		return fmt.Errorf("failed to delete indices: %s", res.String())
+	}
+
+	return nil
+}
@@ -11,6 +11,7 @@ import (
 	"go.mongodb.org/mongo-driver/mongo/options"
 
 	"github.com/yousoon/discovery-service/internal/domain"
+	"github.com/yousoon/shared/infrastructure/outbox"
 )
 
 const categoryCollection = "categories"
@@ -18,12 +19,15 @@ const categoryCollection = "categories"
 // CategoryRepository implements domain.CategoryRepository using MongoDB.
 type CategoryRepository struct {
 	collection *mongo.Collection
+	outbox     *outbox.Store
 }
 
-// NewCategoryRepository creates a new MongoDB category repository.
-func NewCategoryRepository(db *mongo.Database) *CategoryRepository {
+// NewCategoryRepository creates a new MongoDB category repository. Category
+// events are saved to the outbox with the category.
+func NewCategoryRepository(db *mongo.Database, outbox *outbox.Store) *CategoryRepository {
 	return &CategoryRepository{
 		collection: db.Collection(categoryCollection),
+		outbox:     outbox,
 	}
 }
 
@@ -72,7 +76,7 @@ func (r *CategoryRepository) EnsureIndexes(ctx context.Context) error {
 	return nil
 }
 
-// Save persists a category to MongoDB.
+// Save persists a category to MongoDB together with its pending events.
 func (r *CategoryRepository) Save(ctx context.Context, category *domain.Category) error {
 	doc := r.toDocument(category)
 
@@ -80,7 +84,10 @@ func (r *CategoryRepository) Save(ctx context.Context, category *domain.Category
 	update := bson.M{"$set": doc}
 	opts := options.Update().SetUpsert(true)
 
-	_, err := r.collection.UpdateOne(ctx, filter, update, opts)
+	err := r.outbox.SaveWithEvents(ctx, domainEvents{category}, func(sessCtx mongo.SessionContext) error {
+		_, err := r.collection.UpdateOne(sessCtx, filter, update, opts)
+		return err
+	})
 	if err != nil {
 		return fmt.Errorf("failed to save category: %w", err)
 	}
@@ -12,6 +12,8 @@ import (
 	"go.mongodb.org/mongo-driver/mongo/options"
 
 	"github.com/yousoon/discovery-service/internal/domain"
+	shareddomain "github.com/yousoon/shared/domain"
+	"github.com/yousoon/shared/infrastructure/outbox"
 )
 
 // OfferDocument represents the MongoDB document structure for offers.
@@ -46,6 +48,7 @@ type OfferDocument struct {
 	UpdatedAt   time.Time  `bson:"updated_at"`
 	PublishedAt *time.Time `bson:"published_at"`
 	DeletedAt   *time.Time `bson:"deleted_at"`
+	PublishAt   *time.Time `bson:"publish_at"`
 }
 
 // Subdocuments
@@ -128,12 +131,29 @@ type ModerationDoc struct {
 // OfferRepository implements domain.OfferRepository using MongoDB.
 type OfferRepository struct {
 	collection *mongo.Collection
+	outbox     *outbox.Store
 }
 
-// NewOfferRepository creates a new OfferRepository.
-func NewOfferRepository(db *mongo.Database) *OfferRepository {
+// eventSource is a discovery aggregate raising domain events.
+type eventSource interface {
+	Events() []shareddomain.DomainEvent
+	ClearEvents()
+}
+
+// domainEvents adapts a discovery aggregate to outbox.EventSource.
+type domainEvents struct {
+	aggregate eventSource
+}
+
+func (e domainEvents) GetDomainEvents() []shareddomain.DomainEvent { return e.aggregate.Events() }
+func (e domainEvents) ClearDomainEvents()                          { e.aggregate.ClearEvents() }
+
+// NewOfferRepository creates a new OfferRepository. Offer events are saved to
+// the outbox with the offer.
+func NewOfferRepository(db *mongo.Database, outbox *outbox.Store) *OfferRepository {
 	return &OfferRepository{
 		collection: db.Collection("offers"),
+		outbox:     outbox,
 	}
 }
 
@@ -174,13 +194,19 @@ func (r *OfferRepository) EnsureIndexes(ctx context.Context) error {
 		{
 			Keys: bson.D{{Key: "deleted_at", Value: 1}},
 		},
+		{
+			Keys: bson.D{{Key: "updated_at", Value: 1}},
+		},
+		{
+			Keys: bson.D{{Key: "publish_at", Value: 1}},
+		},
 	}
 
 	_, err := r.collection.Indexes().CreateMany(ctx, indexes)
 	return err
 }
 
-// Save persists an offer (create or update).
+// Save persists an offer (create or update) together with its pending events.
 func (r *OfferRepository) Save(ctx context.Context, offer *domain.Offer) error {
 	doc := r.toDocument(offer)
 
@@ -192,8 +218,10 @@ func (r *OfferRepository) Save(ctx context.Context, offer *domain.Offer) error {
 	update := bson.M{"$set": doc}
 	opts := options.Update().SetUpsert(true)
 
-	_, err := r.collection.UpdateOne(ctx, filter, update, opts)
-	return err
+	return r.outbox.SaveWithEvents(ctx, domainEvents{offer}, func(sessCtx mongo.SessionContext) error {
+		_, err := r.collection.UpdateOne(sessCtx, filter, update, opts)
+		return err
+	})
 }
 
 // FindByID retrieves an offer by ID.
@@ -349,6 +377,50 @@ func (r *OfferRepository) Count(ctx context.Context, filter domain.OfferFilter)
 	return r.collection.CountDocuments(ctx, mongoFilter)
 }
 
+// ScanOffers streams offers to fn in batches of batchSize. Without
+// updatedSince it scans every offer that is not deleted; with it, every offer
+// updated since then, deleted ones included, so callers can remove them.
+func (r *OfferRepository) ScanOffers(ctx context.Context, updatedSince *time.Time, batchSize int, fn func([]*domain.Offer) error) error {
+	filter := bson.M{"deleted_at": nil}
+	if updatedSince != nil {
+		filter = bson.M{"updated_at": bson.M{"$gte": *updatedSince}}
+	}
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: "_id", Value: 1}}).
+		SetBatchSize(int32(batchSize))
+
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return err
+	}
+	defer cursor.Close(ctx)
+
+	batch := make([]*domain.Offer, 0, batchSize)
+	for cursor.Next(ctx) {
+		var doc OfferDocument
+		if err := cursor.Decode(&doc); err != nil {
+			return err
+		}
+		batch = append(batch, r.toDomain(&doc))
+
+		if len(batch) == batchSize {
+			if err := fn(batch); err != nil {
+				return err
+			}
+			batch = make([]*domain.Offer, 0, batchSize)
+		}
+	}
+	if err := cursor.Err(); err != nil {
+		return err
+	}
+
+	if len(batch) > 0 {
+		return fn(batch)
+	}
+	return nil
+}
+
 // ExistsActiveForPartner checks if a partner has any active offers.
 func (r *OfferRepository) ExistsActiveForPartner(ctx context.Context, partnerID domain.PartnerID) (bool, error) {
 	filter := bson.M{
@@ -365,6 +437,58 @@ func (r *OfferRepository) ExistsActiveForPartner(ctx context.Context, partnerID
 	return count > 0, nil
 }
 
+// FindDueForPublication retrieves approved offers whose publication time has
+// come: their scheduled time, or their validity start when none was chosen.
+func (r *OfferRepository) FindDueForPublication(ctx context.Context, now time.Time, limit int) ([]*domain.Offer, error) {
+	filter := bson.M{
+		"status":            string(domain.OfferStatusPending),
+		"moderation.status": string(domain.ModerationStatusApproved),
+		"deleted_at":        nil,
+		"validity.end_date": bson.M{"$gt": now},
+		"$or": []bson.M{
+			{"publish_at": bson.M{"$lte": now}},
+			{"publish_at": nil, "validity.start_date": bson.M{"$lte": now}},
+		},
+	}
+
+	opts := options.Find().
+		SetLimit(int64(limit)).
+		SetSort(bson.D{{Key: "validity.start_date", Value: 1}})
+
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	return r.cursorToOffers(ctx, cursor)
+}
+
+// FindPastEndDate retrieves pending, active or paused offers whose validity ended.
+func (r *OfferRepository) FindPastEndDate(ctx context.Context, now time.Time, limit int) ([]*domain.Offer, error) {
+	filter := bson.M{
+		"status": bson.M{"$in": []string{
+			string(domain.OfferStatusPending),
+			string(domain.OfferStatusActive),
+			string(domain.OfferStatusPaused),
+		}},
+		"deleted_at":        nil,
+		"validity.end_date": bson.M{"$lte": now},
+	}
+
+	opts := options.Find().
+		SetLimit(int64(limit)).
+		SetSort(bson.D{{Key: "validity.end_date", Value: 1}})
+
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	return r.cursorToOffers(ctx, cursor)
+}
+
 // =============================================================================
 // Helper Methods
 // =============================================================================
@@ -567,6 +691,7 @@ func (r *OfferRepository) toDocument(offer *domain.Offer) *OfferDocument {
 		UpdatedAt:   offer.UpdatedAt(),
 		PublishedAt: offer.PublishedAt(),
 		DeletedAt:   offer.DeletedAt(),
+		PublishAt:   offer.PublishAt(),
 	}
 }
 
@@ -666,6 +791,7 @@ func (r *OfferRepository) toDomain(doc *OfferDocument) *domain.Offer {
 		doc.UpdatedAt,
 		doc.PublishedAt,
 		doc.DeletedAt,
+		doc.PublishAt,
 	)
 }
 
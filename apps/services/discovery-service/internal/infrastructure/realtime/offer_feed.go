@@ -0,0 +1,278 @@
+// Package realtime pushes offer changes to live GraphQL subscribers.
+package realtime
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/nats-io/nats.go"
+
+	"github.com/yousoon/discovery-service/internal/domain"
+)
+
+const (
+	// offerEventsSubject matches the offer events relayed from the outbox.
+	offerEventsSubject = "yousoon.events.discovery.offer.>"
+
+	offerPublishedEvent = "discovery.offer.published"
+
+	// subscriberBuffer is how many offers a slow subscriber may lag behind
+	// before further offers are dropped for it.
+	subscriberBuffer = 32
+)
+
+// Config controls when favorites are announced as expiring soon.
+type Config struct {
+	// ExpiringSoonWindow is how long before its end date an offer is expiring soon.
+	ExpiringSoonWindow time.Duration
+	// ExpiringSoonInterval is how often favorites are checked.
+	ExpiringSoonInterval time.Duration
+}
+
+// DefaultConfig returns the default feed configuration.
+func DefaultConfig() Config {
+	return Config{
+		ExpiringSoonWindow:   24 * time.Hour,
+		ExpiringSoonInterval: 15 * time.Minute,
+	}
+}
+
+type publishedSubscriber struct {
+	filter domain.PublishedOfferFilter
+}
+
+type expiringSubscriber struct {
+	userID domain.UserID
+	// announced holds the offers already sent, each is announced once
+	announced map[domain.OfferID]bool
+}
+
+// OfferFeed fans offer domain events out to live subscribers of this
+// instance. It listens on a plain NATS subscription rather than a durable
+// consumer so that every instance sees every event; subscribers only get
+// events published while they are connected.
+type OfferFeed struct {
+	offerRepo domain.OfferRepository
+	favorites domain.FavoriteService
+	config    Config
+
+	mu        sync.RWMutex
+	published map[chan *domain.Offer]*publishedSubscriber
+	offers    map[domain.OfferID]map[chan *domain.Offer]struct{}
+	expiring  map[chan *domain.Offer]*expiringSubscriber
+}
+
+// NewOfferFeed creates a new offer feed.
+func NewOfferFeed(offerRepo domain.OfferRepository, favorites domain.FavoriteService, config Config) *OfferFeed {
+	return &OfferFeed{
+		offerRepo: offerRepo,
+		favorites: favorites,
+		config:    config,
+		published: make(map[chan *domain.Offer]*publishedSubscriber),
+		offers:    make(map[domain.OfferID]map[chan *domain.Offer]struct{}),
+		expiring:  make(map[chan *domain.Offer]*expiringSubscriber),
+	}
+}
+
+// SubscribePublished streams offers published from now on that match the filter.
+func (f *OfferFeed) SubscribePublished(ctx context.Context, filter domain.PublishedOfferFilter) (<-chan *domain.Offer, error) {
+	ch := make(chan *domain.Offer, subscriberBuffer)
+
+	f.mu.Lock()
+	f.published[ch] = &publishedSubscriber{filter: filter}
+	f.mu.Unlock()
+
+	f.closeOnDone(ctx, ch, func() {
+		delete(f.published, ch)
+	})
+
+	return ch, nil
+}
+
+// SubscribeOffer streams the offer each time it changes.
+func (f *OfferFeed) SubscribeOffer(ctx context.Context, id domain.OfferID) (<-chan *domain.Offer, error) {
+	ch := make(chan *domain.Offer, subscriberBuffer)
+
+	f.mu.Lock()
+	if f.offers[id] == nil {
+		f.offers[id] = make(map[chan *domain.Offer]struct{})
+	}
+	f.offers[id][ch] = struct{}{}
+	f.mu.Unlock()
+
+	f.closeOnDone(ctx, ch, func() {
+		delete(f.offers[id], ch)
+		if len(f.offers[id]) == 0 {
+			delete(f.offers, id)
+		}
+	})
+
+	return ch, nil
+}
+
+// SubscribeExpiringSoon streams the user's favorite offers once each as they
+// come close to their end date. Favorites already expiring soon are sent
+// right away.
+func (f *OfferFeed) SubscribeExpiringSoon(ctx context.Context, userID domain.UserID) (<-chan *domain.Offer, error) {
+	ch := make(chan *domain.Offer, subscriberBuffer)
+	sub := &expiringSubscriber{
+		userID:    userID,
+		announced: make(map[domain.OfferID]bool),
+	}
+
+	f.mu.Lock()
+	f.expiring[ch] = sub
+	f.mu.Unlock()
+
+	f.closeOnDone(ctx, ch, func() {
+		delete(f.expiring, ch)
+	})
+
+	go f.checkExpiring(ctx, ch, sub)
+
+	return ch, nil
+}
+
+// closeOnDone unregisters the subscriber and closes its channel once ctx is done.
+func (f *OfferFeed) closeOnDone(ctx context.Context, ch chan *domain.Offer, unregister func()) {
+	go func() {
+		<-ctx.Done()
+
+		f.mu.Lock()
+		unregister()
+		close(ch)
+		f.mu.Unlock()
+	}()
+}
+
+// Run feeds subscribers from the offer events on NATS and checks favorites
+// for offers expiring soon, until ctx is done.
+func (f *OfferFeed) Run(ctx context.Context, conn *nats.Conn) error {
+	sub, err := conn.Subscribe(offerEventsSubject, func(msg *nats.Msg) {
+		f.handle(ctx, msg.Data)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to subscribe to offer events: %w", err)
+	}
+
+	ticker := time.NewTicker(f.config.ExpiringSoonInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return sub.Unsubscribe()
+		case <-ticker.C:
+			f.checkAllExpiring(ctx)
+		}
+	}
+}
+
+// offerEvent is the part of the published event envelope the feed needs.
+type offerEvent struct {
+	EventType   string `json:"event_type"`
+	AggregateID string `json:"aggregate_id"`
+}
+
+func (f *OfferFeed) handle(ctx context.Context, data []byte) {
+	var event offerEvent
+	if err := json.Unmarshal(data, &event); err != nil || event.AggregateID == "" {
+		return
+	}
+	id := domain.OfferID(event.AggregateID)
+
+	f.mu.RLock()
+	idle := len(f.offers[id]) == 0 && (event.EventType != offerPublishedEvent || len(f.published) == 0)
+	f.mu.RUnlock()
+	if idle {
+		return
+	}
+
+	// Subscribers receive the offer as currently stored, events only say it changed
+	offer, err := f.offerRepo.FindByID(ctx, id)
+	if err != nil {
+		slog.Warn("Failed to load offer for live update", "offer_id", id, "error", err)
+		return
+	}
+	if offer == nil {
+		// Deleted since
+		return
+	}
+
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	for ch := range f.offers[id] {
+		f.send(ch, offer)
+	}
+	if event.EventType == offerPublishedEvent {
+		for ch, sub := range f.published {
+			if sub.filter.Matches(offer) {
+				f.send(ch, offer)
+			}
+		}
+	}
+}
+
+// send delivers without blocking. Callers hold the read lock so the channel
+// cannot be closed meanwhile.
+func (f *OfferFeed) send(ch chan *domain.Offer, offer *domain.Offer) {
+	select {
+	case ch <- offer:
+	default:
+		slog.Warn("Dropping live update for a slow subscriber", "offer_id", offer.ID())
+	}
+}
+
+func (f *OfferFeed) checkAllExpiring(ctx context.Context) {
+	f.mu.RLock()
+	subs := make(map[chan *domain.Offer]*expiringSubscriber, len(f.expiring))
+	for ch, sub := range f.expiring {
+		subs[ch] = sub
+	}
+	f.mu.RUnlock()
+
+	for ch, sub := range subs {
+		f.checkExpiring(ctx, ch, sub)
+	}
+}
+
+// checkExpiring sends the subscriber's favorites that became expiring soon.
+func (f *OfferFeed) checkExpiring(ctx context.Context, ch chan *domain.Offer, sub *expiringSubscriber) {
+	ids, err := f.favorites.GetFavoriteOfferIDs(ctx, sub.userID)
+	if err != nil {
+		slog.Warn("Failed to load favorites for expiring offers", "user_id", sub.userID, "error", err)
+		return
+	}
+
+	var expiring []*domain.Offer
+	for _, id := range ids {
+		offer, err := f.offerRepo.FindByID(ctx, id)
+		if err != nil {
+			slog.Warn("Failed to load favorite offer", "offer_id", id, "error", err)
+			continue
+		}
+		if offer != nil && offer.IsExpiringSoon(f.config.ExpiringSoonWindow) {
+			expiring = append(expiring, offer)
+		}
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	// Unsubscribed meanwhile, the channel may be closed
+	if f.expiring[ch] != sub {
+		return
+	}
+	for _, offer := range expiring {
+		if sub.announced[offer.ID()] {
+			continue
+		}
+		sub.announced[offer.ID()] = true
+		f.send(ch, offer)
+	}
+}
@@ -0,0 +1,105 @@
+// Package events consumes the events the discovery service reacts to.
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+
+	natsgo "github.com/nats-io/nats.go"
+
+	"github.com/yousoon/discovery-service/internal/domain"
+	"github.com/yousoon/shared/infrastructure/nats"
+)
+
+const (
+	offerEventsSubject      = "yousoon.events.discovery.offer.>"
+	searchProjectorConsumer = "discovery-search-projector"
+)
+
+// OfferLoader loads the current state of an offer.
+// Implemented by mongodb.OfferRepository.
+type OfferLoader interface {
+	FindByID(ctx context.Context, id domain.OfferID) (*domain.Offer, error)
+}
+
+// OfferIndexer writes offers to the search index.
+// Implemented by elasticsearch.OfferSearchRepository.
+type OfferIndexer interface {
+	IndexOffer(ctx context.Context, offer *domain.Offer) error
+	DeleteOffer(ctx context.Context, id domain.OfferID) error
+}
+
+// SearchProjector keeps the offers search index in sync with the offer events
+// relayed from the outbox. It indexes the offer as currently stored rather
+// than applying each event, so redelivered or out-of-order events converge to
+// the same document. It reads a durable consumer, so changes made while no
+// instance runs are projected once one is back.
+//
+// Stats such as views are not evented and only reach the index on the next
+// edit or full reindex.
+type SearchProjector struct {
+	subscriber *nats.Subscriber
+	stream     string
+	offers     OfferLoader
+	index      OfferIndexer
+}
+
+// NewSearchProjector creates a new search projector.
+func NewSearchProjector(subscriber *nats.Subscriber, stream string, offers OfferLoader, index OfferIndexer) *SearchProjector {
+	return &SearchProjector{
+		subscriber: subscriber,
+		stream:     stream,
+		offers:     offers,
+		index:      index,
+	}
+}
+
+// Start subscribes to offer events until ctx is done.
+func (p *SearchProjector) Start(ctx context.Context) error {
+	cfg := nats.DefaultSubscribeConfig(p.stream, searchProjectorConsumer, offerEventsSubject, p.handle)
+	if err := p.subscriber.Subscribe(ctx, cfg); err != nil {
+		return fmt.Errorf("failed to subscribe to offer events: %w", err)
+	}
+	return nil
+}
+
+// offerEvent is the part of the published event envelope the projector needs.
+type offerEvent struct {
+	EventType   string `json:"event_type"`
+	AggregateID string `json:"aggregate_id"`
+}
+
+func (p *SearchProjector) handle(ctx context.Context, msg *natsgo.Msg) error {
+	var event offerEvent
+	if err := json.Unmarshal(msg.Data, &event); err != nil || event.AggregateID == "" {
+		// Redelivering a malformed event cannot succeed
+		slog.Warn("Dropping malformed offer event", "subject", msg.Subject, "error", err)
+		return nil
+	}
+
+	return p.Project(ctx, domain.OfferID(event.AggregateID))
+}
+
+// Project brings the indexed document of an offer in line with its stored
+// state, removing it once the offer is deleted.
+func (p *SearchProjector) Project(ctx context.Context, id domain.OfferID) error {
+	offer, err := p.offers.FindByID(ctx, id)
+	if err != nil {
+		return fmt.Errorf("failed to load offer %s: %w", id, err)
+	}
+
+	// Deleted offers are not found
+	if offer == nil || offer.DeletedAt() != nil {
+		if err := p.index.DeleteOffer(ctx, id); err != nil {
+			return fmt.Errorf("failed to remove offer %s from the index: %w", id, err)
+		}
+		return nil
+	}
+
+	if err := p.index.IndexOffer(ctx, offer); err != nil {
+		return fmt.Errorf("failed to index offer %s: %w", id, err)
+	}
+	return nil
+}
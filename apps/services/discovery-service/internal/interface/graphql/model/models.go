@@ -37,8 +37,11 @@ type Offer struct {
 	CreatedAt          time.Time              `json:"createdAt"`
 	UpdatedAt          time.Time              `json:"updatedAt"`
 	PublishedAt        *time.Time             `json:"publishedAt"`
+	PublishAt          *time.Time             `json:"publishAt"`
 }
 
+func (Offer) IsEntity() {}
+
 // Category represents a category in the GraphQL layer.
 type Category struct {
 	ID          string           `json:"id"`
@@ -56,6 +59,8 @@ type Category struct {
 	UpdatedAt   time.Time        `json:"updatedAt"`
 }
 
+func (Category) IsEntity() {}
+
 // =============================================================================
 // Value Object Types
 // =============================================================================
@@ -361,17 +366,22 @@ func (e OfferSortBy) String() string {
 
 // OfferFilterInput represents filter options for listing offers.
 type OfferFilterInput struct {
-	PartnerID       *string      `json:"partnerId"`
-	EstablishmentID *string      `json:"establishmentId"`
-	CategoryID      *string      `json:"categoryId"`
-	Status          *OfferStatus `json:"status"`
-	OnlyActive      *bool        `json:"onlyActive"`
-	Latitude        *float64     `json:"latitude"`
-	Longitude       *float64     `json:"longitude"`
-	RadiusKm        *float64     `json:"radiusKm"`
-	Offset          *int         `json:"offset"`
-	Limit           *int         `json:"limit"`
-	SortBy          *OfferSortBy `json:"sortBy"`
+	PartnerID        *string       `json:"partnerId"`
+	EstablishmentID  *string       `json:"establishmentId"`
+	CategoryID       *string       `json:"categoryId"`
+	Status           *OfferStatus  `json:"status"`
+	Tags             []string      `json:"tags"`
+	Query            *string       `json:"query"`
+	OnlyActive       *bool         `json:"onlyActive"`
+	OnlyAvailableNow *bool         `json:"onlyAvailableNow"`
+	MinRating        *float64      `json:"minRating"`
+	DiscountType     *DiscountType `json:"discountType"`
+	Latitude         *float64      `json:"latitude"`
+	Longitude        *float64      `json:"longitude"`
+	RadiusKm         *float64      `json:"radiusKm"`
+	Offset           *int          `json:"offset"`
+	Limit            *int          `json:"limit"`
+	SortBy           *OfferSortBy  `json:"sortBy"`
 }
 
 // CreateOfferInput represents input for creating an offer.
@@ -392,11 +402,29 @@ type CreateOfferInput struct {
 	Images             []OfferImageInput `json:"images"`
 }
 
+// UpdateOfferInput represents input for updating an offer.
+type UpdateOfferInput struct {
+	Title              *string           `json:"title"`
+	Description        *string           `json:"description"`
+	ShortDescription   *string           `json:"shortDescription"`
+	CategoryID         *string           `json:"categoryId"`
+	Tags               []string          `json:"tags"`
+	Discount           *DiscountInput    `json:"discount"`
+	Conditions         []ConditionInput  `json:"conditions"`
+	TermsAndConditions *string           `json:"termsAndConditions"`
+	Validity           *ValidityInput    `json:"validity"`
+	Schedule           *ScheduleInput    `json:"schedule"`
+	Quota              *QuotaInput       `json:"quota"`
+	Images             []OfferImageInput `json:"images"`
+}
+
 // DiscountInput represents input for discount.
 type DiscountInput struct {
-	Type    DiscountType `json:"type"`
-	Value   int          `json:"value"`
-	Formula *string      `json:"formula"`
+	Type                  DiscountType `json:"type"`
+	Value                 int          `json:"value"`
+	OriginalPriceAmount   *int         `json:"originalPriceAmount"`
+	OriginalPriceCurrency *string      `json:"originalPriceCurrency"`
+	Formula               *string      `json:"formula"`
 }
 
 // ConditionInput represents input for condition.
@@ -435,9 +463,10 @@ type QuotaInput struct {
 
 // OfferImageInput represents input for offer image.
 type OfferImageInput struct {
-	URL       string `json:"url"`
-	Alt       string `json:"alt"`
-	IsPrimary bool   `json:"isPrimary"`
+	URL       string  `json:"url"`
+	Alt       *string `json:"alt"`
+	IsPrimary *bool   `json:"isPrimary"`
+	Order     *int    `json:"order"`
 }
 
 // CreateCategoryInput represents input for creating a category.
@@ -456,6 +485,7 @@ type CreateCategoryInput struct {
 
 // UpdateCategoryInput represents input for updating a category.
 type UpdateCategoryInput struct {
+	Slug          *string `json:"slug"`
 	NameFr        *string `json:"nameFr"`
 	NameEn        *string `json:"nameEn"`
 	DescriptionFr *string `json:"descriptionFr"`
@@ -467,3 +497,8 @@ type UpdateCategoryInput struct {
 	Order         *int    `json:"order"`
 	IsActive      *bool   `json:"isActive"`
 }
+
+// ReorderCategoriesInput represents the new order of sibling categories.
+type ReorderCategoriesInput struct {
+	CategoryIds []string `json:"categoryIds"`
+}
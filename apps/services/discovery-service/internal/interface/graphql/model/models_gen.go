@@ -0,0 +1,24 @@
+// Code generated by github.com/99designs/gqlgen, DO NOT EDIT.
+
+package model
+
+type Establishment struct {
+	ID string `json:"id"`
+}
+
+func (Establishment) IsEntity() {}
+
+type Mutation struct {
+}
+
+type Partner struct {
+	ID string `json:"id"`
+}
+
+func (Partner) IsEntity() {}
+
+type Query struct {
+}
+
+type Subscription struct {
+}
@@ -4,69 +4,111 @@ package resolver
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/yousoon/discovery-service/internal/application/commands"
 	"github.com/yousoon/discovery-service/internal/application/queries"
 	"github.com/yousoon/discovery-service/internal/domain"
+	"github.com/yousoon/discovery-service/internal/interface/graphql/generated"
 	"github.com/yousoon/discovery-service/internal/interface/graphql/model"
 )
 
+// OfferSuggester completes search terms.
+// Implemented by elasticsearch.OfferSearchRepository.
+type OfferSuggester interface {
+	GetAutocomplete(ctx context.Context, query string, limit int) ([]string, error)
+}
+
 // Resolver is the root resolver.
 type Resolver struct {
 	// Repositories
 	offerRepo    domain.OfferRepository
 	categoryRepo domain.CategoryRepository
 	readRepo     domain.OfferReadRepository
+	suggester    OfferSuggester
+
+	// Live updates
+	offerFeed domain.OfferFeed
 
 	// Command handlers
-	createOfferHandler    *commands.CreateOfferHandler
-	publishOfferHandler   *commands.PublishOfferHandler
-	archiveOfferHandler   *commands.ArchiveOfferHandler
-	createCategoryHandler *commands.CreateCategoryHandler
-	updateCategoryHandler *commands.UpdateCategoryHandler
-	deleteCategoryHandler *commands.DeleteCategoryHandler
+	createOfferHandler       *commands.CreateOfferHandler
+	updateOfferHandler       *commands.UpdateOfferHandler
+	publishOfferHandler      *commands.PublishOfferHandler
+	scheduleOfferHandler     *commands.ScheduleOfferPublicationHandler
+	cancelScheduleHandler    *commands.CancelScheduledOfferPublicationHandler
+	pauseOfferHandler        *commands.PauseOfferHandler
+	resumeOfferHandler       *commands.ResumeOfferHandler
+	archiveOfferHandler      *commands.ArchiveOfferHandler
+	deleteOfferHandler       *commands.DeleteOfferHandler
+	approveOfferHandler      *commands.ApproveOfferHandler
+	rejectOfferHandler       *commands.RejectOfferHandler
+	createCategoryHandler    *commands.CreateCategoryHandler
+	updateCategoryHandler    *commands.UpdateCategoryHandler
+	deleteCategoryHandler    *commands.DeleteCategoryHandler
+	toggleCategoryHandler    *commands.ToggleCategoryHandler
+	reorderCategoriesHandler *commands.ReorderCategoriesHandler
 
 	// Query handlers
-	getOfferHandler          *queries.GetOfferHandler
-	listOffersHandler        *queries.ListOffersHandler
-	searchOffersHandler      *queries.SearchOffersHandler
-	getPartnerOffersHandler  *queries.GetPartnerOffersHandler
-	getNearbyOffersHandler   *queries.GetNearbyOffersHandler
-	getTrendingOffersHandler *queries.GetTrendingOffersHandler
-	getCategoryHandler       *queries.GetCategoryHandler
-	listCategoriesHandler    *queries.ListCategoriesHandler
-	getCategoryTreeHandler   *queries.GetCategoryTreeHandler
+	getOfferHandler             *queries.GetOfferHandler
+	listOffersHandler           *queries.ListOffersHandler
+	searchOffersHandler         *queries.SearchOffersHandler
+	getPartnerOffersHandler     *queries.GetPartnerOffersHandler
+	getNearbyOffersHandler      *queries.GetNearbyOffersHandler
+	getCategoryHandler          *queries.GetCategoryHandler
+	getCategoryBySlugHandler    *queries.GetCategoryBySlugHandler
+	listCategoriesHandler       *queries.ListCategoriesHandler
+	getCategoryTreeHandler      *queries.GetCategoryTreeHandler
+	getCategorySummariesHandler *queries.GetCategorySummariesHandler
+	getChildCategoriesHandler   *queries.GetChildCategoriesHandler
 }
 
+var _ generated.ResolverRoot = (*Resolver)(nil)
+
 // NewResolver creates a new resolver with all dependencies.
 func NewResolver(
 	offerRepo domain.OfferRepository,
 	categoryRepo domain.CategoryRepository,
 	readRepo domain.OfferReadRepository,
+	suggester OfferSuggester,
+	offerFeed domain.OfferFeed,
 ) *Resolver {
 	return &Resolver{
 		offerRepo:    offerRepo,
 		categoryRepo: categoryRepo,
 		readRepo:     readRepo,
+		suggester:    suggester,
+		offerFeed:    offerFeed,
 
 		// Initialize command handlers
-		createOfferHandler:    commands.NewCreateOfferHandler(offerRepo),
-		publishOfferHandler:   commands.NewPublishOfferHandler(offerRepo),
-		archiveOfferHandler:   commands.NewArchiveOfferHandler(offerRepo),
-		createCategoryHandler: commands.NewCreateCategoryHandler(categoryRepo),
-		updateCategoryHandler: commands.NewUpdateCategoryHandler(categoryRepo),
-		deleteCategoryHandler: commands.NewDeleteCategoryHandler(categoryRepo, offerRepo),
+		createOfferHandler:       commands.NewCreateOfferHandler(offerRepo),
+		updateOfferHandler:       commands.NewUpdateOfferHandler(offerRepo),
+		publishOfferHandler:      commands.NewPublishOfferHandler(offerRepo),
+		scheduleOfferHandler:     commands.NewScheduleOfferPublicationHandler(offerRepo),
+		cancelScheduleHandler:    commands.NewCancelScheduledOfferPublicationHandler(offerRepo),
+		pauseOfferHandler:        commands.NewPauseOfferHandler(offerRepo),
+		resumeOfferHandler:       commands.NewResumeOfferHandler(offerRepo),
+		archiveOfferHandler:      commands.NewArchiveOfferHandler(offerRepo),
+		deleteOfferHandler:       commands.NewDeleteOfferHandler(offerRepo),
+		approveOfferHandler:      commands.NewApproveOfferHandler(offerRepo),
+		rejectOfferHandler:       commands.NewRejectOfferHandler(offerRepo),
+		createCategoryHandler:    commands.NewCreateCategoryHandler(categoryRepo),
+		updateCategoryHandler:    commands.NewUpdateCategoryHandler(categoryRepo),
+		deleteCategoryHandler:    commands.NewDeleteCategoryHandler(categoryRepo, offerRepo),
+		toggleCategoryHandler:    commands.NewToggleCategoryHandler(categoryRepo),
+		reorderCategoriesHandler: commands.NewReorderCategoriesHandler(categoryRepo),
 
 		// Initialize query handlers
-		getOfferHandler:          queries.NewGetOfferHandler(offerRepo),
-		listOffersHandler:        queries.NewListOffersHandler(offerRepo),
-		searchOffersHandler:      queries.NewSearchOffersHandler(readRepo),
-		getPartnerOffersHandler:  queries.NewGetPartnerOffersHandler(offerRepo),
-		getNearbyOffersHandler:   queries.NewGetNearbyOffersHandler(readRepo),
-		getTrendingOffersHandler: queries.NewGetTrendingOffersHandler(readRepo),
-		getCategoryHandler:       queries.NewGetCategoryHandler(categoryRepo),
-		listCategoriesHandler:    queries.NewListCategoriesHandler(categoryRepo),
-		getCategoryTreeHandler:   queries.NewGetCategoryTreeHandler(categoryRepo),
+		getOfferHandler:             queries.NewGetOfferHandler(offerRepo),
+		listOffersHandler:           queries.NewListOffersHandler(offerRepo),
+		searchOffersHandler:         queries.NewSearchOffersHandler(readRepo),
+		getPartnerOffersHandler:     queries.NewGetPartnerOffersHandler(offerRepo),
+		getNearbyOffersHandler:      queries.NewGetNearbyOffersHandler(readRepo),
+		getCategoryHandler:          queries.NewGetCategoryHandler(categoryRepo),
+		getCategoryBySlugHandler:    queries.NewGetCategoryBySlugHandler(categoryRepo),
+		listCategoriesHandler:       queries.NewListCategoriesHandler(categoryRepo),
+		getCategoryTreeHandler:      queries.NewGetCategoryTreeHandler(categoryRepo),
+		getCategorySummariesHandler: queries.NewGetCategorySummariesHandler(categoryRepo),
+		getChildCategoriesHandler:   queries.NewGetChildCategoriesHandler(categoryRepo),
 	}
 }
 
@@ -74,8 +116,15 @@ func NewResolver(
 // Query Resolvers
 // =============================================================================
 
+// Query returns the QueryResolver implementation.
+func (r *Resolver) Query() generated.QueryResolver {
+	return &queryResolver{r}
+}
+
+type queryResolver struct{ *Resolver }
+
 // Offer returns an offer by ID.
-func (r *Resolver) Offer(ctx context.Context, id string) (*model.Offer, error) {
+func (r *queryResolver) Offer(ctx context.Context, id string) (*model.Offer, error) {
 	result, err := r.getOfferHandler.Handle(ctx, queries.GetOfferQuery{OfferID: id})
 	if err != nil {
 		return nil, err
@@ -84,7 +133,7 @@ func (r *Resolver) Offer(ctx context.Context, id string) (*model.Offer, error) {
 }
 
 // Offers returns a paginated list of offers.
-func (r *Resolver) Offers(ctx context.Context, filter *model.OfferFilterInput) (*model.OfferListResult, error) {
+func (r *queryResolver) Offers(ctx context.Context, filter *model.OfferFilterInput) (*model.OfferListResult, error) {
 	query := queries.ListOffersQuery{
 		Offset: 0,
 		Limit:  20,
@@ -117,20 +166,11 @@ func (r *Resolver) Offers(ctx context.Context, filter *model.OfferFilterInput) (
 		return nil, err
 	}
 
-	offers := make([]*model.Offer, len(result.Offers))
-	for i, offer := range result.Offers {
-		offers[i] = mapOfferToModel(offer)
-	}
-
-	return &model.OfferListResult{
-		Offers:  offers,
-		Total:   int(result.TotalCount),
-		HasMore: result.HasMore,
-	}, nil
+	return mapOfferListToModel(result), nil
 }
 
 // SearchOffers performs a full-text search on offers.
-func (r *Resolver) SearchOffers(ctx context.Context, query string, filter *model.OfferFilterInput) (*model.OfferSearchResult, error) {
+func (r *queryResolver) SearchOffers(ctx context.Context, query string, filter *model.OfferFilterInput) (*model.OfferSearchResult, error) {
 	searchQuery := queries.SearchOffersQuery{
 		Query: query,
 		Limit: 20,
@@ -164,7 +204,7 @@ func (r *Resolver) SearchOffers(ctx context.Context, query string, filter *model
 }
 
 // NearbyOffers returns offers near a location.
-func (r *Resolver) NearbyOffers(ctx context.Context, latitude, longitude, radiusKm float64, filter *model.OfferFilterInput) (*model.OfferSearchResult, error) {
+func (r *queryResolver) NearbyOffers(ctx context.Context, latitude, longitude, radiusKm float64, filter *model.OfferFilterInput) (*model.OfferSearchResult, error) {
 	query := queries.GetNearbyOffersQuery{
 		Latitude:  latitude,
 		Longitude: longitude,
@@ -195,29 +235,134 @@ func (r *Resolver) NearbyOffers(ctx context.Context, latitude, longitude, radius
 	}, nil
 }
 
-// TrendingOffers returns trending offers.
-func (r *Resolver) TrendingOffers(ctx context.Context, limit *int) ([]*model.OfferSummary, error) {
+// OffersByPartner returns the offers of a partner, newest first.
+func (r *queryResolver) OffersByPartner(ctx context.Context, partnerID string, offset, limit *int) (*model.OfferListResult, error) {
+	query := queries.GetPartnerOffersQuery{PartnerID: partnerID}
+	if offset != nil {
+		query.Offset = *offset
+	}
+	if limit != nil {
+		query.Limit = *limit
+	}
+
+	result, err := r.getPartnerOffersHandler.Handle(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	return mapOfferListToModel(result), nil
+}
+
+// OffersByEstablishment returns the offers of an establishment.
+func (r *queryResolver) OffersByEstablishment(ctx context.Context, establishmentID string, offset, limit *int) (*model.OfferListResult, error) {
+	query := queries.ListOffersQuery{EstablishmentID: &establishmentID}
+	if offset != nil {
+		query.Offset = *offset
+	}
+	if limit != nil {
+		query.Limit = *limit
+	}
+
+	result, err := r.listOffersHandler.Handle(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	return mapOfferListToModel(result), nil
+}
+
+// OffersByCategory returns the active offers of a category.
+func (r *queryResolver) OffersByCategory(ctx context.Context, categoryID string, offset, limit *int) (*model.OfferListResult, error) {
+	query := queries.ListOffersQuery{
+		CategoryID: &categoryID,
+		OnlyActive: true,
+	}
+	if offset != nil {
+		query.Offset = *offset
+	}
+	if limit != nil {
+		query.Limit = *limit
+	}
+
+	result, err := r.listOffersHandler.Handle(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	return mapOfferListToModel(result), nil
+}
+
+// TrendingOffers returns the most booked active offers.
+func (r *queryResolver) TrendingOffers(ctx context.Context, limit *int) ([]*model.TrendingOffer, error) {
+	query := queries.ListOffersQuery{
+		OnlyActive: true,
+		Limit:      10,
+		SortBy:     "popularity",
+		SortOrder:  "desc",
+	}
+	if limit != nil {
+		query.Limit = *limit
+	}
+
+	result, err := r.listOffersHandler.Handle(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	trending := make([]*model.TrendingOffer, len(result.Offers))
+	for i, offer := range result.Offers {
+		trending[i] = &model.TrendingOffer{
+			Offer:  mapOfferToModel(offer),
+			Score:  float64(offer.Stats().Bookings),
+			Reason: "most_booked",
+		}
+	}
+
+	return trending, nil
+}
+
+// RecommendedOffers returns offers for the user. Users only get their own
+// recommendations; until preferences are known these are the most booked
+// active offers.
+func (r *queryResolver) RecommendedOffers(ctx context.Context, userID string, limit *int) ([]*model.Offer, error) {
+	if current := getUserIDFromContext(ctx); current == "" || current != userID {
+		return nil, fmt.Errorf("unauthorized")
+	}
+
+	query := queries.ListOffersQuery{
+		OnlyActive: true,
+		Limit:      10,
+		SortBy:     "popularity",
+		SortOrder:  "desc",
+	}
+	if limit != nil {
+		query.Limit = *limit
+	}
+
+	result, err := r.listOffersHandler.Handle(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	return mapOfferListToModel(result).Offers, nil
+}
+
+// Autocomplete suggests search terms starting with the query.
+func (r *queryResolver) Autocomplete(ctx context.Context, query string, limit *int) (*model.AutocompleteResult, error) {
 	l := 10
 	if limit != nil {
 		l = *limit
 	}
 
-	query := queries.GetTrendingOffersQuery{Limit: l}
-	result, err := r.getTrendingOffersHandler.Handle(ctx, query)
+	suggestions, err := r.suggester.GetAutocomplete(ctx, query, l)
 	if err != nil {
 		return nil, err
 	}
-
-	summaries := make([]*model.OfferSummary, len(result))
-	for i, summary := range result {
-		summaries[i] = mapOfferSummaryToModel(&summary)
+	if suggestions == nil {
+		suggestions = []string{}
 	}
 
-	return summaries, nil
+	return &model.AutocompleteResult{Suggestions: suggestions}, nil
 }
 
 // Category returns a category by ID.
-func (r *Resolver) Category(ctx context.Context, id string) (*model.Category, error) {
+func (r *queryResolver) Category(ctx context.Context, id string) (*model.Category, error) {
 	result, err := r.getCategoryHandler.Handle(ctx, queries.GetCategoryQuery{CategoryID: id})
 	if err != nil {
 		return nil, err
@@ -226,7 +371,7 @@ func (r *Resolver) Category(ctx context.Context, id string) (*model.Category, er
 }
 
 // Categories returns all categories.
-func (r *Resolver) Categories(ctx context.Context, activeOnly *bool) ([]*model.Category, error) {
+func (r *queryResolver) Categories(ctx context.Context, activeOnly *bool) ([]*model.Category, error) {
 	active := true
 	if activeOnly != nil {
 		active = *activeOnly
@@ -237,16 +382,43 @@ func (r *Resolver) Categories(ctx context.Context, activeOnly *bool) ([]*model.C
 		return nil, err
 	}
 
-	categories := make([]*model.Category, len(result))
-	for i, cat := range result {
-		categories[i] = mapCategoryToModel(cat)
+	return mapCategoriesToModel(result), nil
+}
+
+// CategoryBySlug returns a category by slug.
+func (r *queryResolver) CategoryBySlug(ctx context.Context, slug string) (*model.Category, error) {
+	result, err := r.getCategoryBySlugHandler.Handle(ctx, queries.GetCategoryBySlugQuery{Slug: slug})
+	if err != nil {
+		return nil, err
+	}
+	return mapCategoryToModel(result), nil
+}
+
+// RootCategories returns the categories without a parent.
+func (r *queryResolver) RootCategories(ctx context.Context, activeOnly *bool) ([]*model.Category, error) {
+	active := true
+	if activeOnly != nil {
+		active = *activeOnly
+	}
+
+	result, err := r.listCategoriesHandler.Handle(ctx, queries.ListCategoriesQuery{OnlyRoot: true})
+	if err != nil {
+		return nil, err
+	}
+
+	categories := make([]*model.Category, 0, len(result))
+	for _, cat := range result {
+		if active && !cat.IsActive() {
+			continue
+		}
+		categories = append(categories, mapCategoryToModel(cat))
 	}
 
 	return categories, nil
 }
 
 // CategoryTree returns the category tree.
-func (r *Resolver) CategoryTree(ctx context.Context, activeOnly *bool) ([]*model.CategoryTree, error) {
+func (r *queryResolver) CategoryTree(ctx context.Context, activeOnly *bool) ([]*model.CategoryTree, error) {
 	query := queries.GetCategoryTreeQuery{}
 	if activeOnly != nil {
 		query.OnlyActive = *activeOnly
@@ -260,12 +432,43 @@ func (r *Resolver) CategoryTree(ctx context.Context, activeOnly *bool) ([]*model
 	return mapCategoryTreeToModel(result), nil
 }
 
+// CategorySummaries returns the categories with their offer counts.
+func (r *queryResolver) CategorySummaries(ctx context.Context) ([]*model.CategorySummary, error) {
+	result, err := r.getCategorySummariesHandler.Handle(ctx, queries.GetCategorySummariesQuery{})
+	if err != nil {
+		return nil, err
+	}
+
+	summaries := make([]*model.CategorySummary, len(result))
+	for i, summary := range result {
+		summaries[i] = &model.CategorySummary{
+			ID:         summary.ID.String(),
+			Name:       &model.LocalizedString{FR: summary.Name},
+			Slug:       summary.Slug,
+			OfferCount: summary.OfferCount,
+		}
+		if summary.Icon != "" {
+			icon := summary.Icon
+			summaries[i].Icon = &icon
+		}
+	}
+
+	return summaries, nil
+}
+
 // =============================================================================
 // Mutation Resolvers
 // =============================================================================
 
+// Mutation returns the MutationResolver implementation.
+func (r *Resolver) Mutation() generated.MutationResolver {
+	return &mutationResolver{r}
+}
+
+type mutationResolver struct{ *Resolver }
+
 // CreateOffer creates a new offer.
-func (r *Resolver) CreateOffer(ctx context.Context, input model.CreateOfferInput) (*model.Offer, error) {
+func (r *mutationResolver) CreateOffer(ctx context.Context, input model.CreateOfferInput) (*model.Offer, error) {
 	cmd := commands.CreateOfferCommand{
 		PartnerID:       input.PartnerID,
 		EstablishmentID: input.EstablishmentID,
@@ -304,8 +507,94 @@ func (r *Resolver) CreateOffer(ctx context.Context, input model.CreateOfferInput
 	return mapOfferToModel(offer), nil
 }
 
+// UpdateOffer changes the fields set in the input. Images are managed with
+// their own commands and cannot be replaced here.
+func (r *mutationResolver) UpdateOffer(ctx context.Context, id string, input model.UpdateOfferInput) (*model.Offer, error) {
+	if input.Images != nil {
+		return nil, fmt.Errorf("images cannot be updated with updateOffer")
+	}
+
+	cmd := commands.UpdateOfferCommand{
+		OfferID:            id,
+		Title:              input.Title,
+		Description:        input.Description,
+		ShortDescription:   input.ShortDescription,
+		CategoryID:         input.CategoryID,
+		Tags:               input.Tags,
+		TermsAndConditions: input.TermsAndConditions,
+	}
+
+	if input.Discount != nil {
+		discount := mapDiscountInput(*input.Discount)
+		cmd.Discount = &discount
+	}
+	if input.Conditions != nil {
+		cmd.Conditions = make([]commands.ConditionInput, len(input.Conditions))
+		for i, c := range input.Conditions {
+			cmd.Conditions[i] = commands.ConditionInput{
+				Type:  mapConditionTypeToDomain(c.Type),
+				Value: c.Value,
+				Label: c.Label,
+			}
+		}
+	}
+	if input.Validity != nil {
+		cmd.Validity = &commands.ValidityInput{
+			StartDate: input.Validity.StartDate,
+			EndDate:   input.Validity.EndDate,
+			Timezone:  ptrToString(input.Validity.Timezone),
+		}
+	}
+	if input.Schedule != nil {
+		schedule := commands.ScheduleInput{AllDay: input.Schedule.AllDay}
+		for _, slot := range input.Schedule.Slots {
+			schedule.Slots = append(schedule.Slots, commands.TimeSlotInput{
+				DayOfWeek: slot.DayOfWeek,
+				StartTime: slot.StartTime,
+				EndTime:   slot.EndTime,
+			})
+		}
+		cmd.Schedule = &schedule
+	}
+	if input.Quota != nil {
+		cmd.Quota = &commands.QuotaInput{
+			Total:   input.Quota.Total,
+			PerUser: input.Quota.PerUser,
+			PerDay:  input.Quota.PerDay,
+		}
+	}
+
+	offer, err := r.updateOfferHandler.Handle(ctx, cmd)
+	if err != nil {
+		return nil, err
+	}
+	return mapOfferToModel(offer), nil
+}
+
+// ExtendOffer moves the end of the offer's validity.
+func (r *mutationResolver) ExtendOffer(ctx context.Context, id string, newEndDate time.Time) (*model.Offer, error) {
+	current, err := r.getOfferHandler.Handle(ctx, queries.GetOfferQuery{OfferID: id})
+	if err != nil {
+		return nil, err
+	}
+
+	validity := current.Validity()
+	offer, err := r.updateOfferHandler.Handle(ctx, commands.UpdateOfferCommand{
+		OfferID: id,
+		Validity: &commands.ValidityInput{
+			StartDate: validity.StartDate,
+			EndDate:   newEndDate,
+			Timezone:  validity.Timezone,
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return mapOfferToModel(offer), nil
+}
+
 // PublishOffer publishes an offer.
-func (r *Resolver) PublishOffer(ctx context.Context, id string) (*model.Offer, error) {
+func (r *mutationResolver) PublishOffer(ctx context.Context, id string) (*model.Offer, error) {
 	offer, err := r.publishOfferHandler.Handle(ctx, commands.PublishOfferCommand{OfferID: id})
 	if err != nil {
 		return nil, err
@@ -313,8 +602,47 @@ func (r *Resolver) PublishOffer(ctx context.Context, id string) (*model.Offer, e
 	return mapOfferToModel(offer), nil
 }
 
+// ScheduleOfferPublication publishes an offer automatically once approved.
+func (r *mutationResolver) ScheduleOfferPublication(ctx context.Context, id string, publishAt *time.Time) (*model.Offer, error) {
+	offer, err := r.scheduleOfferHandler.Handle(ctx, commands.ScheduleOfferPublicationCommand{
+		OfferID:   id,
+		PublishAt: publishAt,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return mapOfferToModel(offer), nil
+}
+
+// CancelScheduledOfferPublication leaves publication to the partner again.
+func (r *mutationResolver) CancelScheduledOfferPublication(ctx context.Context, id string) (*model.Offer, error) {
+	offer, err := r.cancelScheduleHandler.Handle(ctx, commands.CancelScheduledOfferPublicationCommand{OfferID: id})
+	if err != nil {
+		return nil, err
+	}
+	return mapOfferToModel(offer), nil
+}
+
+// PauseOffer pauses an active offer.
+func (r *mutationResolver) PauseOffer(ctx context.Context, id string) (*model.Offer, error) {
+	offer, err := r.pauseOfferHandler.Handle(ctx, commands.PauseOfferCommand{OfferID: id})
+	if err != nil {
+		return nil, err
+	}
+	return mapOfferToModel(offer), nil
+}
+
+// ResumeOffer resumes a paused offer.
+func (r *mutationResolver) ResumeOffer(ctx context.Context, id string) (*model.Offer, error) {
+	offer, err := r.resumeOfferHandler.Handle(ctx, commands.ResumeOfferCommand{OfferID: id})
+	if err != nil {
+		return nil, err
+	}
+	return mapOfferToModel(offer), nil
+}
+
 // ArchiveOffer archives an offer.
-func (r *Resolver) ArchiveOffer(ctx context.Context, id string) (*model.Offer, error) {
+func (r *mutationResolver) ArchiveOffer(ctx context.Context, id string) (*model.Offer, error) {
 	offer, err := r.archiveOfferHandler.Handle(ctx, commands.ArchiveOfferCommand{OfferID: id})
 	if err != nil {
 		return nil, err
@@ -322,8 +650,49 @@ func (r *Resolver) ArchiveOffer(ctx context.Context, id string) (*model.Offer, e
 	return mapOfferToModel(offer), nil
 }
 
+// DeleteOffer deletes an offer.
+func (r *mutationResolver) DeleteOffer(ctx context.Context, id string) (bool, error) {
+	if err := r.deleteOfferHandler.Handle(ctx, commands.DeleteOfferCommand{OfferID: id}); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// ApproveOffer approves an offer pending moderation. Admin only.
+func (r *mutationResolver) ApproveOffer(ctx context.Context, id string) (*model.Offer, error) {
+	if !hasRole(ctx, "admin") {
+		return nil, fmt.Errorf("forbidden")
+	}
+
+	offer, err := r.approveOfferHandler.Handle(ctx, commands.ApproveOfferCommand{
+		OfferID:    id,
+		ReviewerID: getUserIDFromContext(ctx),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return mapOfferToModel(offer), nil
+}
+
+// RejectOffer rejects an offer pending moderation. Admin only.
+func (r *mutationResolver) RejectOffer(ctx context.Context, id string, reason string) (*model.Offer, error) {
+	if !hasRole(ctx, "admin") {
+		return nil, fmt.Errorf("forbidden")
+	}
+
+	offer, err := r.rejectOfferHandler.Handle(ctx, commands.RejectOfferCommand{
+		OfferID:    id,
+		ReviewerID: getUserIDFromContext(ctx),
+		Reason:     reason,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return mapOfferToModel(offer), nil
+}
+
 // CreateCategory creates a new category.
-func (r *Resolver) CreateCategory(ctx context.Context, input model.CreateCategoryInput) (*model.Category, error) {
+func (r *mutationResolver) CreateCategory(ctx context.Context, input model.CreateCategoryInput) (*model.Category, error) {
 	nameEN := ""
 	if input.NameEn != nil {
 		nameEN = *input.NameEn
@@ -360,8 +729,84 @@ func (r *Resolver) CreateCategory(ctx context.Context, input model.CreateCategor
 	return mapCategoryToModel(category), nil
 }
 
+// UpdateCategory changes the fields set in the input.
+func (r *mutationResolver) UpdateCategory(ctx context.Context, id string, input model.UpdateCategoryInput) (*model.Category, error) {
+	category, err := r.updateCategoryHandler.Handle(ctx, commands.UpdateCategoryCommand{
+		CategoryID:    id,
+		Slug:          input.Slug,
+		NameFR:        input.NameFr,
+		NameEN:        input.NameEn,
+		DescriptionFR: input.DescriptionFr,
+		DescriptionEN: input.DescriptionEn,
+		Icon:          input.Icon,
+		Color:         input.Color,
+		Image:         input.Image,
+		ParentID:      input.ParentID,
+		Order:         input.Order,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if input.IsActive != nil && *input.IsActive != category.IsActive() {
+		category, err = r.toggleCategoryHandler.Handle(ctx, commands.ToggleCategoryCommand{
+			CategoryID: id,
+			Activate:   *input.IsActive,
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return mapCategoryToModel(category), nil
+}
+
+// ReorderCategories orders the categories as listed.
+func (r *mutationResolver) ReorderCategories(ctx context.Context, input model.ReorderCategoriesInput) ([]*model.Category, error) {
+	orders := make([]commands.CategoryOrder, len(input.CategoryIds))
+	for i, id := range input.CategoryIds {
+		orders[i] = commands.CategoryOrder{CategoryID: id, Order: i}
+	}
+
+	if err := r.reorderCategoriesHandler.Handle(ctx, commands.ReorderCategoriesCommand{CategoryOrders: orders}); err != nil {
+		return nil, err
+	}
+
+	categories := make([]*model.Category, 0, len(input.CategoryIds))
+	for _, id := range input.CategoryIds {
+		category, err := r.getCategoryHandler.Handle(ctx, queries.GetCategoryQuery{CategoryID: id})
+		if err == domain.ErrCategoryNotFound {
+			continue // Unknown categories are not reordered
+		}
+		if err != nil {
+			return nil, err
+		}
+		categories = append(categories, mapCategoryToModel(category))
+	}
+
+	return categories, nil
+}
+
+// ActivateCategory activates a category.
+func (r *mutationResolver) ActivateCategory(ctx context.Context, id string) (*model.Category, error) {
+	category, err := r.toggleCategoryHandler.Handle(ctx, commands.ToggleCategoryCommand{CategoryID: id, Activate: true})
+	if err != nil {
+		return nil, err
+	}
+	return mapCategoryToModel(category), nil
+}
+
+// DeactivateCategory deactivates a category.
+func (r *mutationResolver) DeactivateCategory(ctx context.Context, id string) (*model.Category, error) {
+	category, err := r.toggleCategoryHandler.Handle(ctx, commands.ToggleCategoryCommand{CategoryID: id, Activate: false})
+	if err != nil {
+		return nil, err
+	}
+	return mapCategoryToModel(category), nil
+}
+
 // DeleteCategory deletes a category.
-func (r *Resolver) DeleteCategory(ctx context.Context, id string) (bool, error) {
+func (r *mutationResolver) DeleteCategory(ctx context.Context, id string) (bool, error) {
 	err := r.deleteCategoryHandler.Handle(ctx, commands.DeleteCategoryCommand{CategoryID: id})
 	if err != nil {
 		return false, err
@@ -369,18 +814,153 @@ func (r *Resolver) DeleteCategory(ctx context.Context, id string) (bool, error)
 	return true, nil
 }
 
+// =============================================================================
+// Subscription Resolvers
+// =============================================================================
+
+// Subscription returns the SubscriptionResolver implementation.
+func (r *Resolver) Subscription() generated.SubscriptionResolver {
+	return &subscriptionResolver{r}
+}
+
+type subscriptionResolver struct{ *Resolver }
+
+// OfferPublished streams newly published offers, optionally limited to
+// categories and to a radius around a location.
+func (r *subscriptionResolver) OfferPublished(ctx context.Context, categoryIds []string, latitude, longitude, radiusKm *float64) (<-chan *model.Offer, error) {
+	filter := domain.PublishedOfferFilter{}
+	for _, id := range categoryIds {
+		filter.CategoryIDs = append(filter.CategoryIDs, domain.CategoryID(id))
+	}
+
+	if latitude != nil || longitude != nil || radiusKm != nil {
+		if latitude == nil || longitude == nil || radiusKm == nil {
+			return nil, fmt.Errorf("latitude, longitude and radiusKm must be set together")
+		}
+		if *radiusKm <= 0 {
+			return nil, fmt.Errorf("radiusKm must be positive")
+		}
+		near, err := domain.NewGeoLocation(*longitude, *latitude)
+		if err != nil {
+			return nil, err
+		}
+		filter.Near = &near
+		filter.RadiusKm = *radiusKm
+	}
+
+	offers, err := r.offerFeed.SubscribePublished(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	return mapOfferStream(ctx, offers), nil
+}
+
+// OfferUpdated streams the offer each time it changes.
+func (r *subscriptionResolver) OfferUpdated(ctx context.Context, id string) (<-chan *model.Offer, error) {
+	offers, err := r.offerFeed.SubscribeOffer(ctx, domain.OfferID(id))
+	if err != nil {
+		return nil, err
+	}
+	return mapOfferStream(ctx, offers), nil
+}
+
+// OfferExpiringSoon streams the user's favorite offers as they come close to
+// their end date. Users may only watch their own favorites.
+func (r *subscriptionResolver) OfferExpiringSoon(ctx context.Context, userID string) (<-chan *model.Offer, error) {
+	if current := getUserIDFromContext(ctx); current == "" || current != userID {
+		return nil, fmt.Errorf("unauthorized")
+	}
+
+	offers, err := r.offerFeed.SubscribeExpiringSoon(ctx, domain.UserID(userID))
+	if err != nil {
+		return nil, err
+	}
+	return mapOfferStream(ctx, offers), nil
+}
+
+// mapOfferStream maps the offers of a feed until it closes or ctx is done.
+func mapOfferStream(ctx context.Context, offers <-chan *domain.Offer) <-chan *model.Offer {
+	out := make(chan *model.Offer, 1)
+	go func() {
+		defer close(out)
+		for offer := range offers {
+			select {
+			case out <- mapOfferToModel(offer):
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return out
+}
+
 // =============================================================================
 // Entity Resolvers (Federation)
 // =============================================================================
 
+// Entity returns the EntityResolver implementation.
+func (r *Resolver) Entity() generated.EntityResolver {
+	return &entityResolver{r}
+}
+
+type entityResolver struct{ *Resolver }
+
 // FindOfferByID resolves an Offer entity by ID for federation.
-func (r *Resolver) FindOfferByID(ctx context.Context, id string) (*model.Offer, error) {
-	return r.Offer(ctx, id)
+func (r *entityResolver) FindOfferByID(ctx context.Context, id string) (*model.Offer, error) {
+	return r.Query().Offer(ctx, id)
 }
 
 // FindCategoryByID resolves a Category entity by ID for federation.
-func (r *Resolver) FindCategoryByID(ctx context.Context, id string) (*model.Category, error) {
-	return r.Category(ctx, id)
+func (r *entityResolver) FindCategoryByID(ctx context.Context, id string) (*model.Category, error) {
+	return r.Query().Category(ctx, id)
+}
+
+// =============================================================================
+// Field Resolvers
+// =============================================================================
+
+// Offer returns the OfferResolver implementation.
+func (r *Resolver) Offer() generated.OfferResolver {
+	return &offerResolver{r}
+}
+
+type offerResolver struct{ *Resolver }
+
+// Category resolves the category of an offer.
+func (r *offerResolver) Category(ctx context.Context, obj *model.Offer) (*model.Category, error) {
+	category, err := r.categoryRepo.FindByID(ctx, domain.CategoryID(obj.CategoryID))
+	if err != nil {
+		return nil, err
+	}
+	return mapCategoryToModel(category), nil
+}
+
+// Category returns the CategoryResolver implementation.
+func (r *Resolver) Category() generated.CategoryResolver {
+	return &categoryResolver{r}
+}
+
+type categoryResolver struct{ *Resolver }
+
+// Parent resolves the parent of a category, nil for a root category.
+func (r *categoryResolver) Parent(ctx context.Context, obj *model.Category) (*model.Category, error) {
+	if obj.ParentID == nil {
+		return nil, nil
+	}
+	parent, err := r.categoryRepo.FindByID(ctx, domain.CategoryID(*obj.ParentID))
+	if err != nil {
+		return nil, err
+	}
+	return mapCategoryToModel(parent), nil
+}
+
+// Children resolves the direct children of a category.
+func (r *categoryResolver) Children(ctx context.Context, obj *model.Category) ([]*model.Category, error) {
+	children, err := r.getChildCategoriesHandler.Handle(ctx, queries.GetChildCategoriesQuery{ParentID: obj.ID})
+	if err != nil {
+		return nil, err
+	}
+	return mapCategoriesToModel(children), nil
 }
 
 // =============================================================================
@@ -392,9 +972,8 @@ func mapOfferToModel(offer *domain.Offer) *model.Offer {
 		return nil
 	}
 
-	// Check if offer is available now via schedule
-	schedule := offer.Schedule()
-	isAvailableNow := offer.IsActive() && schedule.IsAvailableNow()
+	// Evaluated in the offer's timezone, not the server's
+	isAvailableNow := offer.IsAvailableAt(time.Now())
 
 	m := &model.Offer{
 		ID:               offer.ID().String(),
@@ -411,6 +990,7 @@ func mapOfferToModel(offer *domain.Offer) *model.Offer {
 		CreatedAt:        offer.CreatedAt(),
 		UpdatedAt:        offer.UpdatedAt(),
 		PublishedAt:      offer.PublishedAt(),
+		PublishAt:        offer.PublishAt(),
 	}
 
 	// Map discount
@@ -438,6 +1018,7 @@ func mapOfferToModel(offer *domain.Offer) *model.Offer {
 	}
 
 	// Map schedule
+	schedule := offer.Schedule()
 	m.Schedule = &model.Schedule{
 		AllDay: schedule.AllDay,
 		Slots:  make([]*model.TimeSlot, len(schedule.Slots)),
@@ -611,6 +1192,14 @@ func mapCategoryToModel(cat *domain.Category) *model.Category {
 	return m
 }
 
+func mapCategoriesToModel(categories []*domain.Category) []*model.Category {
+	result := make([]*model.Category, len(categories))
+	for i, cat := range categories {
+		result[i] = mapCategoryToModel(cat)
+	}
+	return result
+}
+
 func mapCategoryTreeToModel(trees []*domain.CategoryTree) []*model.CategoryTree {
 	result := make([]*model.CategoryTree, len(trees))
 	for i, tree := range trees {
@@ -622,6 +1211,19 @@ func mapCategoryTreeToModel(trees []*domain.CategoryTree) []*model.CategoryTree
 	return result
 }
 
+func mapOfferListToModel(result *queries.ListOffersResult) *model.OfferListResult {
+	offers := make([]*model.Offer, len(result.Offers))
+	for i, offer := range result.Offers {
+		offers[i] = mapOfferToModel(offer)
+	}
+
+	return &model.OfferListResult{
+		Offers:  offers,
+		Total:   int(result.TotalCount),
+		HasMore: result.HasMore,
+	}
+}
+
 func mapOfferSummaryToModel(summary *domain.OfferSummary) *model.OfferSummary {
 	if summary == nil {
 		return nil
@@ -703,9 +1305,66 @@ func mapConditionTypeToModel(ct domain.ConditionType) model.ConditionType {
 	}
 }
 
+func mapDiscountInput(input model.DiscountInput) commands.DiscountInput {
+	discount := commands.DiscountInput{
+		Type:    mapDiscountTypeToDomain(input.Type),
+		Value:   input.Value,
+		Formula: ptrToString(input.Formula),
+	}
+	if input.OriginalPriceAmount != nil {
+		// Amounts are exposed in euros and stored in cents
+		cents := int64(*input.OriginalPriceAmount) * 100
+		discount.OriginalPrice = &cents
+	}
+	return discount
+}
+
+func mapDiscountTypeToDomain(dt model.DiscountType) string {
+	switch dt {
+	case model.DiscountTypeFixed:
+		return string(domain.DiscountTypeFixed)
+	case model.DiscountTypeFormula:
+		return string(domain.DiscountTypeFormula)
+	default:
+		return string(domain.DiscountTypePercentage)
+	}
+}
+
+func mapConditionTypeToDomain(ct model.ConditionType) string {
+	switch ct {
+	case model.ConditionTypeMinPurchase:
+		return string(domain.ConditionTypeMinPurchase)
+	case model.ConditionTypeMinPeople:
+		return string(domain.ConditionTypeMinPeople)
+	case model.ConditionTypeFirstVisit:
+		return string(domain.ConditionTypeFirstVisit)
+	default:
+		return string(domain.ConditionTypeOther)
+	}
+}
+
 func ptrToString(s *string) string {
 	if s == nil {
 		return ""
 	}
 	return *s
 }
+
+func getUserIDFromContext(ctx context.Context) string {
+	// Set by the ForwardedUser middleware from the router headers
+	if userID, ok := ctx.Value("user_id").(string); ok {
+		return userID
+	}
+	return ""
+}
+
+// hasRole reports whether the router forwarded the role for the user
+func hasRole(ctx context.Context, role string) bool {
+	roles, _ := ctx.Value("user_roles").([]string)
+	for _, granted := range roles {
+		if granted == role {
+			return true
+		}
+	}
+	return false
+}
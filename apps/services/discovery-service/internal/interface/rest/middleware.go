@@ -0,0 +1,26 @@
+// Package rest contains the HTTP middleware of the Discovery service.
+package rest
+
+import (
+	"context"
+	"net/http"
+	"strings"
+)
+
+// ForwardedUser puts the user authenticated by the router, forwarded in the
+// X-User-ID header, into the request context where resolvers look it up,
+// along with the roles of the comma-separated X-User-Roles header. It also
+// covers websocket subscriptions, whose context comes from the upgrade
+// request.
+func ForwardedUser(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if userID := r.Header.Get("X-User-ID"); userID != "" {
+			ctx := context.WithValue(r.Context(), "user_id", userID)
+			if roles := r.Header.Get("X-User-Roles"); roles != "" {
+				ctx = context.WithValue(ctx, "user_roles", strings.Split(roles, ","))
+			}
+			r = r.WithContext(ctx)
+		}
+		next.ServeHTTP(w, r)
+	})
+}
@@ -40,6 +40,7 @@ type AddEstablishmentCommand struct {
 	Type       string   `json:"type"`
 	Features   []string `json:"features"`
 	PriceRange int      `json:"priceRange"`
+	Timezone   string   `json:"timezone"`
 }
 
 // Validate validates the command.
@@ -124,6 +125,11 @@ func (h *AddEstablishmentHandler) Handle(ctx context.Context, cmd AddEstablishme
 	establishment.SetType(cmd.Type)
 	establishment.SetFeatures(cmd.Features)
 	establishment.SetPriceRange(cmd.PriceRange)
+	if cmd.Timezone != "" {
+		if err := establishment.SetTimezone(cmd.Timezone); err != nil {
+			return nil, err
+		}
+	}
 
 	// Add to partner
 	if err := partner.AddEstablishment(establishment); err != nil {
@@ -33,6 +33,7 @@ var (
 	// Establishment errors
 	ErrEstablishmentNotFound      = errors.New("establishment not found")
 	ErrEstablishmentAlreadyExists = errors.New("establishment at this address already exists")
+	ErrInvalidTimezone            = errors.New("unknown timezone")
 
 	// Team member errors
 	ErrTeamMemberNotFound   = errors.New("team member not found")
@@ -1,9 +1,13 @@
 package domain
 
 import (
+	"strconv"
 	"time"
 )
 
+// defaultTimezone is the timezone of establishments that do not set one.
+const defaultTimezone = "Europe/Paris"
+
 // =============================================================================
 // Establishment Entity
 // =============================================================================
@@ -25,6 +29,9 @@ type Establishment struct {
 	// Geolocation
 	Location GeoLocation `json:"location" bson:"location"`
 
+	// Timezone opening hours are expressed in (IANA name, e.g. "Europe/Paris")
+	Timezone string `json:"timezone" bson:"timezone,omitempty"`
+
 	// Contact
 	Contact EstablishmentContact `json:"contact" bson:"contact"`
 
@@ -66,6 +73,7 @@ func NewEstablishment(name string, address Address, location GeoLocation) Establ
 		Name:         name,
 		Address:      address,
 		Location:     location,
+		Timezone:     defaultTimezone,
 		OpeningHours: make([]OpeningHour, 0),
 		Closures:     make([]Closure, 0),
 		Images:       make([]Image, 0),
@@ -185,6 +193,30 @@ func (e *Establishment) SetPriceRange(priceRange int) {
 	e.UpdatedAt = time.Now()
 }
 
+// SetTimezone sets the timezone opening hours are expressed in.
+func (e *Establishment) SetTimezone(timezone string) error {
+	if _, err := time.LoadLocation(timezone); err != nil || timezone == "" {
+		return ErrInvalidTimezone
+	}
+	e.Timezone = timezone
+	e.UpdatedAt = time.Now()
+	return nil
+}
+
+// TimeLocation returns the establishment's timezone. Establishments stored
+// before timezones were recorded are in Europe/Paris.
+func (e *Establishment) TimeLocation() *time.Location {
+	name := e.Timezone
+	if name == "" {
+		name = defaultTimezone
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
+
 // SetAddress updates the address and location.
 func (e *Establishment) SetAddress(address Address, location GeoLocation) {
 	e.Address = address
@@ -204,32 +236,34 @@ func (e *Establishment) Deactivate() {
 	e.UpdatedAt = time.Now()
 }
 
-// IsOpenAt checks if the establishment is open at a given time.
+// IsOpenAt checks if the establishment is open at a given time, read on the
+// wall clock of its timezone so that opening hours hold across DST changes.
+// A period closing before it opens (e.g. 22:00 - 02:00) runs into the next
+// day, and an exceptional closure closes the periods starting that day.
 func (e *Establishment) IsOpenAt(t time.Time) bool {
-	// Check for exceptional closures
-	for _, closure := range e.Closures {
-		if closure.Date.Truncate(24 * time.Hour).Equal(t.Truncate(24 * time.Hour)) {
-			return false
-		}
-	}
+	local := t.In(e.TimeLocation())
+	yesterday := local.AddDate(0, 0, -1)
+	minute := local.Hour()*60 + local.Minute()
 
-	// Check opening hours for the day of week
-	dayOfWeek := int(t.Weekday())
 	for _, oh := range e.OpeningHours {
-		if oh.DayOfWeek == dayOfWeek && !oh.IsClosed {
-			// Parse times and compare
-			openTime, _ := time.Parse("15:04", oh.Open)
-			closeTime, _ := time.Parse("15:04", oh.Close)
-			currentTime, _ := time.Parse("15:04", t.Format("15:04"))
+		if oh.IsClosed {
+			continue
+		}
+		opens, okOpen := parseClock(oh.Open)
+		closes, okClose := parseClock(oh.Close)
+		if !okOpen || !okClose {
+			continue
+		}
+		crossesMidnight := closes < opens
 
-			if currentTime.After(openTime) && currentTime.Before(closeTime) {
+		if oh.DayOfWeek == int(local.Weekday()) && minute >= opens && (minute < closes || crossesMidnight) {
+			if !e.isClosedOn(local) {
 				return true
 			}
-			// Handle midnight crossing (e.g., 22:00 - 02:00)
-			if closeTime.Before(openTime) {
-				if currentTime.After(openTime) || currentTime.Before(closeTime) {
-					return true
-				}
+		}
+		if crossesMidnight && oh.DayOfWeek == int(yesterday.Weekday()) && minute < closes {
+			if !e.isClosedOn(yesterday) {
+				return true
 			}
 		}
 	}
@@ -237,6 +271,36 @@ func (e *Establishment) IsOpenAt(t time.Time) bool {
 	return false
 }
 
+// isClosedOn checks for an exceptional closure on the calendar day of t.
+// Closure dates are stored at midnight UTC.
+func (e *Establishment) isClosedOn(t time.Time) bool {
+	year, month, day := t.Date()
+	for _, closure := range e.Closures {
+		cy, cm, cd := closure.Date.UTC().Date()
+		if cy == year && cm == month && cd == day {
+			return true
+		}
+	}
+	return false
+}
+
+// parseClock parses an "HH:MM" time into minutes since midnight. "24:00" is
+// accepted as the end of the day.
+func parseClock(value string) (int, bool) {
+	if len(value) != 5 || value[2] != ':' {
+		return 0, false
+	}
+	hour, errHour := strconv.Atoi(value[:2])
+	minute, errMinute := strconv.Atoi(value[3:])
+	if errHour != nil || errMinute != nil || hour < 0 || minute < 0 || minute > 59 {
+		return 0, false
+	}
+	if hour > 23 && !(hour == 24 && minute == 0) {
+		return 0, false
+	}
+	return hour*60 + minute, true
+}
+
 // =============================================================================
 // Opening Hour Value Object
 // =============================================================================
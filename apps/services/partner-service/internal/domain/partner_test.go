@@ -2,6 +2,7 @@ package domain
 
 import (
 	"testing"
+	"time"
 )
 
 // =============================================================================
@@ -133,3 +134,71 @@ func TestNewPartnerStats(t *testing.T) {
 		t.Errorf("NewPartnerStats() totalBookings = %d, want 0", stats.TotalBookings)
 	}
 }
+
+// =============================================================================
+// Establishment Tests
+// =============================================================================
+
+func TestEstablishment_IsOpenAt(t *testing.T) {
+	paris, err := time.LoadLocation("Europe/Paris")
+	if err != nil {
+		t.Skip("timezone database not available")
+	}
+
+	est := NewEstablishment("Le Bar", Address{}, GeoLocation{})
+	est.SetOpeningHours([]OpeningHour{
+		NewOpeningHour(int(time.Friday), "18:00", "02:00"),
+		NewOpeningHour(int(time.Sunday), "09:00", "12:00"),
+	})
+
+	tests := []struct {
+		name string
+		at   time.Time
+		want bool
+	}{
+		{"friday 23:30", time.Date(2026, 10, 16, 23, 30, 0, 0, paris), true},
+		{"saturday 01:30", time.Date(2026, 10, 17, 1, 30, 0, 0, paris), true},
+		{"saturday 02:00", time.Date(2026, 10, 17, 2, 0, 0, 0, paris), false},
+		{"friday 17:00", time.Date(2026, 10, 16, 17, 0, 0, 0, paris), false},
+		// 21:30 UTC is 23:30 in Paris
+		{"same instant in UTC", time.Date(2026, 10, 16, 21, 30, 0, 0, time.UTC), true},
+		// Clocks go forward on 29 March 2026: 07:30 UTC is 09:30 local
+		{"DST day", time.Date(2026, 3, 29, 7, 30, 0, 0, time.UTC), true},
+		{"week before DST", time.Date(2026, 3, 22, 7, 30, 0, 0, time.UTC), false},
+	}
+	for _, tt := range tests {
+		if got := est.IsOpenAt(tt.at); got != tt.want {
+			t.Errorf("%s: IsOpenAt() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+
+	// Closing on Friday also closes the hours after midnight
+	est.AddClosure(NewClosure(time.Date(2026, 10, 16, 0, 0, 0, 0, time.UTC), "Private event"))
+	if est.IsOpenAt(time.Date(2026, 10, 17, 1, 30, 0, 0, paris)) {
+		t.Error("IsOpenAt() should be false after midnight following a closure")
+	}
+}
+
+func TestEstablishment_SetTimezone(t *testing.T) {
+	if _, err := time.LoadLocation("America/New_York"); err != nil {
+		t.Skip("timezone database not available")
+	}
+
+	est := NewEstablishment("Le Bar", Address{}, GeoLocation{})
+	if est.Timezone != "Europe/Paris" {
+		t.Errorf("NewEstablishment() timezone = %v, want Europe/Paris", est.Timezone)
+	}
+
+	if err := est.SetTimezone("Mars/Olympus"); err != ErrInvalidTimezone {
+		t.Errorf("SetTimezone() error = %v, want %v", err, ErrInvalidTimezone)
+	}
+	if err := est.SetTimezone("America/New_York"); err != nil {
+		t.Fatalf("SetTimezone() error = %v, want nil", err)
+	}
+
+	// 23:30 in Paris is 17:30 in New York
+	est.SetOpeningHours([]OpeningHour{NewOpeningHour(int(time.Friday), "17:00", "18:00")})
+	if !est.IsOpenAt(time.Date(2026, 10, 16, 21, 30, 0, 0, time.UTC)) {
+		t.Error("IsOpenAt() should use the establishment's timezone")
+	}
+}
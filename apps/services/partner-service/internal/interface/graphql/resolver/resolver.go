@@ -273,6 +273,7 @@ func (r *mutationResolver) AddEstablishment(ctx context.Context, partnerID strin
 		Type:         derefString(input.Type),
 		Features:     input.Features,
 		PriceRange:   derefInt(input.PriceRange, 2),
+		Timezone:     derefString(input.Timezone),
 	}
 
 	return r.AddEstablishmentHandler.Handle(ctx, cmd)
@@ -300,6 +301,11 @@ func (r *mutationResolver) UpdateEstablishment(ctx context.Context, partnerID st
 		if input.PriceRange != nil {
 			est.SetPriceRange(*input.PriceRange)
 		}
+		if input.Timezone != nil {
+			if err := est.SetTimezone(*input.Timezone); err != nil {
+				return err
+			}
+		}
 		if input.IsActive != nil {
 			if *input.IsActive {
 				est.Activate()
@@ -766,6 +772,7 @@ type AddEstablishmentInput struct {
 	Type         *string
 	Features     []string
 	PriceRange   *int
+	Timezone     *string
 }
 
 type UpdateEstablishmentInput struct {
@@ -785,6 +792,7 @@ type UpdateEstablishmentInput struct {
 	Type         *string
 	Features     []string
 	PriceRange   *int
+	Timezone     *string
 	IsActive     *bool
 }
 
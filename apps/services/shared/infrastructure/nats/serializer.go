@@ -133,9 +133,8 @@ func (r *EventRegistry) RegisterPartnerEvents() {
 
 // Discovery Context Events Registration
 func (r *EventRegistry) RegisterDiscoveryEvents() {
-	// These will be defined in the discovery service
-	// r.Register(&OfferCreated{})
-	// r.Register(&OfferPublished{})
+	// Defined by the discovery service; consumers register them with
+	// github.com/yousoon/discovery-service/events.Register(registry)
 }
 
 // Booking Context Events Registration
@@ -0,0 +1,148 @@
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"time"
+
+	"github.com/yousoon/shared/domain"
+)
+
+// =============================================================================
+// RELAY
+// =============================================================================
+
+// EventPublisher publishes domain events to the message broker.
+// Implemented by shared/infrastructure/nats.EventPublisher.
+type EventPublisher interface {
+	Publish(ctx context.Context, event domain.DomainEvent) error
+}
+
+// Config controls how often and how much the relay drains the outbox.
+type Config struct {
+	PollInterval time.Duration
+	BatchSize    int
+	Lease        time.Duration
+	MaxBackoff   time.Duration
+}
+
+func DefaultConfig() Config {
+	return Config{
+		PollInterval: time.Second,
+		BatchSize:    100,
+		Lease:        30 * time.Second,
+		MaxBackoff:   5 * time.Minute,
+	}
+}
+
+// Relay drains the outbox into the message broker. Delivery is at-least-once:
+// an event is marked as sent only after the broker acknowledged it, so a crash
+// in between re-publishes it once its lease expires. Consumers must
+// deduplicate on event_id.
+type Relay struct {
+	store     *Store
+	publisher EventPublisher
+	config    Config
+}
+
+func NewRelay(store *Store, publisher EventPublisher, config Config) *Relay {
+	return &Relay{
+		store:     store,
+		publisher: publisher,
+		config:    config,
+	}
+}
+
+// Run polls the outbox until ctx is cancelled.
+func (r *Relay) Run(ctx context.Context) {
+	ticker := time.NewTicker(r.config.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		// Keep draining while full batches come back
+		for {
+			sent, err := r.RelayBatch(ctx)
+			if err != nil {
+				log.Printf("outbox relay: %v", err)
+			}
+			if err != nil || sent < r.config.BatchSize {
+				break
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RelayBatch publishes one batch of pending events and returns how many were sent.
+func (r *Relay) RelayBatch(ctx context.Context) (int, error) {
+	docs, err := r.store.ClaimPending(ctx, r.config.BatchSize, r.config.Lease)
+	if err != nil {
+		return 0, err
+	}
+
+	sent := 0
+	for _, doc := range docs {
+		if pubErr := r.publisher.Publish(ctx, newStoredEvent(doc)); pubErr != nil {
+			retryAt := time.Now().Add(r.backoff(doc.Attempts))
+			if markErr := r.store.MarkFailed(ctx, doc.ID, pubErr, retryAt); markErr != nil {
+				log.Printf("outbox relay: %v", markErr)
+			}
+			log.Printf("outbox relay: failed to publish %s (%s), attempt %d: %v",
+				doc.EventName, doc.EventID, doc.Attempts, pubErr)
+			continue
+		}
+
+		if markErr := r.store.MarkSent(ctx, doc.ID); markErr != nil {
+			// The lease will expire and the event will be published again
+			log.Printf("outbox relay: %v", markErr)
+			continue
+		}
+		sent++
+	}
+
+	return sent, nil
+}
+
+// backoff doubles the retry delay with each attempt, capped at MaxBackoff.
+func (r *Relay) backoff(attempts int) time.Duration {
+	delay := r.config.PollInterval
+	for i := 1; i < attempts && delay < r.config.MaxBackoff; i++ {
+		delay *= 2
+	}
+	if delay > r.config.MaxBackoff {
+		delay = r.config.MaxBackoff
+	}
+	return delay
+}
+
+// =============================================================================
+// STORED EVENT
+// =============================================================================
+
+// storedEvent re-hydrates an outbox row as a DomainEvent. It serializes to the
+// original event payload so consumers see the same JSON as the domain event.
+type storedEvent struct {
+	doc *Document
+}
+
+func newStoredEvent(doc *Document) storedEvent {
+	return storedEvent{doc: doc}
+}
+
+func (e storedEvent) EventID() string          { return e.doc.EventID }
+func (e storedEvent) EventName() string        { return e.doc.EventName }
+func (e storedEvent) OccurredAt() time.Time    { return e.doc.OccurredAt }
+func (e storedEvent) AggregateID() string      { return e.doc.AggregateID }
+func (e storedEvent) AggregateType() string    { return e.doc.AggregateType }
+func (e storedEvent) Version() int             { return e.doc.Version }
+func (e storedEvent) Payload() ([]byte, error) { return e.doc.Payload, nil }
+
+func (e storedEvent) MarshalJSON() ([]byte, error) {
+	return json.RawMessage(e.doc.Payload).MarshalJSON()
+}
@@ -0,0 +1,270 @@
+// Package outbox implements the transactional outbox shared by the services:
+// domain events are stored in the transaction that saves their aggregate and
+// relayed to the message broker afterwards.
+package outbox
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/yousoon/shared/domain"
+)
+
+// =============================================================================
+// MONGODB DOCUMENT
+// =============================================================================
+
+// Status is the delivery state of a stored event.
+type Status string
+
+const (
+	StatusPending Status = "pending"
+	StatusSent    Status = "sent"
+)
+
+// Document is a domain event waiting to be relayed to the message broker.
+// It is written in the same transaction as the aggregate that produced it.
+type Document struct {
+	ID            primitive.ObjectID `bson:"_id,omitempty"`
+	EventID       string             `bson:"event_id"`
+	EventName     string             `bson:"event_name"`
+	AggregateID   string             `bson:"aggregate_id"`
+	AggregateType string             `bson:"aggregate_type"`
+	Version       int                `bson:"version"`
+	Payload       []byte             `bson:"payload"`
+	OccurredAt    time.Time          `bson:"occurred_at"`
+
+	// Delivery state
+	Status      Status     `bson:"status"`
+	Attempts    int        `bson:"attempts"`
+	LastError   string     `bson:"last_error,omitempty"`
+	LockedUntil time.Time  `bson:"locked_until"`
+	LeaseID     string     `bson:"lease_id,omitempty"`
+	SentAt      *time.Time `bson:"sent_at,omitempty"`
+
+	// Metadata
+	CreatedAt time.Time `bson:"created_at"`
+}
+
+// =============================================================================
+// STORE
+// =============================================================================
+
+// collectionName is the collection holding each service's outbox
+const collectionName = "outbox"
+
+// sentRetention is how long delivered rows are kept for troubleshooting.
+const sentRetention = 7 * 24 * time.Hour
+
+// Store stores pending domain events until the relay publishes them.
+type Store struct {
+	collection *mongo.Collection
+}
+
+func NewStore(db *mongo.Database) *Store {
+	return &Store{collection: db.Collection(collectionName)}
+}
+
+// EnsureIndexes creates the indexes the relay and the retention rely on.
+func (s *Store) EnsureIndexes(ctx context.Context) error {
+	indexes := []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "status", Value: 1}, {Key: "locked_until", Value: 1}, {Key: "created_at", Value: 1}},
+			Options: options.Index().SetName("pending_events"),
+		},
+		{
+			Keys:    bson.D{{Key: "lease_id", Value: 1}},
+			Options: options.Index().SetSparse(true).SetName("lease_id"),
+		},
+		{
+			Keys:    bson.D{{Key: "event_id", Value: 1}},
+			Options: options.Index().SetUnique(true).SetName("event_id_unique"),
+		},
+		{
+			Keys: bson.D{{Key: "sent_at", Value: 1}},
+			Options: options.Index().
+				SetName("sent_at_ttl").
+				SetExpireAfterSeconds(int32(sentRetention.Seconds())),
+		},
+	}
+
+	if _, err := s.collection.Indexes().CreateMany(ctx, indexes); err != nil {
+		return fmt.Errorf("failed to create outbox indexes: %w", err)
+	}
+	return nil
+}
+
+// EventSource is an aggregate whose pending events go to the outbox, as
+// implemented by domain.AggregateRoot.
+type EventSource interface {
+	GetDomainEvents() []domain.DomainEvent
+	ClearDomainEvents()
+}
+
+// SaveWithEvents runs write in a transaction together with the insertion of
+// the aggregate's pending domain events. The write must use the database of
+// the store. Events are cleared from the aggregate only once the transaction
+// has committed.
+func (s *Store) SaveWithEvents(ctx context.Context, aggregate EventSource, write func(mongo.SessionContext) error) error {
+	events := aggregate.GetDomainEvents()
+
+	session, err := s.collection.Database().Client().StartSession()
+	if err != nil {
+		return fmt.Errorf("failed to start session: %w", err)
+	}
+	defer session.EndSession(ctx)
+
+	_, err = session.WithTransaction(ctx, func(sessCtx mongo.SessionContext) (interface{}, error) {
+		if err := write(sessCtx); err != nil {
+			return nil, err
+		}
+		if err := s.Append(sessCtx, events); err != nil {
+			return nil, err
+		}
+		return nil, nil
+	})
+	if err != nil {
+		return err
+	}
+
+	aggregate.ClearDomainEvents()
+	return nil
+}
+
+// Append stores the given events. When ctx is a mongo.SessionContext the
+// insert takes part in the caller's transaction.
+func (s *Store) Append(ctx context.Context, events []domain.DomainEvent) error {
+	if len(events) == 0 {
+		return nil
+	}
+
+	now := time.Now()
+	docs := make([]interface{}, 0, len(events))
+	for _, event := range events {
+		payload, err := event.Payload()
+		if err != nil {
+			return fmt.Errorf("failed to serialize event %s: %w", event.EventName(), err)
+		}
+
+		docs = append(docs, Document{
+			ID:            primitive.NewObjectID(),
+			EventID:       event.EventID(),
+			EventName:     event.EventName(),
+			AggregateID:   event.AggregateID(),
+			AggregateType: event.AggregateType(),
+			Version:       event.Version(),
+			Payload:       payload,
+			OccurredAt:    event.OccurredAt(),
+			Status:        StatusPending,
+			LockedUntil:   now,
+			CreatedAt:     now,
+		})
+	}
+
+	if _, err := s.collection.InsertMany(ctx, docs); err != nil {
+		return fmt.Errorf("failed to insert outbox events: %w", err)
+	}
+
+	return nil
+}
+
+// ClaimPending leases up to limit pending events for the given duration so that
+// concurrent relays do not pick the same rows. Events whose lease expired
+// (e.g. the relay crashed before acknowledging) are claimed again.
+//
+// The batch is claimed in bulk: the oldest claimable IDs are tagged with a new
+// lease ID, still only if claimable, and the rows carrying that lease ID are
+// the ones this call won.
+func (s *Store) ClaimPending(ctx context.Context, limit int, lease time.Duration) ([]*Document, error) {
+	now := time.Now()
+	claimable := bson.D{
+		{Key: "status", Value: StatusPending},
+		{Key: "locked_until", Value: bson.D{{Key: "$lte", Value: now}}},
+	}
+
+	findOpts := options.Find().
+		SetSort(bson.D{{Key: "created_at", Value: 1}}).
+		SetLimit(int64(limit)).
+		SetProjection(bson.D{{Key: "_id", Value: 1}})
+	cursor, err := s.collection.Find(ctx, claimable, findOpts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find pending outbox events: %w", err)
+	}
+	var candidates []struct {
+		ID primitive.ObjectID `bson:"_id"`
+	}
+	if err := cursor.All(ctx, &candidates); err != nil {
+		return nil, fmt.Errorf("failed to decode pending outbox events: %w", err)
+	}
+	if len(candidates) == 0 {
+		return nil, nil
+	}
+
+	ids := make([]primitive.ObjectID, 0, len(candidates))
+	for _, candidate := range candidates {
+		ids = append(ids, candidate.ID)
+	}
+
+	leaseID := primitive.NewObjectID().Hex()
+	filter := append(bson.D{{Key: "_id", Value: bson.D{{Key: "$in", Value: ids}}}}, claimable...)
+	update := bson.D{
+		{Key: "$set", Value: bson.D{
+			{Key: "locked_until", Value: now.Add(lease)},
+			{Key: "lease_id", Value: leaseID},
+		}},
+		{Key: "$inc", Value: bson.D{{Key: "attempts", Value: 1}}},
+	}
+	if _, err := s.collection.UpdateMany(ctx, filter, update); err != nil {
+		return nil, fmt.Errorf("failed to claim outbox events: %w", err)
+	}
+
+	cursor, err = s.collection.Find(ctx,
+		bson.D{{Key: "lease_id", Value: leaseID}},
+		options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}}),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load claimed outbox events: %w", err)
+	}
+	var claimed []*Document
+	if err := cursor.All(ctx, &claimed); err != nil {
+		return nil, fmt.Errorf("failed to decode claimed outbox events: %w", err)
+	}
+
+	return claimed, nil
+}
+
+// MarkSent flags an event as delivered.
+func (s *Store) MarkSent(ctx context.Context, id primitive.ObjectID) error {
+	now := time.Now()
+	update := bson.D{{Key: "$set", Value: bson.D{
+		{Key: "status", Value: StatusSent},
+		{Key: "sent_at", Value: now},
+		{Key: "last_error", Value: ""},
+	}}}
+
+	if _, err := s.collection.UpdateByID(ctx, id, update); err != nil {
+		return fmt.Errorf("failed to mark outbox event as sent: %w", err)
+	}
+
+	return nil
+}
+
+// MarkFailed records a delivery failure and schedules the next attempt.
+func (s *Store) MarkFailed(ctx context.Context, id primitive.ObjectID, cause error, retryAt time.Time) error {
+	update := bson.D{{Key: "$set", Value: bson.D{
+		{Key: "last_error", Value: cause.Error()},
+		{Key: "locked_until", Value: retryAt},
+	}}}
+
+	if _, err := s.collection.UpdateByID(ctx, id, update); err != nil {
+		return fmt.Errorf("failed to mark outbox event as failed: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,268 @@
+// Package scheduler runs periodic background jobs once across the replicas of
+// a service.
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	"github.com/yousoon/shared/infrastructure/redis"
+)
+
+// =============================================================================
+// JOB
+// =============================================================================
+
+// JobResult reports how many items a job run processed and how many failed.
+type JobResult struct {
+	Processed int
+	Failed    int
+}
+
+// JobFunc executes one run of a job.
+type JobFunc func(ctx context.Context) (JobResult, error)
+
+// Job is a periodic background task. It runs once per interval across
+// replicas: the replica that takes the Redis lock named after the job keeps it
+// between runs and runs the job on each of its ticks, while the others skip
+// theirs until it stops.
+type Job struct {
+	Name     string
+	Interval time.Duration
+	// LockTTL bounds how long a replica crashing during a run can hold the
+	// lock. The lock is extended while the run is in progress; between runs
+	// it is held for half an interval past the owner's next tick.
+	LockTTL time.Duration
+	Run     JobFunc
+}
+
+// =============================================================================
+// METRICS
+// =============================================================================
+
+type metrics struct {
+	runs           *prometheus.CounterVec
+	itemsProcessed *prometheus.CounterVec
+	itemsFailed    *prometheus.CounterVec
+	duration       *prometheus.HistogramVec
+}
+
+func newMetrics(subsystem string) *metrics {
+	return &metrics{
+		runs: promauto.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: "yousoon",
+				Subsystem: subsystem,
+				Name:      "runs_total",
+				Help:      "Total number of job runs by outcome",
+			},
+			[]string{"job", "result"}, // success, error, skipped
+		),
+		itemsProcessed: promauto.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: "yousoon",
+				Subsystem: subsystem,
+				Name:      "items_processed_total",
+				Help:      "Total number of items successfully processed by jobs",
+			},
+			[]string{"job"},
+		),
+		itemsFailed: promauto.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: "yousoon",
+				Subsystem: subsystem,
+				Name:      "items_failed_total",
+				Help:      "Total number of items that failed processing in jobs",
+			},
+			[]string{"job"},
+		),
+		duration: promauto.NewHistogramVec(
+			prometheus.HistogramOpts{
+				Namespace: "yousoon",
+				Subsystem: subsystem,
+				Name:      "run_duration_seconds",
+				Help:      "Job run duration in seconds",
+				Buckets:   []float64{.01, .05, .1, .25, .5, 1, 2.5, 5, 10, 30, 60},
+			},
+			[]string{"job"},
+		),
+	}
+}
+
+// =============================================================================
+// SCHEDULER
+// =============================================================================
+
+// Scheduler runs jobs periodically across replicas.
+type Scheduler struct {
+	redis     *redis.Client
+	keyPrefix string
+	metrics   *metrics
+	jobs      []Job
+	wg        sync.WaitGroup
+}
+
+// NewScheduler creates a scheduler whose lock keys start with keyPrefix and
+// whose metrics are reported under metricsSubsystem, e.g. "booking_jobs".
+// Create one scheduler per process: its metrics are registered globally.
+func NewScheduler(redisClient *redis.Client, keyPrefix, metricsSubsystem string) *Scheduler {
+	return &Scheduler{
+		redis:     redisClient,
+		keyPrefix: keyPrefix,
+		metrics:   newMetrics(metricsSubsystem),
+	}
+}
+
+// Register adds a job. Must be called before Start.
+func (s *Scheduler) Register(job Job) {
+	if job.LockTTL <= 0 {
+		job.LockTTL = job.Interval
+	}
+	s.jobs = append(s.jobs, job)
+}
+
+// Start launches one loop per job. Loops stop when ctx is cancelled.
+func (s *Scheduler) Start(ctx context.Context) {
+	for _, job := range s.jobs {
+		s.wg.Add(1)
+		go func(job Job) {
+			defer s.wg.Done()
+			s.loop(ctx, job)
+		}(job)
+	}
+}
+
+// Wait blocks until all job loops have returned.
+func (s *Scheduler) Wait() {
+	s.wg.Wait()
+}
+
+func (s *Scheduler) loop(ctx context.Context, job Job) {
+	// The lock outlives runs: the replica holding it keeps running the job
+	// while the others skip, until it stops or fails to extend it
+	lock := redis.NewDistributedLock(s.redis, s.keyPrefix+job.Name, job.LockTTL)
+	defer func() {
+		if err := lock.Release(context.Background()); err != nil && !errors.Is(err, redis.ErrLockNotOwned) {
+			log.Printf("scheduler: job %s: %v", job.Name, err)
+		}
+	}()
+
+	ticker := time.NewTicker(job.Interval)
+	defer ticker.Stop()
+
+	for {
+		s.runOnce(ctx, job, lock)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *Scheduler) runOnce(ctx context.Context, job Job, lock *redis.DistributedLock) {
+	owned, err := s.own(ctx, lock, job)
+	if err != nil {
+		log.Printf("scheduler: job %s: %v", job.Name, err)
+		s.metrics.runs.WithLabelValues(job.Name, "error").Inc()
+		return
+	}
+	if !owned {
+		// Another replica owns this job
+		s.metrics.runs.WithLabelValues(job.Name, "skipped").Inc()
+		return
+	}
+
+	start := time.Now()
+	runCtx, cancel := context.WithCancel(ctx)
+	keepAliveDone := make(chan struct{})
+	go func() {
+		defer close(keepAliveDone)
+		s.keepAlive(runCtx, cancel, lock, job)
+	}()
+
+	result, err := job.Run(runCtx)
+	cancel()
+	<-keepAliveDone
+	s.holdUntilNextRun(lock, job, start)
+
+	s.metrics.duration.WithLabelValues(job.Name).Observe(time.Since(start).Seconds())
+	s.metrics.itemsProcessed.WithLabelValues(job.Name).Add(float64(result.Processed))
+	s.metrics.itemsFailed.WithLabelValues(job.Name).Add(float64(result.Failed))
+
+	if err != nil {
+		log.Printf("scheduler: job %s failed: %v", job.Name, err)
+		s.metrics.runs.WithLabelValues(job.Name, "error").Inc()
+		return
+	}
+	s.metrics.runs.WithLabelValues(job.Name, "success").Inc()
+
+	if result.Processed > 0 || result.Failed > 0 {
+		log.Printf("scheduler: job %s processed=%d failed=%d", job.Name, result.Processed, result.Failed)
+	}
+}
+
+// own reports whether this replica owns the job for the run: it keeps the
+// lock it already holds, or takes it when no replica holds it.
+func (s *Scheduler) own(ctx context.Context, lock *redis.DistributedLock, job Job) (bool, error) {
+	if lock.IsAcquired() {
+		err := lock.Extend(ctx, job.LockTTL)
+		if err == nil {
+			return true, nil
+		}
+		if !errors.Is(err, redis.ErrLockNotOwned) {
+			return false, err
+		}
+	}
+	return lock.Acquire(ctx)
+}
+
+// holdUntilNextRun keeps the lock past this replica's next tick, so replicas
+// ticking in between skip the job. If this replica stops without releasing it,
+// another takes over once the lock expires.
+func (s *Scheduler) holdUntilNextRun(lock *redis.DistributedLock, job Job, start time.Time) {
+	if !lock.IsAcquired() {
+		// Lost while the job ran
+		return
+	}
+
+	hold := job.Interval - time.Since(start)
+	if hold < 0 {
+		hold = 0
+	}
+	hold += job.Interval / 2
+
+	if err := lock.Extend(context.Background(), hold); err != nil && !errors.Is(err, redis.ErrLockNotOwned) {
+		log.Printf("scheduler: job %s: %v", job.Name, err)
+	}
+}
+
+// keepAlive extends the lock while the job runs. If ownership is lost the run
+// is cancelled so two replicas never work on the same job concurrently.
+func (s *Scheduler) keepAlive(ctx context.Context, cancel context.CancelFunc, lock *redis.DistributedLock, job Job) {
+	ticker := time.NewTicker(job.LockTTL / 2)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := lock.Extend(ctx, job.LockTTL); err != nil {
+				if ctx.Err() != nil {
+					return
+				}
+				log.Printf("scheduler: job %s lost its lock: %v", job.Name, err)
+				cancel()
+				return
+			}
+		}
+	}
+}